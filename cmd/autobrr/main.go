@@ -15,6 +15,7 @@ import (
 	"github.com/autobrr/autobrr/internal/auth"
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/events"
 	"github.com/autobrr/autobrr/internal/feed"
@@ -55,6 +56,8 @@ func main() {
 	// init dynamic config
 	cfg.DynamicReload(log)
 
+	domain.SetGroupTiers(cfg.Config.GroupTiers, cfg.Config.GroupTierDefault)
+
 	// setup server-sent-events
 	serverEvents := sse.New()
 	serverEvents.CreateStreamWithOpts("logs", sse.StreamOpts{MaxEntries: 1000, AutoReplay: true})
@@ -83,6 +86,11 @@ func main() {
 		apikeyRepo         = database.NewAPIRepo(log, db)
 		downloadClientRepo = database.NewDownloadClientRepo(log, db)
 		actionRepo         = database.NewActionRepo(log, db, downloadClientRepo)
+		actionTemplateRepo = database.NewActionTemplateRepo(log, db)
+		actionResultRepo   = database.NewActionResultRepo(log, db)
+		macroOverrideRepo  = database.NewMacroOverrideRepo(log, db)
+		pendingResumeRepo  = database.NewPendingResumeRepo(log, db)
+		pendingReleaseRepo = database.NewPendingReleaseRepo(log, db)
 		filterRepo         = database.NewFilterRepo(log, db)
 		feedRepo           = database.NewFeedRepo(log, db)
 		feedCacheRepo      = database.NewFeedCacheRepo(log, db)
@@ -96,19 +104,19 @@ func main() {
 	// setup services
 	var (
 		apiService            = api.NewService(log, apikeyRepo)
-		notificationService   = notification.NewService(log, notificationRepo)
+		notificationService   = notification.NewService(log, cfg.Config, notificationRepo)
 		updateService         = update.NewUpdate(log, cfg.Config)
 		schedulingService     = scheduler.NewService(log, cfg.Config, notificationService, updateService)
 		indexerAPIService     = indexer.NewAPIService(log)
 		userService           = user.NewService(userRepo)
 		authService           = auth.NewService(log, userService)
-		downloadClientService = download_client.NewService(log, downloadClientRepo)
-		actionService         = action.NewService(log, actionRepo, downloadClientService, bus)
+		downloadClientService = download_client.NewService(log, cfg.Config, downloadClientRepo)
+		actionService         = action.NewService(log, cfg.Config, actionRepo, actionTemplateRepo, actionResultRepo, macroOverrideRepo, pendingResumeRepo, downloadClientService, bus)
 		indexerService        = indexer.NewService(log, cfg.Config, indexerRepo, indexerAPIService, schedulingService)
-		filterService         = filter.NewService(log, filterRepo, actionRepo, releaseRepo, indexerAPIService, indexerService)
-		releaseService        = release.NewService(log, releaseRepo, actionService, filterService)
+		filterService         = filter.NewService(log, cfg.Config, filterRepo, actionRepo, releaseRepo, indexerAPIService, indexerService)
+		releaseService        = release.NewService(log, cfg.Config, releaseRepo, pendingReleaseRepo, actionService, filterService, bus)
 		ircService            = irc.NewService(log, serverEvents, ircRepo, releaseService, indexerService, notificationService)
-		feedService           = feed.NewService(log, feedRepo, feedCacheRepo, releaseService, schedulingService)
+		feedService           = feed.NewService(log, cfg.Config, feedRepo, feedCacheRepo, releaseService, schedulingService)
 	)
 
 	// register event subscribers
@@ -143,7 +151,7 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
 
-	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, schedulingService, updateService)
+	srv := server.NewServer(log, cfg.Config, actionService, ircService, indexerService, feedService, schedulingService, updateService)
 	if err := srv.Start(); err != nil {
 		log.Fatal().Stack().Err(err).Msg("could not start server")
 		return
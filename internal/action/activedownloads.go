@@ -0,0 +1,40 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// activeDownloadsFunc returns the number of active downloads in the client
+type activeDownloadsFunc func(ctx context.Context) (int, error)
+
+// checkFilterActiveDownloads returns domain.ErrMaxActiveDownloads if the client has as many active downloads as the filter allows,
+// the release service then tries the release again later.
+// Clients without a way to count active downloads pass a nil active and are added without the check.
+func (s *service) checkFilterActiveDownloads(ctx context.Context, action *domain.Action, release *domain.Release, active activeDownloadsFunc) error {
+	if release.Filter == nil || release.Filter.MaxActiveDownloads <= 0 {
+		return nil
+	}
+
+	if active == nil {
+		s.log.Warn().Msgf("action %s: max active downloads is not supported for %s, skipping", action.Name, action.Type)
+		return nil
+	}
+
+	count, err := active(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get active downloads")
+	}
+
+	if count >= release.Filter.MaxActiveDownloads {
+		s.log.Debug().Msgf("action %s: max active downloads reached %d/%d", action.Name, count, release.Filter.MaxActiveDownloads)
+		return domain.ErrMaxActiveDownloads
+	}
+
+	return nil
+}
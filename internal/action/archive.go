@@ -0,0 +1,89 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// archiveTorrent stores the downloaded torrent file itself in the archive path, the data is not touched.
+// The file is named after the release unless a filename is set.
+func (s *service) archiveTorrent(ctx context.Context, action *domain.Action, release domain.Release) error {
+	if release.TorrentTmpFile == "" {
+		return fmt.Errorf("action archive torrent: missing torrent file for %s", release.TorrentName)
+	}
+
+	fileName := action.ArchiveFilename
+	if fileName == "" {
+		fileName = domain.SanitizeFilename(release.TorrentName)
+	}
+
+	if !strings.HasSuffix(fileName, ".torrent") {
+		fileName += ".torrent"
+	}
+
+	if err := os.MkdirAll(action.ArchivePath, os.ModePerm); err != nil {
+		return errors.Wrap(err, "could not create archive folder %v", action.ArchivePath)
+	}
+
+	dst := filepath.Join(action.ArchivePath, fileName)
+
+	s.log.Trace().Msgf("action ARCHIVE_TORRENT: %v file: %v mode: %v", dst, release.TorrentTmpFile, action.ArchiveMode)
+
+	if action.ArchiveMode == domain.ActionArchiveModeHardlink {
+		err := linkFileAtomic(release.TorrentTmpFile, dst)
+		if err == nil {
+			s.log.Info().Msgf("hardlinked torrent file to archive: %v", dst)
+			return nil
+		}
+
+		// hardlinks can't cross filesystems, the tmp dir often is on another one than the archive
+		s.log.Warn().Err(err).Msgf("could not hardlink torrent file to archive, copying instead: %v", dst)
+	}
+
+	data := release.TorrentDataRawBytes
+	if len(data) == 0 {
+		var err error
+		data, err = os.ReadFile(release.TorrentTmpFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read torrent file %v", release.TorrentTmpFile)
+		}
+	}
+
+	if err := writeFileAtomic(dst, data); err != nil {
+		return errors.Wrap(err, "could not write file %v to archive", dst)
+	}
+
+	s.log.Info().Msgf("saved torrent file to archive: %v", dst)
+
+	return nil
+}
+
+// linkFileAtomic hardlinks src to a tmp name next to the target and renames it into place,
+// so an existing file with the same name is replaced
+func linkFileAtomic(src, name string) error {
+	// the downloaded tmp files have unique names, so releases archived at the same time don't collide
+	tmpName := filepath.Join(filepath.Dir(name), fmt.Sprintf(".autobrr-%s.tmp", filepath.Base(src)))
+
+	// a leftover from an earlier failed link would make os.Link fail
+	_ = os.Remove(tmpName)
+
+	if err := os.Link(src, tmpName); err != nil {
+		return errors.Wrap(err, "could not hardlink %v", src)
+	}
+
+	if err := os.Rename(tmpName, name); err != nil {
+		os.Remove(tmpName)
+		return errors.Wrap(err, "could not rename tmp file %v to %v", tmpName, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+const archiveFixture = "../domain/testdata/single-tracker.torrent"
+
+func Test_service_archiveTorrent(t *testing.T) {
+	fixture, err := os.ReadFile(archiveFixture)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		action   domain.Action
+		wantPath string
+		wantLink bool
+	}{
+		{
+			name:     "copy_default_name",
+			action:   domain.Action{ArchivePath: "archive"},
+			wantPath: "archive/That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP.torrent",
+		},
+		{
+			name:     "copy_templated",
+			action:   domain.Action{ArchivePath: "archive/{{ .Indexer }}", ArchiveFilename: "{{ .Indexer }}-{{ .TorrentName }}", ArchiveMode: domain.ActionArchiveModeCopy},
+			wantPath: "archive/mock/mock-That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP.torrent",
+		},
+		{
+			name:     "hardlink",
+			action:   domain.Action{ArchivePath: "archive/{{ .CurrentYear }}", ArchiveFilename: "release.torrent", ArchiveMode: domain.ActionArchiveModeHardlink},
+			wantLink: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			tmpFile := filepath.Join(dir, "autobrr-12345")
+			assert.NoError(t, os.WriteFile(tmpFile, fixture, 0644))
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+			}
+
+			action := tt.action
+			action.Name = "archive"
+			action.Type = domain.ActionTypeArchiveTorrent
+			action.ArchivePath = filepath.Join(dir, action.ArchivePath)
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				TorrentTmpFile: tmpFile,
+				Indexer:        "mock",
+				Protocol:       domain.ReleaseProtocolTorrent,
+			}
+
+			_, err := s.RunAction(context.Background(), &action, release)
+			assert.NoError(t, err)
+
+			dst := filepath.Join(dir, tt.wantPath)
+			if tt.wantLink {
+				dst = filepath.Join(action.ArchivePath, "release.torrent")
+			}
+
+			written, err := os.ReadFile(dst)
+			assert.NoError(t, err)
+			assert.Equal(t, fixture, written)
+
+			srcInfo, err := os.Stat(tmpFile)
+			assert.NoError(t, err)
+			dstInfo, err := os.Stat(dst)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLink, os.SameFile(srcInfo, dstInfo))
+
+			// no tmp files left behind
+			entries, err := os.ReadDir(filepath.Dir(dst))
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1)
+		})
+	}
+}
+
+func Test_service_archiveTorrent_download(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archiveFixture)
+	}))
+	defer ts.Close()
+
+	fixture, err := os.ReadFile(archiveFixture)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+	}
+
+	// the torrent file is downloaded before the action runs
+	release := &domain.Release{
+		TorrentName: "../That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		DownloadURL: ts.URL + "/download/1",
+		Indexer:     "mock",
+		Protocol:    domain.ReleaseProtocolTorrent,
+	}
+	defer release.CleanupTemporaryFiles()
+
+	_, err = s.RunAction(context.Background(), &domain.Action{Name: "archive", Type: domain.ActionTypeArchiveTorrent, ArchivePath: dir}, release)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, release.TorrentTmpFile)
+
+	// the release name can't escape the archive path
+	written, err := os.ReadFile(filepath.Join(dir, ".._That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP.torrent"))
+	assert.NoError(t, err)
+	assert.Equal(t, fixture, written)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBatchMaxSize       = 10
+	defaultBatchFlushInterval = 500 * time.Millisecond
+	batchAddTimeout           = 60 * time.Second
+)
+
+// batchAddFunc sends all items of a batch to the client in a single request
+type batchAddFunc func(ctx context.Context, items []string) error
+
+// addBatcher buffers adds to the same client so they can be sent in one request.
+// A batch is sent when it reaches maxSize or flushInterval after its first item, whatever comes first.
+// Add blocks until the batch is sent so every release still gets the result of its own add, a batch therefore
+// collects the adds running at the same time, like feeds refreshing together. The short flush interval keeps
+// the delay low for a release that ends up alone in its batch.
+// Only qBittorrent magnets are batched: the Transmission and Deluge add calls take a single torrent
+// and return the id needed for labels and limits, so they are still added one by one.
+type addBatcher struct {
+	maxSize       int
+	flushInterval time.Duration
+
+	m       sync.Mutex
+	pending map[string]*addBatch
+}
+
+type addBatch struct {
+	items []string
+	add   batchAddFunc
+	timer *time.Timer
+	done  chan struct{}
+	err   error
+}
+
+func newAddBatcher(maxSize int, flushInterval time.Duration) *addBatcher {
+	return &addBatcher{
+		maxSize:       maxSize,
+		flushInterval: flushInterval,
+		pending:       map[string]*addBatch{},
+	}
+}
+
+// Add queues the item in the batch for key. Items with the same key must be addable with the same options,
+// the add func of the first item is used for the whole batch.
+func (b *addBatcher) Add(ctx context.Context, key string, item string, add batchAddFunc) error {
+	b.m.Lock()
+
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &addBatch{add: add, done: make(chan struct{})}
+		b.pending[key] = batch
+		batch.timer = time.AfterFunc(b.flushInterval, func() { b.flush(key, batch) })
+	}
+
+	batch.items = append(batch.items, item)
+
+	full := len(batch.items) >= b.maxSize
+	b.m.Unlock()
+
+	if full {
+		batch.timer.Stop()
+		b.flush(key, batch)
+	}
+
+	select {
+	case <-batch.done:
+		return batch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush sends the batch unless it was already sent
+func (b *addBatcher) flush(key string, batch *addBatch) {
+	b.m.Lock()
+	if b.pending[key] != batch {
+		b.m.Unlock()
+		return
+	}
+	delete(b.pending, key)
+	b.m.Unlock()
+
+	// the batch outlives the context of the release that started it
+	ctx, cancel := context.WithTimeout(context.Background(), batchAddTimeout)
+	defer cancel()
+
+	batch.err = batch.add(ctx, batch.items)
+	close(batch.done)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingBatchAdd struct {
+	m       sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (r *recordingBatchAdd) add(ctx context.Context, items []string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	batch := append([]string(nil), items...)
+	sort.Strings(batch)
+	r.batches = append(r.batches, batch)
+
+	return r.err
+}
+
+// addConcurrently queues the items from separate goroutines like releases processed from a feed
+func addConcurrently(b *addBatcher, key string, items []string, add batchAddFunc) []error {
+	errs := make([]error, len(items))
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		i, item := i, item
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = b.Add(context.Background(), key, item, add)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func Test_addBatcher_flushInterval(t *testing.T) {
+	rec := &recordingBatchAdd{}
+	b := newAddBatcher(10, 100*time.Millisecond)
+
+	items := []string{"a", "b", "c", "d", "e"}
+
+	start := time.Now()
+	errs := addConcurrently(b, "client", items, rec.add)
+
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, make([]error, len(items)), errs)
+	assert.Equal(t, [][]string{items}, rec.batches)
+	assert.Empty(t, b.pending)
+}
+
+func Test_addBatcher_maxSize(t *testing.T) {
+	rec := &recordingBatchAdd{}
+
+	// the interval is never reached, full batches are sent right away
+	b := newAddBatcher(3, time.Hour)
+
+	var items []string
+	for i := 0; i < 6; i++ {
+		items = append(items, fmt.Sprintf("item-%d", i))
+	}
+
+	errs := addConcurrently(b, "client", items, rec.add)
+
+	assert.Equal(t, make([]error, len(items)), errs)
+	assert.Len(t, rec.batches, 2)
+	for _, batch := range rec.batches {
+		assert.Len(t, batch, 3)
+	}
+}
+
+func Test_addBatcher_keys(t *testing.T) {
+	rec := &recordingBatchAdd{}
+	b := newAddBatcher(10, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"client-1", "client-2"} {
+		key := key
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addConcurrently(b, key, []string{key + "-a", key + "-b"}, rec.add)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(rec.batches, func(i, j int) bool { return rec.batches[i][0] < rec.batches[j][0] })
+	assert.Equal(t, [][]string{{"client-1-a", "client-1-b"}, {"client-2-a", "client-2-b"}}, rec.batches)
+}
+
+func Test_addBatcher_error(t *testing.T) {
+	rec := &recordingBatchAdd{err: errors.New("client unavailable")}
+	b := newAddBatcher(10, 50*time.Millisecond)
+
+	errs := addConcurrently(b, "client", []string{"a", "b"}, rec.add)
+
+	// every release in the batch gets the error
+	for _, err := range errs {
+		assert.EqualError(t, err, "client unavailable")
+	}
+	assert.Len(t, rec.batches, 1)
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/transmission"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+// cleanupTorrent is a torrent in the client as seen by the cleanup action
+type cleanupTorrent struct {
+	ID       int64
+	Hash     string
+	Name     string
+	Category string
+	Tags     []string
+	AddedOn  time.Time
+	Ratio    float64
+}
+
+// cleanupClient lists and removes torrents in a download client
+type cleanupClient interface {
+	List(ctx context.Context) ([]cleanupTorrent, error)
+	Remove(ctx context.Context, torrents []cleanupTorrent, deleteData bool) error
+}
+
+// cleanup removes the torrents matching the action criteria from the client.
+// With dry run the matching torrents are only listed in the response.
+func (s *service) cleanup(ctx context.Context, action *domain.Action) (string, error) {
+	s.log.Debug().Msgf("action cleanup: %s", action.Name)
+
+	client, err := s.cleanupClient(ctx, action)
+	if err != nil {
+		return "", err
+	}
+
+	return s.runCleanup(ctx, action, client, time.Now())
+}
+
+func (s *service) runCleanup(ctx context.Context, action *domain.Action, client cleanupClient, now time.Time) (string, error) {
+	// validation only sees the raw criteria, macros could have expanded all of them to nothing
+	if !action.HasCleanupCriteria() {
+		return "", errors.New("cleanup action %s has no criteria after parsing macros, refusing to remove every torrent", action.Name)
+	}
+
+	torrents, err := client.List(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "could not list torrents for cleanup action: %s", action.Name)
+	}
+
+	var matched []cleanupTorrent
+	for _, t := range torrents {
+		if cleanupMatches(action, t, now) {
+			matched = append(matched, t)
+		}
+	}
+
+	names := make([]string, 0, len(matched))
+	for _, t := range matched {
+		names = append(names, t.Name)
+	}
+
+	if action.CleanupDryRun {
+		s.log.Info().Msgf("action %s: dry run, would remove %d torrents: %s", action.Name, len(matched), strings.Join(names, ", "))
+
+		return fmt.Sprintf("dry run: would remove %d torrents: %s", len(matched), strings.Join(names, ", ")), nil
+	}
+
+	if len(matched) == 0 {
+		return "removed 0 torrents", nil
+	}
+
+	if err := client.Remove(ctx, matched, action.CleanupDeleteData); err != nil {
+		return "", errors.Wrap(err, "could not remove torrents for cleanup action: %s", action.Name)
+	}
+
+	s.log.Info().Msgf("action %s: removed %d torrents: %s", action.Name, len(matched), strings.Join(names, ", "))
+
+	return fmt.Sprintf("removed %d torrents: %s", len(matched), strings.Join(names, ", ")), nil
+}
+
+// cleanupMatches checks the torrent against every criteria set on the action.
+// Tags and label both match the tags of the torrent, all of them have to be set.
+func cleanupMatches(action *domain.Action, t cleanupTorrent, now time.Time) bool {
+	if action.Category != "" && !strings.EqualFold(action.Category, t.Category) {
+		return false
+	}
+
+	wanted := action.TagList()
+	if action.Label != "" {
+		wanted = append(wanted, action.Label)
+	}
+
+	for _, tag := range wanted {
+		found := false
+		for _, have := range t.Tags {
+			if strings.EqualFold(tag, have) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if action.CleanupMinAge > 0 && now.Sub(t.AddedOn) < time.Duration(action.CleanupMinAge)*time.Minute {
+		return false
+	}
+
+	if action.CleanupMinRatio > 0 && t.Ratio < action.CleanupMinRatio {
+		return false
+	}
+
+	return true
+}
+
+// cleanupClient returns the cleanup client for the download client of the action
+func (s *service) cleanupClient(ctx context.Context, action *domain.Action) (cleanupClient, error) {
+	client, err := s.clientSvc.FindByID(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find client by id: %d", action.ClientID)
+	}
+
+	if client == nil {
+		return nil, errors.New("could not find client by id: %d", action.ClientID)
+	}
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		c, err := s.clientSvc.GetCachedClient(ctx, action.ClientID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get client: %s", client.Name)
+		}
+
+		return &qbittorrentCleanup{qbt: c.Qbt}, nil
+
+	case domain.DownloadClientTypeTransmission:
+		scheme := "http"
+		if client.TLS {
+			scheme = "https"
+		}
+
+		u, err := url.Parse(fmt.Sprintf("%s://%s:%d/transmission/rpc", scheme, client.Host, client.Port))
+		if err != nil {
+			return nil, err
+		}
+
+		transport, err := s.clientSvc.GetTransport(client)
+		if err != nil {
+			return nil, err
+		}
+
+		tbt, err := transmission.New(u, &transmission.Config{
+			UserAgent:     "autobrr",
+			Username:      client.Username,
+			Password:      client.Password,
+			TLSSkipVerify: client.TLSSkipVerify,
+			Transport:     transport,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error logging into client: %s", client.Host)
+		}
+
+		return &transmissionCleanup{tbt: tbt}, nil
+	}
+
+	return nil, errors.New("cleanup is not supported for client type: %s", client.Type)
+}
+
+type qbittorrentCleanup struct {
+	qbt *qbittorrent.Client
+}
+
+func (c *qbittorrentCleanup) List(ctx context.Context) ([]cleanupTorrent, error) {
+	torrents, err := c.qbt.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]cleanupTorrent, 0, len(torrents))
+	for _, t := range torrents {
+		list = append(list, cleanupTorrent{
+			Hash:     t.Hash,
+			Name:     t.Name,
+			Category: t.Category,
+			Tags:     domain.SplitTags(t.Tags),
+			AddedOn:  time.Unix(t.AddedOn, 0),
+			Ratio:    t.Ratio,
+		})
+	}
+
+	return list, nil
+}
+
+func (c *qbittorrentCleanup) Remove(ctx context.Context, torrents []cleanupTorrent, deleteData bool) error {
+	hashes := make([]string, 0, len(torrents))
+	for _, t := range torrents {
+		hashes = append(hashes, t.Hash)
+	}
+
+	return c.qbt.DeleteTorrentsCtx(ctx, hashes, deleteData)
+}
+
+type transmissionCleanup struct {
+	tbt *transmissionrpc.Client
+}
+
+func (c *transmissionCleanup) List(ctx context.Context) ([]cleanupTorrent, error) {
+	torrents, err := c.tbt.TorrentGet(ctx, []string{"id", "hashString", "name", "labels", "addedDate", "uploadRatio"}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]cleanupTorrent, 0, len(torrents))
+	for _, t := range torrents {
+		if t.ID == nil {
+			continue
+		}
+
+		torrent := cleanupTorrent{ID: *t.ID, Tags: t.Labels}
+		if t.HashString != nil {
+			torrent.Hash = *t.HashString
+		}
+		if t.Name != nil {
+			torrent.Name = *t.Name
+		}
+		if t.AddedDate != nil {
+			torrent.AddedOn = *t.AddedDate
+		}
+		if t.UploadRatio != nil {
+			torrent.Ratio = *t.UploadRatio
+		}
+
+		list = append(list, torrent)
+	}
+
+	return list, nil
+}
+
+func (c *transmissionCleanup) Remove(ctx context.Context, torrents []cleanupTorrent, deleteData bool) error {
+	ids := make([]int64, 0, len(torrents))
+	for _, t := range torrents {
+		ids = append(ids, t.ID)
+	}
+
+	return c.tbt.TorrentRemove(ctx, transmissionrpc.TorrentRemovePayload{IDs: ids, DeleteLocalData: deleteData})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCleanupClient struct {
+	torrents   []cleanupTorrent
+	removed    []string
+	deleteData bool
+}
+
+func (c *fakeCleanupClient) List(ctx context.Context) ([]cleanupTorrent, error) {
+	return c.torrents, nil
+}
+
+func (c *fakeCleanupClient) Remove(ctx context.Context, torrents []cleanupTorrent, deleteData bool) error {
+	for _, t := range torrents {
+		c.removed = append(c.removed, t.Hash)
+	}
+	c.deleteData = deleteData
+
+	return nil
+}
+
+func Test_service_runCleanup(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	torrents := []cleanupTorrent{
+		{Hash: "a", Name: "Old.Seeded", Category: "tv", Tags: []string{"autobrr", "racing"}, AddedOn: now.Add(-48 * time.Hour), Ratio: 2.5},
+		{Hash: "b", Name: "New.Seeded", Category: "tv", Tags: []string{"autobrr", "racing"}, AddedOn: now.Add(-30 * time.Minute), Ratio: 3},
+		{Hash: "c", Name: "Old.Unseeded", Category: "tv", Tags: []string{"autobrr", "racing"}, AddedOn: now.Add(-48 * time.Hour), Ratio: 0.4},
+		{Hash: "d", Name: "Old.Other.Category", Category: "movies", Tags: []string{"autobrr", "racing"}, AddedOn: now.Add(-48 * time.Hour), Ratio: 5},
+		{Hash: "e", Name: "Old.Missing.Tag", Category: "tv", Tags: []string{"autobrr"}, AddedOn: now.Add(-48 * time.Hour), Ratio: 5},
+	}
+
+	tests := []struct {
+		name         string
+		action       domain.Action
+		wantRemoved  []string
+		wantResponse string
+	}{
+		{
+			name:         "remove_matching",
+			action:       domain.Action{Name: "cleanup", Type: domain.ActionTypeCleanup, Category: "tv", Tags: "autobrr,racing", CleanupMinAge: 24 * 60, CleanupMinRatio: 1, CleanupDeleteData: true},
+			wantRemoved:  []string{"a"},
+			wantResponse: "removed 1 torrents: Old.Seeded",
+		},
+		{
+			name:         "label",
+			action:       domain.Action{Name: "cleanup", Type: domain.ActionTypeCleanup, Label: "racing", CleanupMinRatio: 2},
+			wantRemoved:  []string{"a", "b", "d"},
+			wantResponse: "removed 3 torrents: Old.Seeded, New.Seeded, Old.Other.Category",
+		},
+		{
+			name:         "dry_run",
+			action:       domain.Action{Name: "cleanup", Type: domain.ActionTypeCleanup, Category: "tv", CleanupMinAge: 24 * 60, CleanupDryRun: true},
+			wantResponse: "dry run: would remove 3 torrents: Old.Seeded, Old.Unseeded, Old.Missing.Tag",
+		},
+		{
+			name:         "no_match",
+			action:       domain.Action{Name: "cleanup", Type: domain.ActionTypeCleanup, Category: "music"},
+			wantResponse: "removed 0 torrents",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: logger.Mock().With().Logger()}
+			client := &fakeCleanupClient{torrents: torrents}
+
+			got, err := s.runCleanup(context.Background(), &tt.action, client, now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantResponse, got)
+			assert.Equal(t, tt.wantRemoved, client.removed)
+			assert.Equal(t, tt.action.CleanupDeleteData, client.deleteData)
+		})
+	}
+}
+
+func Test_service_runCleanup_emptyCriteria(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	action := domain.Action{Name: "cleanup", Type: domain.ActionTypeCleanup, Category: "{{ .Category }}", Label: "{{ .Uploader }}", CleanupDeleteData: true}
+	assert.NoError(t, action.Validate())
+
+	// the release has no category or uploader, so every criteria expands to nothing
+	release := domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"}
+	assert.NoError(t, action.ParseMacros(&release))
+
+	s := &service{log: logger.Mock().With().Logger()}
+	client := &fakeCleanupClient{torrents: []cleanupTorrent{
+		{Hash: "a", Name: "Old.Seeded", Category: "tv", AddedOn: now.Add(-48 * time.Hour), Ratio: 2.5},
+	}}
+
+	_, err := s.runCleanup(context.Background(), &action, client, now)
+	assert.Error(t, err)
+	assert.Empty(t, client.removed)
+}
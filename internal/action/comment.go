@@ -0,0 +1,62 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"os"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/anacrolix/torrent/bencode"
+)
+
+// torrentWithComment sets the comment key of the bencoded torrent. Clients read the comment from the
+// metainfo since their add apis have no comment option. The other keys are copied byte for byte so the
+// info dict, and with it the infohash, stays the same.
+func torrentWithComment(data []byte, comment string) ([]byte, error) {
+	var dict map[string]bencode.Bytes
+	if err := bencode.Unmarshal(data, &dict); err != nil {
+		return nil, errors.Wrap(err, "could not decode torrent")
+	}
+
+	if _, ok := dict["info"]; !ok {
+		return nil, errors.New("torrent has no info dict")
+	}
+
+	value, err := bencode.Marshal(comment)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode comment")
+	}
+
+	dict["comment"] = value
+
+	return bencode.Marshal(dict)
+}
+
+// torrentFileWithComment writes a copy of the torrent file with the comment set and returns its path.
+// The caller removes the copy once the torrent is added.
+func torrentFileWithComment(path, comment string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read torrent file: %s", path)
+	}
+
+	data, err = torrentWithComment(data, comment)
+	if err != nil {
+		return "", errors.Wrap(err, "could not set comment for torrent file: %s", path)
+	}
+
+	tmpFile, err := os.CreateTemp("", "autobrr-")
+	if err != nil {
+		return "", errors.Wrap(err, "could not create temp file")
+	}
+	defer tmpFile.Close()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", errors.Wrap(err, "could not write torrent file: %s", tmpFile.Name())
+	}
+
+	return tmpFile.Name(), nil
+}
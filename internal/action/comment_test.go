@@ -0,0 +1,37 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_torrentWithComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		comment string
+		want    string
+		wantErr bool
+	}{
+		{name: "add", data: "d4:infod4:name4:testee", comment: "tv-1080p", want: "d7:comment8:tv-1080p4:infod4:name4:testee"},
+		{name: "replace", data: "d7:comment3:old4:infod4:name4:testee", comment: "new", want: "d7:comment3:new4:infod4:name4:testee"},
+		{name: "keeps_unknown_keys", data: "d4:infod4:name4:teste7:privatei1e6:sourcel3:ptpee", comment: "c", want: "d7:comment1:c4:infod4:name4:teste7:privatei1e6:sourcel3:ptpee"},
+		{name: "no_info", data: "d7:comment3:olde", comment: "new", wantErr: true},
+		{name: "not_bencoded", data: "<html>", comment: "new", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := torrentWithComment([]byte(tt.data), tt.comment)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"time"
+)
+
+// sleepFunc waits for the duration or until the context is done
+type sleepFunc func(ctx context.Context, d time.Duration) error
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait holds the action for its delay so earlier actions of the filter get time to finish,
+// the delay counts towards the action timeout
+func (s *service) wait(ctx context.Context, d time.Duration) error {
+	if s.sleep == nil {
+		return sleepContext(ctx, d)
+	}
+
+	return s.sleep(ctx, d)
+}
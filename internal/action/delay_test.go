@@ -0,0 +1,111 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_RunAction_delay(t *testing.T) {
+	var (
+		m     sync.Mutex
+		clock time.Time
+		calls = map[string]time.Time{}
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		calls[r.URL.Path] = clock
+		m.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+		// the fake clock moves forward by the delay instead of sleeping
+		sleep: func(ctx context.Context, d time.Duration) error {
+			m.Lock()
+			defer m.Unlock()
+
+			clock = clock.Add(d)
+
+			return ctx.Err()
+		},
+	}
+
+	actions := []*domain.Action{
+		{Name: "add", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL + "/add", WebhookData: `{}`},
+		{Name: "notify", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL + "/notify", WebhookData: `{}`, Delay: 5},
+		{Name: "cleanup", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL + "/cleanup", WebhookData: `{}`, Delay: 30},
+	}
+
+	release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"}
+
+	for _, a := range actions {
+		_, err := s.RunAction(context.Background(), a, release)
+		assert.NoError(t, err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	start := time.Time{}
+	assert.Equal(t, map[string]time.Time{
+		"/add":     start,
+		"/notify":  start.Add(5 * time.Second),
+		"/cleanup": start.Add(35 * time.Second),
+	}, calls)
+}
+
+func Test_service_RunAction_delayCancelled(t *testing.T) {
+	called := false
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		action := &domain.Action{Name: "notify", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL, WebhookData: `{}`, Delay: 60, Timeout: 1}
+
+		start := time.Now()
+		_, err := s.RunAction(context.Background(), action, &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"})
+
+		assert.True(t, errors.Is(err, domain.ErrActionTimeout))
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		action := &domain.Action{Name: "notify", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL, WebhookData: `{}`, Delay: 60}
+
+		_, err := s.RunAction(ctx, action, &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	assert.False(t, called)
+}
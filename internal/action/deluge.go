@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/base64"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -15,7 +16,7 @@ import (
 	"github.com/autobrr/go-deluge"
 )
 
-func (s *service) deluge(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) deluge(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action Deluge: %s", action.Name)
 
 	var err error
@@ -89,7 +90,7 @@ func (s *service) delugeCheckRulesCanDownload(ctx context.Context, del deluge.De
 	return nil, nil
 }
 
-func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release *domain.Release) ([]string, error) {
 	settings := deluge.Settings{
 		Hostname:             client.Host,
 		Port:                 uint(client.Port),
@@ -119,6 +120,20 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 		return rejections, nil
 	}
 
+	rejections, err = s.checkFreeSpace(ctx, action, del.GetFreeSpace)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking free space for client: %s", client.Name)
+	}
+	if rejections != nil {
+		return rejections, nil
+	}
+
+	// make sure the label exists before adding, so the torrent isn't added without it
+	labelPlugin, label, err := delugeLabelPlugin(ctx, del, action.Label)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not prepare label: %s on client: %s", action.Label, client.Name)
+	}
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareDelugeOptions(action)
 		if err != nil {
@@ -132,21 +147,18 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent magnet %s to client: %s", release.MagnetURI, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := del.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
+		if labelPlugin != nil {
+			if err := labelPlugin.SetTorrentLabel(ctx, torrentHash, label); err != nil {
+				return nil, errors.Wrap(err, "could not set label: %s on client: %s", label, client.Name)
 			}
+		}
 
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := delugeSetMoveCompleted(ctx, del, torrentHash, action); err != nil {
+			return nil, errors.Wrap(err, "could not set move completed path: %s on client: %s", action.MoveCompletedPath, client.Name)
 		}
 
+		release.ClientTorrentID = torrentHash
+
 		s.log.Info().Msgf("torrent from magnet with hash %s successfully added to client: '%s'", torrentHash, client.Name)
 
 		return nil, nil
@@ -181,28 +193,25 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentTmpFile, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := del.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
+		if labelPlugin != nil {
+			if err := labelPlugin.SetTorrentLabel(ctx, torrentHash, label); err != nil {
+				return nil, errors.Wrap(err, "could not set label: %s on client: %s", label, client.Name)
 			}
+		}
 
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %v on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := delugeSetMoveCompleted(ctx, del, torrentHash, action); err != nil {
+			return nil, errors.Wrap(err, "could not set move completed path: %s on client: %s", action.MoveCompletedPath, client.Name)
 		}
 
+		release.ClientTorrentID = torrentHash
+
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", torrentHash, client.Name)
 	}
 
 	return nil, nil
 }
 
-func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, action *domain.Action, release *domain.Release) ([]string, error) {
 	settings := deluge.Settings{
 		Hostname:             client.Host,
 		Port:                 uint(client.Port),
@@ -232,6 +241,20 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 		return rejections, nil
 	}
 
+	rejections, err = s.checkFreeSpace(ctx, action, del.GetFreeSpace)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking free space for client: %s", client.Name)
+	}
+	if rejections != nil {
+		return rejections, nil
+	}
+
+	// make sure the label exists before adding, so the torrent isn't added without it
+	labelPlugin, label, err := delugeLabelPlugin(ctx, del, action.Label)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not prepare label: %s on client: %s", action.Label, client.Name)
+	}
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareDelugeOptions(action)
 		if err != nil {
@@ -245,21 +268,18 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent magnet %s to client: %s", release.MagnetURI, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := del.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
+		if labelPlugin != nil {
+			if err := labelPlugin.SetTorrentLabel(ctx, torrentHash, label); err != nil {
+				return nil, errors.Wrap(err, "could not set label: %s on client: %s", label, client.Name)
 			}
+		}
 
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := delugeSetMoveCompleted(ctx, del, torrentHash, action); err != nil {
+			return nil, errors.Wrap(err, "could not set move completed path: %s on client: %s", action.MoveCompletedPath, client.Name)
 		}
 
+		release.ClientTorrentID = torrentHash
+
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", torrentHash, client.Name)
 
 		return nil, nil
@@ -295,27 +315,80 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 			return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Name)
 		}
 
-		if action.Label != "" {
-			labelPluginActive, err := del.LabelPlugin(ctx)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not load label plugin for client: %s", client.Name)
+		if labelPlugin != nil {
+			if err := labelPlugin.SetTorrentLabel(ctx, torrentHash, label); err != nil {
+				return nil, errors.Wrap(err, "could not set label: %s on client: %s", label, client.Name)
 			}
+		}
 
-			if labelPluginActive != nil {
-				// TODO first check if label exists, if not, add it, otherwise set
-				err = labelPluginActive.SetTorrentLabel(ctx, torrentHash, action.Label)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not set label: %s on client: %s", action.Label, client.Name)
-				}
-			}
+		if err := delugeSetMoveCompleted(ctx, del, torrentHash, action); err != nil {
+			return nil, errors.Wrap(err, "could not set move completed path: %s on client: %s", action.MoveCompletedPath, client.Name)
 		}
 
+		release.ClientTorrentID = torrentHash
+
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", torrentHash, client.Name)
 	}
 
 	return nil, nil
 }
 
+// delugeLabelPluginClient is implemented by both the v1 and v2 clients
+type delugeLabelPluginClient interface {
+	LabelPlugin(ctx context.Context) (*deluge.LabelPlugin, error)
+}
+
+// delugeLabelPlugin returns the label plugin and the label as stored by the plugin, creating the label if it doesn't exist.
+// The label plugin only sets labels it knows about. Without a label it returns a nil plugin.
+func delugeLabelPlugin(ctx context.Context, del delugeLabelPluginClient, label string) (*deluge.LabelPlugin, string, error) {
+	if label == "" {
+		return nil, "", nil
+	}
+
+	plugin, err := del.LabelPlugin(ctx)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not load label plugin")
+	}
+
+	if plugin == nil {
+		return nil, "", errors.New("label plugin is not enabled in Deluge")
+	}
+
+	// the plugin stores labels in lowercase
+	label = strings.ToLower(strings.TrimSpace(label))
+
+	labels, err := plugin.GetLabels(ctx)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not get labels")
+	}
+
+	for _, l := range labels {
+		if l == label {
+			return plugin, label, nil
+		}
+	}
+
+	if err := plugin.AddLabel(ctx, label); err != nil {
+		return nil, "", errors.Wrap(err, "could not add label")
+	}
+
+	return plugin, label, nil
+}
+
+// delugeSetMoveCompleted sets the move completed path of the added torrent with set_torrent_options
+func delugeSetMoveCompleted(ctx context.Context, del deluge.DelugeClient, hash string, action *domain.Action) error {
+	if !action.MoveCompleted {
+		return nil
+	}
+
+	moveCompleted := true
+
+	return del.SetTorrentOptions(ctx, hash, &deluge.Options{
+		MoveCompleted:     &moveCompleted,
+		MoveCompletedPath: &action.MoveCompletedPath,
+	})
+}
+
 func (s *service) prepareDelugeOptions(action *domain.Action) (deluge.Options, error) {
 
 	// set options
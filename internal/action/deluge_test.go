@@ -0,0 +1,304 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/gdm85/go-rencode"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDeluge struct {
+	m           sync.Mutex
+	labelPlugin bool
+	labels      []string
+	calls       []string
+	labelArgs   []string
+	options     map[string]interface{}
+}
+
+func (f *fakeDeluge) handle(method string, args rencode.List) interface{} {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.calls = append(f.calls, method)
+
+	switch method {
+	case "daemon.login":
+		return int64(10)
+
+	case "core.get_enabled_plugins":
+		var plugins rencode.List
+		if f.labelPlugin {
+			plugins.Add("Label")
+		}
+		return plugins
+
+	case "label.get_labels":
+		var labels rencode.List
+		for _, l := range f.labels {
+			labels.Add(l)
+		}
+		return labels
+
+	case "label.add":
+		var label string
+		_ = args.Scan(&label)
+		f.labels = append(f.labels, label)
+		f.labelArgs = append(f.labelArgs, label)
+
+	case "label.set_torrent":
+		var hash, label string
+		_ = args.Scan(&hash, &label)
+		f.labelArgs = append(f.labelArgs, label)
+
+	case "core.set_torrent_options":
+		var hash string
+		var options rencode.Dictionary
+		_ = args.Scan(&hash, &options)
+		f.options, _ = options.Zip()
+
+		// rencode decodes strings as bytes
+		for k, v := range f.options {
+			if b, ok := v.([]byte); ok {
+				f.options[k] = string(b)
+			}
+		}
+
+	case "core.add_torrent_file":
+		return "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"
+	}
+
+	return nil
+}
+
+// newFakeDelugeV2 starts a fake Deluge v2 daemon speaking the rencoded rpc protocol over tls
+func newFakeDelugeV2(t *testing.T, fake *fakeDeluge) int {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert}, PrivateKey: key}}})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeDeluge(conn, fake)
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func serveFakeDeluge(conn net.Conn, fake *fakeDeluge) {
+	defer conn.Close()
+
+	for {
+		var header [5]byte
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return
+		}
+
+		body := make([]byte, binary.BigEndian.Uint32(header[1:]))
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+
+		var payload, req, args rencode.List
+		var serial int64
+		var method string
+		if err := rencode.NewDecoder(zr).Scan(&payload); err != nil {
+			return
+		}
+		if err := payload.Scan(&req); err != nil {
+			return
+		}
+		if err := req.Scan(&serial, &method, &args); err != nil {
+			return
+		}
+
+		var resp bytes.Buffer
+		zw := zlib.NewWriter(&resp)
+		enc := rencode.NewEncoder(zw)
+		if err := enc.Encode(rencode.NewList(int64(1), serial, fake.handle(method, args))); err != nil {
+			return
+		}
+		_ = zw.Close()
+
+		header[0] = 1
+		binary.BigEndian.PutUint32(header[1:], uint32(resp.Len()))
+		if _, err := conn.Write(append(header[:], resp.Bytes()...)); err != nil {
+			return
+		}
+	}
+}
+
+func Test_service_delugeV2_label(t *testing.T) {
+	tests := []struct {
+		name          string
+		labelPlugin   bool
+		labels        []string
+		label         string
+		wantErr       string
+		wantCalls     []string
+		wantLabelArgs []string
+	}{
+		{
+			name:          "creates_missing_label",
+			labelPlugin:   true,
+			labels:        []string{"movies"},
+			label:         "TV",
+			wantCalls:     []string{"daemon.login", "core.get_enabled_plugins", "label.get_labels", "label.add", "core.add_torrent_file", "label.set_torrent"},
+			wantLabelArgs: []string{"tv", "tv"},
+		},
+		{
+			name:          "existing_label",
+			labelPlugin:   true,
+			labels:        []string{"movies", "tv"},
+			label:         "tv",
+			wantCalls:     []string{"daemon.login", "core.get_enabled_plugins", "label.get_labels", "core.add_torrent_file", "label.set_torrent"},
+			wantLabelArgs: []string{"tv"},
+		},
+		{
+			name:      "plugin_disabled",
+			label:     "tv",
+			wantErr:   "label plugin is not enabled in Deluge",
+			wantCalls: []string{"daemon.login", "core.get_enabled_plugins"},
+		},
+		{
+			name:      "no_label",
+			wantCalls: []string{"daemon.login", "core.add_torrent_file"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDeluge{labelPlugin: tt.labelPlugin, labels: tt.labels}
+			port := newFakeDelugeV2(t, fake)
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					clients: map[int32]*domain.DownloadClient{
+						1: {ID: 1, Name: "deluge", Type: domain.DownloadClientTypeDelugeV2, Host: "127.0.0.1", Port: port},
+					},
+				},
+			}
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				TorrentTmpFile: archiveFixture,
+				Indexer:        "mock",
+			}
+
+			_, err := s.RunAction(context.Background(), &domain.Action{Name: "deluge", Type: domain.ActionTypeDelugeV2, ClientID: 1, Label: tt.label}, release)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			fake.m.Lock()
+			defer fake.m.Unlock()
+
+			assert.Equal(t, tt.wantCalls, fake.calls)
+			assert.Equal(t, tt.wantLabelArgs, fake.labelArgs)
+		})
+	}
+}
+
+func Test_service_delugeV2_moveCompleted(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      domain.Action
+		wantErr     string
+		wantCalls   []string
+		wantOptions map[string]interface{}
+	}{
+		{
+			name:        "expanded_path",
+			action:      domain.Action{Name: "deluge", Type: domain.ActionTypeDelugeV2, ClientID: 1, MoveCompleted: true, MoveCompletedPath: "/mnt/bulk/{{ .Indexer }}/{{ .Title }}"},
+			wantCalls:   []string{"daemon.login", "core.add_torrent_file", "core.set_torrent_options"},
+			wantOptions: map[string]interface{}{"move_completed": true, "move_completed_path": "/mnt/bulk/mock/That Show"},
+		},
+		{
+			name:      "disabled",
+			action:    domain.Action{Name: "deluge", Type: domain.ActionTypeDelugeV2, ClientID: 1, MoveCompletedPath: "/mnt/bulk"},
+			wantCalls: []string{"daemon.login", "core.add_torrent_file"},
+		},
+		{
+			name:    "empty_after_macros",
+			action:  domain.Action{Name: "deluge", Type: domain.ActionTypeDelugeV2, ClientID: 1, MoveCompleted: true, MoveCompletedPath: "{{ .Category }}"},
+			wantErr: "move completed path is empty after parsing macros",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDeluge{}
+			port := newFakeDelugeV2(t, fake)
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					clients: map[int32]*domain.DownloadClient{
+						1: {ID: 1, Name: "deluge", Type: domain.DownloadClientTypeDelugeV2, Host: "127.0.0.1", Port: port},
+					},
+				},
+			}
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				Title:          "That Show",
+				TorrentTmpFile: archiveFixture,
+				Indexer:        "mock",
+			}
+
+			_, err := s.RunAction(context.Background(), &tt.action, release)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			fake.m.Lock()
+			defer fake.m.Unlock()
+
+			assert.Equal(t, tt.wantCalls, fake.calls)
+			assert.Equal(t, tt.wantOptions, fake.options)
+		})
+	}
+}
@@ -5,7 +5,14 @@ package action
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -17,38 +24,148 @@ import (
 func (s *service) execCmd(ctx context.Context, action *domain.Action, release domain.Release) error {
 	s.log.Debug().Msgf("action exec: %s release: %s", action.Name, release.TorrentName)
 
-	// check if program exists
-	cmd, err := exec.LookPath(action.ExecCmd)
+	command, err := execCommand(ctx, action)
 	if err != nil {
-		return errors.Wrap(err, "exec failed, could not find program: %s", action.ExecCmd)
+		return err
 	}
 
-	p := shellwords.NewParser()
-	p.ParseBacktick = true
-	args, err := p.Parse(action.ExecArgs)
-	if err != nil {
-		return errors.Wrap(err, "could not parse exec args: %s", action.ExecArgs)
+	if err := s.policy.checkExec(action, command.Path); err != nil {
+		return err
 	}
 
-	// we need to split on space into a string slice, so we can spread the args into exec
+	// wait for a free slot if too many commands are already running
+	done, err := s.execLimiter.acquire(ctx, action)
+	if err != nil {
+		return errors.Wrap(err, "exec canceled while waiting for a free slot: %s", action.Name)
+	}
+	defer done()
 
 	start := time.Now()
 
-	// setup command and args
-	command := exec.CommandContext(ctx, cmd, args...)
+	// optionally pass release data as environment variables to avoid quoting issues with args
+	if action.ExecEnv {
+		command.Env = append(os.Environ(), execEnv(release)...)
+	}
 
 	// execute command
 	output, err := command.CombinedOutput()
 	if err != nil {
 		// everything other than exit 0 is considered an error
-		return errors.Wrap(err, "error executing command: %s args: %s", cmd, args)
+		return errors.Wrap(err, "error executing command: %s args: %s", command.Path, redactDownloadURL(fmt.Sprint(command.Args[1:]), release))
 	}
 
-	s.log.Trace().Msgf("executed command: '%s'", string(output))
+	s.log.Trace().Msgf("executed command: '%s'", redactDownloadURL(string(output), release))
 
 	duration := time.Since(start)
 
-	s.log.Info().Msgf("executed command: '%s', args: '%s' %s,%s, total time %v", cmd, args, release.TorrentName, release.Indexer, duration)
+	s.log.Info().Msgf("executed command: '%s', args: '%s' %s,%s, total time %v", command.Path, redactDownloadURL(fmt.Sprint(command.Args[1:]), release), release.TorrentName, release.Indexer, duration)
 
 	return nil
 }
+
+// execCommand sets up the command for the action. By default the program is run directly with the split args,
+// with ExecShell the command is run as a script by the system shell so pipes and redirects work.
+// The args are passed to the script as positional parameters, so macro values are never run as shell code.
+func execCommand(ctx context.Context, action *domain.Action) (*exec.Cmd, error) {
+	if action.ExecWorkDir != "" {
+		if info, err := os.Stat(action.ExecWorkDir); err != nil || !info.IsDir() {
+			return nil, errors.New("exec failed, working directory does not exist: %s", action.ExecWorkDir)
+		}
+	}
+
+	var command *exec.Cmd
+
+	if action.ExecShell {
+		// args are split before macros are parsed, otherwise they are still the raw config
+		args := action.ExecArgv
+		if args == nil && action.ExecArgs != "" {
+			var err error
+			args, err = shellwords.Parse(action.ExecArgs)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not parse exec args: %s", action.ExecArgs)
+			}
+		}
+
+		if runtime.GOOS == "windows" {
+			line, err := windowsShellLine(action.ExecCmd, args)
+			if err != nil {
+				return nil, err
+			}
+
+			command = exec.CommandContext(ctx, "cmd", "/C", line)
+		} else {
+			command = exec.CommandContext(ctx, "sh", append([]string{"-c", posixShellScript(action.ExecCmd), "sh"}, args...)...)
+		}
+	} else {
+		// relative programs are looked up from the working directory
+		program := action.ExecCmd
+		if action.ExecWorkDir != "" && strings.ContainsAny(program, `/\`) && !filepath.IsAbs(program) {
+			program = filepath.Join(action.ExecWorkDir, program)
+		}
+
+		// check if program exists
+		cmd, err := exec.LookPath(program)
+		if err != nil {
+			return nil, errors.Wrap(err, "exec failed, could not find program: %s", action.ExecCmd)
+		}
+
+		// use the args split before macros were parsed if available
+		args := action.ExecArgv
+		if args == nil {
+			// we need to split on space into a string slice, so we can spread the args into exec
+			p := shellwords.NewParser()
+			p.ParseBacktick = true
+			args, err = p.Parse(action.ExecArgs)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not parse exec args: %s", action.ExecArgs)
+			}
+		}
+
+		command = exec.CommandContext(ctx, cmd, args...)
+	}
+
+	command.Dir = action.ExecWorkDir
+
+	return command, nil
+}
+
+// shellPositionalRegex matches references to positional parameters like $1, ${2}, $@ and $*
+var shellPositionalRegex = regexp.MustCompile(`\$(\{[0-9]+\}|[0-9@*])`)
+
+// posixShellScript returns the script run by sh. Scripts that don't reference the positional parameters
+// get all of them appended, so "echo" with args behaves like running echo directly.
+func posixShellScript(script string) string {
+	if shellPositionalRegex.MatchString(script) {
+		return script
+	}
+
+	return script + ` "$@"`
+}
+
+// windowsShellLine returns the line run by cmd with every arg quoted. cmd has no positional parameters and
+// expands variables even inside quotes, so args that can break out of the quotes are refused.
+func windowsShellLine(script string, args []string) (string, error) {
+	line := script
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "\"%\r\n") {
+			return "", errors.New("exec failed, arg can't be quoted safely for cmd: %s", arg)
+		}
+
+		line += ` "` + arg + `"`
+	}
+
+	return line, nil
+}
+
+// execEnv returns release fields formatted as AUTOBRR_ environment variables
+func execEnv(release domain.Release) []string {
+	return []string{
+		"AUTOBRR_TORRENT_NAME=" + release.TorrentName,
+		"AUTOBRR_INDEXER=" + release.Indexer,
+		"AUTOBRR_SIZE=" + strconv.FormatUint(release.Size, 10),
+		"AUTOBRR_CATEGORY=" + release.Category,
+		"AUTOBRR_INFOHASH=" + release.TorrentHash,
+		"AUTOBRR_DOWNLOAD_URL=" + release.DownloadURL,
+		"AUTOBRR_TORRENT_PATH=" + release.TorrentTmpFile,
+	}
+}
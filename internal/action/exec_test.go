@@ -5,6 +5,12 @@ package action
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -103,3 +109,296 @@ func Test_service_execCmd(t *testing.T) {
 		})
 	}
 }
+
+func Test_service_execCmd_quotedArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		release  domain.Release
+		action   *domain.Action
+		wantArgv []string
+	}{
+		{
+			name:    "name_with_spaces",
+			release: domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"},
+			action: &domain.Action{
+				Name:     "echo",
+				ExecCmd:  "echo",
+				ExecArgs: `{{ .TorrentName }} {{ .Indexer }}`,
+			},
+			wantArgv: []string{"Sally Goes to the Mall S04E29", "mock"},
+		},
+		{
+			name:    "name_with_embedded_quotes",
+			release: domain.Release{TorrentName: `Sally's "Mall" S04E29`, Indexer: "mock"},
+			action: &domain.Action{
+				Name:     "echo",
+				ExecCmd:  "echo",
+				ExecArgs: `"{{ .TorrentName }}" {{ .Indexer }}`,
+			},
+			wantArgv: []string{`Sally's "Mall" S04E29`, "mock"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{
+				log: logger.Mock().With().Logger(),
+			}
+
+			err := tt.action.ParseMacros(&tt.release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantArgv, tt.action.ExecArgv)
+
+			err = s.execCmd(context.TODO(), tt.action, tt.release)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_service_execCmd_env(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test script requires a posix shell")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "env.sh")
+	out := filepath.Join(dir, "out.txt")
+
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$AUTOBRR_TORRENT_NAME|$AUTOBRR_INDEXER|$AUTOBRR_SIZE\" > \"$1\"\n"), 0755)
+	assert.NoError(t, err)
+
+	release := domain.Release{
+		TorrentName: `Sally's "Mall" S04E29 $HOME`,
+		Indexer:     "mock",
+		Size:        1073741824,
+	}
+	action := &domain.Action{
+		Name:     "env",
+		ExecCmd:  script,
+		ExecArgs: out,
+		ExecEnv:  true,
+	}
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+	}
+
+	err = action.ParseMacros(&release)
+	assert.NoError(t, err)
+
+	err = s.execCmd(context.TODO(), action, release)
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sally's \"Mall\" S04E29 $HOME|mock|1073741824\n", string(data))
+}
+
+func Test_service_execCmd_workDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test script requires a posix shell")
+	}
+
+	dir := t.TempDir()
+
+	// relative script and output path both resolve from the working directory
+	err := os.WriteFile(filepath.Join(dir, "pwd.sh"), []byte("#!/bin/sh\npwd > \"$1\"\n"), 0755)
+	assert.NoError(t, err)
+
+	action := &domain.Action{
+		Name:        "pwd",
+		ExecCmd:     "./pwd.sh",
+		ExecArgs:    "out.txt",
+		ExecWorkDir: dir,
+	}
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+	}
+
+	err = s.execCmd(context.TODO(), action, domain.Release{})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	assert.NoError(t, err)
+
+	want, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, want+"\n", string(data))
+
+	// missing working directory
+	action.ExecWorkDir = filepath.Join(dir, "missing")
+	assert.Error(t, s.execCmd(context.TODO(), action, domain.Release{}))
+}
+
+func Test_service_execCmd_shell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command requires a posix shell")
+	}
+
+	tests := []struct {
+		name      string
+		useShell  bool
+		execCmd   string
+		wantFile  bool
+		wantPiped string
+	}{
+		{name: "direct", useShell: false, execCmd: "echo", wantFile: false},
+		{name: "shell", useShell: true, execCmd: `echo "$1" | tr a-z A-Z > piped.txt`, wantFile: true, wantPiped: "SALLY GOES TO THE MALL\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			release := domain.Release{TorrentName: "Sally Goes to the Mall"}
+			action := &domain.Action{
+				Name:        "pipe",
+				ExecCmd:     tt.execCmd,
+				ExecArgs:    "{{ .TorrentName }} | tr a-z A-Z > piped.txt",
+				ExecWorkDir: dir,
+				ExecShell:   tt.useShell,
+			}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+			}
+
+			err := action.ParseMacros(&release)
+			assert.NoError(t, err)
+
+			err = s.execCmd(context.TODO(), action, release)
+			assert.NoError(t, err)
+
+			// without a shell the pipe and redirect are passed to echo as plain args
+			data, err := os.ReadFile(filepath.Join(dir, "piped.txt"))
+			if !tt.wantFile {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPiped, string(data))
+		})
+	}
+}
+
+func Test_service_execCmd_shellInjection(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test command requires a posix shell")
+	}
+
+	tests := []struct {
+		name        string
+		execCmd     string
+		torrentName string
+	}{
+		{name: "semicolon", execCmd: "echo", torrentName: "x; touch pwned.txt"},
+		{name: "command_substitution", execCmd: "echo", torrentName: "x $(touch pwned.txt)"},
+		{name: "backticks", execCmd: "echo", torrentName: "x `touch pwned.txt`"},
+		{name: "positional_script", execCmd: `echo "$1" > out.txt`, torrentName: "x\"; touch pwned.txt; echo \""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			release := domain.Release{TorrentName: tt.torrentName}
+			action := &domain.Action{
+				Name:        "shell",
+				ExecCmd:     tt.execCmd,
+				ExecArgs:    "{{ .TorrentName }}",
+				ExecWorkDir: dir,
+				ExecShell:   true,
+			}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+			}
+
+			err := action.ParseMacros(&release)
+			assert.NoError(t, err)
+
+			err = s.execCmd(context.TODO(), action, release)
+			assert.NoError(t, err)
+
+			_, err = os.Stat(filepath.Join(dir, "pwned.txt"))
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func Test_windowsShellLine(t *testing.T) {
+	line, err := windowsShellLine("script.bat", []string{"Sally Goes to the Mall & more"})
+	assert.NoError(t, err)
+	assert.Equal(t, `script.bat "Sally Goes to the Mall & more"`, line)
+
+	_, err = windowsShellLine("script.bat", []string{`x" & del /q *`})
+	assert.Error(t, err)
+
+	_, err = windowsShellLine("script.bat", []string{"%PATH%"})
+	assert.Error(t, err)
+}
+
+func Test_service_execCmd_concurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test script requires a posix shell")
+	}
+
+	// the script marks itself as running and records how many commands were running at the same time
+	script := filepath.Join(t.TempDir(), "count.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\ntouch \"$1/running/$$\"\nls \"$1/running\" | wc -l >> \"$1/counts\"\nsleep 0.1\nrm \"$1/running/$$\"\n"), 0755)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		global          int
+		execConcurrency int
+		actions         int
+		runs            int
+		wantMax         int
+	}{
+		{name: "global", global: 2, actions: 1, runs: 8, wantMax: 2},
+		{name: "per_action", execConcurrency: 1, actions: 2, runs: 4, wantMax: 1},
+		{name: "per_action_below_global", global: 3, execConcurrency: 2, actions: 1, runs: 6, wantMax: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{
+				log:         logger.Mock().With().Logger(),
+				execLimiter: newExecLimiter(tt.global),
+			}
+
+			var dirs []string
+			var wg sync.WaitGroup
+			for i := 1; i <= tt.actions; i++ {
+				// every action counts in a directory of its own
+				dir := t.TempDir()
+				assert.NoError(t, os.Mkdir(filepath.Join(dir, "running"), 0755))
+				dirs = append(dirs, dir)
+
+				action := &domain.Action{ID: i, Name: "count", ExecCmd: script, ExecArgs: dir, ExecConcurrency: tt.execConcurrency}
+
+				for j := 0; j < tt.runs; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						assert.NoError(t, s.execCmd(context.Background(), action, domain.Release{}))
+					}()
+				}
+			}
+			wg.Wait()
+
+			for _, dir := range dirs {
+				data, err := os.ReadFile(filepath.Join(dir, "counts"))
+				assert.NoError(t, err)
+
+				counts := strings.Fields(string(data))
+				assert.Len(t, counts, tt.runs)
+
+				for _, c := range counts {
+					n, err := strconv.Atoi(c)
+					assert.NoError(t, err)
+					assert.LessOrEqual(t, n, tt.wantMax)
+				}
+			}
+		})
+	}
+}
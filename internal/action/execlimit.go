@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// execSemaphore holds a slot for every running command
+type execSemaphore struct {
+	limit int
+	slots chan struct{}
+}
+
+// execLimiter limits the number of exec commands running at the same time, globally and per action.
+// Commands over the limit wait for a slot instead of running right away.
+type execLimiter struct {
+	global chan struct{}
+
+	m       sync.Mutex
+	actions map[int]*execSemaphore
+}
+
+// newExecLimiter returns a limiter allowing global concurrent commands, 0 means unlimited
+func newExecLimiter(global int) *execLimiter {
+	l := &execLimiter{actions: map[int]*execSemaphore{}}
+	if global > 0 {
+		l.global = make(chan struct{}, global)
+	}
+
+	return l
+}
+
+// actionSlots returns the slots of the action, or nil if the action has no limit of its own
+func (l *execLimiter) actionSlots(action *domain.Action) chan struct{} {
+	if action.ExecConcurrency <= 0 {
+		return nil
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	// a changed limit gets fresh slots, running commands give back their slot to the old ones
+	sem, ok := l.actions[action.ID]
+	if !ok || sem.limit != action.ExecConcurrency {
+		sem = &execSemaphore{limit: action.ExecConcurrency, slots: make(chan struct{}, action.ExecConcurrency)}
+		l.actions[action.ID] = sem
+	}
+
+	return sem.slots
+}
+
+// acquire waits for a free action and global slot. The returned func must be called when the command is done.
+func (l *execLimiter) acquire(ctx context.Context, action *domain.Action) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// take the action slot first so queued commands of a busy action don't hold global slots
+	actionSlots := l.actionSlots(action)
+	if err := acquireSlot(ctx, actionSlots); err != nil {
+		return nil, err
+	}
+
+	if err := acquireSlot(ctx, l.global); err != nil {
+		releaseSlot(actionSlots)
+		return nil, err
+	}
+
+	return func() {
+		releaseSlot(l.global)
+		releaseSlot(actionSlots)
+	}, nil
+}
+
+func acquireSlot(ctx context.Context, slots chan struct{}) error {
+	if slots == nil {
+		return nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func releaseSlot(slots chan struct{}) {
+	if slots == nil {
+		return
+	}
+
+	<-slots
+}
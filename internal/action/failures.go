@@ -0,0 +1,105 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// failureTracker counts the consecutive failed runs of every action
+type failureTracker struct {
+	m      sync.Mutex
+	counts map[int]int
+}
+
+func newFailureTracker() *failureTracker {
+	return &failureTracker{counts: map[int]int{}}
+}
+
+// Record counts the run and returns true when the failures reached the limit, the count starts over after that.
+// A successful run resets the count.
+func (t *failureTracker) Record(actionID int, failed bool, limit int) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if !failed {
+		delete(t.counts, actionID)
+		return false
+	}
+
+	t.counts[actionID]++
+	if t.counts[actionID] < limit {
+		return false
+	}
+
+	delete(t.counts, actionID)
+
+	return true
+}
+
+// trackFailure disables the action after too many consecutive failures so a broken endpoint doesn't keep failing every release
+func (s *service) trackFailure(action *domain.Action, release *domain.Release, runErr error) {
+	// a deferred action is neither a failure nor a success
+	if s.failures == nil || action.ID == 0 || action.DisableAfterFailures <= 0 || errors.Is(runErr, domain.ErrMaxActiveDownloads) {
+		return
+	}
+
+	if !s.failures.Record(action.ID, runErr != nil, action.DisableAfterFailures) {
+		return
+	}
+
+	ctx := context.Background()
+
+	// ToggleEnabled flips the state, make sure an already disabled action isn't turned back on
+	current, err := s.repo.Get(ctx, &domain.GetActionRequest{Id: action.ID})
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find action %s to disable", action.Name)
+		return
+	}
+
+	if !current.Enabled {
+		return
+	}
+
+	if err := s.repo.ToggleEnabled(action.ID); err != nil {
+		s.log.Error().Err(err).Msgf("could not disable action %s", action.Name)
+		return
+	}
+
+	action.Enabled = false
+
+	s.log.Warn().Msgf("action %s disabled after %d consecutive failures: %v", action.Name, action.DisableAfterFailures, runErr)
+
+	if s.bus == nil {
+		return
+	}
+
+	payload := &domain.NotificationPayload{
+		Subject:        "Action disabled",
+		Message:        fmt.Sprintf("%s was disabled after %d consecutive failures, last error: %v", action.Name, action.DisableAfterFailures, runErr),
+		Event:          domain.NotificationEventActionDisabled,
+		ReleaseName:    release.TorrentName,
+		Filter:         release.FilterName,
+		Indexer:        release.Indexer,
+		Status:         domain.ReleasePushStatusErr,
+		Action:         action.Name,
+		ActionType:     action.Type,
+		Rejections:     []string{runErr.Error()},
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+	}
+
+	if action.Client != nil {
+		payload.ActionClient = action.Client.Name
+	}
+
+	s.bus.Publish("events:notification", &payload.Event, payload)
+}
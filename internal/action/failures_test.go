@@ -0,0 +1,146 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockActionRepo struct {
+	domain.ActionRepo
+
+	m       sync.Mutex
+	actions map[int]*domain.Action
+	toggles int
+}
+
+func (m *mockActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*domain.Action, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	a, ok := m.actions[req.Id]
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	current := *a
+	return &current, nil
+}
+
+func (m *mockActionRepo) ToggleEnabled(actionID int) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.toggles++
+	m.actions[actionID].Enabled = !m.actions[actionID].Enabled
+
+	return nil
+}
+
+func Test_service_RunAction_disableAfterFailures(t *testing.T) {
+	var (
+		m      sync.Mutex
+		status = "error"
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"` + status + `"}`))
+	}))
+	defer ts.Close()
+
+	setStatus := func(s string) {
+		m.Lock()
+		status = s
+		m.Unlock()
+	}
+
+	newAction := func() *domain.Action {
+		return &domain.Action{ID: 7, Name: "notify", Type: domain.ActionTypeWebhook, Enabled: true, WebhookHost: ts.URL, WebhookData: `{}`, WebhookSuccessWhen: "status=accepted", DisableAfterFailures: 3}
+	}
+
+	setup := func() (*service, *mockActionRepo, *[]domain.NotificationPayload) {
+		repo := &mockActionRepo{actions: map[int]*domain.Action{7: newAction()}}
+
+		var events []domain.NotificationPayload
+		bus := EventBus.New()
+		_ = bus.Subscribe("events:notification", func(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
+			if *event == domain.NotificationEventActionDisabled {
+				events = append(events, *payload)
+			}
+		})
+
+		return &service{
+			log:      logger.Mock().With().Logger(),
+			bus:      bus,
+			repo:     repo,
+			failures: newFailureTracker(),
+		}, repo, &events
+	}
+
+	run := func(s *service) error {
+		_, err := s.RunAction(context.Background(), newAction(), &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"})
+		return err
+	}
+
+	t.Run("disabled_after_failures", func(t *testing.T) {
+		setStatus("error")
+		s, repo, events := setup()
+
+		for i := 0; i < 2; i++ {
+			assert.Error(t, run(s))
+		}
+		assert.True(t, repo.actions[7].Enabled)
+		assert.Empty(t, *events)
+
+		assert.Error(t, run(s))
+		assert.False(t, repo.actions[7].Enabled)
+		assert.Equal(t, 1, repo.toggles)
+
+		if assert.Len(t, *events, 1) {
+			assert.Equal(t, "notify", (*events)[0].Action)
+			assert.Equal(t, "Action disabled", (*events)[0].Subject)
+			assert.Contains(t, (*events)[0].Message, "disabled after 3 consecutive failures")
+		}
+
+		// an action that is already disabled is not toggled back on
+		for i := 0; i < 3; i++ {
+			assert.Error(t, run(s))
+		}
+		assert.False(t, repo.actions[7].Enabled)
+		assert.Equal(t, 1, repo.toggles)
+	})
+
+	t.Run("success_resets_count", func(t *testing.T) {
+		setStatus("error")
+		s, repo, events := setup()
+
+		assert.Error(t, run(s))
+		assert.Error(t, run(s))
+
+		setStatus("accepted")
+		assert.NoError(t, run(s))
+
+		setStatus("error")
+		assert.Error(t, run(s))
+		assert.Error(t, run(s))
+
+		assert.True(t, repo.actions[7].Enabled)
+		assert.Equal(t, 0, repo.toggles)
+		assert.Empty(t, *events)
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+// freeSpaceFunc returns the free space in bytes at the path, an empty path is the default save path of the client
+type freeSpaceFunc func(ctx context.Context, path string) (int64, error)
+
+// checkFreeSpace rejects the release when the client reports less free space than the action requires.
+// Clients without a free space api pass a nil freeSpace and are added without the check.
+func (s *service) checkFreeSpace(ctx context.Context, action *domain.Action, freeSpace freeSpaceFunc) ([]string, error) {
+	if action.MinFreeSpace == "" {
+		return nil, nil
+	}
+
+	minFree, err := humanize.ParseBytes(action.MinFreeSpace)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse min free space: %s", action.MinFreeSpace)
+	}
+
+	if freeSpace == nil {
+		s.log.Warn().Msgf("action %s: free space check is not supported for %s, skipping", action.Name, action.Type)
+		return nil, nil
+	}
+
+	free, err := freeSpace(ctx, action.SavePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get free space")
+	}
+
+	if free < 0 || uint64(free) < minFree {
+		s.log.Debug().Msgf("action %s: insufficient free space %s, required %s", action.Name, humanize.Bytes(uint64(free)), humanize.Bytes(minFree))
+
+		return []string{fmt.Sprintf("insufficient free space: %s free, %s required", humanize.Bytes(uint64(free)), humanize.Bytes(minFree))}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_checkFreeSpace(t *testing.T) {
+	// fake client reporting free space per path
+	fakeClient := func(space map[string]int64) freeSpaceFunc {
+		return func(ctx context.Context, path string) (int64, error) {
+			free, ok := space[path]
+			if !ok {
+				return 0, errors.New("no such directory: %s", path)
+			}
+			return free, nil
+		}
+	}
+
+	tests := []struct {
+		name           string
+		action         domain.Action
+		freeSpace      freeSpaceFunc
+		wantRejections []string
+		wantErr        bool
+	}{
+		{
+			name:      "not_set",
+			action:    domain.Action{},
+			freeSpace: fakeClient(nil),
+		},
+		{
+			name:      "high_free_space",
+			action:    domain.Action{MinFreeSpace: "100GiB", SavePath: "/data"},
+			freeSpace: fakeClient(map[string]int64{"/data": 2 << 40}),
+		},
+		{
+			name:           "low_free_space",
+			action:         domain.Action{MinFreeSpace: "100GiB", SavePath: "/data"},
+			freeSpace:      fakeClient(map[string]int64{"/data": 20 << 30}),
+			wantRejections: []string{"insufficient free space: 22 GB free, 107 GB required"},
+		},
+		{
+			name:           "default_save_path",
+			action:         domain.Action{MinFreeSpace: "1 MB"},
+			freeSpace:      fakeClient(map[string]int64{"": 0}),
+			wantRejections: []string{"insufficient free space: 0 B free, 1.0 MB required"},
+		},
+		{
+			// the save path is unknown to the client
+			name:      "client_error",
+			action:    domain.Action{MinFreeSpace: "1 MB", SavePath: "/missing"},
+			freeSpace: fakeClient(map[string]int64{"/data": 1 << 40}),
+			wantErr:   true,
+		},
+		{
+			name:   "unsupported_client",
+			action: domain.Action{MinFreeSpace: "1 MB", Type: domain.ActionTypeRTorrent},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: logger.Mock().With().Logger()}
+
+			rejections, err := s.checkFreeSpace(context.Background(), &tt.action, tt.freeSpace)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRejections, rejections)
+		})
+	}
+}
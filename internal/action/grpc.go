@@ -0,0 +1,87 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/grpcjson"
+)
+
+// grpcReleasePayload is sent when the action has no custom data
+type grpcReleasePayload struct {
+	TorrentName string `json:"torrent_name"`
+	Indexer     string `json:"indexer"`
+	Filter      string `json:"filter"`
+	Size        uint64 `json:"size"`
+	Category    string `json:"category"`
+	DownloadURL string `json:"download_url"`
+	MagnetURI   string `json:"magnet_uri,omitempty"`
+	InfoURL     string `json:"info_url,omitempty"`
+	Protocol    string `json:"protocol"`
+}
+
+func (s *service) grpc(ctx context.Context, action *domain.Action, release domain.Release) (string, error) {
+	s.log.Trace().Msgf("action GRPC: '%s' file: %s", action.Name, release.TorrentName)
+
+	if err := s.policy.checkGRPCTarget(action.WebhookHost); err != nil {
+		return "", err
+	}
+
+	cfg := grpcjson.Config{
+		Target:        action.WebhookHost,
+		TLSSkipVerify: action.GrpcTLSSkipVerify,
+		Timeout:       action.Timeout,
+	}
+
+	if s.policy != nil {
+		cfg.Dialer = func(ctx context.Context, addr string) (net.Conn, error) {
+			return s.policy.dialContext(ctx, "tcp", addr)
+		}
+	}
+
+	client, err := grpcjson.NewClient(cfg)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create grpc client")
+	}
+
+	defer client.Close()
+
+	data := []byte(action.WebhookData)
+	if action.WebhookData == "" {
+		data, err = json.Marshal(grpcReleasePayload{
+			TorrentName: release.TorrentName,
+			Indexer:     release.Indexer,
+			Filter:      release.FilterName,
+			Size:        release.Size,
+			Category:    release.Category,
+			DownloadURL: release.DownloadURL,
+			MagnetURI:   release.MagnetURI,
+			InfoURL:     release.InfoURL,
+			Protocol:    string(release.Protocol),
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "could not marshal grpc payload")
+		}
+	} else if !json.Valid(data) {
+		return "", errors.New("grpc action data must be valid json")
+	}
+
+	start := time.Now()
+
+	res, err := client.Invoke(ctx, action.GrpcMethod, data)
+	if err != nil {
+		return "", errors.Wrap(err, "could not call grpc method %s on %s", action.GrpcMethod, action.WebhookHost)
+	}
+
+	s.log.Info().Msgf("successfully ran grpc action: '%s' to: %s method: %s finished in %s", action.Name, action.WebhookHost, action.GrpcMethod, time.Since(start))
+	s.log.Trace().Msgf("grpc action '%s' response: %s", action.Name, string(res))
+
+	return string(res), nil
+}
@@ -30,10 +30,16 @@ func (s *service) lidarr(ctx context.Context, action *domain.Action, release dom
 	}
 
 	// initial config
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := lidarr.Config{
-		Hostname: client.Host,
-		APIKey:   client.Settings.APIKey,
-		Log:      s.subLogger,
+		Hostname:  client.Host,
+		APIKey:    client.Settings.APIKey,
+		Transport: transport,
+		Log:       s.subLogger,
 	}
 
 	// only set basic auth if enabled
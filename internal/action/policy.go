@@ -0,0 +1,287 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/grpcjson"
+)
+
+var ErrActionPolicy = errors.New("blocked by action policy")
+
+// actionPolicy limits where webhook and grpc actions can send to and which programs exec actions can run.
+// It is checked when the action is stored and again when it runs, after macros are parsed.
+type actionPolicy struct {
+	allowedHosts []string
+	deniedHosts  []string
+	blockPrivate bool
+	execDirs     []string
+}
+
+// WebhookPolicy applies the webhook hosts policy of actions to other outgoing webhooks, like the approval webhook of filters
+type WebhookPolicy struct {
+	policy *actionPolicy
+}
+
+// NewWebhookPolicy returns the webhook hosts policy from the config
+func NewWebhookPolicy(config *domain.Config) *WebhookPolicy {
+	return &WebhookPolicy{policy: newActionPolicy(config)}
+}
+
+// CheckURL checks the host of the url before the request is made
+func (p *WebhookPolicy) CheckURL(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+
+	return p.policy.checkURL(rawURL)
+}
+
+// Apply sets the dialer of the transport to check the addresses it connects to
+func (p *WebhookPolicy) Apply(t *http.Transport) {
+	if p == nil || p.policy == nil {
+		return
+	}
+
+	t.DialContext = p.policy.dialContext
+}
+
+// newActionPolicy returns the policy from the config, or nil if nothing is restricted
+func newActionPolicy(config *domain.Config) *actionPolicy {
+	if len(config.WebhookAllowedHosts) == 0 && len(config.WebhookDeniedHosts) == 0 && !config.WebhookBlockPrivate && len(config.ExecAllowedDirs) == 0 {
+		return nil
+	}
+
+	p := &actionPolicy{
+		allowedHosts: config.WebhookAllowedHosts,
+		deniedHosts:  config.WebhookDeniedHosts,
+		blockPrivate: config.WebhookBlockPrivate,
+	}
+
+	for _, dir := range config.ExecAllowedDirs {
+		if abs, err := filepath.Abs(dir); err == nil {
+			p.execDirs = append(p.execDirs, resolvePath(abs))
+		}
+	}
+
+	return p
+}
+
+// checkAction checks the webhook host, grpc target and exec program of the action. Values with macros are checked when the action runs.
+func (p *actionPolicy) checkAction(action *domain.Action) error {
+	if p == nil {
+		return nil
+	}
+
+	switch action.Type {
+	case domain.ActionTypeWebhook:
+		if strings.Contains(action.WebhookHost, "{{") {
+			return nil
+		}
+
+		return p.checkURL(action.WebhookHost)
+
+	case domain.ActionTypeGRPC:
+		// grpc targets don't support macros
+		return p.checkGRPCTarget(action.WebhookHost)
+
+	case domain.ActionTypeExec:
+		if strings.Contains(action.ExecCmd, "{{") {
+			return nil
+		}
+
+		// programs that can't be found fail when the action runs
+		command, err := execCommand(context.Background(), action)
+		if err != nil {
+			return nil
+		}
+
+		return p.checkExec(action, command.Path)
+	}
+
+	return nil
+}
+
+// checkURL checks the host of the url without resolving it, the resolved address is checked when dialing
+func (p *actionPolicy) checkURL(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(err, "could not parse webhook url")
+	}
+
+	host := u.Hostname()
+
+	allowed, err := p.checkHostname(host)
+	if err != nil || allowed {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return p.checkIP(ip)
+	}
+
+	return nil
+}
+
+// checkGRPCTarget checks the host of the grpc target like a webhook url
+func (p *actionPolicy) checkGRPCTarget(target string) error {
+	if p == nil {
+		return nil
+	}
+
+	u, err := grpcjson.ParseTarget(target)
+	if err != nil {
+		return err
+	}
+
+	return p.checkURL(u.String())
+}
+
+// checkHostname returns true if the host is explicitly allowed, or an error if it is denied
+func (p *actionPolicy) checkHostname(host string) (bool, error) {
+	if matchHosts(host, p.allowedHosts) {
+		return true, nil
+	}
+
+	if matchHosts(host, p.deniedHosts) {
+		return false, errors.Wrap(ErrActionPolicy, "webhook host %s is denied", host)
+	}
+
+	return false, nil
+}
+
+// checkIP checks the address the webhook connects to
+func (p *actionPolicy) checkIP(ip net.IP) error {
+	if matchHosts(ip.String(), p.allowedHosts) {
+		return nil
+	}
+
+	if matchHosts(ip.String(), p.deniedHosts) {
+		return errors.Wrap(ErrActionPolicy, "webhook address %s is denied", ip)
+	}
+
+	if p.blockPrivate && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()) {
+		return errors.Wrap(ErrActionPolicy, "webhook address %s is private", ip)
+	}
+
+	return nil
+}
+
+// dialContext checks the host before resolving it and the address after, so a hostname can't resolve to a blocked address
+func (p *actionPolicy) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := p.checkHostname(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			return p.checkIP(net.ParseIP(host))
+		}
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
+// checkExec checks the program of the exec action is inside one of the allowed dirs
+func (p *actionPolicy) checkExec(action *domain.Action, program string) error {
+	if p == nil || len(p.execDirs) == 0 {
+		return nil
+	}
+
+	// the shell can run anything so it can't be limited to a dir
+	if action.ExecShell {
+		return errors.Wrap(ErrActionPolicy, "exec action %s can't use the shell when exec dirs are restricted", action.Name)
+	}
+
+	abs, err := filepath.Abs(program)
+	if err != nil {
+		return errors.Wrap(err, "could not get absolute path of program: %s", program)
+	}
+
+	abs = resolvePath(abs)
+
+	for _, dir := range p.execDirs {
+		if rel, err := filepath.Rel(dir, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrActionPolicy, "exec program %s is outside of the allowed dirs", program)
+}
+
+// resolvePath follows symlinks so a link in an allowed dir can't point outside of it
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	return path
+}
+
+// matchHosts matches the host against hostnames, IPs and CIDR ranges. Entries starting with a dot also match subdomains.
+func matchHosts(host string, entries []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if ip != nil {
+			if _, cidr, err := net.ParseCIDR(entry); err == nil {
+				if cidr.Contains(ip) {
+					return true
+				}
+				continue
+			}
+
+			if entryIP := net.ParseIP(entry); entryIP != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		if strings.HasPrefix(entry, ".") {
+			if host == entry[1:] || strings.HasSuffix(host, entry) {
+				return true
+			}
+			continue
+		}
+
+		if host == entry {
+			return true
+		}
+	}
+
+	return false
+}
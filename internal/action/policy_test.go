@@ -0,0 +1,190 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchHosts(t *testing.T) {
+	entries := []string{"169.254.0.0/16", "10.0.0.1", "metadata.google.internal", ".corp"}
+
+	assert.True(t, matchHosts("169.254.169.254", entries))
+	assert.True(t, matchHosts("10.0.0.1", entries))
+	assert.False(t, matchHosts("10.0.0.2", entries))
+	assert.True(t, matchHosts("Metadata.Google.Internal.", entries))
+	assert.True(t, matchHosts("corp", entries))
+	assert.True(t, matchHosts("jenkins.corp", entries))
+	assert.False(t, matchHosts("notcorp", entries))
+	assert.False(t, matchHosts("example.com", entries))
+}
+
+func Test_actionPolicy_webhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  domain.Config
+		host    string
+		wantErr bool
+	}{
+		{name: "no_policy", config: domain.Config{}, host: "http://169.254.169.254/latest/meta-data", wantErr: false},
+		{name: "block_private_metadata", config: domain.Config{WebhookBlockPrivate: true}, host: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "block_private_loopback", config: domain.Config{WebhookBlockPrivate: true}, host: "http://127.0.0.1:8989/api", wantErr: true},
+		{name: "block_private_public", config: domain.Config{WebhookBlockPrivate: true}, host: "https://1.1.1.1/hook", wantErr: false},
+		{name: "denied_cidr", config: domain.Config{WebhookDeniedHosts: []string{"169.254.0.0/16"}}, host: "http://169.254.169.254/", wantErr: true},
+		{name: "denied_hostname", config: domain.Config{WebhookDeniedHosts: []string{".internal"}}, host: "http://metadata.google.internal/", wantErr: true},
+		{name: "allowed_overrides_private", config: domain.Config{WebhookBlockPrivate: true, WebhookAllowedHosts: []string{"127.0.0.1"}}, host: "http://127.0.0.1:8989/api", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newActionPolicy(&tt.config)
+
+			err := p.checkAction(&domain.Action{Name: "hook", Type: domain.ActionTypeWebhook, WebhookHost: tt.host})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrActionPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_service_webhook_policy(t *testing.T) {
+	var called bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	s := &service{
+		log:    logger.Mock().With().Logger(),
+		policy: newActionPolicy(&domain.Config{WebhookBlockPrivate: true}),
+	}
+
+	// the metadata endpoint is refused before any request is made
+	_, err := s.webhook(context.Background(), &domain.Action{Name: "hook", Type: domain.ActionTypeWebhook, WebhookHost: "http://169.254.169.254/latest/meta-data"}, domain.Release{})
+	assert.ErrorIs(t, err, ErrActionPolicy)
+
+	// a hostname resolving to a private address is refused when dialing
+	_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	assert.NoError(t, err)
+
+	_, err = s.webhook(context.Background(), &domain.Action{Name: "hook", Type: domain.ActionTypeWebhook, WebhookHost: "http://localhost:" + port}, domain.Release{})
+	assert.ErrorIs(t, err, ErrActionPolicy)
+	assert.False(t, called)
+
+	// allowed hosts are let through
+	s.policy = newActionPolicy(&domain.Config{WebhookBlockPrivate: true, WebhookAllowedHosts: []string{"127.0.0.1"}})
+
+	_, err = s.webhook(context.Background(), &domain.Action{Name: "hook", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL}, domain.Release{})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func Test_actionPolicy_grpc(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  domain.Config
+		target  string
+		wantErr bool
+	}{
+		{name: "no_policy", config: domain.Config{}, target: "127.0.0.1:50051", wantErr: false},
+		{name: "block_private_loopback", config: domain.Config{WebhookBlockPrivate: true}, target: "127.0.0.1:50051", wantErr: true},
+		{name: "block_private_metadata_scheme", config: domain.Config{WebhookBlockPrivate: true}, target: "grpc://169.254.169.254:50051", wantErr: true},
+		{name: "block_private_public", config: domain.Config{WebhookBlockPrivate: true}, target: "grpcs://1.1.1.1:443", wantErr: false},
+		{name: "denied_hostname", config: domain.Config{WebhookDeniedHosts: []string{".internal"}}, target: "grpc://metadata.google.internal:50051", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newActionPolicy(&tt.config)
+
+			err := p.checkAction(&domain.Action{Name: "grpc", Type: domain.ActionTypeGRPC, WebhookHost: tt.target})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrActionPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_service_grpc_policy(t *testing.T) {
+	s := &service{
+		log:    logger.Mock().With().Logger(),
+		policy: newActionPolicy(&domain.Config{WebhookBlockPrivate: true}),
+	}
+
+	// the metadata endpoint is refused before the client is created
+	_, err := s.grpc(context.Background(), &domain.Action{Name: "grpc", Type: domain.ActionTypeGRPC, WebhookHost: "169.254.169.254:50051", Timeout: 1}, domain.Release{})
+	assert.ErrorIs(t, err, ErrActionPolicy)
+
+	// a hostname resolving to a private address is refused when dialing
+	_, err = s.grpc(context.Background(), &domain.Action{Name: "grpc", Type: domain.ActionTypeGRPC, WebhookHost: "localhost:50051", GrpcMethod: "autobrr.Releases/Push", Timeout: 1}, domain.Release{})
+	assert.ErrorContains(t, err, ErrActionPolicy.Error())
+}
+
+func Test_service_execCmd_policy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test script requires a posix shell")
+	}
+
+	allowed := t.TempDir()
+	outside := t.TempDir()
+
+	script := []byte("#!/bin/sh\ntouch \"$1\"\n")
+	assert.NoError(t, os.WriteFile(filepath.Join(allowed, "ok.sh"), script, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "evil.sh"), script, 0755))
+
+	// a link in the allowed dir pointing outside of it is not allowed either
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "evil.sh"), filepath.Join(allowed, "link.sh")))
+
+	policy := newActionPolicy(&domain.Config{ExecAllowedDirs: []string{allowed}})
+
+	s := &service{
+		log:    logger.Mock().With().Logger(),
+		policy: policy,
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+
+	tests := []struct {
+		name    string
+		action  domain.Action
+		wantErr bool
+	}{
+		{name: "inside_allowed_dir", action: domain.Action{Name: "ok", Type: domain.ActionTypeExec, ExecCmd: filepath.Join(allowed, "ok.sh"), ExecArgs: out}},
+		{name: "outside_allowed_dir", action: domain.Action{Name: "evil", Type: domain.ActionTypeExec, ExecCmd: filepath.Join(outside, "evil.sh"), ExecArgs: out}, wantErr: true},
+		{name: "symlink_outside", action: domain.Action{Name: "link", Type: domain.ActionTypeExec, ExecCmd: filepath.Join(allowed, "link.sh"), ExecArgs: out}, wantErr: true},
+		{name: "relative_escape", action: domain.Action{Name: "escape", Type: domain.ActionTypeExec, ExecCmd: "../" + filepath.Base(outside) + "/evil.sh", ExecWorkDir: allowed, ExecArgs: out}, wantErr: true},
+		{name: "shell", action: domain.Action{Name: "shell", Type: domain.ActionTypeExec, ExecCmd: filepath.Join(allowed, "ok.sh"), ExecArgs: out, ExecShell: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := tt.action
+
+			validateErr := policy.checkAction(&action)
+			runErr := s.execCmd(context.Background(), &action, domain.Release{})
+
+			if tt.wantErr {
+				assert.ErrorIs(t, validateErr, ErrActionPolicy)
+				assert.ErrorIs(t, runErr, ErrActionPolicy)
+			} else {
+				assert.NoError(t, validateErr)
+				assert.NoError(t, runErr)
+			}
+		})
+	}
+}
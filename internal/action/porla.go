@@ -18,7 +18,7 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func (s *service) porla(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) porla(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action Porla: %s", action.Name)
 
 	client, err := s.clientSvc.FindByID(ctx, action.ClientID)
@@ -30,12 +30,18 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 		return nil, errors.New("could not find client by id: %d", action.ClientID)
 	}
 
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	porlaSettings := porla.Config{
 		Hostname:      client.Host,
 		AuthToken:     client.Settings.APIKey,
 		TLSSkipVerify: client.TLSSkipVerify,
 		BasicUser:     client.Settings.Basic.Username,
 		BasicPass:     client.Settings.Basic.Password,
+		Transport:     transport,
 	}
 
 	porlaSettings.Log = zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "Porla").Str("client", client.Name).Logger(), zerolog.TraceLevel)
@@ -51,6 +57,11 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 		return rejections, nil
 	}
 
+	// porla has no free space api
+	if _, err := s.checkFreeSpace(ctx, action, nil); err != nil {
+		return nil, err
+	}
+
 	var downloadLimit *int64 = nil
 	var uploadLimit *int64 = nil
 
@@ -66,8 +77,10 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 
 	var preset *string = nil
 
-	if action.Label != "" {
-		preset = &action.Label
+	if action.Preset != "" {
+		preset = &action.Preset
+	} else if client.Settings.RequirePreset {
+		return nil, errors.New("client %s requires a preset, action %s has none", client.Name, action.Name)
 	}
 
 	if release.HasMagnetUri() {
@@ -83,6 +96,8 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 			return nil, errors.Wrap(err, "could not add torrent from magnet %s to client: %s", release.MagnetURI, client.Name)
 		}
 
+		release.ClientTorrentID = release.TorrentHash
+
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", release.TorrentHash, client.Name)
 
 		return nil, nil
@@ -117,6 +132,8 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 			return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Name)
 		}
 
+		release.ClientTorrentID = release.TorrentHash
+
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", release.TorrentHash, client.Name)
 	}
 
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPorlaServer returns a fake Porla json-rpc server that records the torrents.add params
+func newPorlaServer(t *testing.T) (*httptest.Server, *[]map[string]any) {
+	var added []map[string]any
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+			ID     int            `json:"id"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		if req.Method == "torrents.add" {
+			added = append(added, req.Params)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": map[string]any{}})
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &added
+}
+
+func Test_service_porla_preset(t *testing.T) {
+	release := domain.Release{
+		TorrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-GROUP",
+		TorrentHash: "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a",
+		MagnetURI:   "magnet:?xt=urn:btih:3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a",
+		Resolution:  "2160p",
+		Indexer:     "mock",
+	}
+
+	tests := []struct {
+		name          string
+		preset        string
+		requirePreset bool
+		wantPreset    any
+		wantErr       bool
+	}{
+		{name: "macro", preset: "uhd-{{ .Resolution }}", wantPreset: "uhd-2160p"},
+		{name: "empty", preset: "", wantPreset: nil},
+		{name: "required", preset: "", requirePreset: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, added := newPorlaServer(t)
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					clients: map[int32]*domain.DownloadClient{
+						1: {ID: 1, Name: "porla", Type: domain.DownloadClientTypePorla, Host: ts.URL, Settings: domain.DownloadClientSettings{APIKey: "secret", RequirePreset: tt.requirePreset}},
+					},
+				},
+			}
+
+			rls := release
+			_, err := s.RunAction(context.Background(), &domain.Action{Name: "porla", Type: domain.ActionTypePorla, ClientID: 1, Preset: tt.preset}, &rls)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, *added)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Len(t, *added, 1)
+			assert.Equal(t, tt.wantPreset, (*added)[0]["preset"])
+			assert.Equal(t, release.MagnetURI, (*added)[0]["magnet_uri"])
+		})
+	}
+}
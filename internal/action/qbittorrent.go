@@ -6,18 +6,29 @@ package action
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"os"
 	"strings"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/autobrr/go-qbittorrent"
+	goversion "github.com/hashicorp/go-version"
 )
 
-func (s *service) qbittorrent(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+// qbittorrentStopConditionMinVersion is the first qBittorrent version with the stopCondition add option
+var qbittorrentStopConditionMinVersion = goversion.Must(goversion.NewVersion("4.5.0"))
+
+func (s *service) qbittorrent(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action qBittorrent: %s", action.Name)
 
-	c := s.clientSvc.GetCachedClient(ctx, action.ClientID)
+	c, err := s.clientSvc.GetCachedClient(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get client: %d", action.ClientID)
+	}
+
+	action.ApplyClientDefaults(c.Dc)
 
 	if c.Dc.Settings.Rules.Enabled && !action.IgnoreRules {
 		// check for active downloads and other rules
@@ -31,18 +42,58 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		}
 	}
 
+	err = s.checkFilterActiveDownloads(ctx, action, release, func(ctx context.Context) (int, error) {
+		activeDownloads, err := c.Qbt.GetTorrentsActiveDownloadsCtx(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		return len(activeDownloads), nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking active downloads for client: %s", c.Dc.Name)
+	}
+
+	// qBittorrent only reports the free space of the default save path
+	rejections, err := s.checkFreeSpace(ctx, action, func(ctx context.Context, path string) (int64, error) {
+		return c.Qbt.GetFreeSpaceOnDiskCtx(ctx)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking free space for client: %s", c.Dc.Name)
+	}
+
+	if len(rejections) > 0 {
+		return rejections, nil
+	}
+
 	if release.HasMagnetUri() {
+		if action.Comment != "" {
+			s.log.Warn().Msgf("action %s: comment is not supported for magnet links, skipping it", action.Name)
+		}
+
 		options, err := s.prepareQbitOptions(action)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not prepare options")
 		}
 
+		if err := s.qbittorrentSetStopCondition(ctx, action, c.Qbt, options); err != nil {
+			return nil, errors.Wrap(err, "could not set stop condition")
+		}
+
 		s.log.Trace().Msgf("action qBittorrent options: %+v", options)
 
-		if err = c.Qbt.AddTorrentFromUrlCtx(ctx, release.MagnetURI, options); err != nil {
+		if err = s.qbittorrentAddMagnet(ctx, c.Qbt, action.ClientID, release, options); err != nil {
 			return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.MagnetURI, c.Dc.Name)
 		}
 
+		release.ClientTorrentID = release.TorrentHash
+
+		if release.TorrentHash != "" {
+			if err := s.qbittorrentSetQueuePosition(ctx, action, c.Qbt, release.TorrentHash); err != nil {
+				s.log.Warn().Err(err).Msgf("could not set queue position for hash: %s", release.TorrentHash)
+			}
+		}
+
 		s.log.Info().Msgf("torrent from magnet successfully added to client: '%s'", c.Dc.Name)
 
 		return nil, nil
@@ -59,13 +110,68 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		return nil, errors.Wrap(err, "could not prepare options")
 	}
 
+	if err := s.qbittorrentSetStopCondition(ctx, action, c.Qbt, options); err != nil {
+		return nil, errors.Wrap(err, "could not set stop condition")
+	}
+
+	recheck := action.RecheckResume && !action.Paused && release.TorrentHash != ""
+	if recheck {
+		// add stopped and only resume once the recheck found all data
+		options["paused"] = "true"
+		delete(options, "skip_checking")
+	}
+
 	s.log.Trace().Msgf("action qBittorrent options: %+v", options)
 
-	if err = c.Qbt.AddTorrentFromFileCtx(ctx, release.TorrentTmpFile, options); err != nil {
+	torrentFile := release.TorrentTmpFile
+	if action.Comment != "" {
+		torrentFile, err = torrentFileWithComment(release.TorrentTmpFile, action.Comment)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not set comment")
+		}
+		defer os.Remove(torrentFile)
+	}
+
+	if err = c.Qbt.AddTorrentFromFileCtx(ctx, torrentFile, options); err != nil {
 		return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, c.Dc.Name)
 	}
 
-	if !action.Paused && !action.ReAnnounceSkip && release.TorrentHash != "" {
+	release.ClientTorrentID = release.TorrentHash
+
+	if release.TorrentHash != "" {
+		if err := s.qbittorrentSetQueuePosition(ctx, action, c.Qbt, release.TorrentHash); err != nil {
+			s.log.Warn().Err(err).Msgf("could not set queue position for hash: %s", release.TorrentHash)
+		}
+	}
+
+	// the torrent stays stopped while the recheck runs in the background
+	paused := action.Paused
+
+	if recheck {
+		s.log.Debug().Msgf("recheck torrent with hash %s in client: '%s'", release.TorrentHash, c.Dc.Name)
+
+		rechecker := newQbittorrentRechecker(c.Qbt, release.TorrentHash)
+
+		done, err := rechecker.start(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not recheck and resume torrent: %s", release.TorrentHash)
+		}
+
+		if !done {
+			paused = true
+			go s.qbittorrentWaitRecheck(rechecker, release.TorrentHash, c.Dc.Name)
+		}
+	}
+
+	if !paused && !action.ReAnnounceSkip && release.TorrentHash != "" && action.ReAnnounceTargetPeers > 0 {
+		if err := s.qbittorrentReannounceTargetPeers(ctx, action, c.Qbt, release.TorrentHash); err != nil {
+			if errors.Is(err, ErrReannounceTookTooLong) {
+				return []string{fmt.Sprintf("re-announce did not reach %d peers for hash: %s", action.ReAnnounceTargetPeers, release.TorrentHash)}, nil
+			}
+
+			return nil, errors.Wrap(err, "could not reannounce torrent: %s", release.TorrentHash)
+		}
+	} else if !paused && !action.ReAnnounceSkip && release.TorrentHash != "" {
 		opts := qbittorrent.ReannounceOptions{
 			Interval:        int(action.ReAnnounceInterval),
 			MaxAttempts:     int(action.ReAnnounceMaxAttempts),
@@ -81,11 +187,179 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		}
 	}
 
+	if action.VerifyStart && !paused && release.TorrentHash != "" {
+		s.verifyStart(action, release, c.Dc.Name, newStartVerifier(func(ctx context.Context) (torrentStartState, string, error) {
+			torrents, err := c.Qbt.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{release.TorrentHash}})
+			if err != nil {
+				return torrentStatePending, "", errors.Wrap(err, "could not get torrent: %s", release.TorrentHash)
+			}
+
+			if len(torrents) == 0 {
+				return torrentStatePending, "not found", nil
+			}
+
+			return qbittorrentStartState(torrents[0].State), string(torrents[0].State), nil
+		}))
+	}
+
 	s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", release.TorrentHash, c.Dc.Name)
 
 	return nil, nil
 }
 
+// qbittorrentWaitRecheck waits for the recheck to finish and resumes the torrent if all data is present.
+// Rechecking a large torrent can take minutes so the action doesn't wait for it.
+func (s *service) qbittorrentWaitRecheck(r *qbittorrentRechecker, hash, clientName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout())
+	defer cancel()
+
+	if err := r.wait(ctx); err != nil {
+		s.log.Error().Err(err).Msgf("could not recheck and resume torrent with hash %s in client: '%s'", hash, clientName)
+		return
+	}
+
+	s.log.Info().Msgf("recheck found all data, resumed torrent with hash %s in client: '%s'", hash, clientName)
+}
+
+// qbittorrentAddMagnet adds the magnet link. Releases from feeds are batched with other magnets for the same
+// client and options into a single request, since qBittorrent accepts multiple urls separated by newlines.
+// Announces from irc are always added right away.
+func (s *service) qbittorrentAddMagnet(ctx context.Context, qbt *qbittorrent.Client, clientID int32, release *domain.Release, options map[string]string) error {
+	if s.batcher == nil || release.Implementation == domain.ReleaseImplementationIRC {
+		return qbt.AddTorrentFromUrlCtx(ctx, release.MagnetURI, options)
+	}
+
+	values := url.Values{}
+	for k, v := range options {
+		values.Set(k, v)
+	}
+
+	key := fmt.Sprintf("qbittorrent:%d:%s", clientID, values.Encode())
+
+	return s.batcher.Add(ctx, key, release.MagnetURI, func(ctx context.Context, urls []string) error {
+		s.log.Debug().Msgf("adding batch of %d magnets to qBittorrent", len(urls))
+
+		return qbt.AddTorrentFromUrlCtx(ctx, strings.Join(urls, "\n"), options)
+	})
+}
+
+// qbittorrentSetStopCondition adds the stop condition to the add options if the client supports it.
+// Older clients ignore unknown options and would start the torrent anyway, so it's left out with a warning.
+func (s *service) qbittorrentSetStopCondition(ctx context.Context, action *domain.Action, qbt *qbittorrent.Client, options map[string]string) error {
+	if action.StopCondition == "" {
+		return nil
+	}
+
+	appVersion, err := qbt.GetAppVersionCtx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get app version")
+	}
+
+	v, err := goversion.NewVersion(strings.TrimSpace(appVersion))
+	if err != nil {
+		return errors.Wrap(err, "could not parse app version: %s", appVersion)
+	}
+
+	if v.Core().LessThan(qbittorrentStopConditionMinVersion) {
+		s.log.Warn().Msgf("qBittorrent %s does not support stop condition, requires %s or newer, skip setting stop condition: %s", appVersion, qbittorrentStopConditionMinVersion, action.StopCondition)
+		return nil
+	}
+
+	options["stopCondition"] = string(action.StopCondition)
+
+	return nil
+}
+
+// qbittorrentSetQueuePosition moves the torrent to the top of the queue, or to the queue position counted from the top.
+// Queue positions only exist with queueing enabled in the client, otherwise it's skipped.
+func (s *service) qbittorrentSetQueuePosition(ctx context.Context, action *domain.Action, qbt *qbittorrent.Client, hash string) error {
+	if !action.TopOfQueue && action.QueuePosition <= 0 {
+		return nil
+	}
+
+	prefs, err := qbt.GetAppPreferencesCtx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get app preferences")
+	}
+
+	if !prefs.QueueingEnabled {
+		s.log.Warn().Msgf("torrent queueing is disabled in client, skip setting queue position for hash: %s", hash)
+		return nil
+	}
+
+	if err := qbt.SetMaxPriorityCtx(ctx, []string{hash}); err != nil {
+		return err
+	}
+
+	for i := 1; i < action.QueuePosition; i++ {
+		if err := qbt.DecreasePriorityCtx(ctx, []string{hash}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// qbittorrentReannounceTargetPeers keeps re-announcing until the working trackers report enough seeds and leechers
+func (s *service) qbittorrentReannounceTargetPeers(ctx context.Context, action *domain.Action, qbt *qbittorrent.Client, hash string) error {
+	interval, maxAttempts := reannounceOptions(action.ReAnnounceInterval, action.ReAnnounceMaxAttempts)
+
+	r := peerReannouncer{
+		log:         s.log,
+		name:        hash,
+		target:      int(action.ReAnnounceTargetPeers),
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		peers: func(ctx context.Context) (int, error) {
+			trackers, err := qbt.GetTorrentTrackersCtx(ctx, hash)
+			if err != nil {
+				return 0, err
+			}
+
+			return qbittorrentTrackerPeers(trackers), nil
+		},
+		reannounce: func(ctx context.Context) error {
+			return qbt.ReAnnounceTorrentsCtx(ctx, []string{hash})
+		},
+	}
+
+	if err := r.run(ctx); err != nil {
+		if !errors.Is(err, ErrReannounceTookTooLong) || !action.ReAnnounceDelete {
+			return err
+		}
+
+		s.log.Info().Msgf("re-announce for %s did not reach %d peers, deleting torrent", hash, r.target)
+
+		if err := qbt.DeleteTorrentsCtx(ctx, []string{hash}, false); err != nil {
+			return errors.Wrap(err, "could not delete torrent with hash: %s", hash)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// qbittorrentTrackerPeers sums seeds and leechers from working trackers.
+// Disabled trackers are DHT, PeX and LSD and are not counted.
+func qbittorrentTrackerPeers(trackers []qbittorrent.TorrentTracker) int {
+	peers := 0
+	for _, tracker := range trackers {
+		if tracker.Status != qbittorrent.TrackerStatusOK || isUnregistered(tracker.Message) {
+			continue
+		}
+
+		if tracker.NumSeeds > 0 {
+			peers += tracker.NumSeeds
+		}
+		if tracker.NumLeechers > 0 {
+			peers += tracker.NumLeechers
+		}
+	}
+
+	return peers
+}
+
 func (s *service) prepareQbitOptions(action *domain.Action) (map[string]string, error) {
 	opts := &qbittorrent.TorrentAddOptions{}
 
@@ -104,28 +378,16 @@ func (s *service) prepareQbitOptions(action *domain.Action) (map[string]string,
 		}
 		// if ORIGINAL then leave empty
 	}
-	if action.SavePath != "" {
+	// with automatic torrent management the category decides the save path
+	if action.SavePath != "" && !action.AutoTMM {
 		opts.SavePath = strings.TrimSpace(action.SavePath)
 		opts.AutoTMM = false
 	}
 	if action.Category != "" {
 		opts.Category = strings.TrimSpace(action.Category)
 	}
-	if action.Tags != "" {
-		// Split the action.Tags string by comma
-		tags := strings.Split(action.Tags, ",")
-
-		// Create a new slice to store the trimmed tags
-		trimmedTags := make([]string, 0, len(tags))
-
-		// Iterate over the tags and trim each one
-		for _, tag := range tags {
-			trimmedTag := strings.TrimSpace(tag)
-			trimmedTags = append(trimmedTags, trimmedTag)
-		}
-
-		// Join the trimmed tags back together with commas
-		opts.Tags = strings.Join(trimmedTags, ",")
+	if tags := action.TagList(); len(tags) > 0 {
+		opts.Tags = strings.Join(tags, ",")
 	}
 	if action.LimitUploadSpeed > 0 {
 		opts.LimitUploadSpeed = action.LimitUploadSpeed
@@ -140,7 +402,18 @@ func (s *service) prepareQbitOptions(action *domain.Action) (map[string]string,
 		opts.LimitSeedTime = action.LimitSeedTime
 	}
 
-	return opts.Prepare(), nil
+	options := opts.Prepare()
+
+	if action.AutoTMM {
+		options["autoTMM"] = "true"
+	}
+
+	// qBittorrent 4.5+ places the torrent at the top of the queue on add, older versions rely on the topPrio call after add
+	if action.TopOfQueue || action.QueuePosition > 0 {
+		options["addToTopOfQueue"] = "true"
+	}
+
+	return options, nil
 }
 
 // qbittorrentCheckRulesCanDownload
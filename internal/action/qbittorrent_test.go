@@ -0,0 +1,639 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/asaskevich/EventBus"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQbittorrent struct {
+	m               sync.Mutex
+	queueingEnabled bool
+	addForm         map[string]string
+	addedURLs       []string
+	addedFile       []byte
+	freeSpace       int64
+	appVersion      string
+	calls           []string
+
+	// activeDownloads is the active download count returned by each torrents/info call, the last one repeats
+	activeDownloads []int
+}
+
+// newFakeQbittorrent returns a server for the qBittorrent web api endpoints used when adding torrents
+func newFakeQbittorrent(t *testing.T, queueingEnabled bool) (*httptest.Server, *fakeQbittorrent) {
+	fake := &fakeQbittorrent{queueingEnabled: queueingEnabled, addForm: map[string]string{}, appVersion: "v4.6.0"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.m.Lock()
+		defer fake.m.Unlock()
+
+		endpoint := strings.TrimPrefix(r.URL.Path, "/api/v2/")
+		fake.calls = append(fake.calls, endpoint)
+
+		switch endpoint {
+		case "auth/login":
+			_, _ = w.Write([]byte("Ok."))
+
+		case "sync/maindata":
+			_ = json.NewEncoder(w).Encode(map[string]any{"rid": 1, "server_state": map[string]any{"free_space_on_disk": fake.freeSpace}})
+
+		case "torrents/info":
+			count := 0
+			if len(fake.activeDownloads) > 0 {
+				count = fake.activeDownloads[0]
+				if len(fake.activeDownloads) > 1 {
+					fake.activeDownloads = fake.activeDownloads[1:]
+				}
+			}
+			torrents := make([]map[string]any, count)
+			for i := range torrents {
+				torrents[i] = map[string]any{"state": "downloading"}
+			}
+			_ = json.NewEncoder(w).Encode(torrents)
+
+		case "app/version":
+			_, _ = w.Write([]byte(fake.appVersion))
+
+		case "app/preferences":
+			_ = json.NewEncoder(w).Encode(map[string]any{"queueing_enabled": fake.queueingEnabled})
+
+		case "torrents/add":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				_ = r.ParseForm()
+			}
+			for key, values := range r.Form {
+				fake.addForm[key] = values[0]
+			}
+			if r.MultipartForm != nil && len(r.MultipartForm.File["torrents"]) > 0 {
+				if f, err := r.MultipartForm.File["torrents"][0].Open(); err == nil {
+					fake.addedFile, _ = io.ReadAll(f)
+					f.Close()
+				}
+			}
+			if urls := r.Form.Get("urls"); urls != "" {
+				fake.addedURLs = append(fake.addedURLs, urls)
+			}
+			_, _ = w.Write([]byte("Ok."))
+
+		case "torrents/topPrio", "torrents/decreasePrio":
+			if !fake.queueingEnabled {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			_ = r.ParseForm()
+			fake.calls[len(fake.calls)-1] += "?" + r.Form.Get("hashes")
+
+		case "torrents/resume":
+			_ = r.ParseForm()
+			fake.calls[len(fake.calls)-1] += "?" + r.Form.Get("hashes")
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, fake
+}
+
+func Test_service_qbittorrent_queuePosition(t *testing.T) {
+	hash := "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"
+
+	tests := []struct {
+		name            string
+		topOfQueue      bool
+		queuePosition   int
+		queueingEnabled bool
+		wantAddFlag     bool
+		wantCalls       []string
+	}{
+		{
+			name:            "default",
+			queueingEnabled: true,
+			wantCalls:       []string{"torrents/add"},
+		},
+		{
+			name:            "top_of_queue",
+			topOfQueue:      true,
+			queueingEnabled: true,
+			wantAddFlag:     true,
+			wantCalls:       []string{"torrents/add", "app/preferences", "torrents/topPrio?" + hash},
+		},
+		{
+			name:            "queue_position",
+			queuePosition:   3,
+			queueingEnabled: true,
+			wantAddFlag:     true,
+			wantCalls:       []string{"torrents/add", "app/preferences", "torrents/topPrio?" + hash, "torrents/decreasePrio?" + hash, "torrents/decreasePrio?" + hash},
+		},
+		{
+			name:            "queueing_disabled",
+			topOfQueue:      true,
+			queueingEnabled: false,
+			wantAddFlag:     true,
+			wantCalls:       []string{"torrents/add", "app/preferences"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeQbittorrent(t, tt.queueingEnabled)
+
+			client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					cached: map[int32]*domain.DownloadClientCached{
+						1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+					},
+				},
+			}
+
+			torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+			assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				TorrentTmpFile: torrentFile,
+				TorrentHash:    hash,
+				Indexer:        "mock",
+			}
+
+			action := &domain.Action{
+				Name:           "qbit",
+				Type:           domain.ActionTypeQbittorrent,
+				ClientID:       1,
+				ReAnnounceSkip: true,
+				TopOfQueue:     tt.topOfQueue,
+				QueuePosition:  tt.queuePosition,
+			}
+
+			rejections, err := s.RunAction(context.Background(), action, release)
+			assert.NoError(t, err)
+			assert.Empty(t, rejections)
+
+			if tt.wantAddFlag {
+				assert.Equal(t, "true", fake.addForm["addToTopOfQueue"])
+			} else {
+				assert.NotContains(t, fake.addForm, "addToTopOfQueue")
+			}
+
+			assert.Equal(t, tt.wantCalls, fake.calls)
+		})
+	}
+}
+
+func Test_service_qbittorrent_batchMagnets(t *testing.T) {
+	ts, fake := newFakeQbittorrent(t, true)
+
+	client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+		clientSvc: &mockDownloadClientService{
+			cached: map[int32]*domain.DownloadClientCached{
+				1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+			},
+		},
+		batcher: newAddBatcher(defaultBatchMaxSize, 200*time.Millisecond),
+	}
+
+	run := func(implementation domain.ReleaseImplementation, magnets []string) {
+		var wg sync.WaitGroup
+		for _, magnet := range magnets {
+			magnet := magnet
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				release := &domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", MagnetURI: magnet, Implementation: implementation, Indexer: "mock"}
+
+				_, err := s.RunAction(context.Background(), &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Category: "tv"}, release)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	}
+
+	magnets := []string{
+		"magnet:?xt=urn:btih:1111111111111111111111111111111111111111",
+		"magnet:?xt=urn:btih:2222222222222222222222222222222222222222",
+		"magnet:?xt=urn:btih:3333333333333333333333333333333333333333",
+		"magnet:?xt=urn:btih:4444444444444444444444444444444444444444",
+	}
+
+	// releases from a feed end up in one request
+	run(domain.ReleaseImplementationRSS, magnets)
+
+	assert.Equal(t, []string{"torrents/add"}, fake.calls)
+	assert.Len(t, fake.addedURLs, 1)
+	assert.ElementsMatch(t, magnets, strings.Split(fake.addedURLs[0], "\n"))
+
+	// announces are added right away
+	fake.calls = nil
+	fake.addedURLs = nil
+
+	run(domain.ReleaseImplementationIRC, magnets[:2])
+
+	assert.Equal(t, []string{"torrents/add", "torrents/add"}, fake.calls)
+	assert.ElementsMatch(t, magnets[:2], fake.addedURLs)
+}
+
+func Test_service_qbittorrent_minFreeSpace(t *testing.T) {
+	tests := []struct {
+		name           string
+		minFreeSpace   string
+		freeSpace      int64
+		wantRejections []string
+		wantCalls      []string
+	}{
+		{
+			name:      "disabled",
+			freeSpace: 1 << 30,
+			wantCalls: []string{"torrents/add"},
+		},
+		{
+			name:         "enough_space",
+			minFreeSpace: "50 GB",
+			freeSpace:    120_000_000_000,
+			wantCalls:    []string{"sync/maindata", "torrents/add"},
+		},
+		{
+			name:           "insufficient_space",
+			minFreeSpace:   "50 GB",
+			freeSpace:      4_000_000_000,
+			wantRejections: []string{"insufficient free space: 4.0 GB free, 50 GB required"},
+			wantCalls:      []string{"sync/maindata"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeQbittorrent(t, true)
+			fake.freeSpace = tt.freeSpace
+
+			client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					cached: map[int32]*domain.DownloadClientCached{
+						1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+					},
+				},
+			}
+
+			release := &domain.Release{
+				TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				MagnetURI:   "magnet:?xt=urn:btih:3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a",
+				Indexer:     "mock",
+			}
+
+			rejections, err := s.RunAction(context.Background(), &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, MinFreeSpace: tt.minFreeSpace}, release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRejections, rejections)
+			assert.Equal(t, tt.wantCalls, fake.calls)
+		})
+	}
+}
+
+func Test_service_qbittorrent_autoTMM(t *testing.T) {
+	tests := []struct {
+		name         string
+		autoTMM      bool
+		savePath     string
+		wantSavePath string
+		wantAutoTMM  string
+	}{
+		{name: "auto_tmm", autoTMM: true, wantAutoTMM: "true"},
+		{name: "auto_tmm_ignores_save_path", autoTMM: true, savePath: "/data/tv", wantAutoTMM: "true"},
+		{name: "manual_save_path", savePath: "/data/tv", wantSavePath: "/data/tv", wantAutoTMM: "false"},
+		{name: "client_default", wantAutoTMM: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeQbittorrent(t, true)
+
+			client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					cached: map[int32]*domain.DownloadClientCached{
+						1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+					},
+				},
+			}
+
+			torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+			assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				TorrentTmpFile: torrentFile,
+				Indexer:        "mock",
+			}
+
+			action := &domain.Action{
+				Name:           "qbit",
+				Type:           domain.ActionTypeQbittorrent,
+				ClientID:       1,
+				ReAnnounceSkip: true,
+				Category:       "tv",
+				SavePath:       tt.savePath,
+				AutoTMM:        tt.autoTMM,
+			}
+
+			rejections, err := s.RunAction(context.Background(), action, release)
+			assert.NoError(t, err)
+			assert.Empty(t, rejections)
+
+			assert.Equal(t, "tv", fake.addForm["category"])
+
+			if tt.wantSavePath == "" {
+				assert.NotContains(t, fake.addForm, "savepath")
+			} else {
+				assert.Equal(t, tt.wantSavePath, fake.addForm["savepath"])
+			}
+
+			if tt.wantAutoTMM == "" {
+				assert.NotContains(t, fake.addForm, "autoTMM")
+			} else {
+				assert.Equal(t, tt.wantAutoTMM, fake.addForm["autoTMM"])
+			}
+		})
+	}
+}
+
+func Test_service_qbittorrent_tags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     string
+		release  domain.Release
+		wantTags string
+	}{
+		{name: "empty_and_duplicate", tags: "{{ .Resolution }},,{{ .HDR }}, hdr", release: domain.Release{Resolution: "4k", HDR: []string{"hdr"}}, wantTags: "4k,hdr"},
+		{name: "macro_list", tags: "autobrr,{{ range .Categories }}{{ . }},{{ end }}", release: domain.Release{Categories: []string{"TV", "TV/HD", "TV"}}, wantTags: "autobrr,TV,TV/HD"},
+		{name: "only_empty", tags: "{{ .Resolution }}, ,", wantTags: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeQbittorrent(t, true)
+
+			client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					cached: map[int32]*domain.DownloadClientCached{
+						1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+					},
+				},
+			}
+
+			torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+			assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+			release := tt.release
+			release.TorrentName = "That.Show.S01E01.2160p.WEB-DL.DDP5.1.HDR.H.265-GROUP"
+			release.TorrentTmpFile = torrentFile
+			release.Indexer = "mock"
+
+			action := &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, ReAnnounceSkip: true, Tags: tt.tags}
+
+			_, err := s.RunAction(context.Background(), action, &release)
+			assert.NoError(t, err)
+
+			if tt.wantTags == "" {
+				assert.NotContains(t, fake.addForm, "tags")
+			} else {
+				assert.Equal(t, tt.wantTags, fake.addForm["tags"])
+			}
+		})
+	}
+}
+
+func Test_service_qbittorrent_filterMaxActiveDownloads(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxActive       int
+		activeDownloads []int
+		wantDeferred    bool
+		wantChecks      int
+	}{
+		{name: "disabled", activeDownloads: []int{5}, wantChecks: 0},
+		{name: "below_limit", maxActive: 2, activeDownloads: []int{1}, wantChecks: 1},
+		{name: "at_limit", maxActive: 2, activeDownloads: []int{2}, wantDeferred: true, wantChecks: 1},
+		{name: "above_limit", maxActive: 2, activeDownloads: []int{4}, wantDeferred: true, wantChecks: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeQbittorrent(t, true)
+			fake.activeDownloads = tt.activeDownloads
+
+			client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					cached: map[int32]*domain.DownloadClientCached{
+						1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+					},
+				},
+			}
+
+			torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+			assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				TorrentTmpFile: torrentFile,
+				Indexer:        "mock",
+				Filter:         &domain.Filter{ID: 1, Name: "tv", MaxActiveDownloads: tt.maxActive},
+			}
+
+			action := &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, ReAnnounceSkip: true}
+
+			// the client is checked once, the release service tries a deferred release again later
+			rejections, err := s.RunAction(context.Background(), action, release)
+			if tt.wantDeferred {
+				assert.ErrorIs(t, err, domain.ErrMaxActiveDownloads)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			checks := 0
+			for _, call := range fake.calls {
+				if call == "torrents/info" {
+					checks++
+				}
+			}
+			assert.Equal(t, tt.wantChecks, checks)
+
+			if tt.wantDeferred {
+				assert.Empty(t, rejections)
+				assert.NotContains(t, fake.calls, "torrents/add")
+			} else {
+				assert.Empty(t, rejections)
+				assert.Contains(t, fake.calls, "torrents/add")
+			}
+		})
+	}
+}
+
+func Test_service_qbittorrent_comment(t *testing.T) {
+	ts, fake := newFakeQbittorrent(t, true)
+
+	client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+		clientSvc: &mockDownloadClientService{
+			cached: map[int32]*domain.DownloadClientCached{
+				1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+			},
+		},
+	}
+
+	torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+	assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+	release := &domain.Release{
+		TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		TorrentTmpFile: torrentFile,
+		Indexer:        "mock",
+		FilterName:     "tv-1080p",
+		Timestamp:      time.Date(2023, 10, 1, 12, 30, 0, 0, time.UTC),
+	}
+
+	action := &domain.Action{
+		Name:           "qbit",
+		Type:           domain.ActionTypeQbittorrent,
+		ClientID:       1,
+		ReAnnounceSkip: true,
+		Comment:        `autobrr filter: {{ .FilterName }} announced: {{ .AnnouncedAt }}`,
+	}
+
+	rejections, err := s.RunAction(context.Background(), action, release)
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	meta, err := metainfo.Load(bytes.NewReader(fake.addedFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "autobrr filter: tv-1080p announced: 2023-10-01T12:30:00Z", meta.Comment)
+	assert.Equal(t, "d4:name4:teste", string(meta.InfoBytes))
+
+	// the comment is written into the uploaded file, qBittorrent has no add option for it
+	assert.NotContains(t, fake.addForm, "comment")
+}
+
+func Test_service_qbittorrent_stopCondition(t *testing.T) {
+	tests := []struct {
+		name          string
+		appVersion    string
+		stopCondition domain.ActionStopCondition
+		magnet        bool
+		want          string
+	}{
+		{
+			name:          "metadata_received",
+			appVersion:    "v4.6.0",
+			stopCondition: domain.ActionStopConditionMetadataReceived,
+			magnet:        true,
+			want:          "MetadataReceived",
+		},
+		{
+			name:          "files_checked",
+			appVersion:    "v4.5.0",
+			stopCondition: domain.ActionStopConditionFilesChecked,
+			want:          "FilesChecked",
+		},
+		{
+			name:          "old_client",
+			appVersion:    "v4.4.5",
+			stopCondition: domain.ActionStopConditionMetadataReceived,
+			magnet:        true,
+		},
+		{
+			name:       "not_set",
+			appVersion: "v4.6.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeQbittorrent(t, true)
+			fake.appVersion = tt.appVersion
+
+			client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					cached: map[int32]*domain.DownloadClientCached{
+						1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+					},
+				},
+			}
+
+			release := &domain.Release{
+				TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				Indexer:     "mock",
+			}
+
+			if tt.magnet {
+				release.MagnetURI = "magnet:?xt=urn:btih:3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"
+			} else {
+				release.TorrentTmpFile = filepath.Join(t.TempDir(), "release.torrent")
+				assert.NoError(t, os.WriteFile(release.TorrentTmpFile, []byte("d4:infod4:name4:testee"), 0644))
+			}
+
+			action := &domain.Action{
+				Name:           "qbit",
+				Type:           domain.ActionTypeQbittorrent,
+				ClientID:       1,
+				ReAnnounceSkip: true,
+				StopCondition:  tt.stopCondition,
+			}
+
+			rejections, err := s.RunAction(context.Background(), action, release)
+			assert.NoError(t, err)
+			assert.Empty(t, rejections)
+
+			if tt.want == "" {
+				assert.NotContains(t, fake.addForm, "stopCondition")
+				return
+			}
+
+			assert.Equal(t, tt.want, fake.addForm["stopCondition"])
+		})
+	}
+}
@@ -29,10 +29,16 @@ func (s *service) radarr(ctx context.Context, action *domain.Action, release dom
 	}
 
 	// initial config
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := radarr.Config{
-		Hostname: client.Host,
-		APIKey:   client.Settings.APIKey,
-		Log:      s.subLogger,
+		Hostname:  client.Host,
+		APIKey:    client.Settings.APIKey,
+		Transport: transport,
+		Log:       s.subLogger,
 	}
 
 	// only set basic auth if enabled
@@ -60,6 +66,7 @@ func (s *service) radarr(ctx context.Context, action *domain.Action, release dom
 		DownloadProtocol: string(release.Protocol),
 		Protocol:         string(release.Protocol),
 		PublishDate:      time.Now().Format(time.RFC3339),
+		Tags:             arrTags(action),
 	}
 
 	arr := radarr.New(cfg)
@@ -79,3 +86,13 @@ func (s *service) radarr(ctx context.Context, action *domain.Action, release dom
 
 	return nil, nil
 }
+
+// arrTags returns the macro expanded tags and label of the action as a list of tags for the arr push
+func arrTags(action *domain.Action) []string {
+	tags := domain.SplitTags(action.Tags + "," + action.Label)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return tags
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// peerReannouncer checks the peer count reported by the trackers and re-announces until a target is reached
+type peerReannouncer struct {
+	log         zerolog.Logger
+	name        string
+	target      int
+	interval    time.Duration
+	maxAttempts int
+
+	// peers returns the total seeds and leechers reported by working trackers
+	peers func(ctx context.Context) (int, error)
+
+	// reannounce asks the client to announce to the trackers again
+	reannounce func(ctx context.Context) error
+}
+
+// run returns ErrReannounceTookTooLong if the target is not reached within max attempts
+func (r *peerReannouncer) run(ctx context.Context) error {
+	for attempts := 0; attempts < r.maxAttempts; attempts++ {
+		// add delay for next run
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+
+		count, err := r.peers(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get peers for torrent: %s", r.name)
+		}
+
+		r.log.Debug().Msgf("re-announce %s attempt: %d/%d peers: %d/%d", r.name, attempts+1, r.maxAttempts, count, r.target)
+
+		if count >= r.target {
+			return nil
+		}
+
+		if err := r.reannounce(ctx); err != nil {
+			return errors.Wrap(err, "could not re-announce torrent: %s", r.name)
+		}
+	}
+
+	return errors.Wrap(ErrReannounceTookTooLong, "could not reach %d peers for torrent %s after %d attempts", r.target, r.name, r.maxAttempts)
+}
+
+func reannounceOptions(interval, maxAttempts int64) (time.Duration, int) {
+	i := ReannounceInterval
+	if interval > 0 {
+		i = int(interval)
+	}
+
+	m := ReannounceMaxAttempts
+	if maxAttempts > 0 {
+		m = int(maxAttempts)
+	}
+
+	return time.Duration(i) * time.Second, m
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_peerReannouncer_run(t *testing.T) {
+	tests := []struct {
+		name            string
+		target          int
+		maxAttempts     int
+		peers           []int
+		wantReannounces int
+		wantErr         error
+	}{
+		{
+			name:            "reach_target",
+			target:          5,
+			maxAttempts:     5,
+			peers:           []int{0, 2, 4, 6},
+			wantReannounces: 3,
+		},
+		{
+			name:            "reach_target_first_attempt",
+			target:          1,
+			maxAttempts:     5,
+			peers:           []int{3},
+			wantReannounces: 0,
+		},
+		{
+			name:            "fail_target",
+			target:          10,
+			maxAttempts:     3,
+			peers:           []int{1, 2, 3},
+			wantReannounces: 3,
+			wantErr:         ErrReannounceTookTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			reannounces := 0
+
+			r := peerReannouncer{
+				log:         zerolog.Nop(),
+				name:        "abc123",
+				target:      tt.target,
+				interval:    time.Millisecond,
+				maxAttempts: tt.maxAttempts,
+				peers: func(ctx context.Context) (int, error) {
+					count := tt.peers[calls]
+					calls++
+					return count, nil
+				},
+				reannounce: func(ctx context.Context) error {
+					reannounces++
+					return nil
+				},
+			}
+
+			err := r.run(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantReannounces, reannounces)
+		})
+	}
+}
+
+func Test_peerReannouncer_run_peersError(t *testing.T) {
+	r := peerReannouncer{
+		log:         zerolog.Nop(),
+		name:        "abc123",
+		target:      1,
+		interval:    time.Millisecond,
+		maxAttempts: 3,
+		peers: func(ctx context.Context) (int, error) {
+			return 0, errors.New("connection refused")
+		},
+		reannounce: func(ctx context.Context) error {
+			return nil
+		},
+	}
+
+	err := r.run(context.Background())
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrReannounceTookTooLong))
+}
+
+func Test_qbittorrentTrackerPeers(t *testing.T) {
+	trackers := []qbittorrent.TorrentTracker{
+		{Url: "** [DHT] **", Status: qbittorrent.TrackerStatusDisabled, NumSeeds: 20, NumLeechers: 10},
+		{Url: "https://tracker.example.com/announce", Status: qbittorrent.TrackerStatusOK, NumSeeds: 3, NumLeechers: 2},
+		{Url: "https://backup.example.com/announce", Status: qbittorrent.TrackerStatusOK, NumSeeds: -1, NumLeechers: -1},
+		{Url: "https://other.example.com/announce", Status: qbittorrent.TrackerStatusOK, NumSeeds: 4, Message: "Unregistered torrent"},
+		{Url: "https://slow.example.com/announce", Status: qbittorrent.TrackerStatusUpdating, NumSeeds: 8},
+	}
+
+	assert.Equal(t, 5, qbittorrentTrackerPeers(trackers))
+}
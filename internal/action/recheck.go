@@ -0,0 +1,132 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/autobrr/go-qbittorrent"
+)
+
+const (
+	RecheckInterval    = 2   // interval in seconds
+	RecheckMaxAttempts = 150 // 5 minutes with the default interval
+)
+
+var (
+	ErrRecheckMissingData = errors.New("recheck found missing data")
+	ErrRecheckTookTooLong = errors.New("recheck took too long")
+	ErrRecheckTorrentGone = errors.New("torrent not found in client")
+)
+
+// qbittorrentRecheckClient is the part of the qBittorrent client used to recheck and resume torrents
+type qbittorrentRecheckClient interface {
+	GetTorrentsCtx(ctx context.Context, o qbittorrent.TorrentFilterOptions) ([]qbittorrent.Torrent, error)
+	RecheckCtx(ctx context.Context, hashes []string) error
+	ResumeCtx(ctx context.Context, hashes []string) error
+}
+
+// recheckSettledPolls is how many polls the torrent can stay unchanged before the recheck counts as finished.
+// A recheck of a small torrent can finish before it's seen checking.
+const recheckSettledPolls = 3
+
+// qbittorrentRechecker forces a recheck of a paused torrent and resumes it if all data is present
+type qbittorrentRechecker struct {
+	client      qbittorrentRecheckClient
+	hash        string
+	interval    time.Duration
+	maxAttempts int
+
+	checking bool
+	settled  int
+}
+
+func newQbittorrentRechecker(client qbittorrentRecheckClient, hash string) *qbittorrentRechecker {
+	return &qbittorrentRechecker{
+		client:      client,
+		hash:        hash,
+		interval:    RecheckInterval * time.Second,
+		maxAttempts: RecheckMaxAttempts,
+	}
+}
+
+// timeout is how long wait can poll at most
+func (r *qbittorrentRechecker) timeout() time.Duration {
+	return time.Duration(r.maxAttempts+1) * r.interval
+}
+
+// start forces the recheck and records the state right after it, so a recheck that finishes before the
+// first poll is still seen. It returns true if the recheck already finished and the torrent was resumed.
+func (r *qbittorrentRechecker) start(ctx context.Context) (bool, error) {
+	if err := r.client.RecheckCtx(ctx, []string{r.hash}); err != nil {
+		return false, errors.Wrap(err, "could not recheck torrent: %s", r.hash)
+	}
+
+	return r.poll(ctx)
+}
+
+// wait polls the torrent until the recheck finished. It returns ErrRecheckMissingData if the recheck
+// finished without all data present, the torrent is left paused in that case.
+func (r *qbittorrentRechecker) wait(ctx context.Context) error {
+	for attempt := 0; attempt < r.maxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+
+		done, err := r.poll(ctx)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrRecheckTookTooLong, "torrent %s after %d attempts", r.hash, r.maxAttempts)
+}
+
+// poll checks the state of the torrent once and resumes it when the recheck found all data
+func (r *qbittorrentRechecker) poll(ctx context.Context) (bool, error) {
+	torrents, err := r.client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{r.hash}})
+	if err != nil {
+		return false, errors.Wrap(err, "could not get torrent: %s", r.hash)
+	}
+
+	if len(torrents) == 0 {
+		return false, errors.Wrap(ErrRecheckTorrentGone, "hash: %s", r.hash)
+	}
+
+	torrent := torrents[0]
+
+	switch torrent.State {
+	case qbittorrent.TorrentStateCheckingDl, qbittorrent.TorrentStateCheckingUp, qbittorrent.TorrentStateCheckingResumeData:
+		r.checking = true
+		return false, nil
+
+	case qbittorrent.TorrentStateMissingFiles, qbittorrent.TorrentStateError:
+		return false, errors.Wrap(ErrRecheckMissingData, "torrent %s state: %s", r.hash, torrent.State)
+	}
+
+	if torrent.Progress >= 1 {
+		if err := r.client.ResumeCtx(ctx, []string{r.hash}); err != nil {
+			return false, errors.Wrap(err, "could not resume torrent: %s", r.hash)
+		}
+
+		return true, nil
+	}
+
+	// the recheck may not have started yet, fail once it has been seen checking or the state settled
+	r.settled++
+	if r.checking || r.settled > recheckSettledPolls {
+		return false, errors.Wrap(ErrRecheckMissingData, "torrent %s progress: %.2f%%", r.hash, torrent.Progress*100)
+	}
+
+	return false, nil
+}
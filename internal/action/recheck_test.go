@@ -0,0 +1,151 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRecheckClient returns the next torrent state on every poll, the last one repeats
+type fakeRecheckClient struct {
+	states    []qbittorrent.Torrent
+	polls     int
+	rechecked bool
+	resumed   bool
+}
+
+func (c *fakeRecheckClient) GetTorrentsCtx(ctx context.Context, o qbittorrent.TorrentFilterOptions) ([]qbittorrent.Torrent, error) {
+	if len(c.states) == 0 {
+		return nil, nil
+	}
+
+	idx := c.polls
+	if idx >= len(c.states) {
+		idx = len(c.states) - 1
+	}
+	c.polls++
+
+	return []qbittorrent.Torrent{c.states[idx]}, nil
+}
+
+func (c *fakeRecheckClient) RecheckCtx(ctx context.Context, hashes []string) error {
+	c.rechecked = true
+	return nil
+}
+
+func (c *fakeRecheckClient) ResumeCtx(ctx context.Context, hashes []string) error {
+	c.resumed = true
+	return nil
+}
+
+func Test_qbittorrentRechecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		states      []qbittorrent.Torrent
+		maxAttempts int
+		wantResumed bool
+		wantErr     error
+	}{
+		{
+			name: "complete",
+			states: []qbittorrent.Torrent{
+				{State: qbittorrent.TorrentStatePausedDl, Progress: 0},
+				{State: qbittorrent.TorrentStateCheckingDl, Progress: 0.25},
+				{State: qbittorrent.TorrentStateCheckingDl, Progress: 0.75},
+				{State: qbittorrent.TorrentStatePausedUp, Progress: 1},
+			},
+			maxAttempts: 10,
+			wantResumed: true,
+		},
+		{
+			name: "complete_before_first_poll",
+			states: []qbittorrent.Torrent{
+				{State: qbittorrent.TorrentStatePausedUp, Progress: 1},
+			},
+			maxAttempts: 10,
+			wantResumed: true,
+		},
+		{
+			name: "partial_data",
+			states: []qbittorrent.Torrent{
+				{State: qbittorrent.TorrentStateCheckingDl, Progress: 0.25},
+				{State: qbittorrent.TorrentStateCheckingDl, Progress: 0.5},
+				{State: qbittorrent.TorrentStatePausedDl, Progress: 0.6},
+			},
+			maxAttempts: 10,
+			wantErr:     ErrRecheckMissingData,
+		},
+		{
+			name: "missing_files",
+			states: []qbittorrent.Torrent{
+				{State: qbittorrent.TorrentStateMissingFiles, Progress: 0},
+			},
+			maxAttempts: 10,
+			wantErr:     ErrRecheckMissingData,
+		},
+		{
+			name: "missing_data_before_first_poll",
+			states: []qbittorrent.Torrent{
+				{State: qbittorrent.TorrentStatePausedDl, Progress: 0.6},
+			},
+			maxAttempts: 10,
+			wantErr:     ErrRecheckMissingData,
+		},
+		{
+			name: "checking_too_long",
+			states: []qbittorrent.Torrent{
+				{State: qbittorrent.TorrentStateCheckingDl, Progress: 0.1},
+			},
+			maxAttempts: 3,
+			wantErr:     ErrRecheckTookTooLong,
+		},
+		{
+			name:        "torrent_removed",
+			maxAttempts: 3,
+			wantErr:     ErrRecheckTorrentGone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeRecheckClient{states: tt.states}
+
+			r := newQbittorrentRechecker(client, "abc123")
+			r.interval = time.Millisecond
+			r.maxAttempts = tt.maxAttempts
+
+			done, err := r.start(context.Background())
+			if err == nil && !done {
+				err = r.wait(context.Background())
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.True(t, client.rechecked)
+			assert.Equal(t, tt.wantResumed, client.resumed)
+		})
+	}
+}
+
+func Test_qbittorrentRechecker_start(t *testing.T) {
+	// the first poll is right after the recheck call, without waiting the interval
+	client := &fakeRecheckClient{states: []qbittorrent.Torrent{{State: qbittorrent.TorrentStatePausedUp, Progress: 1}}}
+
+	r := newQbittorrentRechecker(client, "abc123")
+	r.interval = time.Hour
+
+	done, err := r.start(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.True(t, client.resumed)
+	assert.Equal(t, 1, client.polls)
+}
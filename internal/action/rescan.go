@@ -0,0 +1,116 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/radarr"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+)
+
+// triggerRescan queues a downloaded scan in the Sonarr or Radarr instance of the action after the torrent was added.
+// A failed rescan does not fail the action, the torrent is already in the client.
+func (s *service) triggerRescan(ctx context.Context, action *domain.Action, release *domain.Release) {
+	switch action.Type {
+	case domain.ActionTypeQbittorrent, domain.ActionTypeDelugeV1, domain.ActionTypeDelugeV2, domain.ActionTypeRTorrent, domain.ActionTypeTransmission, domain.ActionTypePorla:
+	default:
+		s.log.Warn().Msgf("action %s: rescan is not supported for %s, skipping", action.Name, action.Type)
+		return
+	}
+
+	id, err := s.rescan(ctx, action, release)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("action %s: could not trigger rescan for release: %s", action.Name, release.TorrentName)
+		return
+	}
+
+	s.log.Info().Msgf("action %s: rescan queued with command id %d for release: %s", action.Name, id, release.TorrentName)
+}
+
+// rescan sends the scan command and returns the id of the command queue
+func (s *service) rescan(ctx context.Context, action *domain.Action, release *domain.Release) (int, error) {
+	client, err := s.clientSvc.FindByID(ctx, action.RescanClientID)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not find rescan client: %d", action.RescanClientID)
+	}
+
+	if client == nil {
+		return 0, errors.New("could not find rescan client: %d", action.RescanClientID)
+	}
+
+	// the arrs track torrents by the uppercase infohash
+	if release.TorrentHash == "" {
+		_ = release.ComputeInfoHash()
+	}
+	downloadID := strings.ToUpper(release.TorrentHash)
+	path := strings.TrimSpace(action.SavePath)
+
+	switch client.Type {
+	case domain.DownloadClientTypeSonarr:
+		transport, err := s.clientSvc.GetTransport(client)
+		if err != nil {
+			return 0, err
+		}
+
+		cfg := sonarr.Config{
+			Hostname:  client.Host,
+			APIKey:    client.Settings.APIKey,
+			Transport: transport,
+			Log:       s.subLogger,
+		}
+
+		if client.Settings.Basic.Auth {
+			cfg.BasicAuth = client.Settings.Basic.Auth
+			cfg.Username = client.Settings.Basic.Username
+			cfg.Password = client.Settings.Basic.Password
+		}
+
+		res, err := sonarr.New(cfg).Command(ctx, sonarr.Command{
+			Name:             sonarr.CommandDownloadedEpisodesScan,
+			Path:             path,
+			DownloadClientId: downloadID,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return res.ID, nil
+
+	case domain.DownloadClientTypeRadarr:
+		transport, err := s.clientSvc.GetTransport(client)
+		if err != nil {
+			return 0, err
+		}
+
+		cfg := radarr.Config{
+			Hostname:  client.Host,
+			APIKey:    client.Settings.APIKey,
+			Transport: transport,
+			Log:       s.subLogger,
+		}
+
+		if client.Settings.Basic.Auth {
+			cfg.BasicAuth = client.Settings.Basic.Auth
+			cfg.Username = client.Settings.Basic.Username
+			cfg.Password = client.Settings.Basic.Password
+		}
+
+		res, err := radarr.New(cfg).Command(ctx, radarr.Command{
+			Name:             radarr.CommandDownloadedMoviesScan,
+			Path:             path,
+			DownloadClientId: downloadID,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return res.ID, nil
+	}
+
+	return 0, errors.New("rescan is not supported for client type: %s", client.Type)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeArr records the commands posted to the command endpoint of a Sonarr or Radarr instance
+type fakeArr struct {
+	m        sync.Mutex
+	apiKey   string
+	commands []map[string]interface{}
+}
+
+func (f *fakeArr) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v3/command" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if r.Header.Get("X-Api-Key") != f.apiKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var cmd map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.m.Lock()
+	f.commands = append(f.commands, cmd)
+	f.m.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "name": cmd["name"], "status": "queued"})
+}
+
+func Test_service_RunAction_rescan(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientType   domain.DownloadClientType
+		apiKey       string
+		savePath     string
+		wantCommands []map[string]interface{}
+	}{
+		{
+			name:       "sonarr",
+			clientType: domain.DownloadClientTypeSonarr,
+			apiKey:     "sonarr-key",
+			savePath:   "/downloads/tv",
+			wantCommands: []map[string]interface{}{
+				{"name": "DownloadedEpisodesScan", "path": "/downloads/tv"},
+			},
+		},
+		{
+			name:       "radarr",
+			clientType: domain.DownloadClientTypeRadarr,
+			apiKey:     "radarr-key",
+			wantCommands: []map[string]interface{}{
+				{"name": "DownloadedMoviesScan"},
+			},
+		},
+		{
+			name:       "bad_api_key",
+			clientType: domain.DownloadClientTypeSonarr,
+			apiKey:     "wrong-key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := &fakeArr{apiKey: "sonarr-key"}
+			if tt.clientType == domain.DownloadClientTypeRadarr {
+				arr.apiKey = "radarr-key"
+			}
+			ts := httptest.NewServer(arr)
+			defer ts.Close()
+
+			port := newFakeDelugeV2(t, &fakeDeluge{})
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					clients: map[int32]*domain.DownloadClient{
+						1: {ID: 1, Name: "deluge", Type: domain.DownloadClientTypeDelugeV2, Host: "127.0.0.1", Port: port},
+						2: {ID: 2, Name: "arr", Type: tt.clientType, Host: ts.URL, Settings: domain.DownloadClientSettings{APIKey: tt.apiKey}},
+					},
+				},
+			}
+
+			release := &domain.Release{
+				TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				TorrentTmpFile: archiveFixture,
+				Indexer:        "mock",
+			}
+
+			action := &domain.Action{Name: "deluge", Type: domain.ActionTypeDelugeV2, ClientID: 1, SavePath: tt.savePath, RescanClientID: 2}
+
+			// a failed rescan is logged, the torrent is already added
+			_, err := s.RunAction(context.Background(), action, release)
+			assert.NoError(t, err)
+
+			for _, cmd := range tt.wantCommands {
+				cmd["downloadClientId"] = strings.ToUpper(release.TorrentHash)
+			}
+
+			arr.m.Lock()
+			defer arr.m.Unlock()
+
+			assert.NotEmpty(t, release.TorrentHash)
+			assert.Equal(t, tt.wantCommands, arr.commands)
+		})
+	}
+}
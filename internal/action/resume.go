@@ -0,0 +1,231 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/transmission"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/rs/zerolog"
+)
+
+const resumeTimeout = 1 * time.Minute
+
+// resumeFunc resumes the paused torrent in the download client
+type resumeFunc func(ctx context.Context, pending domain.PendingResume) error
+
+// resumeScheduler resumes torrents that were added paused once the resume delay of the action has passed.
+// The pending resumes are stored so they still run after a restart, overdue ones run right away.
+type resumeScheduler struct {
+	log    zerolog.Logger
+	repo   domain.PendingResumeRepo
+	resume resumeFunc
+
+	// now and afterFunc can be replaced in tests
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func())
+
+	m         sync.Mutex
+	scheduled map[int]bool
+}
+
+func newResumeScheduler(log zerolog.Logger, repo domain.PendingResumeRepo, resume resumeFunc) *resumeScheduler {
+	return &resumeScheduler{
+		log:    log,
+		repo:   repo,
+		resume: resume,
+		now:    time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		scheduled: map[int]bool{},
+	}
+}
+
+// Schedule stores the pending resume of the torrent and starts the timer
+func (r *resumeScheduler) Schedule(ctx context.Context, action *domain.Action, hash string) error {
+	pending := &domain.PendingResume{
+		ClientID:    action.ClientID,
+		ActionID:    action.ID,
+		TorrentHash: hash,
+		ResumeAt:    r.now().Add(time.Duration(action.ResumeDelay) * time.Minute),
+	}
+
+	if err := r.repo.Store(ctx, pending); err != nil {
+		return errors.Wrap(err, "could not store pending resume for hash: %s", hash)
+	}
+
+	r.schedule(*pending)
+
+	return nil
+}
+
+// Recover starts the timers of the stored pending resumes
+func (r *resumeScheduler) Recover(ctx context.Context) error {
+	pending, err := r.repo.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list pending resumes")
+	}
+
+	for _, p := range pending {
+		r.schedule(p)
+	}
+
+	if len(pending) > 0 {
+		r.log.Debug().Msgf("recovered %d pending torrent resumes", len(pending))
+	}
+
+	return nil
+}
+
+func (r *resumeScheduler) schedule(pending domain.PendingResume) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.scheduled[pending.ID] {
+		return
+	}
+	r.scheduled[pending.ID] = true
+
+	delay := pending.ResumeAt.Sub(r.now())
+	if delay < 0 {
+		delay = 0
+	}
+
+	r.log.Debug().Msgf("resume torrent with hash %s scheduled in %s", pending.TorrentHash, delay)
+
+	r.afterFunc(delay, func() {
+		r.run(pending)
+	})
+}
+
+// run resumes the torrent and removes the pending resume, a failed resume is not retried
+func (r *resumeScheduler) run(pending domain.PendingResume) {
+	ctx, cancel := context.WithTimeout(context.Background(), resumeTimeout)
+	defer cancel()
+
+	if err := r.resume(ctx, pending); err != nil {
+		r.log.Error().Err(err).Msgf("could not resume torrent with hash: %s", pending.TorrentHash)
+	} else {
+		r.log.Info().Msgf("resumed torrent with hash: %s", pending.TorrentHash)
+	}
+
+	if err := r.repo.Delete(ctx, pending.ID); err != nil {
+		r.log.Error().Err(err).Msgf("could not delete pending resume: %d", pending.ID)
+	}
+
+	r.m.Lock()
+	delete(r.scheduled, pending.ID)
+	r.m.Unlock()
+}
+
+// scheduleResume schedules the resume of a torrent added paused by the action
+func (s *service) scheduleResume(ctx context.Context, action *domain.Action, release *domain.Release) {
+	if s.resumer == nil {
+		return
+	}
+
+	switch action.Type {
+	case domain.ActionTypeQbittorrent, domain.ActionTypeDelugeV1, domain.ActionTypeDelugeV2, domain.ActionTypeTransmission:
+	default:
+		s.log.Warn().Msgf("action %s: resume delay is not supported for %s, skipping", action.Name, action.Type)
+		return
+	}
+
+	if release.TorrentHash == "" {
+		if err := release.ComputeInfoHash(); err != nil || release.TorrentHash == "" {
+			s.log.Warn().Msgf("action %s: could not schedule resume, missing infohash for release: %s", action.Name, release.TorrentName)
+			return
+		}
+	}
+
+	if err := s.resumer.Schedule(ctx, action, release.TorrentHash); err != nil {
+		s.log.Error().Err(err).Msgf("action %s: could not schedule resume", action.Name)
+	}
+}
+
+// resumeTorrent resumes the torrent in the client of the pending resume
+func (s *service) resumeTorrent(ctx context.Context, pending domain.PendingResume) error {
+	client, err := s.clientSvc.FindByID(ctx, pending.ClientID)
+	if err != nil {
+		return errors.Wrap(err, "could not find client by id: %d", pending.ClientID)
+	}
+
+	if client == nil {
+		return errors.New("could not find client by id: %d", pending.ClientID)
+	}
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		c, err := s.clientSvc.GetCachedClient(ctx, pending.ClientID)
+		if err != nil {
+			return errors.Wrap(err, "could not get client: %s", client.Name)
+		}
+
+		return c.Qbt.ResumeCtx(ctx, []string{pending.TorrentHash})
+
+	case domain.DownloadClientTypeDelugeV1, domain.DownloadClientTypeDelugeV2:
+		settings := deluge.Settings{
+			Hostname:         client.Host,
+			Port:             uint(client.Port),
+			Login:            client.Username,
+			Password:         client.Password,
+			ReadWriteTimeout: time.Second * 30,
+		}
+
+		var del deluge.DelugeClient
+		if client.Type == domain.DownloadClientTypeDelugeV1 {
+			del = deluge.NewV1(settings)
+		} else {
+			del = deluge.NewV2(settings)
+		}
+
+		if err := del.Connect(ctx); err != nil {
+			return errors.Wrap(err, "could not connect to client %s at %s", client.Name, client.Host)
+		}
+
+		defer del.Close()
+
+		return del.ResumeTorrents(ctx, pending.TorrentHash)
+
+	case domain.DownloadClientTypeTransmission:
+		scheme := "http"
+		if client.TLS {
+			scheme = "https"
+		}
+
+		u, err := url.Parse(fmt.Sprintf("%s://%s:%d/transmission/rpc", scheme, client.Host, client.Port))
+		if err != nil {
+			return err
+		}
+
+		transport, err := s.clientSvc.GetTransport(client)
+		if err != nil {
+			return err
+		}
+
+		tbt, err := transmission.New(u, &transmission.Config{
+			UserAgent:     "autobrr",
+			Username:      client.Username,
+			Password:      client.Password,
+			TLSSkipVerify: client.TLSSkipVerify,
+			Transport:     transport,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error logging into client: %s", client.Host)
+		}
+
+		return tbt.TorrentStartHashes(ctx, []string{pending.TorrentHash})
+	}
+
+	return errors.New("resume is not supported for client type: %s", client.Type)
+}
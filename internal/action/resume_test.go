@@ -0,0 +1,204 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPendingResumeRepo keeps the pending resumes in memory, it outlives the schedulers like the database
+type mockPendingResumeRepo struct {
+	m       sync.Mutex
+	nextID  int
+	pending map[int]domain.PendingResume
+}
+
+func newMockPendingResumeRepo() *mockPendingResumeRepo {
+	return &mockPendingResumeRepo{pending: map[int]domain.PendingResume{}}
+}
+
+func (r *mockPendingResumeRepo) List(ctx context.Context) ([]domain.PendingResume, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	list := make([]domain.PendingResume, 0, len(r.pending))
+	for _, p := range r.pending {
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (r *mockPendingResumeRepo) Store(ctx context.Context, resume *domain.PendingResume) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.nextID++
+	resume.ID = r.nextID
+	r.pending[resume.ID] = *resume
+	return nil
+}
+
+func (r *mockPendingResumeRepo) Delete(ctx context.Context, id int) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.pending, id)
+	return nil
+}
+
+type scheduledResume struct {
+	delay time.Duration
+	run   func()
+}
+
+// newTestResumeScheduler returns a scheduler with a fake clock and the timers collected instead of started
+func newTestResumeScheduler(repo domain.PendingResumeRepo, now *time.Time, resumed *[]string) (*resumeScheduler, *[]scheduledResume) {
+	var timers []scheduledResume
+
+	r := newResumeScheduler(zerolog.Nop(), repo, func(ctx context.Context, pending domain.PendingResume) error {
+		*resumed = append(*resumed, pending.TorrentHash)
+		return nil
+	})
+	r.now = func() time.Time { return *now }
+	r.afterFunc = func(d time.Duration, f func()) { timers = append(timers, scheduledResume{delay: d, run: f}) }
+
+	return r, &timers
+}
+
+func TestResumeScheduler_Schedule(t *testing.T) {
+	repo := newMockPendingResumeRepo()
+	now := time.Date(2023, 11, 4, 12, 0, 0, 0, time.UTC)
+	var resumed []string
+
+	r, timers := newTestResumeScheduler(repo, &now, &resumed)
+
+	action := &domain.Action{ID: 3, ClientID: 1, Paused: true, ResumeDelay: 30}
+	assert.NoError(t, r.Schedule(context.Background(), action, "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"))
+
+	assert.Len(t, *timers, 1)
+	assert.Equal(t, 30*time.Minute, (*timers)[0].delay)
+	assert.Empty(t, resumed)
+
+	pending, _ := repo.List(context.Background())
+	assert.Len(t, pending, 1)
+	assert.Equal(t, now.Add(30*time.Minute), pending[0].ResumeAt)
+	assert.Equal(t, int32(1), pending[0].ClientID)
+	assert.Equal(t, 3, pending[0].ActionID)
+
+	// the timer fires after the delay
+	now = now.Add(30 * time.Minute)
+	(*timers)[0].run()
+
+	assert.Equal(t, []string{"3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"}, resumed)
+
+	pending, _ = repo.List(context.Background())
+	assert.Empty(t, pending)
+}
+
+func TestResumeScheduler_Recover(t *testing.T) {
+	tests := []struct {
+		name      string
+		downtime  time.Duration
+		wantDelay time.Duration
+	}{
+		{name: "remaining_delay", downtime: 10 * time.Minute, wantDelay: 20 * time.Minute},
+		{name: "overdue", downtime: time.Hour, wantDelay: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockPendingResumeRepo()
+			now := time.Date(2023, 11, 4, 12, 0, 0, 0, time.UTC)
+			var resumed []string
+
+			// the timer of the first run never fires, as if autobrr was stopped
+			r, _ := newTestResumeScheduler(repo, &now, &resumed)
+			assert.NoError(t, r.Schedule(context.Background(), &domain.Action{ClientID: 1, Paused: true, ResumeDelay: 30}, "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"))
+
+			now = now.Add(tt.downtime)
+
+			restarted, timers := newTestResumeScheduler(repo, &now, &resumed)
+			assert.NoError(t, restarted.Recover(context.Background()))
+
+			assert.Len(t, *timers, 1)
+			assert.Equal(t, tt.wantDelay, (*timers)[0].delay)
+
+			(*timers)[0].run()
+
+			assert.Equal(t, []string{"3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"}, resumed)
+
+			pending, _ := repo.List(context.Background())
+			assert.Empty(t, pending)
+		})
+	}
+}
+
+func Test_service_qbittorrent_resumeDelay(t *testing.T) {
+	ts, fake := newFakeQbittorrent(t, false)
+
+	hash := "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a"
+	client := &domain.DownloadClient{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Host: ts.URL}
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+		clientSvc: &mockDownloadClientService{
+			clients: map[int32]*domain.DownloadClient{1: client},
+			cached: map[int32]*domain.DownloadClientCached{
+				1: {Dc: client, Qbt: qbittorrent.NewClient(qbittorrent.Config{Host: ts.URL})},
+			},
+		},
+	}
+
+	repo := newMockPendingResumeRepo()
+	now := time.Date(2023, 11, 4, 12, 0, 0, 0, time.UTC)
+	var timers []scheduledResume
+
+	s.resumer = newResumeScheduler(s.log, repo, s.resumeTorrent)
+	s.resumer.now = func() time.Time { return now }
+	s.resumer.afterFunc = func(d time.Duration, f func()) { timers = append(timers, scheduledResume{delay: d, run: f}) }
+
+	torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+	assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+	release := &domain.Release{
+		TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		TorrentTmpFile: torrentFile,
+		TorrentHash:    hash,
+		Indexer:        "mock",
+	}
+
+	rejections, err := s.RunAction(context.Background(), &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, Paused: true, ResumeDelay: 30}, release)
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	fake.m.Lock()
+	assert.Equal(t, "true", fake.addForm["paused"])
+	assert.NotContains(t, fake.calls, "torrents/resume?"+hash)
+	fake.m.Unlock()
+
+	assert.Len(t, timers, 1)
+	assert.Equal(t, 30*time.Minute, timers[0].delay)
+
+	timers[0].run()
+
+	fake.m.Lock()
+	assert.Contains(t, fake.calls, "torrents/resume?"+hash)
+	fake.m.Unlock()
+
+	pending, _ := repo.List(context.Background())
+	assert.Empty(t, pending)
+}
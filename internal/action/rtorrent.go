@@ -13,7 +13,7 @@ import (
 	"github.com/autobrr/go-rtorrent"
 )
 
-func (s *service) rtorrent(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) rtorrent(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action rTorrent: %s", action.Name)
 
 	var err error
@@ -29,14 +29,24 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 		return nil, errors.New("could not find client by id: %d", action.ClientID)
 	}
 
-	var rejections []string
+	// rtorrent has no free space api
+	rejections, err := s.checkFreeSpace(ctx, action, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	// create config
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := rtorrent.Config{
 		Addr:          client.Host,
 		TLSSkipVerify: client.TLSSkipVerify,
 		BasicUser:     client.Settings.Basic.Username,
 		BasicPass:     client.Settings.Basic.Password,
+		Transport:     transport,
 	}
 
 	// create client
@@ -76,6 +86,8 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 			return nil, errors.Wrap(err, "could not add torrent from magnet: %s", release.MagnetURI)
 		}
 
+		release.ClientTorrentID = release.TorrentHash
+
 		s.log.Info().Msgf("torrent from magnet successfully added to client: '%s'", client.Name)
 
 		return nil, nil
@@ -126,6 +138,11 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 			return nil, errors.Wrap(err, "could not add torrent file: %s", release.TorrentTmpFile)
 		}
 
+		// rtorrent identifies torrents by infohash
+		if err := release.ComputeInfoHash(); err == nil {
+			release.ClientTorrentID = release.TorrentHash
+		}
+
 		s.log.Info().Msgf("torrent successfully added to client: '%s'", client.Name)
 	}
 
@@ -7,15 +7,20 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
@@ -24,8 +29,17 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 	var (
 		err        error
 		rejections []string
+		response   string
 	)
 
+	result := domain.NewActionResult(action, release)
+
+	// emit one result per action run, including the early returns
+	defer func() {
+		s.storeResult(result, action, release, rejections, response, err)
+		s.trackFailure(action, release, err)
+	}()
+
 	defer func() {
 		if r := recover(); r != nil {
 			s.log.Error().Msgf("recovering from panic in run action %s error: %v", action.Name, r)
@@ -34,17 +48,56 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		}
 	}()
 
+	// resolve the action template before using any of the action config
+	if err = s.applyTemplate(ctx, action); err != nil {
+		return nil, err
+	}
+
+	// cancel exec commands, http requests and client calls if the action takes too long
+	if action.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(action.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	// give the earlier actions of the filter time to finish, like a torrent to be registered before a webhook
+	if action.Delay > 0 {
+		s.log.Debug().Msgf("action %s: delaying run by %d seconds", action.Name, action.Delay)
+
+		if err = s.wait(ctx, time.Duration(action.Delay)*time.Second); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = errors.Wrap(domain.ErrActionTimeout, "action %s exceeded timeout of %d seconds while waiting the delay of %d seconds", action.Name, action.Timeout, action.Delay)
+			}
+			return nil, err
+		}
+	}
+
 	// if set, try to resolve MagnetURI before parsing macros
 	// to allow webhook and exec to get the magnet_uri
-	if err := release.ResolveMagnetUri(ctx); err != nil {
+	if err = release.ResolveMagnetUri(ctx); err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.findMacroOverrides(ctx, release.Indexer)
+	if err != nil {
 		return nil, err
 	}
 
+	// the proxied url lets actions download the torrent through autobrr without the indexer keys
+	if release.ID > 0 && release.ProxiedDownloadURL == "" && s.sessionSecret != "" {
+		release.ProxiedDownloadURL = domain.ProxiedDownloadURL(s.serverURL, s.sessionSecret, release.ID, time.Now().Add(domain.ProxiedDownloadTTL))
+	}
+
 	// parse all macros in one go
-	if err := action.ParseMacros(release); err != nil {
+	if err = action.ParseMacros(release, overrides...); err != nil {
 		return nil, err
 	}
 
+	// the comment is written into the torrent file, only qBittorrent and Transmission add it from there
+	if action.Comment != "" && action.Type != domain.ActionTypeQbittorrent && action.Type != domain.ActionTypeTransmission {
+		s.log.Warn().Msgf("action %s: comment is not supported for %s, skipping it", action.Name, action.Type)
+	}
+
 	switch action.Type {
 	case domain.ActionTypeTest:
 		s.test(action.Name)
@@ -55,23 +108,29 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 	case domain.ActionTypeWatchFolder:
 		err = s.watchFolder(ctx, action, *release)
 
+	case domain.ActionTypeArchiveTorrent:
+		err = s.archiveTorrent(ctx, action, *release)
+
 	case domain.ActionTypeWebhook:
-		err = s.webhook(ctx, action, *release)
+		response, err = s.webhook(ctx, action, *release)
+
+	case domain.ActionTypeGRPC:
+		response, err = s.grpc(ctx, action, *release)
 
 	case domain.ActionTypeDelugeV1, domain.ActionTypeDelugeV2:
-		rejections, err = s.deluge(ctx, action, *release)
+		rejections, err = s.deluge(ctx, action, release)
 
 	case domain.ActionTypeQbittorrent:
-		rejections, err = s.qbittorrent(ctx, action, *release)
+		rejections, err = s.qbittorrent(ctx, action, release)
 
 	case domain.ActionTypeRTorrent:
-		rejections, err = s.rtorrent(ctx, action, *release)
+		rejections, err = s.rtorrent(ctx, action, release)
 
 	case domain.ActionTypeTransmission:
-		rejections, err = s.transmission(ctx, action, *release)
+		rejections, err = s.transmission(ctx, action, release)
 
 	case domain.ActionTypePorla:
-		rejections, err = s.porla(ctx, action, *release)
+		rejections, err = s.porla(ctx, action, release)
 
 	case domain.ActionTypeRadarr:
 		rejections, err = s.radarr(ctx, action, *release)
@@ -89,10 +148,33 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		rejections, err = s.readarr(ctx, action, *release)
 
 	case domain.ActionTypeSabnzbd:
-		rejections, err = s.sabnzbd(ctx, action, *release)
+		rejections, err = s.sabnzbd(ctx, action, release)
+
+	case domain.ActionTypeCleanup:
+		response, err = s.cleanup(ctx, action)
 
 	default:
-		return nil, errors.New("unsupported action type: %s", action.Type)
+		err = errors.New("unsupported action type: %s", action.Type)
+		return nil, err
+	}
+
+	// the release service runs the action again later, so it's not announced as failed
+	if errors.Is(err, domain.ErrMaxActiveDownloads) {
+		return nil, err
+	}
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = errors.Wrap(domain.ErrActionTimeout, "action %s exceeded timeout of %d seconds: %v", action.Name, action.Timeout, err)
+	}
+
+	// torrents added paused are resumed in the background once the delay has passed
+	if err == nil && rejections == nil && action.Paused && action.ResumeDelay > 0 {
+		s.scheduleResume(ctx, action, release)
+	}
+
+	// let sonarr or radarr pick up the torrent added by the action
+	if err == nil && rejections == nil && action.RescanClientID > 0 {
+		s.triggerRescan(ctx, action, release)
 	}
 
 	payload := &domain.NotificationPayload{
@@ -100,11 +182,13 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		ReleaseName:    release.TorrentName,
 		Filter:         release.FilterName,
 		Indexer:        release.Indexer,
+		InfoURL:        release.InfoURL,
 		InfoHash:       release.TorrentHash,
 		Size:           release.Size,
 		Status:         domain.ReleasePushStatusApproved,
 		Action:         action.Name,
 		ActionType:     action.Type,
+		ActionOutcome:  domain.ActionResultStatusSuccess,
 		Rejections:     []string{},
 		Protocol:       release.Protocol,
 		Implementation: release.Implementation,
@@ -120,15 +204,43 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 
 		payload.Event = domain.NotificationEventPushError
 		payload.Status = domain.ReleasePushStatusErr
+		payload.ActionOutcome = domain.ActionResultStatusFailed
 		payload.Rejections = []string{err.Error()}
+
+		if errors.Is(err, domain.ErrActionTimeout) {
+			payload.Status = domain.ReleasePushStatusTimeout
+		}
 	}
 
 	if rejections != nil {
 		payload.Event = domain.NotificationEventPushRejected
 		payload.Status = domain.ReleasePushStatusRejected
+		payload.ActionOutcome = domain.ActionResultStatusSkipped
 		payload.Rejections = rejections
 	}
 
+	// summarize the earlier actions of the filter together with this one
+	for _, status := range release.ActionStatus {
+		if status.FilterID == int64(release.FilterID) {
+			payload.ActionStatuses = append(payload.ActionStatuses, status)
+		}
+	}
+
+	if len(payload.ActionStatuses) > 0 {
+		payload.ActionStatuses = append(payload.ActionStatuses, domain.ReleaseActionStatus{
+			Status:     payload.Status,
+			Action:     action.Name,
+			ActionID:   int64(action.ID),
+			Type:       action.Type,
+			Client:     payload.ActionClient,
+			Filter:     release.FilterName,
+			FilterID:   int64(release.FilterID),
+			Rejections: payload.Rejections,
+			ReleaseID:  release.ID,
+			Timestamp:  payload.Timestamp,
+		})
+	}
+
 	// send separate event for notifications
 	s.bus.Publish("events:notification", &payload.Event, payload)
 
@@ -172,29 +284,71 @@ func (s *service) watchFolder(ctx context.Context, action *domain.Action, releas
 		return errors.Wrap(err, "could not create new folders %v", dir)
 	}
 
-	// Create new file
-	newFile, err := os.Create(newFileName)
+	// write to a tmp file in the same dir and rename it into place,
+	// so clients watching the folder never pick up a partially written torrent
+	if err := writeFileAtomic(newFileName, release.TorrentDataRawBytes); err != nil {
+		return errors.Wrap(err, "could not write file %v to watch folder", newFileName)
+	}
+
+	s.log.Info().Msgf("saved file to watch folder: %v", newFileName)
+
+	return nil
+}
+
+// writeFileAtomic writes data to a tmp file next to the target and renames it into place
+func writeFileAtomic(name string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(name), ".autobrr-*.tmp")
 	if err != nil {
-		return errors.Wrap(err, "could not create new file %v", newFileName)
+		return errors.Wrap(err, "could not create tmp file")
+	}
+
+	tmpName := tmpFile.Name()
+
+	if _, err := io.Copy(tmpFile, bytes.NewReader(data)); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpName)
+		return errors.Wrap(err, "could not write tmp file %v", tmpName)
 	}
-	defer newFile.Close()
 
-	// Copy file
-	if _, err := io.Copy(newFile, bytes.NewReader(release.TorrentDataRawBytes)); err != nil {
-		return errors.Wrap(err, "could not copy file %v to watch folder", newFileName)
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpName)
+		return errors.Wrap(err, "could not sync tmp file %v", tmpName)
 	}
 
-	s.log.Info().Msgf("saved file to watch folder: %v", newFileName)
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpName)
+		return errors.Wrap(err, "could not close tmp file %v", tmpName)
+	}
+
+	// CreateTemp uses 0600, match the permissions os.Create would have used
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		os.Remove(tmpName)
+		return errors.Wrap(err, "could not set permissions on tmp file %v", tmpName)
+	}
+
+	if err := os.Rename(tmpName, name); err != nil {
+		os.Remove(tmpName)
+		return errors.Wrap(err, "could not rename tmp file %v to %v", tmpName, name)
+	}
 
 	return nil
 }
 
-func (s *service) webhook(ctx context.Context, action *domain.Action, release domain.Release) error {
+func (s *service) webhook(ctx context.Context, action *domain.Action, release domain.Release) (string, error) {
 	s.log.Trace().Msgf("action WEBHOOK: '%s' file: %s", action.Name, release.TorrentName)
-	if len(action.WebhookData) > 1024 {
-		s.log.Trace().Msgf("webhook action '%s' - host: %s data: %s", action.Name, action.WebhookHost, action.WebhookData[:1024])
+
+	// redact before truncating so no part of the download url ends up in the logs
+	logHost := redactDownloadURL(action.WebhookHost, release)
+	logData := redactDownloadURL(action.WebhookData, release)
+	if len(logData) > 1024 {
+		s.log.Trace().Msgf("webhook action '%s' - host: %s data: %s", action.Name, logHost, logData[:1024])
 	} else {
-		s.log.Trace().Msgf("webhook action '%s' - host: %s data: %s", action.Name, action.WebhookHost, action.WebhookData)
+		s.log.Trace().Msgf("webhook action '%s' - host: %s data: %s", action.Name, logHost, logData)
+	}
+
+	if err := s.policy.checkURL(action.WebhookHost); err != nil {
+		return "", err
 	}
 
 	t := &http.Transport{
@@ -203,30 +357,278 @@ func (s *service) webhook(ctx context.Context, action *domain.Action, release do
 		},
 	}
 
+	if s.policy != nil {
+		t.DialContext = s.policy.dialContext
+	}
+
 	client := http.Client{Transport: t, Timeout: 120 * time.Second}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.WebhookHost, bytes.NewBufferString(action.WebhookData))
+	var (
+		reqBody     io.Reader = bytes.NewBufferString(action.WebhookData)
+		contentType           = "application/json"
+	)
+
+	if action.WebhookFileField != "" {
+		multipartBody, multipartContentType, err := newWebhookMultipartBody(action, &release)
+		if err != nil {
+			return "", errors.Wrap(err, "could not build multipart body for webhook action %s", action.Name)
+		}
+
+		reqBody, contentType = multipartBody, multipartContentType
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.WebhookHost, reqBody)
 	if err != nil {
-		return errors.Wrap(err, "could not build request for webhook")
+		return "", errors.Wrap(err, "could not build request for webhook")
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", "autobrr")
 
+	for _, header := range action.WebhookHeaders {
+		name, value, found := strings.Cut(header, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		// the multipart boundary is part of the content type
+		if action.WebhookFileField != "" && strings.EqualFold(strings.TrimSpace(name), "Content-Type") {
+			continue
+		}
+
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	if action.WebhookValidateJSON && strings.Contains(strings.ToLower(req.Header.Get("Content-Type")), "json") {
+		if err := validateJSON(action.WebhookData); err != nil {
+			return "", errors.Wrap(err, "webhook data for action %s is not valid json", action.Name)
+		}
+	}
+
+	s.log.Trace().Msgf("webhook action '%s' - headers: %s", action.Name, redactHeaders(req.Header))
+
 	start := time.Now()
 
 	res, err := client.Do(req)
 	if err != nil {
-		return errors.Wrap(err, "could not make request for webhook")
+		// the error includes the request url, which can have the download url as a query value
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactDownloadURL(urlErr.URL, release)
+		}
+		return "", errors.Wrap(err, "could not make request for webhook")
 	}
 
 	defer res.Body.Close()
 
-	if len(action.WebhookData) > 256 {
-		s.log.Info().Msgf("successfully ran webhook action: '%s' to: %s payload: %s finished in %s", action.Name, action.WebhookHost, action.WebhookData[:256], time.Since(start))
+	// the success rule needs the whole json document, the stored response is still truncated
+	limit := int64(1024)
+	if action.WebhookSuccessWhen != "" {
+		limit = 1 << 20
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, limit))
+	if err != nil {
+		return "", errors.Wrap(err, "could not read webhook response")
+	}
+
+	response := strings.TrimSpace(string(body))
+	if len(response) > 1024 {
+		response = response[:1024]
+	}
+	response = fmt.Sprintf("%d %s", res.StatusCode, response)
+
+	if action.WebhookSuccessWhen != "" {
+		rule, err := domain.ParseWebhookSuccessRule(action.WebhookSuccessWhen)
+		if err != nil {
+			return response, errors.Wrap(err, "webhook action %s", action.Name)
+		}
+
+		ok, err := rule.Match(body)
+		if err != nil {
+			return response, errors.Wrap(err, "webhook action %s failed: %s", action.Name, response)
+		}
+
+		if !ok {
+			return response, errors.New("webhook action %s failed, response does not match %q: %s", action.Name, action.WebhookSuccessWhen, response)
+		}
+	}
+
+	if len(logData) > 256 {
+		s.log.Info().Msgf("successfully ran webhook action: '%s' to: %s payload: %s finished in %s", action.Name, logHost, logData[:256], time.Since(start))
 	} else {
-		s.log.Info().Msgf("successfully ran webhook action: '%s' to: %s payload: %s finished in %s", action.Name, action.WebhookHost, action.WebhookData, time.Since(start))
+		s.log.Info().Msgf("successfully ran webhook action: '%s' to: %s payload: %s finished in %s", action.Name, logHost, logData, time.Since(start))
 	}
 
-	return nil
+	return response, nil
+}
+
+// newWebhookMultipartBody attaches the torrent file as the file field of the action. The webhook data is a json object
+// of metadata fields, string values are sent as is and other values as json.
+func newWebhookMultipartBody(action *domain.Action, release *domain.Release) (*bytes.Buffer, string, error) {
+	var fields map[string]json.RawMessage
+	if strings.TrimSpace(action.WebhookData) != "" {
+		if err := json.Unmarshal([]byte(action.WebhookData), &fields); err != nil {
+			return nil, "", errors.Wrap(err, "webhook data must be a json object of form fields")
+		}
+	}
+
+	data := release.TorrentDataRawBytes
+	if len(data) == 0 {
+		if release.TorrentTmpFile == "" {
+			return nil, "", errors.New("torrent file for release %s is not downloaded", release.TorrentName)
+		}
+
+		var err error
+		data, err = os.ReadFile(release.TorrentTmpFile)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "could not read torrent file: %s", release.TorrentTmpFile)
+		}
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	// write the fields in a stable order
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := string(fields[name])
+
+		var str string
+		if err := json.Unmarshal(fields[name], &str); err == nil {
+			value = str
+		}
+
+		if err := w.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	part, err := w.CreateFormFile(action.WebhookFileField, domain.SanitizeFilename(release.TorrentName)+".torrent")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body, w.FormDataContentType(), nil
+}
+
+// validateJSON returns an error with the line and column of the first syntax error
+func validateJSON(data string) error {
+	var v any
+	err := json.Unmarshal([]byte(data), &v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	// offset points just past the invalid byte
+	offset := int(syntaxErr.Offset)
+	if offset > 0 {
+		offset--
+	}
+
+	line, column := 1, 1
+	for _, r := range data[:offset] {
+		if r == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+
+	return errors.New("%s at line %d column %d", syntaxErr.Error(), line, column)
+}
+
+// redactDownloadURL masks the download url of the release in the text, it often contains the passkey of the indexer
+func redactDownloadURL(text string, release domain.Release) string {
+	return domain.RedactDownloadURL(text, release.DownloadURL)
+}
+
+// sensitiveHeaders are masked when headers are logged
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"Api-Key",
+	"X-Auth-Token",
+	"X-Access-Token",
+	"X-Plex-Token",
+	"X-Emby-Token",
+}
+
+// redactHeaders formats the headers for logging with the values of sensitive headers masked
+func redactHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header.Values(name), ", ")
+
+		for _, sensitive := range sensitiveHeaders {
+			if strings.EqualFold(name, sensitive) {
+				value = "<redacted>"
+				break
+			}
+		}
+
+		parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// storeResult persists the outcome of the action run so it can be looked up per release
+func (s *service) storeResult(result *domain.ActionResult, action *domain.Action, release *domain.Release, rejections []string, response string, err error) {
+	result.Duration = time.Since(result.StartedAt)
+	result.Fields = action.ResolvedFields(release)
+	result.Response = redactDownloadURL(response, *release)
+
+	switch {
+	case errors.Is(err, domain.ErrMaxActiveDownloads):
+		result.Status = domain.ActionResultStatusSkipped
+		result.Rejections = []string{err.Error()}
+	case err != nil:
+		result.Status = domain.ActionResultStatusFailed
+		result.Error = redactDownloadURL(err.Error(), *release)
+	case len(rejections) > 0:
+		result.Status = domain.ActionResultStatusSkipped
+		result.Rejections = rejections
+	default:
+		result.Status = domain.ActionResultStatusSuccess
+	}
+
+	metrics.ActionRun(string(action.Type), strings.ToLower(string(result.Status)), result.Duration)
+
+	// results need a stored release to be attached to
+	if s.resultRepo == nil || result.ReleaseID == 0 {
+		return
+	}
+
+	if err := s.resultRepo.Store(context.Background(), result); err != nil {
+		s.log.Error().Err(err).Msgf("could not store action result for action: %s", action.Name)
+	}
 }
@@ -0,0 +1,760 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_RunAction_timeout(t *testing.T) {
+	done := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	tests := []struct {
+		name   string
+		action *domain.Action
+	}{
+		{
+			name: "webhook",
+			action: &domain.Action{
+				Name:        "slow webhook",
+				Type:        domain.ActionTypeWebhook,
+				WebhookHost: ts.URL,
+				WebhookData: `{"release":"{{ .TorrentName }}"}`,
+				Timeout:     1,
+			},
+		},
+		{
+			name: "exec",
+			action: &domain.Action{
+				Name:     "slow exec",
+				Type:     domain.ActionTypeExec,
+				ExecCmd:  "sleep",
+				ExecArgs: "10",
+				Timeout:  1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.action.Type == domain.ActionTypeExec {
+				if _, err := exec.LookPath(tt.action.ExecCmd); err != nil {
+					t.Skip("sleep not available")
+				}
+			}
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+			}
+
+			release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"}
+
+			start := time.Now()
+			_, err := s.RunAction(context.Background(), tt.action, release)
+
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, domain.ErrActionTimeout))
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func Test_service_watchFolder_atomic(t *testing.T) {
+	dir := t.TempDir()
+
+	// large enough that a non-atomic write would be observable
+	data := make([]byte, 8<<20)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+	}
+
+	action := &domain.Action{
+		Name:        "watch",
+		Type:        domain.ActionTypeWatchFolder,
+		WatchFolder: filepath.Join(dir, "release.torrent"),
+	}
+	release := domain.Release{
+		TorrentName:         "Sally Goes to the Mall S04E29",
+		TorrentTmpFile:      "autobrr-12345",
+		TorrentDataRawBytes: data,
+	}
+
+	var (
+		wg      sync.WaitGroup
+		done    = make(chan struct{})
+		partial []int
+	)
+
+	// watch the folder like a download client would and record any partial torrent files
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			entries, _ := os.ReadDir(dir)
+			for _, e := range entries {
+				if !strings.HasSuffix(e.Name(), ".torrent") {
+					continue
+				}
+				if info, err := e.Info(); err == nil && info.Size() != int64(len(data)) {
+					partial = append(partial, int(info.Size()))
+				}
+			}
+		}
+	}()
+
+	err := s.watchFolder(context.Background(), action, release)
+	close(done)
+	wg.Wait()
+
+	assert.NoError(t, err)
+	assert.Empty(t, partial)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "release.torrent", entries[0].Name())
+
+	written, err := os.ReadFile(filepath.Join(dir, "release.torrent"))
+	assert.NoError(t, err)
+	assert.Equal(t, data, written)
+}
+
+func Test_service_watchFolder_sanitizedName(t *testing.T) {
+	dir := t.TempDir()
+	watchDir := filepath.Join(dir, "watch")
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+	}
+
+	action := &domain.Action{
+		Name:        "watch",
+		Type:        domain.ActionTypeWatchFolder,
+		WatchFolder: watchDir + "/{{ .TorrentName }}.torrent",
+	}
+	release := &domain.Release{
+		TorrentName:         "../../Sally/Goes to the Mall S04E29",
+		TorrentTmpFile:      "autobrr-12345",
+		TorrentDataRawBytes: []byte("d4:infod6:lengthi1eee"),
+	}
+
+	err := action.ParseMacros(release)
+	assert.NoError(t, err)
+
+	err = s.watchFolder(context.Background(), action, *release)
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(watchDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, ".._.._Sally_Goes to the Mall S04E29.torrent", entries[0].Name())
+
+	// nothing escaped the watch dir
+	entries, err = os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+type mockDownloadClientService struct {
+	download_client.Service
+	clients map[int32]*domain.DownloadClient
+	cached  map[int32]*domain.DownloadClientCached
+}
+
+func (m *mockDownloadClientService) FindByID(ctx context.Context, id int32) (*domain.DownloadClient, error) {
+	return m.clients[id], nil
+}
+
+func (m *mockDownloadClientService) GetCachedClient(ctx context.Context, id int32) (*domain.DownloadClientCached, error) {
+	return m.cached[id], nil
+}
+
+func (m *mockDownloadClientService) GetTransport(client *domain.DownloadClient) (http.RoundTripper, error) {
+	return nil, nil
+}
+
+func Test_service_RunAction_clientTorrentID(t *testing.T) {
+	sab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"nzo_ids":["SABnzbd_nzo_kyt1f0"]}`))
+	}))
+	defer sab.Close()
+
+	var body []byte
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer hook.Close()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+		clientSvc: &mockDownloadClientService{
+			clients: map[int32]*domain.DownloadClient{
+				1: {ID: 1, Name: "sab", Type: domain.DownloadClientTypeSabnzbd, Host: sab.URL, Settings: domain.DownloadClientSettings{APIKey: "secret"}},
+			},
+		},
+	}
+
+	release := &domain.Release{
+		TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+		Indexer:     "mock",
+		Protocol:    domain.ReleaseProtocolNzb,
+		DownloadURL: "https://indexer.example.com/getnzb/1",
+	}
+
+	add := &domain.Action{Name: "sab", Type: domain.ActionTypeSabnzbd, ClientID: 1}
+	notify := &domain.Action{
+		Name:        "notify",
+		Type:        domain.ActionTypeWebhook,
+		WebhookHost: hook.URL,
+		WebhookData: `{"id":"{{ .ClientTorrentID }}","name":"{{ .TorrentName }}"}`,
+	}
+
+	_, err := s.RunAction(context.Background(), add, release)
+	assert.NoError(t, err)
+	assert.Equal(t, "SABnzbd_nzo_kyt1f0", release.ClientTorrentID)
+
+	_, err = s.RunAction(context.Background(), notify, release)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"SABnzbd_nzo_kyt1f0","name":"That.Show.S01E01.1080p.WEB-DL-GROUP"}`, string(body))
+}
+
+func Test_service_webhook_headers(t *testing.T) {
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+
+	s := &service{
+		log: zerolog.New(&buf).Level(zerolog.TraceLevel),
+		bus: EventBus.New(),
+	}
+
+	action := &domain.Action{
+		Name:        "webhook",
+		Type:        domain.ActionTypeWebhook,
+		WebhookHost: ts.URL,
+		WebhookData: `{"release":"{{ .TorrentName }}"}`,
+		WebhookHeaders: []string{
+			"X-Indexer={{ .Indexer }}",
+			"Authorization=Bearer {{ .Indexer }}-s3cr3t",
+			"X-Api-Key=abc=123",
+		},
+	}
+
+	release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"}
+
+	_, err := s.RunAction(context.Background(), action, release)
+	assert.NoError(t, err)
+
+	// templated headers are expanded and sent
+	assert.Equal(t, "mock", header.Get("X-Indexer"))
+	assert.Equal(t, "Bearer mock-s3cr3t", header.Get("Authorization"))
+	assert.Equal(t, "abc=123", header.Get("X-Api-Key"))
+
+	// sensitive headers are masked in the trace output
+	out := buf.String()
+	assert.Contains(t, out, "X-Indexer: mock")
+	assert.Contains(t, out, "Authorization: <redacted>")
+	assert.Contains(t, out, "X-Api-Key: <redacted>")
+	assert.NotContains(t, out, "s3cr3t")
+	assert.NotContains(t, out, "abc=123")
+}
+
+func Test_service_webhook_validateJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headers     []string
+		wantErr     string
+		wantRequest bool
+	}{
+		{
+			name:        "valid",
+			data:        `{"name":"{{ .TorrentName }}","indexer":"{{ .Indexer }}"}`,
+			wantRequest: true,
+		},
+		{
+			name:    "unquoted_macro",
+			data:    "{\n  \"name\": {{ .TorrentName }}\n}",
+			wantErr: "line 2 column 11",
+		},
+		{
+			name:    "trailing_comma",
+			data:    `{"name":"{{ .TorrentName }}",}`,
+			wantErr: "line 1 column 41",
+		},
+		{
+			name:        "not_json_content_type",
+			data:        `name={{ .TorrentName }}`,
+			headers:     []string{"Content-Type=application/x-www-form-urlencoded"},
+			wantRequest: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := false
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = true
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+			}
+
+			action := &domain.Action{
+				Name:                "webhook",
+				Type:                domain.ActionTypeWebhook,
+				WebhookHost:         ts.URL,
+				WebhookData:         tt.data,
+				WebhookHeaders:      tt.headers,
+				WebhookValidateJSON: true,
+			}
+
+			release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"}
+
+			_, err := s.RunAction(context.Background(), action, release)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantRequest, requested)
+		})
+	}
+}
+
+func Test_service_webhook_successWhen(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		successWhen string
+		wantErr     bool
+	}{
+		{name: "no_rule", response: `{"status":"rejected"}`},
+		{name: "rejected", response: `{"status":"rejected"}`, successWhen: "status=accepted", wantErr: true},
+		{name: "accepted", response: `{"status":"accepted"}`, successWhen: "status=accepted"},
+		{name: "not_json", response: `rejected`, successWhen: "status=accepted", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer ts.Close()
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+			}
+
+			action := &domain.Action{
+				Name:               "webhook",
+				Type:               domain.ActionTypeWebhook,
+				WebhookHost:        ts.URL,
+				WebhookData:        `{"name":"{{ .TorrentName }}"}`,
+				WebhookSuccessWhen: tt.successWhen,
+			}
+
+			release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"}
+
+			_, err := s.RunAction(context.Background(), action, release)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.response)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_service_webhook_filterMacros(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer ts.Close()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+	}
+
+	action := &domain.Action{
+		Name:        "webhook",
+		Type:        domain.ActionTypeWebhook,
+		WebhookHost: ts.URL,
+		WebhookData: `{"filter":"{{ .FilterName }}","filter_id":{{ .FilterID }},"indexer":"{{ .Indexer }}"}`,
+		FilterID:    42,
+	}
+
+	// set the same way the release service does when a filter matches
+	f := &domain.Filter{ID: 42, Name: "tv-1080p"}
+	release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock", Filter: f, FilterName: f.Name, FilterID: f.ID}
+
+	_, err := s.RunAction(context.Background(), action, release)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"filter":"tv-1080p","filter_id":42,"indexer":"mock"}`, body)
+}
+
+func Test_service_webhook_multipartTorrent(t *testing.T) {
+	fixture, err := os.ReadFile(archiveFixture)
+	assert.NoError(t, err)
+
+	// the torrent file is only downloaded because the webhook attaches it
+	tracker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archiveFixture)
+	}))
+	defer tracker.Close()
+
+	var (
+		fields   map[string][]string
+		fileName string
+		file     []byte
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fields = r.MultipartForm.Value
+
+		f, header, err := r.FormFile("torrent")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+
+		fileName = header.Filename
+		file, _ = io.ReadAll(f)
+	}))
+	defer ts.Close()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+	}
+
+	action := &domain.Action{
+		Name:             "webhook",
+		Type:             domain.ActionTypeWebhook,
+		WebhookHost:      ts.URL,
+		WebhookData:      `{"indexer":"{{ .Indexer }}","name":"{{ .TorrentName }}","filter_id":{{ .FilterID }}}`,
+		WebhookHeaders:   []string{"Content-Type=application/json"},
+		WebhookFileField: "torrent",
+	}
+
+	release := &domain.Release{
+		TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		DownloadURL: tracker.URL + "/download/1",
+		Indexer:     "mock",
+		FilterID:    42,
+		Protocol:    domain.ReleaseProtocolTorrent,
+	}
+	defer release.CleanupTemporaryFiles()
+
+	rejections, err := s.RunAction(context.Background(), action, release)
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	assert.Equal(t, map[string][]string{
+		"indexer":   {"mock"},
+		"name":      {"That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"},
+		"filter_id": {"42"},
+	}, fields)
+	assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP.torrent", fileName)
+	assert.Equal(t, fixture, file)
+}
+
+func Test_service_RunAction_actionStatuses(t *testing.T) {
+	bus := EventBus.New()
+
+	var payloads []*domain.NotificationPayload
+	assert.NoError(t, bus.Subscribe("events:notification", func(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
+		payloads = append(payloads, payload)
+	}))
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: bus,
+	}
+
+	release := &domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", Indexer: "mock", FilterName: "tv", FilterID: 2}
+
+	// first action of the filter, nothing to summarize yet
+	_, err := s.RunAction(context.Background(), &domain.Action{Name: "first", Type: domain.ActionTypeTest}, release)
+	assert.NoError(t, err)
+	assert.Empty(t, payloads[0].ActionStatuses)
+
+	// set by the release service after each action, statuses of other filters are left out
+	release.ActionStatus = []domain.ReleaseActionStatus{
+		{Action: "other filter", Type: domain.ActionTypeTest, FilterID: 1, Status: domain.ReleasePushStatusApproved},
+		{Action: "first", Type: domain.ActionTypeTest, FilterID: 2, Status: domain.ReleasePushStatusApproved},
+	}
+
+	_, err = s.RunAction(context.Background(), &domain.Action{Name: "second", Type: domain.ActionTypeTest}, release)
+	assert.NoError(t, err)
+
+	statuses := payloads[1].ActionStatuses
+	if assert.Len(t, statuses, 2) {
+		assert.Equal(t, "first", statuses[0].Action)
+		assert.Equal(t, "second", statuses[1].Action)
+		assert.Equal(t, domain.ReleasePushStatusApproved, statuses[1].Status)
+	}
+}
+
+// scrapeMetric returns the value of the series from the metrics endpoint, 0 if it's not there yet
+func scrapeMetric(t *testing.T, series string) float64 {
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if v, ok := strings.CutPrefix(line, series+" "); ok {
+			value, err := strconv.ParseFloat(v, 64)
+			assert.NoError(t, err)
+			return value
+		}
+	}
+
+	return 0
+}
+
+func Test_service_RunAction_metrics(t *testing.T) {
+	const (
+		testSuccess     = `autobrr_actions_total{outcome="success",type="TEST"}`
+		webhookFailed   = `autobrr_actions_total{outcome="failed",type="WEBHOOK"}`
+		testDurationCnt = `autobrr_action_duration_seconds_count{type="TEST"}`
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"rejected"}`))
+	}))
+	defer ts.Close()
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+	}
+
+	beforeSuccess, beforeFailed, beforeCount := scrapeMetric(t, testSuccess), scrapeMetric(t, webhookFailed), scrapeMetric(t, testDurationCnt)
+
+	release := &domain.Release{TorrentName: "Sally Goes to the Mall S04E29", Indexer: "mock"}
+
+	for i := 0; i < 2; i++ {
+		_, err := s.RunAction(context.Background(), &domain.Action{Name: "test", Type: domain.ActionTypeTest}, release)
+		assert.NoError(t, err)
+	}
+
+	_, err := s.RunAction(context.Background(), &domain.Action{Name: "webhook", Type: domain.ActionTypeWebhook, WebhookHost: ts.URL, WebhookSuccessWhen: "status=accepted"}, release)
+	assert.Error(t, err)
+
+	assert.Equal(t, beforeSuccess+2, scrapeMetric(t, testSuccess))
+	assert.Equal(t, beforeFailed+1, scrapeMetric(t, webhookFailed))
+	assert.Equal(t, beforeCount+2, scrapeMetric(t, testDurationCnt))
+}
+
+func Test_service_RunAction_arrTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		actionType domain.ActionType
+		clientType domain.DownloadClientType
+	}{
+		{name: "radarr", actionType: domain.ActionTypeRadarr, clientType: domain.DownloadClientTypeRadarr},
+		{name: "sonarr", actionType: domain.ActionTypeSonarr, clientType: domain.DownloadClientTypeSonarr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var push map[string]any
+			arr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v3/release/push", r.URL.Path)
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&push))
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[{"approved":true,"rejected":false,"rejections":[]}]`))
+			}))
+			defer arr.Close()
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{
+					clients: map[int32]*domain.DownloadClient{
+						1: {ID: 1, Name: tt.name, Type: tt.clientType, Host: arr.URL, Settings: domain.DownloadClientSettings{APIKey: "secret"}},
+					},
+				},
+			}
+
+			release := &domain.Release{
+				TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+				Indexer:     "mock",
+				Resolution:  "1080p",
+				Source:      "WEB-DL",
+				Protocol:    domain.ReleaseProtocolTorrent,
+				DownloadURL: "https://indexer.example.com/download/1",
+			}
+
+			action := &domain.Action{
+				Name:     tt.name,
+				Type:     tt.actionType,
+				ClientID: 1,
+				Tags:     "autobrr, {{ .Resolution }}",
+				Label:    "{{ .Source }}",
+			}
+
+			rejections, err := s.RunAction(context.Background(), action, release)
+			assert.NoError(t, err)
+			assert.Empty(t, rejections)
+
+			assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL-GROUP", push["title"])
+			assert.Equal(t, []any{"autobrr", "1080p", "WEB-DL"}, push["tags"])
+		})
+	}
+}
+
+func Test_service_RunAction_downloadURL(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec test needs echo")
+	}
+
+	const downloadURL = "https://tracker.example/torrent/download/123?passkey=secretkey"
+
+	var received struct {
+		m     sync.Mutex
+		query string
+		body  string
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		received.m.Lock()
+		received.query = r.URL.Query().Get("url")
+		received.body = string(body)
+		received.m.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		action *domain.Action
+	}{
+		{
+			name: "exec",
+			action: &domain.Action{
+				Name:     "exec",
+				Type:     domain.ActionTypeExec,
+				ExecCmd:  "echo",
+				ExecArgs: "{{ .DownloadURL }} {{ .ProxiedDownloadURL }}",
+			},
+		},
+		{
+			name: "webhook",
+			action: &domain.Action{
+				Name:        "webhook",
+				Type:        domain.ActionTypeWebhook,
+				WebhookHost: srv.URL + "/grab?url={{ .DownloadURL }}",
+				WebhookData: `{"url":"{{ .DownloadURL }}","proxied":"{{ .ProxiedDownloadURL }}"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			s := &service{
+				log:           zerolog.New(&logs).Level(zerolog.TraceLevel),
+				bus:           EventBus.New(),
+				serverURL:     "http://127.0.0.1:7474",
+				sessionSecret: "session-secret",
+			}
+
+			release := &domain.Release{
+				ID:          42,
+				TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+				Indexer:     "mock",
+				Protocol:    domain.ReleaseProtocolTorrent,
+				DownloadURL: downloadURL,
+			}
+
+			_, err := s.RunAction(context.Background(), tt.action, release)
+			assert.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(release.ProxiedDownloadURL, "http://127.0.0.1:7474/api/release/42/download?expires="))
+			assert.NotContains(t, logs.String(), "secretkey")
+			assert.Contains(t, logs.String(), "https://tracker.example/<redacted>")
+
+			if tt.action.Type == domain.ActionTypeWebhook {
+				received.m.Lock()
+				defer received.m.Unlock()
+
+				// the macros are expanded in the request, only the logs are redacted
+				assert.Equal(t, downloadURL, received.query)
+				assert.Contains(t, received.body, `"url":"`+downloadURL+`"`)
+				assert.Contains(t, received.body, release.ProxiedDownloadURL)
+			}
+		})
+	}
+}
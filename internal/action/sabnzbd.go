@@ -11,7 +11,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/sabnzbd"
 )
 
-func (s *service) sabnzbd(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) sabnzbd(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Trace().Msg("action Sabnzbd")
 
 	if release.Protocol != domain.ReleaseProtocolNzb {
@@ -29,10 +29,16 @@ func (s *service) sabnzbd(ctx context.Context, action *domain.Action, release do
 		return nil, errors.New("no sabnzbd client found by id: %d", action.ClientID)
 	}
 
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := sabnzbd.Options{
-		Addr:   client.Host,
-		ApiKey: client.Settings.APIKey,
-		Log:    nil,
+		Addr:      client.Host,
+		ApiKey:    client.Settings.APIKey,
+		Transport: transport,
+		Log:       nil,
 	}
 
 	if client.Settings.Basic.Auth {
@@ -42,11 +48,22 @@ func (s *service) sabnzbd(ctx context.Context, action *domain.Action, release do
 
 	sab := sabnzbd.New(opts)
 
-	ids, err := sab.AddFromUrl(ctx, sabnzbd.AddNzbRequest{Url: release.DownloadURL, Category: action.Category})
+	req := sabnzbd.AddNzbRequest{
+		Url:      release.DownloadURL,
+		Category: action.Category,
+		Priority: action.Priority,
+		Script:   action.PostProcessScript,
+	}
+
+	ids, err := sab.AddFromUrl(ctx, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not add nzb to sabnzbd")
 	}
 
+	if len(ids.NzoIDs) > 0 {
+		release.ClientTorrentID = ids.NzoIDs[0]
+	}
+
 	s.log.Trace().Msgf("nzb successfully added to client: '%+v'", ids)
 
 	s.log.Info().Msgf("nzb successfully added to client: '%s'", client.Name)
@@ -6,10 +6,13 @@ package action
 import (
 	"context"
 	"log"
+	"net"
+	"strconv"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/asaskevich/EventBus"
 	"github.com/dcarbone/zadapters/zstdlog"
@@ -24,32 +27,91 @@ type Service interface {
 	Delete(ctx context.Context, req *domain.DeleteActionRequest) error
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	ToggleEnabled(actionID int) error
+	Clone(ctx context.Context, req *domain.CloneActionRequest) ([]*domain.Action, error)
+
+	ListTemplates(ctx context.Context) ([]domain.ActionTemplate, error)
+	StoreTemplate(ctx context.Context, template *domain.ActionTemplate) error
+	UpdateTemplate(ctx context.Context, template *domain.ActionTemplate) error
+	DeleteTemplate(ctx context.Context, id int) error
+
+	ListMacroOverrides(ctx context.Context) ([]domain.MacroOverride, error)
+	StoreMacroOverride(ctx context.Context, override *domain.MacroOverride) error
+	DeleteMacroOverride(ctx context.Context, id int) error
+
+	FindResults(ctx context.Context, params domain.ActionResultQueryParams) ([]domain.ActionResult, error)
 
 	RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error)
+
+	Start() error
 }
 
 type service struct {
-	log       zerolog.Logger
-	subLogger *log.Logger
-	repo      domain.ActionRepo
-	clientSvc download_client.Service
-	bus       EventBus.Bus
+	log               zerolog.Logger
+	subLogger         *log.Logger
+	repo              domain.ActionRepo
+	templateRepo      domain.ActionTemplateRepo
+	resultRepo        domain.ActionResultRepo
+	macroOverrideRepo domain.MacroOverrideRepo
+	clientSvc         download_client.Service
+	bus               EventBus.Bus
+	batcher           *addBatcher
+	execLimiter       *execLimiter
+	resumer           *resumeScheduler
+	failures          *failureTracker
+	policy            *actionPolicy
+
+	// sleep can be replaced in tests
+	sleep sleepFunc
+
+	// serverURL and sessionSecret are used to sign proxied download urls
+	serverURL     string
+	sessionSecret string
 }
 
-func NewService(log logger.Logger, repo domain.ActionRepo, clientSvc download_client.Service, bus EventBus.Bus) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.ActionRepo, templateRepo domain.ActionTemplateRepo, resultRepo domain.ActionResultRepo, macroOverrideRepo domain.MacroOverrideRepo, pendingResumeRepo domain.PendingResumeRepo, clientSvc download_client.Service, bus EventBus.Bus) Service {
 	s := &service{
-		log:       log.With().Str("module", "action").Logger(),
-		repo:      repo,
-		clientSvc: clientSvc,
-		bus:       bus,
+		log:               log.With().Str("module", "action").Logger(),
+		repo:              repo,
+		templateRepo:      templateRepo,
+		resultRepo:        resultRepo,
+		macroOverrideRepo: macroOverrideRepo,
+		clientSvc:         clientSvc,
+		bus:               bus,
+		batcher:           newAddBatcher(defaultBatchMaxSize, defaultBatchFlushInterval),
+		execLimiter:       newExecLimiter(config.ExecConcurrency),
+		serverURL:         localServerURL(config),
+		sessionSecret:     config.SessionSecret,
+		sleep:             sleepContext,
+		failures:          newFailureTracker(),
+		policy:            newActionPolicy(config),
 	}
 
 	s.subLogger = zstdlog.NewStdLoggerWithLevel(s.log.With().Logger(), zerolog.TraceLevel)
+	s.resumer = newResumeScheduler(s.log, pendingResumeRepo, s.resumeTorrent)
 
 	return s
 }
 
+// localServerURL returns the url actions on this machine can reach autobrr on
+func localServerURL(config *domain.Config) string {
+	host := config.Host
+	switch host {
+	case "", "0.0.0.0", "::":
+		host = "127.0.0.1"
+	}
+
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(config.Port))
+}
+
 func (s *service) Store(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.checkAction(&action); err != nil {
+		return nil, errors.Wrap(err, "validation error: action %q", action.Name)
+	}
+
 	return s.repo.Store(ctx, action)
 }
 
@@ -91,3 +153,82 @@ func (s *service) DeleteByFilterID(ctx context.Context, filterID int) error {
 func (s *service) ToggleEnabled(actionID int) error {
 	return s.repo.ToggleEnabled(actionID)
 }
+
+func (s *service) Clone(ctx context.Context, req *domain.CloneActionRequest) ([]*domain.Action, error) {
+	if len(req.FilterIDs) == 0 {
+		return nil, errors.New("clone action: no filters selected")
+	}
+
+	return s.repo.Clone(ctx, req)
+}
+
+func (s *service) ListTemplates(ctx context.Context) ([]domain.ActionTemplate, error) {
+	return s.templateRepo.List(ctx)
+}
+
+func (s *service) StoreTemplate(ctx context.Context, template *domain.ActionTemplate) error {
+	return s.templateRepo.Store(ctx, template)
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, template *domain.ActionTemplate) error {
+	return s.templateRepo.Update(ctx, template)
+}
+
+func (s *service) DeleteTemplate(ctx context.Context, id int) error {
+	return s.templateRepo.Delete(ctx, id)
+}
+
+func (s *service) ListMacroOverrides(ctx context.Context) ([]domain.MacroOverride, error) {
+	return s.macroOverrideRepo.List(ctx)
+}
+
+func (s *service) StoreMacroOverride(ctx context.Context, override *domain.MacroOverride) error {
+	if err := override.Validate(); err != nil {
+		return err
+	}
+
+	return s.macroOverrideRepo.Store(ctx, override)
+}
+
+func (s *service) DeleteMacroOverride(ctx context.Context, id int) error {
+	return s.macroOverrideRepo.Delete(ctx, id)
+}
+
+func (s *service) FindResults(ctx context.Context, params domain.ActionResultQueryParams) ([]domain.ActionResult, error) {
+	return s.resultRepo.Find(ctx, params)
+}
+
+// Start recovers the pending resumes of torrents added paused before a restart
+func (s *service) Start() error {
+	return s.resumer.Recover(context.Background())
+}
+
+// applyTemplate resolves the template referenced by the action, if any
+func (s *service) applyTemplate(ctx context.Context, action *domain.Action) error {
+	if action.TemplateID == 0 {
+		return nil
+	}
+
+	template, err := s.templateRepo.FindByID(ctx, action.TemplateID)
+	if err != nil {
+		return errors.Wrap(err, "could not find action template: %d", action.TemplateID)
+	}
+
+	action.ApplyTemplate(template)
+
+	return nil
+}
+
+// findMacroOverrides returns the macro overrides for the release indexer, if any
+func (s *service) findMacroOverrides(ctx context.Context, indexer string) ([]domain.MacroOverride, error) {
+	if s.macroOverrideRepo == nil || indexer == "" {
+		return nil, nil
+	}
+
+	overrides, err := s.macroOverrideRepo.FindByIndexer(ctx, indexer)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find macro overrides for indexer: %s", indexer)
+	}
+
+	return overrides, nil
+}
@@ -29,10 +29,16 @@ func (s *service) sonarr(ctx context.Context, action *domain.Action, release dom
 	}
 
 	// initial config
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := sonarr.Config{
-		Hostname: client.Host,
-		APIKey:   client.Settings.APIKey,
-		Log:      s.subLogger,
+		Hostname:  client.Host,
+		APIKey:    client.Settings.APIKey,
+		Transport: transport,
+		Log:       s.subLogger,
 	}
 
 	// only set basic auth if enabled
@@ -60,6 +66,7 @@ func (s *service) sonarr(ctx context.Context, action *domain.Action, release dom
 		DownloadProtocol: string(release.Protocol),
 		Protocol:         string(release.Protocol),
 		PublishDate:      time.Now().Format(time.RFC3339),
+		Tags:             arrTags(action),
 	}
 
 	arr := sonarr.New(cfg)
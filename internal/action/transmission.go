@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,7 +27,7 @@ const (
 var ErrReannounceTookTooLong = errors.New("ErrReannounceTookTooLong")
 var TrTrue = true
 
-func (s *service) transmission(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+func (s *service) transmission(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
 	s.log.Debug().Msgf("action Transmission: %s", action.Name)
 
 	var err error
@@ -41,6 +43,8 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return nil, errors.New("could not find client by id: %d", action.ClientID)
 	}
 
+	action.ApplyClientDefaults(client)
+
 	scheme := "http"
 	if client.TLS {
 		scheme = "https"
@@ -51,11 +55,17 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return nil, err
 	}
 
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	tbt, err := transmission.New(u, &transmission.Config{
 		UserAgent:     "autobrr",
 		Username:      client.Username,
 		Password:      client.Password,
 		TLSSkipVerify: client.TLSSkipVerify,
+		Transport:     transport,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "error logging into client: %s", client.Host)
@@ -70,6 +80,17 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return rejections, nil
 	}
 
+	rejections, err = s.checkFreeSpace(ctx, action, func(ctx context.Context, path string) (int64, error) {
+		return transmissionFreeSpace(ctx, tbt, path)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking free space for client: %s", client.Name)
+	}
+
+	if len(rejections) > 0 {
+		return rejections, nil
+	}
+
 	payload := transmissionrpc.TorrentAddPayload{}
 
 	if action.SavePath != "" {
@@ -78,8 +99,18 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 	if action.Paused {
 		payload.Paused = &action.Paused
 	}
+	if priority, ok := transmissionBandwidthPriority(action.BandwidthPriority); ok {
+		payload.BandwidthPriority = &priority
+	}
+	if action.PeerLimit > 0 {
+		payload.PeerLimit = &action.PeerLimit
+	}
 
 	if release.HasMagnetUri() {
+		if action.Comment != "" {
+			s.log.Warn().Msgf("action %s: comment is not supported for magnet links, skipping it", action.Name)
+		}
+
 		payload.Filename = &release.MagnetURI
 
 		// Prepare and send payload
@@ -88,6 +119,10 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 			return nil, errors.Wrap(err, "could not add torrent from magnet %s to client: %s", release.MagnetURI, client.Host)
 		}
 
+		if torrent.ID != nil {
+			release.ClientTorrentID = strconv.FormatInt(*torrent.ID, 10)
+		}
+
 		if action.Label != "" || action.LimitUploadSpeed > 0 || action.LimitDownloadSpeed > 0 || action.LimitRatio > 0 || action.LimitSeedTime > 0 {
 			p := transmissionrpc.TorrentSetPayload{
 				IDs: []int64{*torrent.ID},
@@ -139,7 +174,16 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		}
 	}
 
-	b64, err := transmissionrpc.File2Base64(release.TorrentTmpFile)
+	torrentFile := release.TorrentTmpFile
+	if action.Comment != "" {
+		torrentFile, err = torrentFileWithComment(release.TorrentTmpFile, action.Comment)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not set comment")
+		}
+		defer os.Remove(torrentFile)
+	}
+
+	b64, err := transmissionrpc.File2Base64(torrentFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "cant encode file %s into base64", release.TorrentTmpFile)
 	}
@@ -152,6 +196,10 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Host)
 	}
 
+	if torrent.ID != nil {
+		release.ClientTorrentID = strconv.FormatInt(*torrent.ID, 10)
+	}
+
 	if action.Label != "" || action.LimitUploadSpeed > 0 || action.LimitDownloadSpeed > 0 || action.LimitRatio > 0 || action.LimitSeedTime > 0 {
 		p := transmissionrpc.TorrentSetPayload{
 			IDs: []int64{*torrent.ID},
@@ -200,8 +248,33 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 
 			return nil, errors.Wrap(err, "could not reannounce torrent: %s", *torrent.HashString)
 		}
+	}
 
-		return nil, nil
+	if action.VerifyStart && !action.Paused {
+		s.verifyStart(action, release, client.Name, newStartVerifier(func(ctx context.Context) (torrentStartState, string, error) {
+			torrents, err := tbt.TorrentGet(ctx, []string{"status", "error", "errorString"}, []int64{*torrent.ID})
+			if err != nil {
+				return torrentStatePending, "", errors.Wrap(err, "could not get torrent: %d", *torrent.ID)
+			}
+
+			if len(torrents) == 0 || torrents[0].Status == nil {
+				return torrentStatePending, "not found", nil
+			}
+
+			t := torrents[0]
+
+			var errCode int64
+			if t.Error != nil {
+				errCode = *t.Error
+			}
+
+			status := t.Status.String()
+			if errCode != 0 && t.ErrorString != nil {
+				status = fmt.Sprintf("%s: %s", status, *t.ErrorString)
+			}
+
+			return transmissionStartState(*t.Status, errCode), status, nil
+		}))
 	}
 
 	s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", *torrent.HashString, client.Name)
@@ -210,6 +283,10 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 }
 
 func (s *service) transmissionReannounce(ctx context.Context, action *domain.Action, tbt *transmissionrpc.Client, torrentId int64) error {
+	if action.ReAnnounceTargetPeers > 0 {
+		return s.transmissionReannounceTargetPeers(ctx, action, tbt, torrentId)
+	}
+
 	interval := ReannounceInterval
 	if action.ReAnnounceInterval > 0 {
 		interval = int(action.ReAnnounceInterval)
@@ -279,6 +356,87 @@ func (s *service) transmissionReannounce(ctx context.Context, action *domain.Act
 	return nil
 }
 
+// transmissionReannounceTargetPeers keeps re-announcing until the working trackers report enough seeds and leechers
+func (s *service) transmissionReannounceTargetPeers(ctx context.Context, action *domain.Action, tbt *transmissionrpc.Client, torrentId int64) error {
+	interval, maxAttempts := reannounceOptions(action.ReAnnounceInterval, action.ReAnnounceMaxAttempts)
+
+	r := peerReannouncer{
+		log:         s.log,
+		name:        fmt.Sprintf("%d", torrentId),
+		target:      int(action.ReAnnounceTargetPeers),
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		peers: func(ctx context.Context) (int, error) {
+			t, err := tbt.TorrentGet(ctx, []string{"trackerStats"}, []int64{torrentId})
+			if err != nil {
+				return 0, errors.Wrap(err, "reannounced, failed to find torrentid")
+			}
+
+			if len(t) < 1 {
+				return 0, errors.New("reannounced, failed to get torrent from id")
+			}
+
+			peers := 0
+			for _, tracker := range t[0].TrackerStats {
+				if tracker.IsBackup || isUnregistered(tracker.LastAnnounceResult) {
+					continue
+				}
+
+				if tracker.SeederCount > 0 {
+					peers += int(tracker.SeederCount)
+				}
+				if tracker.LeecherCount > 0 {
+					peers += int(tracker.LeecherCount)
+				}
+			}
+
+			return peers, nil
+		},
+		reannounce: func(ctx context.Context) error {
+			return tbt.TorrentReannounceIDs(ctx, []int64{torrentId})
+		},
+	}
+
+	if err := r.run(ctx); err != nil {
+		if !errors.Is(err, ErrReannounceTookTooLong) || !action.ReAnnounceDelete {
+			return err
+		}
+
+		s.log.Info().Msgf("re-announce for %v did not reach %d peers, deleting torrent", torrentId, r.target)
+
+		if err := tbt.TorrentRemove(ctx, transmissionrpc.TorrentRemovePayload{IDs: []int64{torrentId}}); err != nil {
+			return errors.Wrap(err, "could not delete torrent: %v from client after max re-announce attempts reached", torrentId)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// transmissionFreeSpace returns the free space at the path, or at the session download dir if the path is empty
+func transmissionFreeSpace(ctx context.Context, tbt *transmissionrpc.Client, path string) (int64, error) {
+	if path == "" {
+		session, err := tbt.SessionArgumentsGet(ctx, []string{"download-dir"})
+		if err != nil {
+			return 0, errors.Wrap(err, "could not get session download dir")
+		}
+
+		if session.DownloadDir == nil {
+			return 0, errors.New("session is missing download dir")
+		}
+
+		path = *session.DownloadDir
+	}
+
+	free, _, err := tbt.FreeSpace(ctx, path)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get free space for path: %s", path)
+	}
+
+	return int64(free.Byte()), nil
+}
+
 func (s *service) transmissionCheckRulesCanDownload(ctx context.Context, action *domain.Action, client *domain.DownloadClient, tbt *transmissionrpc.Client) ([]string, error) {
 	s.log.Trace().Msgf("action transmission: %s check rules", action.Name)
 
@@ -315,6 +473,20 @@ func (s *service) transmissionCheckRulesCanDownload(ctx context.Context, action
 	return nil, nil
 }
 
+// transmissionBandwidthPriority maps the priority to the transmission tr_priority_t, unset keeps the client default
+func transmissionBandwidthPriority(priority domain.ActionBandwidthPriority) (int64, bool) {
+	switch priority {
+	case domain.ActionBandwidthPriorityHigh:
+		return 1, true
+	case domain.ActionBandwidthPriorityNormal:
+		return 0, true
+	case domain.ActionBandwidthPriorityLow:
+		return -1, true
+	}
+
+	return 0, false
+}
+
 func isUnregistered(msg string) bool {
 	words := []string{"unregistered", "not registered", "not found", "not exist"}
 
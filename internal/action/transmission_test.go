@@ -0,0 +1,171 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTransmission struct {
+	m       sync.Mutex
+	addArgs map[string]any
+}
+
+// newFakeTransmission returns a server for the transmission rpc methods used when adding torrents
+func newFakeTransmission(t *testing.T) (*httptest.Server, *fakeTransmission) {
+	fake := &fakeTransmission{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/transmission/rpc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var req struct {
+			Method    string         `json:"method"`
+			Arguments map[string]any `json:"arguments"`
+			Tag       int            `json:"tag"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fake.m.Lock()
+		defer fake.m.Unlock()
+
+		arguments := map[string]any{}
+
+		switch req.Method {
+		case "torrent-add":
+			fake.addArgs = req.Arguments
+			arguments["torrent-added"] = map[string]any{"id": 1, "hashString": "3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a", "name": "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": "success", "arguments": arguments, "tag": req.Tag})
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, fake
+}
+
+func Test_service_transmission_bandwidthPriority(t *testing.T) {
+	tests := []struct {
+		name          string
+		priority      domain.ActionBandwidthPriority
+		peerLimit     int64
+		wantPriority  any
+		wantPeerLimit any
+	}{
+		{name: "high", priority: domain.ActionBandwidthPriorityHigh, peerLimit: 50, wantPriority: float64(1), wantPeerLimit: float64(50)},
+		{name: "normal", priority: domain.ActionBandwidthPriorityNormal, wantPriority: float64(0)},
+		{name: "low", priority: domain.ActionBandwidthPriorityLow, peerLimit: 10, wantPriority: float64(-1), wantPeerLimit: float64(10)},
+		{name: "unset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, fake := newFakeTransmission(t)
+
+			host, portStr, err := net.SplitHostPort(ts.Listener.Addr().String())
+			assert.NoError(t, err)
+			port, err := strconv.Atoi(portStr)
+			assert.NoError(t, err)
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+				bus: EventBus.New(),
+				clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+					1: {ID: 1, Name: "transmission", Type: domain.DownloadClientTypeTransmission, Host: host, Port: port},
+				}},
+			}
+
+			action := &domain.Action{
+				Name:              "transmission",
+				Type:              domain.ActionTypeTransmission,
+				ClientID:          1,
+				BandwidthPriority: tt.priority,
+				PeerLimit:         tt.peerLimit,
+			}
+			release := &domain.Release{
+				TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+				MagnetURI:   "magnet:?xt=urn:btih:3f8a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a",
+			}
+
+			rejections, err := s.transmission(context.Background(), action, release)
+			assert.NoError(t, err)
+			assert.Empty(t, rejections)
+			assert.Equal(t, "1", release.ClientTorrentID)
+
+			fake.m.Lock()
+			defer fake.m.Unlock()
+
+			assert.Equal(t, tt.wantPriority, fake.addArgs["bandwidthPriority"])
+			assert.Equal(t, tt.wantPeerLimit, fake.addArgs["peer-limit"])
+		})
+	}
+}
+
+func Test_service_transmission_comment(t *testing.T) {
+	ts, fake := newFakeTransmission(t)
+
+	host, portStr, err := net.SplitHostPort(ts.Listener.Addr().String())
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	assert.NoError(t, err)
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		bus: EventBus.New(),
+		clientSvc: &mockDownloadClientService{clients: map[int32]*domain.DownloadClient{
+			1: {ID: 1, Name: "transmission", Type: domain.DownloadClientTypeTransmission, Host: host, Port: port},
+		}},
+	}
+
+	torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+	assert.NoError(t, os.WriteFile(torrentFile, []byte("d7:comment3:old4:infod4:name4:testee"), 0644))
+
+	action := &domain.Action{
+		Name:     "transmission",
+		Type:     domain.ActionTypeTransmission,
+		ClientID: 1,
+		Comment:  "autobrr filter: tv-1080p",
+	}
+	release := &domain.Release{
+		TorrentName:    "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		TorrentTmpFile: torrentFile,
+	}
+
+	rejections, err := s.transmission(context.Background(), action, release)
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+
+	fake.m.Lock()
+	defer fake.m.Unlock()
+
+	data, err := base64.StdEncoding.DecodeString(fake.addArgs["metainfo"].(string))
+	assert.NoError(t, err)
+
+	meta, err := metainfo.Load(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "autobrr filter: tv-1080p", meta.Comment)
+	assert.Equal(t, "d4:name4:teste", string(meta.InfoBytes))
+}
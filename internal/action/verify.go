@@ -0,0 +1,164 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+const (
+	VerifyStartAttempts = 5
+	VerifyStartInterval = 2 // interval in seconds
+)
+
+type torrentStartState int
+
+const (
+	// torrentStatePending is queued, allocating, waiting for metadata or stopped, keep polling
+	torrentStatePending torrentStartState = iota
+	torrentStateStarted
+	// torrentStateFailed is an error in the client like missing files, stalled torrents are started
+	torrentStateFailed
+)
+
+// startVerifier polls the client after a torrent is added until it is downloading or checking
+type startVerifier struct {
+	interval time.Duration
+	attempts int
+
+	// state returns the start state and the client status for messages
+	state func(ctx context.Context) (torrentStartState, string, error)
+}
+
+func newStartVerifier(state func(ctx context.Context) (torrentStartState, string, error)) *startVerifier {
+	return &startVerifier{
+		interval: VerifyStartInterval * time.Second,
+		attempts: VerifyStartAttempts,
+		state:    state,
+	}
+}
+
+// timeout is how long run can poll at most
+func (v *startVerifier) timeout() time.Duration {
+	return time.Duration(v.attempts+1) * v.interval
+}
+
+// run polls until the torrent started or failed and returns that state with the last client status.
+// It returns torrentStatePending if the torrent is still pending after all attempts.
+func (v *startVerifier) run(ctx context.Context) (torrentStartState, string, error) {
+	status := ""
+
+	for attempt := 0; attempt < v.attempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return torrentStatePending, status, ctx.Err()
+		case <-time.After(v.interval):
+		}
+
+		state, s, err := v.state(ctx)
+		if err != nil {
+			return torrentStatePending, status, err
+		}
+
+		status = s
+
+		if state != torrentStatePending {
+			return state, status, nil
+		}
+	}
+
+	return torrentStatePending, status, nil
+}
+
+// verifyStart checks the torrent started in the background, the action doesn't wait for it
+func (s *service) verifyStart(action *domain.Action, release *domain.Release, client string, v *startVerifier) {
+	a, r := *action, *release
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), v.timeout())
+		defer cancel()
+
+		s.checkStarted(ctx, &a, &r, client, v)
+	}()
+}
+
+// checkStarted polls the torrent state and sends a warning notification if it failed to start
+func (s *service) checkStarted(ctx context.Context, action *domain.Action, release *domain.Release, client string, v *startVerifier) {
+	state, status, err := v.run(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not verify torrent %s started in client: %s", release.TorrentName, client)
+		return
+	}
+
+	switch state {
+	case torrentStateStarted:
+		s.log.Debug().Msgf("torrent %s started in client: %s status: %s", release.TorrentName, client, status)
+		return
+
+	case torrentStatePending:
+		s.log.Debug().Msgf("torrent %s not started yet in client: %s status: %s", release.TorrentName, client, status)
+		return
+	}
+
+	s.log.Warn().Msgf("torrent %s failed to start in client: %s status: %s", release.TorrentName, client, status)
+
+	if s.bus == nil {
+		return
+	}
+
+	payload := &domain.NotificationPayload{
+		Subject:        "Torrent failed to start",
+		Message:        fmt.Sprintf("%s is %s in %s", release.TorrentName, status, client),
+		Event:          domain.NotificationEventTorrentStalled,
+		ReleaseName:    release.TorrentName,
+		Filter:         release.FilterName,
+		Indexer:        release.Indexer,
+		InfoURL:        release.InfoURL,
+		InfoHash:       release.TorrentHash,
+		Size:           release.Size,
+		Action:         action.Name,
+		ActionType:     action.Type,
+		ActionClient:   client,
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+	}
+
+	s.bus.Publish("events:notification", &payload.Event, payload)
+}
+
+// qbittorrentStartState maps the qBittorrent torrent state, a stalled torrent is started and waiting for peers
+func qbittorrentStartState(state qbittorrent.TorrentState) torrentStartState {
+	switch state {
+	case qbittorrent.TorrentStateDownloading, qbittorrent.TorrentStateForcedDl, qbittorrent.TorrentStateStalledDl,
+		qbittorrent.TorrentStateCheckingDl, qbittorrent.TorrentStateCheckingUp, qbittorrent.TorrentStateCheckingResumeData,
+		qbittorrent.TorrentStateUploading, qbittorrent.TorrentStateForcedUp, qbittorrent.TorrentStateStalledUp:
+		return torrentStateStarted
+	case qbittorrent.TorrentStateError, qbittorrent.TorrentStateMissingFiles:
+		return torrentStateFailed
+	default:
+		return torrentStatePending
+	}
+}
+
+// transmissionStartState maps the Transmission torrent status and error
+func transmissionStartState(status transmissionrpc.TorrentStatus, errCode int64) torrentStartState {
+	if errCode != 0 {
+		return torrentStateFailed
+	}
+
+	switch status {
+	case transmissionrpc.TorrentStatusDownload, transmissionrpc.TorrentStatusCheck, transmissionrpc.TorrentStatusSeed:
+		return torrentStateStarted
+	default:
+		return torrentStatePending
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/hekmon/transmissionrpc/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_startVerifier_run(t *testing.T) {
+	tests := []struct {
+		name       string
+		states     []qbittorrent.TorrentState
+		wantState  torrentStartState
+		wantStatus string
+		wantPolls  int
+	}{
+		{
+			name:       "started",
+			states:     []qbittorrent.TorrentState{qbittorrent.TorrentStateMetaDl, qbittorrent.TorrentStateQueuedDl, qbittorrent.TorrentStateDownloading},
+			wantState:  torrentStateStarted,
+			wantStatus: "downloading",
+			wantPolls:  3,
+		},
+		{
+			name:       "checking",
+			states:     []qbittorrent.TorrentState{qbittorrent.TorrentStateCheckingDl},
+			wantState:  torrentStateStarted,
+			wantStatus: "checkingDL",
+			wantPolls:  1,
+		},
+		{
+			name:       "stalled",
+			states:     []qbittorrent.TorrentState{qbittorrent.TorrentStateMetaDl, qbittorrent.TorrentStateStalledDl},
+			wantState:  torrentStateStarted,
+			wantStatus: "stalledDL",
+			wantPolls:  2,
+		},
+		{
+			name:       "pending",
+			states:     []qbittorrent.TorrentState{qbittorrent.TorrentStateMetaDl, qbittorrent.TorrentStateQueuedDl, qbittorrent.TorrentStatePausedDl},
+			wantState:  torrentStatePending,
+			wantStatus: "pausedDL",
+			wantPolls:  3,
+		},
+		{
+			name:       "error",
+			states:     []qbittorrent.TorrentState{qbittorrent.TorrentStateMetaDl, qbittorrent.TorrentStateError},
+			wantState:  torrentStateFailed,
+			wantStatus: "error",
+			wantPolls:  2,
+		},
+		{
+			name:       "missing_files",
+			states:     []qbittorrent.TorrentState{qbittorrent.TorrentStateMissingFiles},
+			wantState:  torrentStateFailed,
+			wantStatus: "missingFiles",
+			wantPolls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+
+			v := startVerifier{
+				interval: time.Millisecond,
+				attempts: 3,
+				state: func(ctx context.Context) (torrentStartState, string, error) {
+					state := tt.states[polls]
+					polls++
+					return qbittorrentStartState(state), string(state), nil
+				},
+			}
+
+			state, status, err := v.run(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantState, state)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantPolls, polls)
+		})
+	}
+}
+
+func Test_service_verifyStart_failedEvent(t *testing.T) {
+	bus := EventBus.New()
+
+	var mu sync.Mutex
+	var events []domain.NotificationPayload
+	err := bus.Subscribe("events:notification", func(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, *payload)
+	})
+	assert.NoError(t, err)
+
+	s := &service{
+		log: zerolog.Nop(),
+		bus: bus,
+	}
+
+	action := &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, VerifyStart: true}
+	release := &domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP", Indexer: "mock"}
+
+	verifier := func(state torrentStartState, status string) *startVerifier {
+		v := newStartVerifier(func(ctx context.Context) (torrentStartState, string, error) {
+			return state, status, nil
+		})
+		v.interval = time.Millisecond
+		return v
+	}
+
+	// started or still pending, no warning
+	s.checkStarted(context.Background(), action, release, "qbittorrent", verifier(torrentStateStarted, "stalledDL"))
+	s.checkStarted(context.Background(), action, release, "qbittorrent", verifier(torrentStatePending, "queuedDL"))
+	assert.Len(t, events, 0)
+
+	// failed, warning sent
+	s.checkStarted(context.Background(), action, release, "qbittorrent", verifier(torrentStateFailed, "missingFiles"))
+
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, domain.NotificationEventTorrentStalled, events[0].Event)
+		assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL-GROUP", events[0].ReleaseName)
+		assert.Equal(t, "qbittorrent", events[0].ActionClient)
+		assert.Contains(t, events[0].Message, "missingFiles")
+	}
+
+	// verifyStart returns right away and checks in the background
+	unblock := make(chan struct{})
+	v := verifier(torrentStateFailed, "error")
+	state := v.state
+	v.state = func(ctx context.Context) (torrentStartState, string, error) {
+		<-unblock
+		return state(ctx)
+	}
+
+	s.verifyStart(action, release, "qbittorrent", v)
+	close(unblock)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 2
+	}, time.Second, time.Millisecond)
+}
+
+func Test_transmissionStartState(t *testing.T) {
+	assert.Equal(t, torrentStateStarted, transmissionStartState(transmissionrpc.TorrentStatusDownload, 0))
+	assert.Equal(t, torrentStateStarted, transmissionStartState(transmissionrpc.TorrentStatusCheck, 0))
+	assert.Equal(t, torrentStatePending, transmissionStartState(transmissionrpc.TorrentStatusDownloadWait, 0))
+	assert.Equal(t, torrentStatePending, transmissionStartState(transmissionrpc.TorrentStatusStopped, 0))
+	assert.Equal(t, torrentStateFailed, transmissionStartState(transmissionrpc.TorrentStatusDownload, 3))
+}
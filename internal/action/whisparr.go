@@ -29,10 +29,16 @@ func (s *service) whisparr(ctx context.Context, action *domain.Action, release d
 	}
 
 	// initial config
+	transport, err := s.clientSvc.GetTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := whisparr.Config{
-		Hostname: client.Host,
-		APIKey:   client.Settings.APIKey,
-		Log:      s.subLogger,
+		Hostname:  client.Host,
+		APIKey:    client.Settings.APIKey,
+		Transport: transport,
+		Log:       s.subLogger,
 	}
 
 	// only set basic auth if enabled
@@ -153,6 +153,9 @@ func (a *announceProcessor) onLinesMatched(def *domain.IndexerDefinition, vars m
 		rls.TorrentName = strings.ReplaceAll(rls.TorrentName, "–", "-")
 	}
 
+	// keep the raw vars so indexer specific fields are available as macros
+	rls.Vars = def.IRC.Parse.CapturedVars(vars)
+
 	// parse fields
 	// run before ParseMatch to not potentially use a reconstructed TorrentName
 	rls.ParseString(rls.TorrentName)
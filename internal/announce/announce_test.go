@@ -6,6 +6,10 @@ package announce
 import (
 	"testing"
 
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/indexer"
+
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,3 +73,331 @@ func Test_announceProcessor_processTorrentUrl(t *testing.T) {
 		})
 	}
 }
+
+func Test_announceProcessor_onLinesMatched_freeleech(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		URLS:       []string{"https://mock.local/"},
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Lines: []domain.IndexerIRCParseLine{
+					{
+						Pattern: `New Torrent: (.*) \[(\d+)% FL\]\s*\[(Internal)?\]\s*-\s*(https?\:\/\/[^\/]+\/)torrent\/(\d+)`,
+						Vars:    []string{"torrentName", "freeleechPercent", "origin", "baseUrl", "torrentId"},
+					},
+				},
+				Match: domain.IndexerIRCParseMatch{
+					TorrentURL: "{{ .baseUrl }}download/{{ .torrentId }}",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		line                 string
+		wantFreeleech        bool
+		wantFreeleechPercent int
+		wantOrigin           string
+		wantMacro            string
+	}{
+		{
+			name:                 "freeleech_internal",
+			line:                 "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP [50% FL] [Internal] - https://mock.local/torrent/1234",
+			wantFreeleech:        true,
+			wantFreeleechPercent: 50,
+			wantOrigin:           "Internal",
+			wantMacro:            "true 50 Internal",
+		},
+		{
+			name:                 "not_freeleech",
+			line:                 "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP [0% FL] [] - https://mock.local/torrent/1234",
+			wantFreeleech:        false,
+			wantFreeleechPercent: 0,
+			wantOrigin:           "",
+			wantMacro:            "false 0 ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := zerolog.Nop()
+			vars := map[string]string{}
+
+			match, err := indexer.ParseLine(&log, def.IRC.Parse.Lines[0].Pattern, def.IRC.Parse.Lines[0].Vars, vars, tt.line, false)
+			assert.NoError(t, err)
+			assert.True(t, match)
+
+			a := &announceProcessor{log: log}
+			rls := domain.NewRelease(def.Identifier)
+
+			err = a.onLinesMatched(def, vars, rls)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wantFreeleech, rls.Freeleech)
+			assert.Equal(t, tt.wantFreeleechPercent, rls.FreeleechPercent)
+			assert.Equal(t, tt.wantOrigin, rls.Origin)
+			assert.Equal(t, "https://mock.local/download/1234", rls.DownloadURL)
+
+			got, err := domain.NewMacro(*rls).Parse("{{ .Freeleech }} {{ .FreeleechPercent }} {{ .Origin }}")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMacro, got)
+
+			filter := &domain.Filter{Freeleech: true, FreeleechPercent: "50-100", Origins: []string{"INTERNAL"}}
+			_, matched := filter.CheckFilter(rls)
+			assert.Equal(t, tt.wantFreeleech, matched)
+		})
+	}
+}
+
+func Test_announceProcessor_onLinesMatched_uploaderScene(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		URLS:       []string{"https://mock.local/"},
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Lines: []domain.IndexerIRCParseLine{
+					{
+						Pattern: `New Torrent: (.*) Uploader: (\S+) Type: (Scene|P2P) - (https?\:\/\/[^\/]+\/)torrent\/(\d+)`,
+						Vars:    []string{"torrentName", "uploader", "scene", "baseUrl", "torrentId"},
+					},
+				},
+				Match: domain.IndexerIRCParseMatch{
+					TorrentURL: "{{ .baseUrl }}download/{{ .torrentId }}",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		line         string
+		wantUploader string
+		wantScene    bool
+		wantOrigin   string
+		wantMacro    string
+		wantMatch    bool
+	}{
+		{
+			name:         "trusted_scene",
+			line:         "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP Uploader: trusted Type: Scene - https://mock.local/torrent/1234",
+			wantUploader: "trusted",
+			wantScene:    true,
+			wantOrigin:   "SCENE",
+			wantMacro:    "trusted true SCENE",
+			wantMatch:    true,
+		},
+		{
+			name:         "trusted_p2p",
+			line:         "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP Uploader: trusted Type: P2P - https://mock.local/torrent/1234",
+			wantUploader: "trusted",
+			wantScene:    false,
+			wantOrigin:   "P2P",
+			wantMacro:    "trusted false P2P",
+			wantMatch:    false,
+		},
+		{
+			name:         "untrusted_scene",
+			line:         "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP Uploader: someone Type: Scene - https://mock.local/torrent/1234",
+			wantUploader: "someone",
+			wantScene:    true,
+			wantOrigin:   "SCENE",
+			wantMacro:    "someone true SCENE",
+			wantMatch:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := zerolog.Nop()
+			vars := map[string]string{}
+
+			match, err := indexer.ParseLine(&log, def.IRC.Parse.Lines[0].Pattern, def.IRC.Parse.Lines[0].Vars, vars, tt.line, false)
+			assert.NoError(t, err)
+			assert.True(t, match)
+
+			a := &announceProcessor{log: log}
+			rls := domain.NewRelease(def.Identifier)
+
+			err = a.onLinesMatched(def, vars, rls)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wantUploader, rls.Uploader)
+			assert.Equal(t, tt.wantScene, rls.IsScene)
+			assert.Equal(t, tt.wantOrigin, rls.Origin)
+
+			got, err := domain.NewMacro(*rls).Parse("{{ .Uploader }} {{ .IsScene }} {{ .Origin }}")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMacro, got)
+
+			filter := &domain.Filter{Scene: true, MatchUploaders: "trusted,other"}
+			_, matched := filter.CheckFilter(rls)
+			assert.Equal(t, tt.wantMatch, matched)
+
+			// scene releases can also be excluded by origin
+			filter = &domain.Filter{ExceptOrigins: []string{"SCENE"}, ExceptUploaders: "someone"}
+			_, matched = filter.CheckFilter(rls)
+			assert.Equal(t, tt.name == "trusted_p2p", matched)
+		})
+	}
+}
+
+func Test_announceProcessor_onLinesMatched_seeders(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		URLS:       []string{"https://mock.local/"},
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Lines: []domain.IndexerIRCParseLine{
+					{
+						Pattern: `New Torrent: (.*?)(?: Seeders: (\d+) Leechers: (\d+))? - (https?\:\/\/[^\/]+\/)torrent\/(\d+)`,
+						Vars:    []string{"torrentName", "seeders", "leechers", "baseUrl", "torrentId"},
+					},
+				},
+				Match: domain.IndexerIRCParseMatch{
+					TorrentURL: "{{ .baseUrl }}download/{{ .torrentId }}",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		line           string
+		filter         domain.Filter
+		wantSeeders    int
+		wantLeechers   int
+		wantHasSeeders bool
+		wantMacro      string
+		wantMatch      bool
+	}{
+		{
+			name:           "seeders_within_range",
+			line:           "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP Seeders: 12 Leechers: 3 - https://mock.local/torrent/1234",
+			filter:         domain.Filter{MinSeeders: 5, MaxSeeders: 20},
+			wantSeeders:    12,
+			wantLeechers:   3,
+			wantHasSeeders: true,
+			wantMacro:      "12 3",
+			wantMatch:      true,
+		},
+		{
+			name:           "seeders_below_min",
+			line:           "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP Seeders: 0 Leechers: 8 - https://mock.local/torrent/1234",
+			filter:         domain.Filter{MinSeeders: 1},
+			wantSeeders:    0,
+			wantLeechers:   8,
+			wantHasSeeders: true,
+			wantMacro:      "0 8",
+			wantMatch:      false,
+		},
+		{
+			name:           "seeders_above_max",
+			line:           "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP Seeders: 50 Leechers: 1 - https://mock.local/torrent/1234",
+			filter:         domain.Filter{MaxSeeders: 10},
+			wantSeeders:    50,
+			wantLeechers:   1,
+			wantHasSeeders: true,
+			wantMacro:      "50 1",
+			wantMatch:      false,
+		},
+		{
+			name:           "unknown_seeders_pass",
+			line:           "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP - https://mock.local/torrent/1234",
+			filter:         domain.Filter{MinSeeders: 5},
+			wantHasSeeders: false,
+			wantMacro:      "0 0",
+			wantMatch:      true,
+		},
+		{
+			name:           "unknown_seeders_reject",
+			line:           "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP - https://mock.local/torrent/1234",
+			filter:         domain.Filter{MinSeeders: 5, RejectUnknownSeeders: true},
+			wantHasSeeders: false,
+			wantMacro:      "0 0",
+			wantMatch:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := zerolog.Nop()
+			vars := map[string]string{}
+
+			match, err := indexer.ParseLine(&log, def.IRC.Parse.Lines[0].Pattern, def.IRC.Parse.Lines[0].Vars, vars, tt.line, false)
+			assert.NoError(t, err)
+			assert.True(t, match)
+
+			a := &announceProcessor{log: log}
+			rls := domain.NewRelease(def.Identifier)
+
+			err = a.onLinesMatched(def, vars, rls)
+			assert.NoError(t, err)
+
+			assert.Equal(t, "That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP", rls.TorrentName)
+			assert.Equal(t, tt.wantSeeders, rls.Seeders)
+			assert.Equal(t, tt.wantLeechers, rls.Leechers)
+			assert.Equal(t, tt.wantHasSeeders, rls.HasSeeders)
+
+			got, err := domain.NewMacro(*rls).Parse("{{ .Seeders }} {{ .Leechers }}")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMacro, got)
+
+			rejections, matched := tt.filter.CheckFilter(rls)
+			assert.Equal(t, tt.wantMatch, matched, rejections)
+		})
+	}
+}
+
+func Test_announceProcessor_onLinesMatched_vars(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier:  "mock",
+		URLS:        []string{"https://mock.local/"},
+		SettingsMap: map[string]string{"passkey": "secret"},
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Lines: []domain.IndexerIRCParseLine{
+					{
+						Pattern: `New Torrent: (.*) \[(.*?) / (.*?)\] - (https?\:\/\/[^\/]+\/)torrent\/(\d+)`,
+						Vars:    []string{"torrentName", "taxonomy", "genre", "baseUrl", "torrentId"},
+					},
+				},
+				Match: domain.IndexerIRCParseMatch{
+					TorrentURL: "{{ .baseUrl }}download/{{ .torrentId }}?passkey={{ .passkey }}",
+				},
+			},
+		},
+	}
+
+	log := zerolog.Nop()
+	vars := map[string]string{}
+
+	line := "New Torrent: That Show S01E01 1080p WEB-DL DDP5.1 H.264-GROUP [TV/HD / Documentary] - https://mock.local/torrent/1234"
+	match, err := indexer.ParseLine(&log, def.IRC.Parse.Lines[0].Pattern, def.IRC.Parse.Lines[0].Vars, vars, line, false)
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	a := &announceProcessor{log: log}
+	rls := domain.NewRelease(def.Identifier)
+
+	err = a.onLinesMatched(def, vars, rls)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TV/HD", rls.Vars["taxonomy"])
+	assert.Equal(t, "Documentary", rls.Vars["genre"])
+	assert.Equal(t, "1234", rls.Vars["torrentId"])
+
+	// settings are merged for the torrent url but must not end up in the vars
+	_, ok := rls.Vars["passkey"]
+	assert.False(t, ok)
+
+	action := &domain.Action{
+		Name:     "vars",
+		Type:     domain.ActionTypeQbittorrent,
+		SavePath: `/data/{{ index .Vars "taxonomy" }}/{{ index .Vars "genre" }}`,
+		Category: `{{ index .Vars "missing" }}`,
+	}
+
+	err = action.ParseMacros(rls)
+	assert.NoError(t, err)
+	// vars are path safe in the save path, the separator in the value can't add a directory
+	assert.Equal(t, "/data/TV_HD/Documentary", action.SavePath)
+	assert.Equal(t, "", action.Category)
+}
@@ -82,9 +82,90 @@ logLevel = "DEBUG"
 #
 checkForUpdates = true
 
+# Notification dispatch
+# Senders are notified in order of their dispatch order.
+# "best-effort" keeps going when a sender fails, "fail-fast" stops at the first failure.
+#
+# Default: "best-effort"
+#
+# Options: "best-effort", "fail-fast"
+#
+#notificationDispatch = "best-effort"
+
+# Health check TTL
+# Seconds to cache the download client and IRC checks of /api/healthz/status.
+#
+# Default: 60
+#
+#healthCheckTTL = 60
+
+# Feed jitter
+# Delay the fetches of each feed by a random part of its interval, in percent of the interval.
+# Spreads feeds that would otherwise all be fetched at the same time.
+#
+# Default: 0
+#
+#feedJitter = 0
+
+# Exec concurrency
+# Max number of exec actions running at the same time, further commands wait for a free slot.
+# Actions can set a lower limit of their own. 0 means unlimited.
+#
+# Default: 0
+#
+#execConcurrency = 0
+
+# Torrent download retries
+# Attempts to download a torrent file when the tracker responds with a server error or times out.
+# The delay in seconds between attempts doubles after every attempt. Not found is never retried.
+#
+# Default: 3, 3
+#
+#downloadRetries = 3
+#downloadRetryDelay = 3
+
+# Indexer limits
+# Max number of releases of a single indexer processed at the same time, and max releases of an indexer started per minute.
+# Releases over the limit wait for their indexer, releases of other indexers are not held up. 0 means unlimited.
+#
+# Default: 0, 0
+#
+#indexerConcurrency = 0
+#indexerRateLimit = 0
+
+# Exec allowed dirs
+# Only allow exec actions to run programs inside these directories. Shell commands are rejected when set.
+#
+# Default: [] (any program)
+#
+#execAllowedDirs = ["/scripts"]
+
+# Webhook host policy
+# Hostnames, IPs or CIDR ranges webhook actions can't send to, and ones that are always allowed.
+# A hostname starting with a dot also matches its subdomains.
+# With webhookBlockPrivate the resolved address can't be loopback, private or link-local (eg. cloud metadata endpoints).
+#
+# Default: [], [], false
+#
+#webhookDeniedHosts = ["169.254.169.254", ".internal"]
+#webhookAllowedHosts = ["127.0.0.1"]
+#webhookBlockPrivate = false
+
 # Session secret
 #
 sessionSecret = "{{ .sessionSecret }}"
+
+# Release group tiers
+# Trust tier of release groups, available as the GroupTier macro and for the min group tier of filters.
+# A higher tier is more trusted, groups not listed get the default tier. Keep the table at the end of the file.
+#
+# Default: 0
+#
+#groupTierDefault = 0
+#
+#[groupTiers]
+#GROUP = 3
+#OTHERGROUP = 1
 `
 
 func (c *AppConfig) writeConfig(configPath string, configFile string) error {
@@ -186,25 +267,29 @@ func New(configPath string, version string) *AppConfig {
 
 func (c *AppConfig) defaults() {
 	c.Config = &domain.Config{
-		Version:             "dev",
-		Host:                "localhost",
-		Port:                7474,
-		LogLevel:            "TRACE",
-		LogPath:             "",
-		LogMaxSize:          50,
-		LogMaxBackups:       3,
-		BaseURL:             "/",
-		SessionSecret:       api.GenerateSecureToken(16),
-		CustomDefinitions:   "",
-		CheckForUpdates:     true,
-		DatabaseType:        "sqlite",
-		PostgresHost:        "",
-		PostgresPort:        0,
-		PostgresDatabase:    "",
-		PostgresUser:        "",
-		PostgresPass:        "",
-		PostgresSSLMode:     "disable",
-		PostgresExtraParams: "",
+		Version:              "dev",
+		Host:                 "localhost",
+		Port:                 7474,
+		LogLevel:             "TRACE",
+		LogPath:              "",
+		LogMaxSize:           50,
+		LogMaxBackups:        3,
+		BaseURL:              "/",
+		SessionSecret:        api.GenerateSecureToken(16),
+		CustomDefinitions:    "",
+		CheckForUpdates:      true,
+		NotificationDispatch: string(domain.NotificationDispatchBestEffort),
+		HealthCheckTTL:       60,
+		DownloadRetries:      3,
+		DownloadRetryDelay:   3,
+		DatabaseType:         "sqlite",
+		PostgresHost:         "",
+		PostgresPort:         0,
+		PostgresDatabase:     "",
+		PostgresUser:         "",
+		PostgresPass:         "",
+		PostgresSSLMode:      "disable",
+		PostgresExtraParams:  "",
 	}
 
 }
@@ -261,6 +346,82 @@ func (c *AppConfig) loadFromEnv() {
 		c.Config.CheckForUpdates = strings.EqualFold(strings.ToLower(v), "true")
 	}
 
+	if v := os.Getenv(prefix + "NOTIFICATION_DISPATCH"); v != "" {
+		c.Config.NotificationDispatch = v
+	}
+
+	if v := os.Getenv(prefix + "HEALTH_CHECK_TTL"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.HealthCheckTTL = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "FEED_JITTER"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 && i <= 100 {
+			c.Config.FeedJitter = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "EXEC_CONCURRENCY"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.ExecConcurrency = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "DOWNLOAD_RETRIES"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.DownloadRetries = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "DOWNLOAD_RETRY_DELAY"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i > 0 {
+			c.Config.DownloadRetryDelay = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "INDEXER_CONCURRENCY"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.IndexerConcurrency = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "INDEXER_RATE_LIMIT"); v != "" {
+		i, _ := strconv.ParseInt(v, 10, 32)
+		if i >= 0 {
+			c.Config.IndexerRateLimit = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "GROUP_TIER_DEFAULT"); v != "" {
+		i, err := strconv.ParseInt(v, 10, 32)
+		if err == nil {
+			c.Config.GroupTierDefault = int(i)
+		}
+	}
+
+	if v := os.Getenv(prefix + "EXEC_ALLOWED_DIRS"); v != "" {
+		c.Config.ExecAllowedDirs = splitList(v)
+	}
+
+	if v := os.Getenv(prefix + "WEBHOOK_ALLOWED_HOSTS"); v != "" {
+		c.Config.WebhookAllowedHosts = splitList(v)
+	}
+
+	if v := os.Getenv(prefix + "WEBHOOK_DENIED_HOSTS"); v != "" {
+		c.Config.WebhookDeniedHosts = splitList(v)
+	}
+
+	if v := os.Getenv(prefix + "WEBHOOK_BLOCK_PRIVATE"); v != "" {
+		c.Config.WebhookBlockPrivate = strings.EqualFold(v, "true")
+	}
+
 	if v := os.Getenv(prefix + "DATABASE_TYPE"); v != "" {
 		if validDatabaseType(v) {
 			c.Config.DatabaseType = v
@@ -299,6 +460,18 @@ func (c *AppConfig) loadFromEnv() {
 	}
 }
 
+// splitList splits a comma separated env value
+func splitList(v string) []string {
+	var list []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
+
 func validDatabaseType(v string) bool {
 	valid := []string{"sqlite", "postgres"}
 	for _, s := range valid {
@@ -13,6 +13,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -68,6 +69,7 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int, a
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_env",
 			"watch_folder",
 			"category",
 			"tags",
@@ -85,12 +87,52 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int, a
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_target_peers",
+			"verify_start",
+			"recheck_resume",
+			"timeout",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"grpc_method",
+			"priority",
+			"pp_script",
+			"webhook_headers",
+			"webhook_validate_json",
+			"path_os",
+			"exec_workdir",
+			"exec_shell",
+			"preset",
+			"webhook_success_when",
+			"top_of_queue",
+			"queue_position",
+			"archive_path",
+			"archive_filename",
+			"archive_mode",
+			"min_free_space",
+			"auto_tmm",
+			"webhook_file_field",
+			"comment",
+			"exec_concurrency",
+			"resume_delay",
+			"rescan_client_id",
+			"delay",
+			"disable_after_failures",
+			"bandwidth_priority",
+			"peer_limit",
+			"cleanup_min_age",
+			"cleanup_min_ratio",
+			"cleanup_delete_data",
+			"cleanup_dry_run",
+			"move_completed",
+			"move_completed_path",
+			"stop_condition",
+			"grpc_tls_skip_verify",
 			"external_client_id",
 			"client_id",
+			"template_id",
+			"template_overrides",
 		).
 		From("action").
 		Where(sq.Eq{"filter_id": filterID})
@@ -115,14 +157,14 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int, a
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, grpcMethod, priority, ppScript, pathOS, execWorkDir, preset, webhookSuccessWhen, archivePath, archiveFilename, archiveMode, minFreeSpace, webhookFileField, comment sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
-		var externalClientID, clientID sql.NullInt32
+		var externalClientID, clientID, templateID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &a.ExecEnv, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceTargetPeers, &a.VerifyStart, &a.RecheckResume, &a.Timeout, &webhookHost, &webhookType, &webhookMethod, &webhookData, &grpcMethod, &priority, &ppScript, pq.Array(&a.WebhookHeaders), &a.WebhookValidateJSON, &pathOS, &execWorkDir, &a.ExecShell, &preset, &webhookSuccessWhen, &a.TopOfQueue, &a.QueuePosition, &archivePath, &archiveFilename, &archiveMode, &minFreeSpace, &a.AutoTMM, &webhookFileField, &comment, &a.ExecConcurrency, &a.ResumeDelay, &a.RescanClientID, &a.Delay, &a.DisableAfterFailures, &a.BandwidthPriority, &a.PeerLimit, &a.CleanupMinAge, &a.CleanupMinRatio, &a.CleanupDeleteData, &a.CleanupDryRun, &a.MoveCompleted, &a.MoveCompletedPath, &a.StopCondition, &a.GrpcTLSSkipVerify, &externalClientID, &clientID, &templateID, pq.Array(&a.TemplateOverrides)); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -136,6 +178,16 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int, a
 		a.Paused = paused.Bool
 		a.IgnoreRules = ignoreRules.Bool
 		a.ContentLayout = domain.ActionContentLayout(contentLayout.String)
+		a.PathOS = domain.ActionPathOS(pathOS.String)
+		a.ExecWorkDir = execWorkDir.String
+		a.Preset = preset.String
+		a.WebhookSuccessWhen = webhookSuccessWhen.String
+		a.Comment = comment.String
+		a.WebhookFileField = webhookFileField.String
+		a.MinFreeSpace = minFreeSpace.String
+		a.ArchivePath = archivePath.String
+		a.ArchiveFilename = archiveFilename.String
+		a.ArchiveMode = domain.ActionArchiveMode(archiveMode.String)
 
 		a.LimitDownloadSpeed = limitDl.Int64
 		a.LimitUploadSpeed = limitUl.Int64
@@ -146,9 +198,13 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int, a
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.GrpcMethod = grpcMethod.String
+		a.Priority = priority.String
+		a.PostProcessScript = ppScript.String
 
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ClientID = clientID.Int32
+		a.TemplateID = int(templateID.Int32)
 
 		actions = append(actions, &a)
 	}
@@ -218,6 +274,7 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_env",
 			"watch_folder",
 			"category",
 			"tags",
@@ -235,12 +292,52 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_target_peers",
+			"verify_start",
+			"recheck_resume",
+			"timeout",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"grpc_method",
+			"priority",
+			"pp_script",
+			"webhook_headers",
+			"webhook_validate_json",
+			"path_os",
+			"exec_workdir",
+			"exec_shell",
+			"preset",
+			"webhook_success_when",
+			"top_of_queue",
+			"queue_position",
+			"archive_path",
+			"archive_filename",
+			"archive_mode",
+			"min_free_space",
+			"auto_tmm",
+			"webhook_file_field",
+			"comment",
+			"exec_concurrency",
+			"resume_delay",
+			"rescan_client_id",
+			"delay",
+			"disable_after_failures",
+			"bandwidth_priority",
+			"peer_limit",
+			"cleanup_min_age",
+			"cleanup_min_ratio",
+			"cleanup_delete_data",
+			"cleanup_dry_run",
+			"move_completed",
+			"move_completed_path",
+			"stop_condition",
+			"grpc_tls_skip_verify",
 			"external_client_id",
 			"client_id",
+			"template_id",
+			"template_overrides",
 		).
 		From("action")
 
@@ -260,13 +357,13 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, grpcMethod, priority, ppScript, pathOS, execWorkDir, preset, webhookSuccessWhen, archivePath, archiveFilename, archiveMode, minFreeSpace, webhookFileField, comment sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
-		var externalClientID, clientID sql.NullInt32
+		var externalClientID, clientID, templateID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &a.ExecEnv, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceTargetPeers, &a.VerifyStart, &a.RecheckResume, &a.Timeout, &webhookHost, &webhookType, &webhookMethod, &webhookData, &grpcMethod, &priority, &ppScript, pq.Array(&a.WebhookHeaders), &a.WebhookValidateJSON, &pathOS, &execWorkDir, &a.ExecShell, &preset, &webhookSuccessWhen, &a.TopOfQueue, &a.QueuePosition, &archivePath, &archiveFilename, &archiveMode, &minFreeSpace, &a.AutoTMM, &webhookFileField, &comment, &a.ExecConcurrency, &a.ResumeDelay, &a.RescanClientID, &a.Delay, &a.DisableAfterFailures, &a.BandwidthPriority, &a.PeerLimit, &a.CleanupMinAge, &a.CleanupMinRatio, &a.CleanupDeleteData, &a.CleanupDryRun, &a.MoveCompleted, &a.MoveCompletedPath, &a.StopCondition, &a.GrpcTLSSkipVerify, &externalClientID, &clientID, &templateID, pq.Array(&a.TemplateOverrides)); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -277,6 +374,16 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.Paused = paused.Bool
 		a.IgnoreRules = ignoreRules.Bool
 		a.ContentLayout = domain.ActionContentLayout(contentLayout.String)
+		a.PathOS = domain.ActionPathOS(pathOS.String)
+		a.ExecWorkDir = execWorkDir.String
+		a.Preset = preset.String
+		a.WebhookSuccessWhen = webhookSuccessWhen.String
+		a.Comment = comment.String
+		a.WebhookFileField = webhookFileField.String
+		a.MinFreeSpace = minFreeSpace.String
+		a.ArchivePath = archivePath.String
+		a.ArchiveFilename = archiveFilename.String
+		a.ArchiveMode = domain.ActionArchiveMode(archiveMode.String)
 
 		a.LimitDownloadSpeed = limitDl.Int64
 		a.LimitUploadSpeed = limitUl.Int64
@@ -287,9 +394,13 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.GrpcMethod = grpcMethod.String
+		a.Priority = priority.String
+		a.PostProcessScript = ppScript.String
 
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ClientID = clientID.Int32
+		a.TemplateID = int(templateID.Int32)
 
 		actions = append(actions, a)
 
@@ -310,6 +421,7 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_env",
 			"watch_folder",
 			"category",
 			"tags",
@@ -327,12 +439,52 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_target_peers",
+			"verify_start",
+			"recheck_resume",
+			"timeout",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"grpc_method",
+			"priority",
+			"pp_script",
+			"webhook_headers",
+			"webhook_validate_json",
+			"path_os",
+			"exec_workdir",
+			"exec_shell",
+			"preset",
+			"webhook_success_when",
+			"top_of_queue",
+			"queue_position",
+			"archive_path",
+			"archive_filename",
+			"archive_mode",
+			"min_free_space",
+			"auto_tmm",
+			"webhook_file_field",
+			"comment",
+			"exec_concurrency",
+			"resume_delay",
+			"rescan_client_id",
+			"delay",
+			"disable_after_failures",
+			"bandwidth_priority",
+			"peer_limit",
+			"cleanup_min_age",
+			"cleanup_min_ratio",
+			"cleanup_delete_data",
+			"cleanup_dry_run",
+			"move_completed",
+			"move_completed_path",
+			"stop_condition",
+			"grpc_tls_skip_verify",
 			"external_client_id",
 			"client_id",
+			"template_id",
+			"template_overrides",
 			"filter_id",
 		).
 		From("action").
@@ -354,13 +506,13 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 
 	var a domain.Action
 
-	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, grpcMethod, priority, ppScript, pathOS, execWorkDir, preset, webhookSuccessWhen, archivePath, archiveFilename, archiveMode, minFreeSpace, webhookFileField, comment sql.NullString
 	var limitUl, limitDl, limitSeedTime sql.NullInt64
 	var limitRatio sql.NullFloat64
-	var externalClientID, clientID, filterID sql.NullInt32
+	var externalClientID, clientID, templateID, filterID sql.NullInt32
 	var paused, ignoreRules sql.NullBool
 
-	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &clientID, &filterID); err != nil {
+	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &a.ExecEnv, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &a.ReAnnounceTargetPeers, &a.VerifyStart, &a.RecheckResume, &a.Timeout, &webhookHost, &webhookType, &webhookMethod, &webhookData, &grpcMethod, &priority, &ppScript, pq.Array(&a.WebhookHeaders), &a.WebhookValidateJSON, &pathOS, &execWorkDir, &a.ExecShell, &preset, &webhookSuccessWhen, &a.TopOfQueue, &a.QueuePosition, &archivePath, &archiveFilename, &archiveMode, &minFreeSpace, &a.AutoTMM, &webhookFileField, &comment, &a.ExecConcurrency, &a.ResumeDelay, &a.RescanClientID, &a.Delay, &a.DisableAfterFailures, &a.BandwidthPriority, &a.PeerLimit, &a.CleanupMinAge, &a.CleanupMinRatio, &a.CleanupDeleteData, &a.CleanupDryRun, &a.MoveCompleted, &a.MoveCompletedPath, &a.StopCondition, &a.GrpcTLSSkipVerify, &externalClientID, &clientID, &templateID, pq.Array(&a.TemplateOverrides), &filterID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -378,6 +530,16 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 	a.Paused = paused.Bool
 	a.IgnoreRules = ignoreRules.Bool
 	a.ContentLayout = domain.ActionContentLayout(contentLayout.String)
+	a.PathOS = domain.ActionPathOS(pathOS.String)
+	a.ExecWorkDir = execWorkDir.String
+	a.Preset = preset.String
+	a.WebhookSuccessWhen = webhookSuccessWhen.String
+	a.Comment = comment.String
+	a.WebhookFileField = webhookFileField.String
+	a.MinFreeSpace = minFreeSpace.String
+	a.ArchivePath = archivePath.String
+	a.ArchiveFilename = archiveFilename.String
+	a.ArchiveMode = domain.ActionArchiveMode(archiveMode.String)
 
 	a.LimitDownloadSpeed = limitDl.Int64
 	a.LimitUploadSpeed = limitUl.Int64
@@ -388,9 +550,13 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 	a.WebhookType = webhookType.String
 	a.WebhookMethod = webhookMethod.String
 	a.WebhookData = webhookData.String
+	a.GrpcMethod = grpcMethod.String
+	a.Priority = priority.String
+	a.PostProcessScript = ppScript.String
 
 	a.ExternalDownloadClientID = externalClientID.Int32
 	a.ClientID = clientID.Int32
+	a.TemplateID = int(templateID.Int32)
 	a.FilterID = int(filterID.Int32)
 
 	return &a, nil
@@ -437,6 +603,10 @@ func (r *ActionRepo) DeleteByFilterID(ctx context.Context, filterID int) error {
 }
 
 func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	return r.store(ctx, r.db.handler, action)
+}
+
+func (r *ActionRepo) store(ctx context.Context, runner sq.BaseRunner, action domain.Action) (*domain.Action, error) {
 	queryBuilder := r.db.squirrel.
 		Insert("action").
 		Columns(
@@ -445,6 +615,7 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_env",
 			"watch_folder",
 			"category",
 			"tags",
@@ -462,12 +633,52 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"reannounce_delete",
 			"reannounce_interval",
 			"reannounce_max_attempts",
+			"reannounce_target_peers",
+			"verify_start",
+			"recheck_resume",
+			"timeout",
 			"webhook_host",
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"grpc_method",
+			"priority",
+			"pp_script",
+			"webhook_headers",
+			"webhook_validate_json",
+			"path_os",
+			"exec_workdir",
+			"exec_shell",
+			"preset",
+			"webhook_success_when",
+			"top_of_queue",
+			"queue_position",
+			"archive_path",
+			"archive_filename",
+			"archive_mode",
+			"min_free_space",
+			"auto_tmm",
+			"webhook_file_field",
+			"comment",
+			"exec_concurrency",
+			"resume_delay",
+			"rescan_client_id",
+			"delay",
+			"disable_after_failures",
+			"bandwidth_priority",
+			"peer_limit",
+			"cleanup_min_age",
+			"cleanup_min_ratio",
+			"cleanup_delete_data",
+			"cleanup_dry_run",
+			"move_completed",
+			"move_completed_path",
+			"stop_condition",
+			"grpc_tls_skip_verify",
 			"external_client_id",
 			"client_id",
+			"template_id",
+			"template_overrides",
 			"filter_id",
 		).
 		Values(
@@ -476,6 +687,7 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			action.Enabled,
 			toNullString(action.ExecCmd),
 			toNullString(action.ExecArgs),
+			action.ExecEnv,
 			toNullString(action.WatchFolder),
 			toNullString(action.Category),
 			toNullString(action.Tags),
@@ -493,15 +705,55 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			action.ReAnnounceDelete,
 			action.ReAnnounceInterval,
 			action.ReAnnounceMaxAttempts,
+			action.ReAnnounceTargetPeers,
+			action.VerifyStart,
+			action.RecheckResume,
+			action.Timeout,
 			toNullString(action.WebhookHost),
 			toNullString(action.WebhookType),
 			toNullString(action.WebhookMethod),
 			toNullString(action.WebhookData),
+			toNullString(action.GrpcMethod),
+			toNullString(action.Priority),
+			toNullString(action.PostProcessScript),
+			pq.Array(action.WebhookHeaders),
+			action.WebhookValidateJSON,
+			toNullString(string(action.PathOS)),
+			toNullString(action.ExecWorkDir),
+			action.ExecShell,
+			toNullString(action.Preset),
+			toNullString(action.WebhookSuccessWhen),
+			action.TopOfQueue,
+			action.QueuePosition,
+			toNullString(action.ArchivePath),
+			toNullString(action.ArchiveFilename),
+			toNullString(string(action.ArchiveMode)),
+			toNullString(action.MinFreeSpace),
+			action.AutoTMM,
+			toNullString(action.WebhookFileField),
+			toNullString(action.Comment),
+			action.ExecConcurrency,
+			action.ResumeDelay,
+			action.RescanClientID,
+			action.Delay,
+			action.DisableAfterFailures,
+			string(action.BandwidthPriority),
+			action.PeerLimit,
+			action.CleanupMinAge,
+			action.CleanupMinRatio,
+			action.CleanupDeleteData,
+			action.CleanupDryRun,
+			action.MoveCompleted,
+			action.MoveCompletedPath,
+			action.StopCondition,
+			action.GrpcTLSSkipVerify,
 			toNullInt32(action.ExternalDownloadClientID),
 			toNullInt32(action.ClientID),
+			toNullInt32(int32(action.TemplateID)),
+			pq.Array(action.TemplateOverrides),
 			toNullInt32(int32(action.FilterID)),
 		).
-		Suffix("RETURNING id").RunWith(r.db.handler)
+		Suffix("RETURNING id").RunWith(runner)
 
 	// return values
 	var retID int64
@@ -525,6 +777,7 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("enabled", action.Enabled).
 		Set("exec_cmd", toNullString(action.ExecCmd)).
 		Set("exec_args", toNullString(action.ExecArgs)).
+		Set("exec_env", action.ExecEnv).
 		Set("watch_folder", toNullString(action.WatchFolder)).
 		Set("category", toNullString(action.Category)).
 		Set("tags", toNullString(action.Tags)).
@@ -542,12 +795,52 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("reannounce_delete", action.ReAnnounceDelete).
 		Set("reannounce_interval", action.ReAnnounceInterval).
 		Set("reannounce_max_attempts", action.ReAnnounceMaxAttempts).
+		Set("reannounce_target_peers", action.ReAnnounceTargetPeers).
+		Set("verify_start", action.VerifyStart).
+		Set("recheck_resume", action.RecheckResume).
+		Set("timeout", action.Timeout).
 		Set("webhook_host", toNullString(action.WebhookHost)).
 		Set("webhook_type", toNullString(action.WebhookType)).
 		Set("webhook_method", toNullString(action.WebhookMethod)).
 		Set("webhook_data", toNullString(action.WebhookData)).
+		Set("grpc_method", toNullString(action.GrpcMethod)).
+		Set("priority", toNullString(action.Priority)).
+		Set("pp_script", toNullString(action.PostProcessScript)).
+		Set("webhook_headers", pq.Array(action.WebhookHeaders)).
+		Set("webhook_validate_json", action.WebhookValidateJSON).
+		Set("path_os", toNullString(string(action.PathOS))).
+		Set("exec_workdir", toNullString(action.ExecWorkDir)).
+		Set("exec_shell", action.ExecShell).
+		Set("preset", toNullString(action.Preset)).
+		Set("webhook_success_when", toNullString(action.WebhookSuccessWhen)).
+		Set("top_of_queue", action.TopOfQueue).
+		Set("queue_position", action.QueuePosition).
+		Set("archive_path", toNullString(action.ArchivePath)).
+		Set("archive_filename", toNullString(action.ArchiveFilename)).
+		Set("archive_mode", toNullString(string(action.ArchiveMode))).
+		Set("min_free_space", toNullString(action.MinFreeSpace)).
+		Set("auto_tmm", action.AutoTMM).
+		Set("webhook_file_field", toNullString(action.WebhookFileField)).
+		Set("comment", toNullString(action.Comment)).
+		Set("exec_concurrency", action.ExecConcurrency).
+		Set("resume_delay", action.ResumeDelay).
+		Set("rescan_client_id", action.RescanClientID).
+		Set("delay", action.Delay).
+		Set("disable_after_failures", action.DisableAfterFailures).
+		Set("bandwidth_priority", string(action.BandwidthPriority)).
+		Set("peer_limit", action.PeerLimit).
+		Set("cleanup_min_age", action.CleanupMinAge).
+		Set("cleanup_min_ratio", action.CleanupMinRatio).
+		Set("cleanup_delete_data", action.CleanupDeleteData).
+		Set("cleanup_dry_run", action.CleanupDryRun).
+		Set("move_completed", action.MoveCompleted).
+		Set("move_completed_path", action.MoveCompletedPath).
+		Set("stop_condition", action.StopCondition).
+		Set("grpc_tls_skip_verify", action.GrpcTLSSkipVerify).
 		Set("external_client_id", toNullInt32(action.ExternalDownloadClientID)).
 		Set("client_id", toNullInt32(action.ClientID)).
+		Set("template_id", toNullInt32(int32(action.TemplateID))).
+		Set("template_overrides", pq.Array(action.TemplateOverrides)).
 		Set("filter_id", toNullInt32(int32(action.FilterID))).
 		Where(sq.Eq{"id": action.ID})
 
@@ -584,6 +877,7 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 				Set("enabled", action.Enabled).
 				Set("exec_cmd", toNullString(action.ExecCmd)).
 				Set("exec_args", toNullString(action.ExecArgs)).
+				Set("exec_env", action.ExecEnv).
 				Set("watch_folder", toNullString(action.WatchFolder)).
 				Set("category", toNullString(action.Category)).
 				Set("tags", toNullString(action.Tags)).
@@ -601,12 +895,52 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 				Set("reannounce_delete", action.ReAnnounceDelete).
 				Set("reannounce_interval", action.ReAnnounceInterval).
 				Set("reannounce_max_attempts", action.ReAnnounceMaxAttempts).
+				Set("reannounce_target_peers", action.ReAnnounceTargetPeers).
+				Set("verify_start", action.VerifyStart).
+				Set("recheck_resume", action.RecheckResume).
+				Set("timeout", action.Timeout).
 				Set("webhook_host", toNullString(action.WebhookHost)).
 				Set("webhook_type", toNullString(action.WebhookType)).
 				Set("webhook_method", toNullString(action.WebhookMethod)).
 				Set("webhook_data", toNullString(action.WebhookData)).
+				Set("grpc_method", toNullString(action.GrpcMethod)).
+				Set("priority", toNullString(action.Priority)).
+				Set("pp_script", toNullString(action.PostProcessScript)).
+				Set("webhook_headers", pq.Array(action.WebhookHeaders)).
+				Set("webhook_validate_json", action.WebhookValidateJSON).
+				Set("path_os", toNullString(string(action.PathOS))).
+				Set("exec_workdir", toNullString(action.ExecWorkDir)).
+				Set("exec_shell", action.ExecShell).
+				Set("preset", toNullString(action.Preset)).
+				Set("webhook_success_when", toNullString(action.WebhookSuccessWhen)).
+				Set("top_of_queue", action.TopOfQueue).
+				Set("queue_position", action.QueuePosition).
+				Set("archive_path", toNullString(action.ArchivePath)).
+				Set("archive_filename", toNullString(action.ArchiveFilename)).
+				Set("archive_mode", toNullString(string(action.ArchiveMode))).
+				Set("min_free_space", toNullString(action.MinFreeSpace)).
+				Set("auto_tmm", action.AutoTMM).
+				Set("webhook_file_field", toNullString(action.WebhookFileField)).
+				Set("comment", toNullString(action.Comment)).
+				Set("exec_concurrency", action.ExecConcurrency).
+				Set("resume_delay", action.ResumeDelay).
+				Set("rescan_client_id", action.RescanClientID).
+				Set("delay", action.Delay).
+				Set("disable_after_failures", action.DisableAfterFailures).
+				Set("bandwidth_priority", string(action.BandwidthPriority)).
+				Set("peer_limit", action.PeerLimit).
+				Set("cleanup_min_age", action.CleanupMinAge).
+				Set("cleanup_min_ratio", action.CleanupMinRatio).
+				Set("cleanup_delete_data", action.CleanupDeleteData).
+				Set("cleanup_dry_run", action.CleanupDryRun).
+				Set("move_completed", action.MoveCompleted).
+				Set("move_completed_path", action.MoveCompletedPath).
+				Set("stop_condition", action.StopCondition).
+				Set("grpc_tls_skip_verify", action.GrpcTLSSkipVerify).
 				Set("external_client_id", toNullInt32(action.ExternalDownloadClientID)).
 				Set("client_id", toNullInt32(action.ClientID)).
+				Set("template_id", toNullInt32(int32(action.TemplateID))).
+				Set("template_overrides", pq.Array(action.TemplateOverrides)).
 				Set("filter_id", toNullInt64(filterID)).
 				Where(sq.Eq{"id": action.ID})
 
@@ -630,6 +964,7 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					"enabled",
 					"exec_cmd",
 					"exec_args",
+					"exec_env",
 					"watch_folder",
 					"category",
 					"tags",
@@ -647,12 +982,52 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					"reannounce_delete",
 					"reannounce_interval",
 					"reannounce_max_attempts",
+					"reannounce_target_peers",
+					"verify_start",
+					"recheck_resume",
+					"timeout",
 					"webhook_host",
 					"webhook_type",
 					"webhook_method",
 					"webhook_data",
+					"grpc_method",
+					"priority",
+					"pp_script",
+					"webhook_headers",
+					"webhook_validate_json",
+					"path_os",
+					"exec_workdir",
+					"exec_shell",
+					"preset",
+					"webhook_success_when",
+					"top_of_queue",
+					"queue_position",
+					"archive_path",
+					"archive_filename",
+					"archive_mode",
+					"min_free_space",
+					"auto_tmm",
+					"webhook_file_field",
+					"comment",
+					"exec_concurrency",
+					"resume_delay",
+					"rescan_client_id",
+					"delay",
+					"disable_after_failures",
+					"bandwidth_priority",
+					"peer_limit",
+					"cleanup_min_age",
+					"cleanup_min_ratio",
+					"cleanup_delete_data",
+					"cleanup_dry_run",
+					"move_completed",
+					"move_completed_path",
+					"stop_condition",
+					"grpc_tls_skip_verify",
 					"external_client_id",
 					"client_id",
+					"template_id",
+					"template_overrides",
 					"filter_id",
 				).
 				Values(
@@ -661,6 +1036,7 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					action.Enabled,
 					toNullString(action.ExecCmd),
 					toNullString(action.ExecArgs),
+					action.ExecEnv,
 					toNullString(action.WatchFolder),
 					toNullString(action.Category),
 					toNullString(action.Tags),
@@ -678,12 +1054,52 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					action.ReAnnounceDelete,
 					action.ReAnnounceInterval,
 					action.ReAnnounceMaxAttempts,
+					action.ReAnnounceTargetPeers,
+					action.VerifyStart,
+					action.RecheckResume,
+					action.Timeout,
 					toNullString(action.WebhookHost),
 					toNullString(action.WebhookType),
 					toNullString(action.WebhookMethod),
 					toNullString(action.WebhookData),
+					toNullString(action.GrpcMethod),
+					toNullString(action.Priority),
+					toNullString(action.PostProcessScript),
+					pq.Array(action.WebhookHeaders),
+					action.WebhookValidateJSON,
+					toNullString(string(action.PathOS)),
+					toNullString(action.ExecWorkDir),
+					action.ExecShell,
+					toNullString(action.Preset),
+					toNullString(action.WebhookSuccessWhen),
+					action.TopOfQueue,
+					action.QueuePosition,
+					toNullString(action.ArchivePath),
+					toNullString(action.ArchiveFilename),
+					toNullString(string(action.ArchiveMode)),
+					toNullString(action.MinFreeSpace),
+					action.AutoTMM,
+					toNullString(action.WebhookFileField),
+					toNullString(action.Comment),
+					action.ExecConcurrency,
+					action.ResumeDelay,
+					action.RescanClientID,
+					action.Delay,
+					action.DisableAfterFailures,
+					string(action.BandwidthPriority),
+					action.PeerLimit,
+					action.CleanupMinAge,
+					action.CleanupMinRatio,
+					action.CleanupDeleteData,
+					action.CleanupDryRun,
+					action.MoveCompleted,
+					action.MoveCompletedPath,
+					action.StopCondition,
+					action.GrpcTLSSkipVerify,
 					toNullInt32(action.ExternalDownloadClientID),
 					toNullInt32(action.ClientID),
+					toNullInt32(int32(action.TemplateID)),
+					pq.Array(action.TemplateOverrides),
 					toNullInt64(filterID),
 				).
 				Suffix("RETURNING id").RunWith(tx)
@@ -736,3 +1152,44 @@ func (r *ActionRepo) ToggleEnabled(actionID int) error {
 
 	return nil
 }
+
+// Clone copies the action onto the filters in one transaction
+func (r *ActionRepo) Clone(ctx context.Context, req *domain.CloneActionRequest) ([]*domain.Action, error) {
+	source, err := r.Get(ctx, &domain.GetActionRequest{Id: req.ActionID})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	clones := make([]*domain.Action, 0, len(req.FilterIDs))
+
+	for _, filterID := range req.FilterIDs {
+		clone := source.Copy()
+		clone.FilterID = filterID
+
+		if req.Name != "" {
+			clone.Name = req.Name
+		}
+
+		stored, err := r.store(ctx, tx, clone)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not clone action %d to filter %d", req.ActionID, filterID)
+		}
+
+		clones = append(clones, stored)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "error cloning action")
+	}
+
+	r.log.Debug().Msgf("action.clone: cloned %d to filters %v", req.ActionID, req.FilterIDs)
+
+	return clones, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+type ActionResultRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewActionResultRepo(log logger.Logger, db *DB) domain.ActionResultRepo {
+	return &ActionResultRepo{
+		log: log.With().Str("repo", "action_result").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ActionResultRepo) Store(ctx context.Context, result *domain.ActionResult) error {
+	var fields sql.NullString
+	if len(result.Fields) > 0 {
+		data, err := json.Marshal(result.Fields)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal action result fields")
+		}
+		fields = toNullString(string(data))
+	}
+
+	rejections := result.Rejections
+	if rejections == nil {
+		rejections = []string{}
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("action_result").
+		Columns(
+			"action_id",
+			"action",
+			"type",
+			"release_id",
+			"release_name",
+			"status",
+			"error",
+			"rejections",
+			"fields",
+			"response",
+			"started_at",
+			"duration_ms",
+		).
+		Values(
+			toNullInt64(result.ActionID),
+			result.Action,
+			result.Type,
+			result.ReleaseID,
+			toNullString(result.ReleaseName),
+			result.Status,
+			toNullString(result.Error),
+			pq.Array(rejections),
+			fields,
+			toNullString(result.Response),
+			result.StartedAt,
+			result.Duration.Milliseconds(),
+		).
+		Suffix("RETURNING id").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&result.ID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Trace().Msgf("action_result.store: added new %d", result.ID)
+
+	return nil
+}
+
+func (r *ActionResultRepo) Find(ctx context.Context, params domain.ActionResultQueryParams) ([]domain.ActionResult, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"action_id",
+			"action",
+			"type",
+			"release_id",
+			"release_name",
+			"status",
+			"error",
+			"rejections",
+			"fields",
+			"response",
+			"started_at",
+			"duration_ms",
+		).
+		From("action_result").
+		OrderBy("id DESC")
+
+	if params.ReleaseID > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"release_id": params.ReleaseID})
+	}
+
+	if params.Status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"status": params.Status})
+	}
+
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(params.Limit)
+	} else {
+		queryBuilder = queryBuilder.Limit(100)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	results := make([]domain.ActionResult, 0)
+	for rows.Next() {
+		var res domain.ActionResult
+
+		var actionID sql.NullInt64
+		var releaseName, errStr, fields, response sql.NullString
+		var durationMs int64
+
+		if err := rows.Scan(&res.ID, &actionID, &res.Action, &res.Type, &res.ReleaseID, &releaseName, &res.Status, &errStr, pq.Array(&res.Rejections), &fields, &response, &res.StartedAt, &durationMs); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		res.ActionID = actionID.Int64
+		res.ReleaseName = releaseName.String
+		res.Error = errStr.String
+		res.Response = response.String
+		res.Duration = time.Duration(durationMs) * time.Millisecond
+
+		if fields.Valid && fields.String != "" {
+			if err := json.Unmarshal([]byte(fields.String), &res.Fields); err != nil {
+				return nil, errors.Wrap(err, "could not unmarshal action result fields: %d", res.ID)
+			}
+		}
+
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return results, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockActionResult(releaseID int64, status domain.ActionResultStatus) *domain.ActionResult {
+	return &domain.ActionResult{
+		Action:      "qbit movies",
+		Type:        domain.ActionTypeQbittorrent,
+		ReleaseID:   releaseID,
+		ReleaseName: "Example.Torrent.Name",
+		Status:      status,
+		Rejections:  []string{},
+		Fields: map[string]string{
+			"category":  "movies",
+			"save_path": "/downloads/movies/Example.Torrent.Name",
+		},
+		Response:  "200 OK",
+		StartedAt: time.Now(),
+		Duration:  1500 * time.Millisecond,
+	}
+}
+
+func TestActionResultRepo_Find(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		filterRepo := NewFilterRepo(log, db)
+		releaseRepo := NewReleaseRepo(log, db)
+		repo := NewActionResultRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_And_Find_By_Release_And_Status [%s]", dbType), func(t *testing.T) {
+			// Setup
+			err := filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			release := getMockRelease()
+			release.FilterID = createdFilters[0].ID
+			err = releaseRepo.Store(context.Background(), release)
+			assert.NoError(t, err)
+
+			otherRelease := getMockRelease()
+			otherRelease.FilterID = createdFilters[0].ID
+			err = releaseRepo.Store(context.Background(), otherRelease)
+			assert.NoError(t, err)
+
+			success := getMockActionResult(release.ID, domain.ActionResultStatusSuccess)
+			failed := getMockActionResult(release.ID, domain.ActionResultStatusFailed)
+			failed.Error = "could not add torrent to client"
+			skipped := getMockActionResult(otherRelease.ID, domain.ActionResultStatusSkipped)
+			skipped.Rejections = []string{"max active downloads reached"}
+
+			// Execute
+			for _, res := range []*domain.ActionResult{success, failed, skipped} {
+				err = repo.Store(context.Background(), res)
+				assert.NoError(t, err)
+				assert.NotZero(t, res.ID)
+			}
+
+			// Verify by release
+			results, err := repo.Find(context.Background(), domain.ActionResultQueryParams{ReleaseID: release.ID})
+			assert.NoError(t, err)
+			assert.Len(t, results, 2)
+
+			// newest first
+			assert.Equal(t, failed.ID, results[0].ID)
+			assert.Equal(t, domain.ActionResultStatusFailed, results[0].Status)
+			assert.Equal(t, "could not add torrent to client", results[0].Error)
+			assert.Equal(t, success.ID, results[1].ID)
+			assert.Equal(t, "movies", results[1].Fields["category"])
+			assert.Equal(t, "200 OK", results[1].Response)
+			assert.Equal(t, 1500*time.Millisecond, results[1].Duration)
+
+			// Verify by status
+			results, err = repo.Find(context.Background(), domain.ActionResultQueryParams{Status: domain.ActionResultStatusSkipped})
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+			assert.Equal(t, otherRelease.ID, results[0].ReleaseID)
+			assert.Equal(t, []string{"max active downloads reached"}, results[0].Rejections)
+
+			// Verify by release and status
+			results, err = repo.Find(context.Background(), domain.ActionResultQueryParams{ReleaseID: release.ID, Status: domain.ActionResultStatusSkipped})
+			assert.NoError(t, err)
+			assert.Len(t, results, 0)
+
+			// Cleanup
+			_ = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+		})
+
+		t.Run(fmt.Sprintf("Results_Deleted_With_Release [%s]", dbType), func(t *testing.T) {
+			// Setup
+			err := filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+
+			release := getMockRelease()
+			release.FilterID = createdFilters[0].ID
+			err = releaseRepo.Store(context.Background(), release)
+			assert.NoError(t, err)
+
+			err = repo.Store(context.Background(), getMockActionResult(release.ID, domain.ActionResultStatusSuccess))
+			assert.NoError(t, err)
+
+			// Execute
+			err = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			assert.NoError(t, err)
+
+			// Verify
+			results, err := repo.Find(context.Background(), domain.ActionResultQueryParams{ReleaseID: release.ID})
+			assert.NoError(t, err)
+			assert.Len(t, results, 0)
+
+			// Cleanup
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+		})
+	}
+}
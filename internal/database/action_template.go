@@ -0,0 +1,216 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type ActionTemplateRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewActionTemplateRepo(log logger.Logger, db *DB) domain.ActionTemplateRepo {
+	return &ActionTemplateRepo{
+		log: log.With().Str("repo", "action_template").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ActionTemplateRepo) List(ctx context.Context) ([]domain.ActionTemplate, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"config",
+			"created_at",
+			"updated_at",
+		).
+		From("action_template").
+		OrderBy("name ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	templates := make([]domain.ActionTemplate, 0)
+	for rows.Next() {
+		var t domain.ActionTemplate
+		var config sql.NullString
+
+		if err := rows.Scan(&t.ID, &t.Name, &config, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		if err := unmarshalTemplateConfig(config, &t); err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return templates, nil
+}
+
+func (r *ActionTemplateRepo) FindByID(ctx context.Context, id int) (*domain.ActionTemplate, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"config",
+			"created_at",
+			"updated_at",
+		).
+		From("action_template").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	var t domain.ActionTemplate
+	var config sql.NullString
+
+	if err := row.Scan(&t.ID, &t.Name, &config, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	if err := unmarshalTemplateConfig(config, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *ActionTemplateRepo) Store(ctx context.Context, template *domain.ActionTemplate) error {
+	config, err := marshalTemplateConfig(template)
+	if err != nil {
+		return err
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("action_template").
+		Columns("name", "config").
+		Values(template.Name, config).
+		Suffix("RETURNING id, created_at, updated_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("action_template.store: added new %d", template.ID)
+
+	return nil
+}
+
+func (r *ActionTemplateRepo) Update(ctx context.Context, template *domain.ActionTemplate) error {
+	config, err := marshalTemplateConfig(template)
+	if err != nil {
+		return err
+	}
+
+	queryBuilder := r.db.squirrel.
+		Update("action_template").
+		Set("name", template.Name).
+		Set("config", config).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": template.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	r.log.Debug().Msgf("action_template.update: %d", template.ID)
+
+	return nil
+}
+
+func (r *ActionTemplateRepo) Delete(ctx context.Context, id int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("action_template").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("action_template.delete: %d", id)
+
+	return nil
+}
+
+// marshalTemplateConfig stores the action config as json, without the fields that belong to a filter action
+func marshalTemplateConfig(template *domain.ActionTemplate) (string, error) {
+	config := template.Action
+	config.ID = 0
+	config.FilterID = 0
+	config.TemplateID = 0
+	config.Client = nil
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", errors.Wrap(err, "could not marshal action template config")
+	}
+
+	return string(data), nil
+}
+
+func unmarshalTemplateConfig(config sql.NullString, template *domain.ActionTemplate) error {
+	if !config.Valid || config.String == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(config.String), &template.Action); err != nil {
+		return errors.Wrap(err, "could not unmarshal action template config: %d", template.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockActionTemplate() domain.ActionTemplate {
+	return domain.ActionTemplate{
+		Name: "qbit movies",
+		Action: domain.Action{
+			Type:       domain.ActionTypeQbittorrent,
+			Category:   "movies",
+			SavePath:   "/downloads/movies",
+			LimitRatio: 2,
+			Paused:     true,
+			AutoTMM:    true,
+		},
+	}
+}
+
+func TestActionTemplateRepo_Store(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewActionTemplateRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_Succeeds [%s]", dbType), func(t *testing.T) {
+			mockData := getMockActionTemplate()
+
+			err := repo.Store(context.Background(), &mockData)
+			assert.NoError(t, err)
+			assert.NotZero(t, mockData.ID)
+
+			template, err := repo.FindByID(context.Background(), mockData.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, "qbit movies", template.Name)
+			assert.Equal(t, "movies", template.Action.Category)
+			assert.Equal(t, float64(2), template.Action.LimitRatio)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), mockData.ID)
+		})
+
+		t.Run(fmt.Sprintf("FindByID_Fails_Not_Found [%s]", dbType), func(t *testing.T) {
+			_, err := repo.FindByID(context.Background(), 9999)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+		})
+	}
+}
+
+func TestActionTemplateRepo_Update_Propagates(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewActionTemplateRepo(log, db)
+
+		t.Run(fmt.Sprintf("Update_Propagates_To_Actions [%s]", dbType), func(t *testing.T) {
+			// Setup
+			template := getMockActionTemplate()
+			err := repo.Store(context.Background(), &template)
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdFilters)
+
+			// per-filter override of the save path and turning off paused
+			createdAction, err := actionRepo.Store(context.Background(), domain.Action{
+				Name:              "from template",
+				Type:              domain.ActionTypeQbittorrent,
+				Enabled:           true,
+				SavePath:          "/downloads/override",
+				FilterID:          createdFilters[0].ID,
+				TemplateID:        template.ID,
+				TemplateOverrides: []string{"paused"},
+			})
+			assert.NoError(t, err)
+
+			// change the template
+			template.Action.Category = "movies-uhd"
+			err = repo.Update(context.Background(), &template)
+			assert.NoError(t, err)
+
+			// resolve the action like it's done at execution
+			actions, err := actionRepo.FindByFilterID(context.Background(), createdFilters[0].ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, actions, 1)
+			assert.Equal(t, template.ID, actions[0].TemplateID)
+			assert.Equal(t, []string{"paused"}, actions[0].TemplateOverrides)
+
+			updated, err := repo.FindByID(context.Background(), actions[0].TemplateID)
+			assert.NoError(t, err)
+
+			actions[0].ApplyTemplate(updated)
+
+			assert.Equal(t, "movies-uhd", actions[0].Category)
+			assert.Equal(t, "/downloads/override", actions[0].SavePath)
+			assert.Equal(t, float64(2), actions[0].LimitRatio)
+			assert.False(t, actions[0].Paused)
+			assert.True(t, actions[0].AutoTMM)
+
+			// Cleanup
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = repo.Delete(context.Background(), template.ID)
+		})
+	}
+}
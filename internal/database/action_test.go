@@ -341,6 +341,7 @@ func TestActionRepo_Get(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, action)
 			assert.Equal(t, createdActions[0].ID, action.ID)
+			assert.Equal(t, mockData.WebhookHeaders, action.WebhookHeaders)
 
 			// Cleanup
 			_ = repo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdActions[0].ID})
@@ -519,3 +520,75 @@ func TestActionRepo_ToggleEnabled(t *testing.T) {
 
 	}
 }
+
+func TestActionRepo_Clone(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		repo := NewActionRepo(log, db, downloadClientRepo)
+		mockData := getMockAction()
+
+		t.Run(fmt.Sprintf("Clone_Succeeds [%s]", dbType), func(t *testing.T) {
+			// Setup
+			createdClient, err := downloadClientRepo.Store(context.Background(), getMockDownloadClient())
+			assert.NoError(t, err)
+			assert.NotNil(t, createdClient)
+
+			for i := 0; i < 3; i++ {
+				err = filterRepo.Store(context.Background(), getMockFilter())
+				assert.NoError(t, err)
+			}
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, createdFilters, 3)
+
+			mockData.ClientID = int32(createdClient.ID)
+			mockData.FilterID = createdFilters[0].ID
+			createdActions, err := repo.StoreFilterActions(context.Background(), int64(createdFilters[0].ID), []*domain.Action{&mockData})
+			assert.NoError(t, err)
+
+			source, err := repo.Get(context.Background(), &domain.GetActionRequest{Id: createdActions[0].ID})
+			assert.NoError(t, err)
+
+			// Actual test for Clone
+			targets := []int{createdFilters[1].ID, createdFilters[2].ID}
+			clones, err := repo.Clone(context.Background(), &domain.CloneActionRequest{ActionID: source.ID, FilterIDs: targets, Name: "cloned"})
+			assert.NoError(t, err)
+			assert.Len(t, clones, 2)
+
+			for i, clone := range clones {
+				got, err := repo.Get(context.Background(), &domain.GetActionRequest{Id: clone.ID})
+				assert.NoError(t, err)
+
+				assert.NotEqual(t, source.ID, got.ID)
+				assert.Equal(t, targets[i], got.FilterID)
+				assert.Equal(t, "cloned", got.Name)
+
+				// everything but the ids and name is the same as the source
+				want := *source
+				want.ID = got.ID
+				want.FilterID = got.FilterID
+				want.Name = got.Name
+				assert.Equal(t, want, *got)
+
+				_ = repo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: got.ID})
+			}
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdActions[0].ID})
+			for _, filter := range createdFilters {
+				_ = filterRepo.Delete(context.Background(), filter.ID)
+			}
+			_ = downloadClientRepo.Delete(context.Background(), createdClient.ID)
+		})
+
+		t.Run(fmt.Sprintf("Clone_Fails_No_Record [%s]", dbType), func(t *testing.T) {
+			clones, err := repo.Clone(context.Background(), &domain.CloneActionRequest{ActionID: 9999, FilterIDs: []int{1}})
+			assert.Error(t, err)
+			assert.Equal(t, domain.ErrRecordNotFound, err)
+			assert.Nil(t, clones)
+		})
+	}
+}
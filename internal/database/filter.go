@@ -231,12 +231,28 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"f.except_uploaders",
 			"f.match_language",
 			"f.except_language",
+			"f.match_subtitles",
+			"f.except_subtitles",
 			"f.tags",
 			"f.except_tags",
 			"f.tags_match_logic",
 			"f.except_tags_match_logic",
 			"f.origins",
 			"f.except_origins",
+			"f.min_trackers",
+			"f.max_trackers",
+			"f.dedup_window",
+			"f.dedup_key",
+			"f.stop_on_match",
+			"f.max_active_downloads",
+			"f.min_seeders",
+			"f.max_seeders",
+			"f.reject_unknown_seeders",
+			"f.require_download",
+			"f.match_audio",
+			"f.except_audio",
+			"f.require_approval",
+			"f.min_group_tier",
 			"f.created_at",
 			"f.updated_at",
 			"fe.id as external_id",
@@ -255,6 +271,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"fe.webhook_retry_status",
 			"fe.webhook_retry_attempts",
 			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_timeout",
+			"fe.webhook_fail_open",
 		).
 		From("filter f").
 		LeftJoin("filter_external fe ON f.id = fe.filter_id").
@@ -285,8 +303,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 
 		// filter external
 		var extName, extType, extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus sql.NullString
-		var extId, extIndex, extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extExecStatus sql.NullInt32
-		var extEnabled sql.NullBool
+		var extId, extIndex, extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extWebhookTimeout, extExecStatus sql.NullInt32
+		var extEnabled, extWebhookFailOpen sql.NullBool
 
 		if err := rows.Scan(
 			&f.ID,
@@ -341,12 +359,28 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			&exceptUploaders,
 			pq.Array(&f.MatchLanguage),
 			pq.Array(&f.ExceptLanguage),
+			pq.Array(&f.MatchSubtitles),
+			pq.Array(&f.ExceptSubtitles),
 			&tags,
 			&exceptTags,
 			&tagsMatchLogic,
 			&exceptTagsMatchLogic,
 			pq.Array(&f.Origins),
 			pq.Array(&f.ExceptOrigins),
+			&f.MinTrackers,
+			&f.MaxTrackers,
+			&f.DedupWindow,
+			&f.DedupKey,
+			&f.StopOnMatch,
+			&f.MaxActiveDownloads,
+			&f.MinSeeders,
+			&f.MaxSeeders,
+			&f.RejectUnknownSeeders,
+			&f.RequireDownload,
+			pq.Array(&f.MatchAudio),
+			pq.Array(&f.ExceptAudio),
+			&f.RequireApproval,
+			&f.MinGroupTier,
 			&f.CreatedAt,
 			&f.UpdatedAt,
 			&extId,
@@ -365,6 +399,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			&extWebhookRetryStatus,
 			&extWebhookRetryAttempts,
 			&extWebhookDelaySeconds,
+			&extWebhookTimeout,
+			&extWebhookFailOpen,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
@@ -423,6 +459,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 				WebhookRetryStatus:       extWebhookRetryStatus.String,
 				WebhookRetryAttempts:     int(extWebhookRetryAttempts.Int32),
 				WebhookRetryDelaySeconds: int(extWebhookDelaySeconds.Int32),
+				WebhookTimeout:           int(extWebhookTimeout.Int32),
+				WebhookFailOpen:          extWebhookFailOpen.Bool,
 			}
 			externalMap[external.ID] = external
 		}
@@ -495,12 +533,28 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			"f.except_uploaders",
 			"f.match_language",
 			"f.except_language",
+			"f.match_subtitles",
+			"f.except_subtitles",
 			"f.tags",
 			"f.except_tags",
 			"f.tags_match_logic",
 			"f.except_tags_match_logic",
 			"f.origins",
 			"f.except_origins",
+			"f.min_trackers",
+			"f.max_trackers",
+			"f.dedup_window",
+			"f.dedup_key",
+			"f.stop_on_match",
+			"f.max_active_downloads",
+			"f.min_seeders",
+			"f.max_seeders",
+			"f.reject_unknown_seeders",
+			"f.require_download",
+			"f.match_audio",
+			"f.except_audio",
+			"f.require_approval",
+			"f.min_group_tier",
 			"f.created_at",
 			"f.updated_at",
 			"fe.id as external_id",
@@ -519,6 +573,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			"fe.webhook_retry_status",
 			"fe.webhook_retry_attempts",
 			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_timeout",
+			"fe.webhook_fail_open",
 			"fe.filter_id",
 		).
 		From("filter f").
@@ -528,7 +584,7 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 		Where(sq.Eq{"i.identifier": indexer}).
 		Where(sq.Eq{"i.enabled": true}).
 		Where(sq.Eq{"f.enabled": true}).
-		OrderBy("f.priority DESC")
+		OrderBy("f.priority DESC", "f.id ASC")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -544,6 +600,9 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 
 	filtersMap := make(map[int]*domain.Filter)
 
+	// keep the filters in priority order, the external filters join returns a row per external filter
+	var filters []*domain.Filter
+
 	for rows.Next() {
 		var f domain.Filter
 
@@ -553,8 +612,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 
 		// filter external
 		var extName, extType, extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus sql.NullString
-		var extId, extIndex, extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extExecStatus, extFilterId sql.NullInt32
-		var extEnabled sql.NullBool
+		var extId, extIndex, extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extWebhookTimeout, extExecStatus, extFilterId sql.NullInt32
+		var extEnabled, extWebhookFailOpen sql.NullBool
 
 		if err := rows.Scan(
 			&f.ID,
@@ -609,12 +668,28 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			&exceptUploaders,
 			pq.Array(&f.MatchLanguage),
 			pq.Array(&f.ExceptLanguage),
+			pq.Array(&f.MatchSubtitles),
+			pq.Array(&f.ExceptSubtitles),
 			&tags,
 			&exceptTags,
 			&tagsMatchLogic,
 			&exceptTagsMatchLogic,
 			pq.Array(&f.Origins),
 			pq.Array(&f.ExceptOrigins),
+			&f.MinTrackers,
+			&f.MaxTrackers,
+			&f.DedupWindow,
+			&f.DedupKey,
+			&f.StopOnMatch,
+			&f.MaxActiveDownloads,
+			&f.MinSeeders,
+			&f.MaxSeeders,
+			&f.RejectUnknownSeeders,
+			&f.RequireDownload,
+			pq.Array(&f.MatchAudio),
+			pq.Array(&f.ExceptAudio),
+			&f.RequireApproval,
+			&f.MinGroupTier,
 			&f.CreatedAt,
 			&f.UpdatedAt,
 			&extId,
@@ -633,6 +708,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			&extWebhookRetryStatus,
 			&extWebhookRetryAttempts,
 			&extWebhookDelaySeconds,
+			&extWebhookTimeout,
+			&extWebhookFailOpen,
 			&extFilterId,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
@@ -680,6 +757,7 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 
 			filter = &f
 			filtersMap[f.ID] = filter
+			filters = append(filters, filter)
 		}
 
 		if extId.Valid {
@@ -700,19 +778,14 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 				WebhookRetryStatus:       extWebhookRetryStatus.String,
 				WebhookRetryAttempts:     int(extWebhookRetryAttempts.Int32),
 				WebhookRetryDelaySeconds: int(extWebhookDelaySeconds.Int32),
+				WebhookTimeout:           int(extWebhookTimeout.Int32),
+				WebhookFailOpen:          extWebhookFailOpen.Bool,
 				FilterId:                 int(extFilterId.Int32),
 			}
 			filter.External = append(filter.External, external)
 		}
 	}
 
-	var filters []*domain.Filter
-
-	for _, filter := range filtersMap {
-		filter := filter
-		filters = append(filters, filter)
-	}
-
 	return filters, nil
 }
 
@@ -735,6 +808,8 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 			"fe.webhook_retry_status",
 			"fe.webhook_retry_attempts",
 			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_timeout",
+			"fe.webhook_fail_open",
 		).
 		From("filter_external fe").
 		Where(sq.Eq{"fe.filter_id": filterId})
@@ -759,7 +834,8 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 
 		// filter external
 		var extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus sql.NullString
-		var extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extExecStatus sql.NullInt32
+		var extWebhookStatus, extWebhookRetryAttempts, extWebhookDelaySeconds, extWebhookTimeout, extExecStatus sql.NullInt32
+		var extWebhookFailOpen sql.NullBool
 
 		if err := rows.Scan(
 			&external.ID,
@@ -778,6 +854,8 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 			&extWebhookRetryStatus,
 			&extWebhookRetryAttempts,
 			&extWebhookDelaySeconds,
+			&extWebhookTimeout,
+			&extWebhookFailOpen,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
@@ -794,6 +872,8 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 		external.WebhookRetryStatus = extWebhookRetryStatus.String
 		external.WebhookRetryAttempts = int(extWebhookRetryAttempts.Int32)
 		external.WebhookRetryDelaySeconds = int(extWebhookDelaySeconds.Int32)
+		external.WebhookTimeout = int(extWebhookTimeout.Int32)
+		external.WebhookFailOpen = extWebhookFailOpen.Bool
 
 		externalFilters = append(externalFilters, external)
 	}
@@ -846,6 +926,8 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			"except_uploaders",
 			"match_language",
 			"except_language",
+			"match_subtitles",
+			"except_subtitles",
 			"tags",
 			"except_tags",
 			"tags_match_logic",
@@ -862,6 +944,20 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			"perfect_flac",
 			"origins",
 			"except_origins",
+			"min_trackers",
+			"max_trackers",
+			"dedup_window",
+			"dedup_key",
+			"stop_on_match",
+			"max_active_downloads",
+			"min_seeders",
+			"max_seeders",
+			"reject_unknown_seeders",
+			"require_download",
+			"match_audio",
+			"except_audio",
+			"require_approval",
+			"min_group_tier",
 		).
 		Values(
 			filter.Name,
@@ -905,6 +1001,8 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			filter.ExceptUploaders,
 			pq.Array(filter.MatchLanguage),
 			pq.Array(filter.ExceptLanguage),
+			pq.Array(filter.MatchSubtitles),
+			pq.Array(filter.ExceptSubtitles),
 			filter.Tags,
 			filter.ExceptTags,
 			filter.TagsMatchLogic,
@@ -921,6 +1019,20 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			filter.PerfectFlac,
 			pq.Array(filter.Origins),
 			pq.Array(filter.ExceptOrigins),
+			filter.MinTrackers,
+			filter.MaxTrackers,
+			filter.DedupWindow,
+			filter.DedupKey,
+			filter.StopOnMatch,
+			filter.MaxActiveDownloads,
+			filter.MinSeeders,
+			filter.MaxSeeders,
+			filter.RejectUnknownSeeders,
+			filter.RequireDownload,
+			pq.Array(filter.MatchAudio),
+			pq.Array(filter.ExceptAudio),
+			filter.RequireApproval,
+			filter.MinGroupTier,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -982,6 +1094,8 @@ func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
 		Set("except_uploaders", filter.ExceptUploaders).
 		Set("match_language", pq.Array(filter.MatchLanguage)).
 		Set("except_language", pq.Array(filter.ExceptLanguage)).
+		Set("match_subtitles", pq.Array(filter.MatchSubtitles)).
+		Set("except_subtitles", pq.Array(filter.ExceptSubtitles)).
 		Set("tags", filter.Tags).
 		Set("except_tags", filter.ExceptTags).
 		Set("tags_match_logic", filter.TagsMatchLogic).
@@ -998,6 +1112,20 @@ func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
 		Set("perfect_flac", filter.PerfectFlac).
 		Set("origins", pq.Array(filter.Origins)).
 		Set("except_origins", pq.Array(filter.ExceptOrigins)).
+		Set("min_trackers", filter.MinTrackers).
+		Set("max_trackers", filter.MaxTrackers).
+		Set("dedup_window", filter.DedupWindow).
+		Set("dedup_key", filter.DedupKey).
+		Set("stop_on_match", filter.StopOnMatch).
+		Set("max_active_downloads", filter.MaxActiveDownloads).
+		Set("min_seeders", filter.MinSeeders).
+		Set("max_seeders", filter.MaxSeeders).
+		Set("reject_unknown_seeders", filter.RejectUnknownSeeders).
+		Set("require_download", filter.RequireDownload).
+		Set("match_audio", pq.Array(filter.MatchAudio)).
+		Set("except_audio", pq.Array(filter.ExceptAudio)).
+		Set("require_approval", filter.RequireApproval).
+		Set("min_group_tier", filter.MinGroupTier).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where(sq.Eq{"id": filter.ID})
 
@@ -1148,6 +1276,12 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.ExceptLanguage != nil {
 		q = q.Set("except_language", pq.Array(filter.ExceptLanguage))
 	}
+	if filter.MatchSubtitles != nil {
+		q = q.Set("match_subtitles", pq.Array(filter.MatchSubtitles))
+	}
+	if filter.ExceptSubtitles != nil {
+		q = q.Set("except_subtitles", pq.Array(filter.ExceptSubtitles))
+	}
 	if filter.Tags != nil {
 		q = q.Set("tags", filter.Tags)
 	}
@@ -1196,6 +1330,48 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.ExceptOrigins != nil {
 		q = q.Set("except_origins", pq.Array(filter.ExceptOrigins))
 	}
+	if filter.MinTrackers != nil {
+		q = q.Set("min_trackers", filter.MinTrackers)
+	}
+	if filter.MaxTrackers != nil {
+		q = q.Set("max_trackers", filter.MaxTrackers)
+	}
+	if filter.DedupWindow != nil {
+		q = q.Set("dedup_window", filter.DedupWindow)
+	}
+	if filter.DedupKey != nil {
+		q = q.Set("dedup_key", filter.DedupKey)
+	}
+	if filter.StopOnMatch != nil {
+		q = q.Set("stop_on_match", filter.StopOnMatch)
+	}
+	if filter.MaxActiveDownloads != nil {
+		q = q.Set("max_active_downloads", filter.MaxActiveDownloads)
+	}
+	if filter.MinSeeders != nil {
+		q = q.Set("min_seeders", filter.MinSeeders)
+	}
+	if filter.MaxSeeders != nil {
+		q = q.Set("max_seeders", filter.MaxSeeders)
+	}
+	if filter.RejectUnknownSeeders != nil {
+		q = q.Set("reject_unknown_seeders", filter.RejectUnknownSeeders)
+	}
+	if filter.RequireDownload != nil {
+		q = q.Set("require_download", filter.RequireDownload)
+	}
+	if filter.MatchAudio != nil {
+		q = q.Set("match_audio", pq.Array(filter.MatchAudio))
+	}
+	if filter.ExceptAudio != nil {
+		q = q.Set("except_audio", pq.Array(filter.ExceptAudio))
+	}
+	if filter.RequireApproval != nil {
+		q = q.Set("require_approval", filter.RequireApproval)
+	}
+	if filter.MinGroupTier != nil {
+		q = q.Set("min_group_tier", filter.MinGroupTier)
+	}
 	if filter.ExternalScriptEnabled != nil {
 		q = q.Set("external_script_enabled", filter.ExternalScriptEnabled)
 	}
@@ -1524,6 +1700,8 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 			"webhook_retry_status",
 			"webhook_retry_attempts",
 			"webhook_retry_delay_seconds",
+			"webhook_timeout",
+			"webhook_fail_open",
 			"filter_id",
 		)
 
@@ -1544,6 +1722,8 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 			toNullString(external.WebhookRetryStatus),
 			toNullInt32(int32(external.WebhookRetryAttempts)),
 			toNullInt32(int32(external.WebhookRetryDelaySeconds)),
+			toNullInt32(int32(external.WebhookTimeout)),
+			external.WebhookFailOpen,
 			filterID,
 		)
 	}
@@ -23,6 +23,7 @@ func getMockFilter() *domain.Filter {
 		Priority:             1,
 		MaxDownloads:         100,
 		MaxDownloadsUnit:     domain.FilterMaxDownloadsHour,
+		MaxActiveDownloads:   3,
 		MatchReleases:        "BRRip",
 		ExceptReleases:       "BRRip",
 		UseRegex:             false,
@@ -441,6 +442,7 @@ func TestFilterRepo_FindByID(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, filter)
 			assert.Equal(t, createdFilters[0].ID, filter.ID)
+			assert.Equal(t, 3, filter.MaxActiveDownloads)
 
 			// Cleanup
 			_ = repo.Delete(context.Background(), createdFilters[0].ID)
@@ -488,6 +490,45 @@ func TestFilterRepo_FindByIndexerIdentifier(t *testing.T) {
 			_ = repo.Delete(context.Background(), mockData.ID)
 		})
 
+		t.Run(fmt.Sprintf("FindByIndexerIdentifier_Priority_Order [%s]", dbType), func(t *testing.T) {
+			// Setup
+			indexer, err := indexerRepo.Store(context.Background(), indexerMockData)
+			assert.NoError(t, err)
+
+			var ids []int
+			for _, priority := range []int32{1, 20, 10} {
+				f := getMockFilter()
+				f.Name = fmt.Sprintf("priority %d", priority)
+				f.Priority = priority
+				f.StopOnMatch = priority == 20
+
+				assert.NoError(t, repo.Store(context.Background(), f))
+				assert.NoError(t, repo.StoreIndexerConnection(context.Background(), f.ID, int(indexer.ID)))
+				assert.NoError(t, repo.StoreFilterExternal(context.Background(), f.ID, []domain.FilterExternal{getMockFilterExternal(), getMockFilterExternal()}))
+
+				ids = append(ids, f.ID)
+			}
+
+			// Execute
+			filters, err := repo.FindByIndexerIdentifier(context.Background(), indexerMockData.Identifier)
+			assert.NoError(t, err)
+
+			var names []string
+			for _, f := range filters {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, []string{"priority 20", "priority 10", "priority 1"}, names)
+			assert.True(t, filters[0].StopOnMatch)
+			assert.False(t, filters[1].StopOnMatch)
+			assert.Len(t, filters[0].External, 2)
+
+			// Cleanup
+			_ = indexerRepo.Delete(context.Background(), int(indexer.ID))
+			for _, id := range ids {
+				_ = repo.Delete(context.Background(), id)
+			}
+		})
+
 		t.Run(fmt.Sprintf("FindByIndexerIdentifier_Fails_Invalid_Identifier [%s]", dbType), func(t *testing.T) {
 			filters, err := repo.FindByIndexerIdentifier(context.Background(), "invalid-identifier")
 			assert.NoError(t, err) // should return an error??
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type MacroOverrideRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewMacroOverrideRepo(log logger.Logger, db *DB) domain.MacroOverrideRepo {
+	return &MacroOverrideRepo{
+		log: log.With().Str("repo", "macro_override").Logger(),
+		db:  db,
+	}
+}
+
+func (r *MacroOverrideRepo) List(ctx context.Context) ([]domain.MacroOverride, error) {
+	return r.find(ctx, nil)
+}
+
+func (r *MacroOverrideRepo) FindByIndexer(ctx context.Context, indexer string) ([]domain.MacroOverride, error) {
+	return r.find(ctx, sq.Eq{"indexer": indexer})
+}
+
+func (r *MacroOverrideRepo) find(ctx context.Context, where sq.Sqlizer) ([]domain.MacroOverride, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"indexer",
+			"field",
+			"match_value",
+			"value",
+			"created_at",
+		).
+		From("indexer_macro_override").
+		OrderBy("indexer ASC", "id ASC")
+
+	if where != nil {
+		queryBuilder = queryBuilder.Where(where)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	overrides := make([]domain.MacroOverride, 0)
+	for rows.Next() {
+		var o domain.MacroOverride
+		var match, value sql.NullString
+
+		if err := rows.Scan(&o.ID, &o.Indexer, &o.Field, &match, &value, &o.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		o.Match = match.String
+		o.Value = value.String
+
+		overrides = append(overrides, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return overrides, nil
+}
+
+func (r *MacroOverrideRepo) Store(ctx context.Context, override *domain.MacroOverride) error {
+	queryBuilder := r.db.squirrel.
+		Insert("indexer_macro_override").
+		Columns("indexer", "field", "match_value", "value").
+		Values(override.Indexer, override.Field, toNullString(override.Match), toNullString(override.Value)).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&override.ID, &override.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("macro_override.store: added new %d", override.ID)
+
+	return nil
+}
+
+func (r *MacroOverrideRepo) Delete(ctx context.Context, id int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("indexer_macro_override").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("macro_override.delete: %d", id)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMacroOverrideRepo_FindByIndexer(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		repo := NewMacroOverrideRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_And_Find_By_Indexer [%s]", dbType), func(t *testing.T) {
+			// Setup
+			category := &domain.MacroOverride{Indexer: "mock", Field: "Category", Match: "Movies/HD-1080p", Value: "movies"}
+			resolution := &domain.MacroOverride{Indexer: "mock", Field: "Resolution", Value: "1080p"}
+			other := &domain.MacroOverride{Indexer: "other", Field: "Category", Match: "TV", Value: "tv"}
+
+			// Execute
+			for _, o := range []*domain.MacroOverride{category, resolution, other} {
+				err := repo.Store(context.Background(), o)
+				assert.NoError(t, err)
+				assert.NotZero(t, o.ID)
+			}
+
+			// Verify
+			overrides, err := repo.FindByIndexer(context.Background(), "mock")
+			assert.NoError(t, err)
+			assert.Len(t, overrides, 2)
+			assert.Equal(t, category.ID, overrides[0].ID)
+			assert.Equal(t, "Movies/HD-1080p", overrides[0].Match)
+			assert.Equal(t, "movies", overrides[0].Value)
+			assert.Equal(t, "", overrides[1].Match)
+
+			all, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, all, 3)
+
+			// Cleanup
+			for _, o := range all {
+				_ = repo.Delete(context.Background(), o.ID)
+			}
+
+			overrides, err = repo.FindByIndexer(context.Background(), "mock")
+			assert.NoError(t, err)
+			assert.Len(t, overrides, 0)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -31,7 +32,7 @@ func NewNotificationRepo(log logger.Logger, db *DB) domain.NotificationRepo {
 func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQueryParams) ([]domain.Notification, int, error) {
 
 	queryBuilder := r.db.squirrel.
-		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "priority", "topic", "host", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
+		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "priority", "topic", "host", "username", "password", "targets", "email_from", "user_agent", "image_url", "rate_limit", "dispatch_order", "min_interval", "max_per_hour", "timeout", "settle_period", "suppress_skipped", "event_channels", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
 		From("notification").
 		OrderBy("name")
 
@@ -52,9 +53,9 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 	for rows.Next() {
 		var n domain.Notification
 
-		var webhook, token, apiKey, channel, host, topic sql.NullString
+		var webhook, token, apiKey, channel, host, topic, username, password, targets, emailFrom, userAgent, imageURL, eventChannels sql.NullString
 
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &n.Priority, &topic, &host, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &n.Priority, &topic, &host, &username, &password, &targets, &emailFrom, &userAgent, &imageURL, &n.RateLimit, &n.DispatchOrder, &n.MinInterval, &n.MaxPerHour, &n.Timeout, &n.SettlePeriod, &n.SuppressSkipped, &eventChannels, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
 			return nil, 0, errors.Wrap(err, "error scanning row")
 		}
 
@@ -64,6 +65,16 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 		n.Channel = channel.String
 		n.Topic = topic.String
 		n.Host = host.String
+		n.Username = username.String
+		n.Password = password.String
+		n.Targets = targets.String
+		n.EmailFrom = emailFrom.String
+		n.UserAgent = userAgent.String
+		n.ImageURL = imageURL.String
+
+		if err := unmarshalEventChannels(eventChannels, &n); err != nil {
+			return nil, 0, err
+		}
 
 		notifications = append(notifications, n)
 	}
@@ -76,7 +87,7 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 
 func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, error) {
 
-	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key,  webhook, title, icon, host, username, password, channel, targets, devices, priority, topic, created_at, updated_at FROM notification ORDER BY name ASC")
+	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key,  webhook, title, icon, host, username, password, channel, targets, devices, priority, topic, rate_limit, dispatch_order, min_interval, max_per_hour, timeout, settle_period, suppress_skipped, email_from, user_agent, image_url, event_channels, created_at, updated_at FROM notification ORDER BY name ASC")
 	if err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -88,8 +99,8 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		var n domain.Notification
 		//var eventsSlice []string
 
-		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic sql.NullString
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic, emailFrom, userAgent, imageURL, eventChannels sql.NullString
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &n.RateLimit, &n.DispatchOrder, &n.MinInterval, &n.MaxPerHour, &n.Timeout, &n.SettlePeriod, &n.SuppressSkipped, &emailFrom, &userAgent, &imageURL, &eventChannels, &n.CreatedAt, &n.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -106,6 +117,13 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		n.Targets = targets.String
 		n.Devices = devices.String
 		n.Topic = topic.String
+		n.EmailFrom = emailFrom.String
+		n.UserAgent = userAgent.String
+		n.ImageURL = imageURL.String
+
+		if err := unmarshalEventChannels(eventChannels, &n); err != nil {
+			return nil, err
+		}
 
 		notifications = append(notifications, n)
 	}
@@ -138,6 +156,17 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 			"devices",
 			"priority",
 			"topic",
+			"rate_limit",
+			"dispatch_order",
+			"min_interval",
+			"max_per_hour",
+			"timeout",
+			"settle_period",
+			"suppress_skipped",
+			"email_from",
+			"user_agent",
+			"image_url",
+			"event_channels",
 			"created_at",
 			"updated_at",
 		).
@@ -156,8 +185,8 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 
 	var n domain.Notification
 
-	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic sql.NullString
-	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &n.CreatedAt, &n.UpdatedAt); err != nil {
+	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic, emailFrom, userAgent, imageURL, eventChannels sql.NullString
+	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &topic, &n.RateLimit, &n.DispatchOrder, &n.MinInterval, &n.MaxPerHour, &n.Timeout, &n.SettlePeriod, &n.SuppressSkipped, &emailFrom, &userAgent, &imageURL, &eventChannels, &n.CreatedAt, &n.UpdatedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
@@ -173,6 +202,13 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 	n.Targets = targets.String
 	n.Devices = devices.String
 	n.Topic = topic.String
+	n.EmailFrom = emailFrom.String
+	n.UserAgent = userAgent.String
+	n.ImageURL = imageURL.String
+
+	if err := unmarshalEventChannels(eventChannels, &n); err != nil {
+		return nil, err
+	}
 
 	return &n, nil
 }
@@ -185,6 +221,11 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 	topic := toNullString(notification.Topic)
 	host := toNullString(notification.Host)
 
+	eventChannels, err := marshalEventChannels(notification.EventChannels)
+	if err != nil {
+		return nil, err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Insert("notification").
 		Columns(
@@ -199,6 +240,20 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			"priority",
 			"topic",
 			"host",
+			"username",
+			"password",
+			"targets",
+			"email_from",
+			"user_agent",
+			"image_url",
+			"rate_limit",
+			"dispatch_order",
+			"min_interval",
+			"max_per_hour",
+			"timeout",
+			"settle_period",
+			"suppress_skipped",
+			"event_channels",
 		).
 		Values(
 			notification.Name,
@@ -212,6 +267,20 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			notification.Priority,
 			topic,
 			host,
+			toNullString(notification.Username),
+			toNullString(notification.Password),
+			toNullString(notification.Targets),
+			toNullString(notification.EmailFrom),
+			toNullString(notification.UserAgent),
+			toNullString(notification.ImageURL),
+			notification.RateLimit,
+			notification.DispatchOrder,
+			notification.MinInterval,
+			notification.MaxPerHour,
+			notification.Timeout,
+			notification.SettlePeriod,
+			notification.SuppressSkipped,
+			eventChannels,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -236,6 +305,11 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 	topic := toNullString(notification.Topic)
 	host := toNullString(notification.Host)
 
+	eventChannels, err := marshalEventChannels(notification.EventChannels)
+	if err != nil {
+		return nil, err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Update("notification").
 		Set("name", notification.Name).
@@ -249,6 +323,20 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 		Set("priority", notification.Priority).
 		Set("topic", topic).
 		Set("host", host).
+		Set("username", toNullString(notification.Username)).
+		Set("password", toNullString(notification.Password)).
+		Set("targets", toNullString(notification.Targets)).
+		Set("email_from", toNullString(notification.EmailFrom)).
+		Set("user_agent", toNullString(notification.UserAgent)).
+		Set("image_url", toNullString(notification.ImageURL)).
+		Set("rate_limit", notification.RateLimit).
+		Set("dispatch_order", notification.DispatchOrder).
+		Set("min_interval", notification.MinInterval).
+		Set("max_per_hour", notification.MaxPerHour).
+		Set("timeout", notification.Timeout).
+		Set("settle_period", notification.SettlePeriod).
+		Set("suppress_skipped", notification.SuppressSkipped).
+		Set("event_channels", eventChannels).
 		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where(sq.Eq{"id": notification.ID})
 
@@ -284,3 +372,91 @@ func (r *NotificationRepo) Delete(ctx context.Context, notificationID int) error
 
 	return nil
 }
+
+func (r *NotificationRepo) StoreLog(ctx context.Context, log *domain.NotificationLog) error {
+	queryBuilder := r.db.squirrel.
+		Insert("notification_log").
+		Columns("notification_id", "event", "message_id", "error").
+		Values(log.NotificationID, log.Event, toNullString(log.MessageID), toNullString(log.Error)).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&log.ID, &log.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Trace().Msgf("notification_log.store: added new %d", log.ID)
+
+	return nil
+}
+
+// FindLogs returns the latest logs of the notification, newest first
+func (r *NotificationRepo) FindLogs(ctx context.Context, notificationID int, limit int) ([]domain.NotificationLog, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "notification_id", "event", "message_id", "error", "created_at").
+		From("notification_log").
+		Where(sq.Eq{"notification_id": notificationID}).
+		OrderBy("created_at DESC", "id DESC")
+
+	if limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(limit))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	logs := make([]domain.NotificationLog, 0)
+	for rows.Next() {
+		var l domain.NotificationLog
+		var messageID, logErr sql.NullString
+
+		if err := rows.Scan(&l.ID, &l.NotificationID, &l.Event, &messageID, &logErr, &l.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		l.MessageID = messageID.String
+		l.Error = logErr.String
+
+		logs = append(logs, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return logs, nil
+}
+
+// marshalEventChannels stores the per event channel overrides as json
+func marshalEventChannels(eventChannels map[string]string) (sql.NullString, error) {
+	if len(eventChannels) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(eventChannels)
+	if err != nil {
+		return sql.NullString{}, errors.Wrap(err, "could not marshal event channels")
+	}
+
+	return toNullString(string(data)), nil
+}
+
+func unmarshalEventChannels(eventChannels sql.NullString, notification *domain.Notification) error {
+	if !eventChannels.Valid || eventChannels.String == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(eventChannels.String), &notification.EventChannels); err != nil {
+		return errors.Wrap(err, "could not unmarshal event channels for notification: %d", notification.ID)
+	}
+
+	return nil
+}
@@ -32,6 +32,12 @@ func getMockNotification() domain.Notification {
 		Devices:   "device1,device2",
 		Priority:  1,
 		Topic:     "mock-topic",
+		UserAgent: "Mozilla/5.0",
+		ImageURL:  "https://domain.ltd/image.png",
+		Timeout:   10,
+		EventChannels: map[string]string{
+			string(domain.NotificationEventPushError): "#mock-errors",
+		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -163,6 +169,7 @@ func TestNotificationRepo_Find(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, 3, len(notifications)) // TODO: This should be 2 technically since limit is 2, but it's returning 3 because params are not being applied.
 			assert.Equal(t, 3, totalCount)
+			assert.Equal(t, mockData1.EventChannels, notifications[0].EventChannels)
 
 			// Cleanup
 			notificationsList, _ = repo.List(context.Background())
@@ -194,6 +201,10 @@ func TestNotificationRepo_FindByID(t *testing.T) {
 			assert.NotNil(t, notification)
 			assert.Equal(t, mockData.Name, notification.Name)
 			assert.Equal(t, mockData.Type, notification.Type)
+			assert.Equal(t, mockData.EventChannels, notification.EventChannels)
+			assert.Equal(t, mockData.UserAgent, notification.UserAgent)
+			assert.Equal(t, mockData.ImageURL, notification.ImageURL)
+			assert.Equal(t, mockData.Timeout, notification.Timeout)
 
 			// Cleanup
 			_ = repo.Delete(context.Background(), mockData.ID)
@@ -234,3 +245,45 @@ func TestNotificationRepo_List(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationRepo_Logs(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		repo := NewNotificationRepo(log, db)
+		mockData := getMockNotification()
+
+		t.Run(fmt.Sprintf("StoreLog_And_FindLogs [%s]", dbType), func(t *testing.T) {
+			// Setup
+			notification, err := repo.Store(context.Background(), mockData)
+			assert.NoError(t, err)
+
+			sent := &domain.NotificationLog{NotificationID: notification.ID, Event: domain.NotificationEventPushApproved, MessageID: "647d2300-702c-4b38-8b2f-d56326ae460b"}
+			failed := &domain.NotificationLog{NotificationID: notification.ID, Event: domain.NotificationEventPushError, Error: "bad status: 500"}
+
+			// Execute
+			for _, l := range []*domain.NotificationLog{sent, failed} {
+				err := repo.StoreLog(context.Background(), l)
+				assert.NoError(t, err)
+				assert.NotZero(t, l.ID)
+			}
+
+			// Verify
+			logs, err := repo.FindLogs(context.Background(), notification.ID, 10)
+			assert.NoError(t, err)
+			assert.Len(t, logs, 2)
+			assert.Equal(t, failed.ID, logs[0].ID)
+			assert.Equal(t, "bad status: 500", logs[0].Error)
+			assert.Equal(t, "", logs[0].MessageID)
+			assert.Equal(t, sent.MessageID, logs[1].MessageID)
+			assert.Equal(t, domain.NotificationEventPushApproved, logs[1].Event)
+
+			logs, err = repo.FindLogs(context.Background(), notification.ID, 1)
+			assert.NoError(t, err)
+			assert.Len(t, logs, 1)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), notification.ID)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type PendingReleaseRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewPendingReleaseRepo(log logger.Logger, db *DB) domain.PendingReleaseRepo {
+	return &PendingReleaseRepo{
+		log: log.With().Str("repo", "pending_release").Logger(),
+		db:  db,
+	}
+}
+
+func (r *PendingReleaseRepo) selectQuery() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"p.id",
+			"p.release_id",
+			"p.filter_id",
+			"r.torrent_name",
+			"r.indexer",
+			"f.name",
+			"p.created_at",
+			"p.magnet_uri",
+			"p.vars",
+		).
+		From("pending_release p").
+		Join("release r ON r.id = p.release_id").
+		Join("filter f ON f.id = p.filter_id")
+}
+
+func (r *PendingReleaseRepo) List(ctx context.Context) ([]domain.PendingRelease, error) {
+	queryBuilder := r.selectQuery().OrderBy("p.created_at ASC", "p.id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	pending := make([]domain.PendingRelease, 0)
+	for rows.Next() {
+		var p domain.PendingRelease
+		var magnetURI, vars sql.NullString
+
+		if err := rows.Scan(&p.ID, &p.ReleaseID, &p.FilterID, &p.TorrentName, &p.Indexer, &p.FilterName, &p.CreatedAt, &magnetURI, &vars); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		p.MagnetURI = magnetURI.String
+
+		if err := unmarshalPendingVars(vars, &p); err != nil {
+			return nil, err
+		}
+
+		pending = append(pending, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return pending, nil
+}
+
+func (r *PendingReleaseRepo) FindByID(ctx context.Context, id int) (*domain.PendingRelease, error) {
+	queryBuilder := r.selectQuery().Where(sq.Eq{"p.id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+
+	var p domain.PendingRelease
+	var magnetURI, vars sql.NullString
+
+	if err := row.Scan(&p.ID, &p.ReleaseID, &p.FilterID, &p.TorrentName, &p.Indexer, &p.FilterName, &p.CreatedAt, &magnetURI, &vars); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	p.MagnetURI = magnetURI.String
+
+	if err := unmarshalPendingVars(vars, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *PendingReleaseRepo) Store(ctx context.Context, pending *domain.PendingRelease) error {
+	var vars sql.NullString
+	if len(pending.Vars) > 0 {
+		data, err := json.Marshal(pending.Vars)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal pending release vars")
+		}
+
+		vars = sql.NullString{String: string(data), Valid: true}
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("pending_release").
+		Columns("release_id", "filter_id", "magnet_uri", "vars").
+		Values(pending.ReleaseID, pending.FilterID, toNullString(pending.MagnetURI), vars).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&pending.ID, &pending.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("pending_release.store: added new %d", pending.ID)
+
+	return nil
+}
+
+func (r *PendingReleaseRepo) Delete(ctx context.Context, id int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("pending_release").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	r.log.Debug().Msgf("pending_release.delete: %d", id)
+
+	return nil
+}
+
+func unmarshalPendingVars(vars sql.NullString, pending *domain.PendingRelease) error {
+	if !vars.Valid || vars.String == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(vars.String), &pending.Vars); err != nil {
+		return errors.Wrap(err, "could not unmarshal pending release vars: %d", pending.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingReleaseRepo(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		filterRepo := NewFilterRepo(log, db)
+		releaseRepo := NewReleaseRepo(log, db)
+		repo := NewPendingReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_List_And_Delete [%s]", dbType), func(t *testing.T) {
+			// Setup
+			mockFilter := getMockFilter()
+			err := filterRepo.Store(context.Background(), mockFilter)
+			assert.NoError(t, err)
+
+			mockRelease := getMockRelease()
+			mockRelease.FilterID = mockFilter.ID
+			err = releaseRepo.Store(context.Background(), mockRelease)
+			assert.NoError(t, err)
+
+			pending := &domain.PendingRelease{
+				ReleaseID: mockRelease.ID,
+				FilterID:  mockFilter.ID,
+				MagnetURI: "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567",
+				Vars:      map[string]string{"freeleech": "true"},
+			}
+
+			// Execute
+			err = repo.Store(context.Background(), pending)
+			assert.NoError(t, err)
+			assert.NotZero(t, pending.ID)
+
+			// Verify
+			list, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, list, 1)
+			assert.Equal(t, mockRelease.ID, list[0].ReleaseID)
+			assert.Equal(t, mockRelease.TorrentName, list[0].TorrentName)
+			assert.Equal(t, mockFilter.Name, list[0].FilterName)
+
+			found, err := repo.FindByID(context.Background(), pending.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, mockFilter.ID, found.FilterID)
+			assert.Equal(t, pending.MagnetURI, found.MagnetURI)
+			assert.Equal(t, pending.Vars, found.Vars)
+
+			assert.NoError(t, repo.Delete(context.Background(), pending.ID))
+
+			_, err = repo.FindByID(context.Background(), pending.ID)
+			assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+			assert.ErrorIs(t, repo.Delete(context.Background(), pending.ID), domain.ErrRecordNotFound)
+
+			// Cleanup
+			_ = releaseRepo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = filterRepo.Delete(context.Background(), mockFilter.ID)
+		})
+	}
+}
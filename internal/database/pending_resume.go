@@ -0,0 +1,109 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type PendingResumeRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewPendingResumeRepo(log logger.Logger, db *DB) domain.PendingResumeRepo {
+	return &PendingResumeRepo{
+		log: log.With().Str("repo", "pending_resume").Logger(),
+		db:  db,
+	}
+}
+
+func (r *PendingResumeRepo) List(ctx context.Context) ([]domain.PendingResume, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"client_id",
+			"action_id",
+			"torrent_hash",
+			"resume_at",
+			"created_at",
+		).
+		From("pending_resume").
+		OrderBy("resume_at ASC", "id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	resumes := make([]domain.PendingResume, 0)
+	for rows.Next() {
+		var p domain.PendingResume
+		var actionID sql.NullInt32
+
+		if err := rows.Scan(&p.ID, &p.ClientID, &actionID, &p.TorrentHash, &p.ResumeAt, &p.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		p.ActionID = int(actionID.Int32)
+
+		resumes = append(resumes, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return resumes, nil
+}
+
+func (r *PendingResumeRepo) Store(ctx context.Context, resume *domain.PendingResume) error {
+	queryBuilder := r.db.squirrel.
+		Insert("pending_resume").
+		Columns("client_id", "action_id", "torrent_hash", "resume_at").
+		Values(resume.ClientID, toNullInt32(int32(resume.ActionID)), resume.TorrentHash, resume.ResumeAt).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&resume.ID, &resume.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("pending_resume.store: added new %d", resume.ID)
+
+	return nil
+}
+
+func (r *PendingResumeRepo) Delete(ctx context.Context, id int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("pending_resume").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("pending_resume.delete: %d", id)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingResumeRepo_List(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		repo := NewPendingResumeRepo(log, db)
+
+		t.Run(fmt.Sprintf("Store_And_List [%s]", dbType), func(t *testing.T) {
+			// Setup
+			createdClient, err := downloadClientRepo.Store(context.Background(), getMockDownloadClient())
+			assert.NoError(t, err)
+
+			resumeAt := time.Date(2023, 11, 4, 12, 30, 0, 0, time.UTC)
+			later := &domain.PendingResume{ClientID: int32(createdClient.ID), TorrentHash: "2222222222222222222222222222222222222222", ResumeAt: resumeAt.Add(time.Hour)}
+			first := &domain.PendingResume{ClientID: int32(createdClient.ID), TorrentHash: "1111111111111111111111111111111111111111", ResumeAt: resumeAt}
+
+			// Execute
+			for _, p := range []*domain.PendingResume{later, first} {
+				err := repo.Store(context.Background(), p)
+				assert.NoError(t, err)
+				assert.NotZero(t, p.ID)
+			}
+
+			// Verify
+			resumes, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, resumes, 2)
+			assert.Equal(t, first.ID, resumes[0].ID)
+			assert.Equal(t, first.TorrentHash, resumes[0].TorrentHash)
+			assert.True(t, resumeAt.Equal(resumes[0].ResumeAt))
+			assert.Equal(t, 0, resumes[0].ActionID)
+
+			assert.NoError(t, repo.Delete(context.Background(), first.ID))
+
+			resumes, err = repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, resumes, 1)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), later.ID)
+			_ = downloadClientRepo.Delete(context.Background(), createdClient.ID)
+		})
+	}
+}
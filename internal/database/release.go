@@ -690,3 +690,51 @@ func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, title string, seas
 
 	return true, nil
 }
+
+// FindGrabbedEpisodes returns the releases of the episode that were approved by at least one action
+func (repo *ReleaseRepo) FindGrabbedEpisodes(ctx context.Context, title string, season int, episode int) ([]*domain.Release, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("r.id", "r.torrent_name", "r.resolution", "r.source").
+		Distinct().
+		From("release r").
+		InnerJoin("release_action_status ras ON r.id = ras.release_id").
+		Where(repo.db.ILike("r.title", title)).
+		Where(sq.Eq{"r.season": season, "r.episode": episode, "ras.status": domain.ReleasePushStatusApproved}).
+		OrderBy("r.id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	var releases []*domain.Release
+	for rows.Next() {
+		var rls domain.Release
+		var resolution, source sql.NullString
+
+		if err := rows.Scan(&rls.ID, &rls.TorrentName, &resolution, &source); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		rls.Title = title
+		rls.Season = season
+		rls.Episode = episode
+		rls.Resolution = resolution.String
+		rls.Source = source.String
+
+		releases = append(releases, &rls)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return releases, nil
+}
@@ -630,3 +630,69 @@ func TestReleaseRepo_CanDownloadShow(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseRepo_FindGrabbedEpisodes(t *testing.T) {
+	for dbType, db := range testDBs {
+		log := setupLoggerForTest()
+
+		downloadClientRepo := NewDownloadClientRepo(log, db)
+		filterRepo := NewFilterRepo(log, db)
+		actionRepo := NewActionRepo(log, db, downloadClientRepo)
+		repo := NewReleaseRepo(log, db)
+
+		t.Run(fmt.Sprintf("Only_Approved [%s]", dbType), func(t *testing.T) {
+			// Setup
+			createdClient, err := downloadClientRepo.Store(context.Background(), getMockDownloadClient())
+			assert.NoError(t, err)
+
+			err = filterRepo.Store(context.Background(), getMockFilter())
+			assert.NoError(t, err)
+
+			createdFilters, err := filterRepo.ListFilters(context.Background())
+			assert.NoError(t, err)
+
+			actionMockData := getMockAction()
+			actionMockData.FilterID = createdFilters[0].ID
+			actionMockData.ClientID = int32(createdClient.ID)
+			createdAction, err := actionRepo.Store(context.Background(), actionMockData)
+			assert.NoError(t, err)
+
+			store := func(torrentName string, status domain.ReleasePushStatus) {
+				release := getMockRelease()
+				release.TorrentName = torrentName
+				release.FilterID = createdFilters[0].ID
+				assert.NoError(t, repo.Store(context.Background(), release))
+
+				actionStatus := getMockReleaseActionStatus()
+				actionStatus.Status = status
+				actionStatus.ReleaseID = release.ID
+				actionStatus.ActionID = int64(createdAction.ID)
+				actionStatus.FilterID = int64(createdFilters[0].ID)
+				assert.NoError(t, repo.StoreReleaseActionStatus(context.Background(), actionStatus))
+			}
+
+			store("Example.Title.S01E02.1080p.BluRay-GROUP", domain.ReleasePushStatusApproved)
+			store("Example.Title.S01E02.1080p.BluRay-OTHER", domain.ReleasePushStatusRejected)
+
+			// Execute
+			grabbed, err := repo.FindGrabbedEpisodes(context.Background(), "example title", 1, 2)
+			assert.NoError(t, err)
+
+			// Verify
+			assert.Len(t, grabbed, 1)
+			assert.Equal(t, "Example.Title.S01E02.1080p.BluRay-GROUP", grabbed[0].TorrentName)
+			assert.Equal(t, "1080p", grabbed[0].Resolution)
+			assert.Equal(t, "BluRay", grabbed[0].Source)
+
+			other, err := repo.FindGrabbedEpisodes(context.Background(), "Example Title", 1, 3)
+			assert.NoError(t, err)
+			assert.Empty(t, other)
+
+			// Cleanup
+			_ = repo.Delete(context.Background(), &domain.DeleteReleaseRequest{OlderThan: 0})
+			_ = actionRepo.Delete(context.Background(), &domain.DeleteActionRequest{ActionId: createdAction.ID})
+			_ = filterRepo.Delete(context.Background(), createdFilters[0].ID)
+			_ = downloadClientRepo.Delete(context.Background(), createdClient.ID)
+		})
+	}
+}
@@ -48,6 +48,7 @@ CREATE TABLE irc_network
     use_bouncer         BOOLEAN,
     bouncer_addr        TEXT,
     bot_mode            BOOLEAN DEFAULT FALSE,
+    proxy               TEXT,
     connected           BOOLEAN,
     connected_since     TIMESTAMP,
     created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -122,12 +123,28 @@ CREATE TABLE filter
     except_uploaders               TEXT,
     match_language                 TEXT []   DEFAULT '{}',
     except_language                TEXT []   DEFAULT '{}',
+    match_subtitles                TEXT []   DEFAULT '{}',
+    except_subtitles               TEXT []   DEFAULT '{}',
     tags                           TEXT,
     except_tags                    TEXT,
     tags_match_logic               TEXT,
     except_tags_match_logic        TEXT,
     origins                        TEXT []   DEFAULT '{}',
     except_origins                 TEXT []   DEFAULT '{}',
+    min_trackers                   INTEGER DEFAULT 0,
+    max_trackers                   INTEGER DEFAULT 0,
+    dedup_window                   INTEGER DEFAULT 0,
+    dedup_key                      TEXT DEFAULT '',
+    stop_on_match                  BOOLEAN DEFAULT TRUE,
+    max_active_downloads           INTEGER DEFAULT 0,
+    min_seeders                    INTEGER DEFAULT 0,
+    max_seeders                    INTEGER DEFAULT 0,
+    reject_unknown_seeders         BOOLEAN DEFAULT FALSE,
+    require_download               BOOLEAN DEFAULT FALSE,
+    match_audio                    TEXT []   DEFAULT '{}',
+    except_audio                   TEXT []   DEFAULT '{}',
+    require_approval               BOOLEAN DEFAULT FALSE,
+    min_group_tier                 INTEGER DEFAULT 0,
     created_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -150,6 +167,8 @@ CREATE TABLE filter_external
     webhook_retry_status                TEXT,
     webhook_retry_attempts              INTEGER,
     webhook_retry_delay_seconds         INTEGER,
+    webhook_timeout                     INTEGER,
+    webhook_fail_open                   BOOLEAN DEFAULT FALSE,
     filter_id                           INTEGER NOT NULL,
     FOREIGN KEY (filter_id)             REFERENCES filter(id) ON DELETE CASCADE
 );
@@ -178,6 +197,28 @@ CREATE TABLE client
     settings 		JSON
 );
 
+CREATE TABLE action_template
+(
+    id         INTEGER PRIMARY KEY,
+    name       TEXT NOT NULL,
+    config     TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE indexer_macro_override
+(
+    id          INTEGER PRIMARY KEY,
+    indexer     TEXT NOT NULL,
+    field       TEXT NOT NULL,
+    match_value TEXT,
+    value       TEXT,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX indexer_macro_override_indexer_index
+    ON indexer_macro_override (indexer);
+
 CREATE TABLE action
 (
     id                      INTEGER PRIMARY KEY,
@@ -186,6 +227,7 @@ CREATE TABLE action
     enabled                 BOOLEAN,
     exec_cmd                TEXT,
     exec_args               TEXT,
+    exec_env                BOOLEAN DEFAULT FALSE,
     watch_folder            TEXT,
     category                TEXT,
     tags                    TEXT,
@@ -203,16 +245,56 @@ CREATE TABLE action
     reannounce_delete       BOOLEAN DEFAULT false,
     reannounce_interval     INTEGER DEFAULT 7,
     reannounce_max_attempts INTEGER DEFAULT 50,
+    reannounce_target_peers INTEGER DEFAULT 0,
+    verify_start            BOOLEAN DEFAULT false,
+    recheck_resume          BOOLEAN DEFAULT false,
+    timeout                 INTEGER DEFAULT 0,
     webhook_host            TEXT,
     webhook_method          TEXT,
     webhook_type            TEXT,
     webhook_data            TEXT,
+    grpc_method             TEXT,
+    priority                TEXT,
+    pp_script               TEXT,
     webhook_headers         TEXT[] DEFAULT '{}',
+    webhook_validate_json   BOOLEAN DEFAULT false,
+    path_os                 TEXT,
+    exec_workdir            TEXT,
+    exec_shell              BOOLEAN DEFAULT false,
+    preset                  TEXT,
+    webhook_success_when    TEXT,
+    top_of_queue            BOOLEAN DEFAULT false,
+    queue_position          INTEGER DEFAULT 0,
+    archive_path            TEXT,
+    archive_filename        TEXT,
+    archive_mode            TEXT,
+    min_free_space          TEXT,
+    auto_tmm                BOOLEAN DEFAULT false,
+    webhook_file_field      TEXT,
+    comment                 TEXT,
+    exec_concurrency        INTEGER DEFAULT 0,
+    resume_delay            INTEGER DEFAULT 0,
+    rescan_client_id        INTEGER DEFAULT 0,
+    delay                   INTEGER DEFAULT 0,
+    disable_after_failures  INTEGER DEFAULT 0,
+    bandwidth_priority      TEXT DEFAULT '' NOT NULL,
+    peer_limit              INTEGER DEFAULT 0,
+    cleanup_min_age         INTEGER DEFAULT 0,
+    cleanup_min_ratio       REAL DEFAULT 0,
+    cleanup_delete_data     BOOLEAN DEFAULT FALSE,
+    cleanup_dry_run         BOOLEAN DEFAULT FALSE,
+    move_completed          BOOLEAN DEFAULT FALSE,
+    move_completed_path     TEXT DEFAULT '' NOT NULL,
+    stop_condition          TEXT DEFAULT '' NOT NULL,
+    grpc_tls_skip_verify    BOOLEAN DEFAULT FALSE,
     external_client_id      INTEGER,
     client_id               INTEGER,
     filter_id               INTEGER,
+    template_id             INTEGER,
+    template_overrides      TEXT[] DEFAULT '{}',
     FOREIGN KEY (filter_id) REFERENCES filter (id),
-    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL,
+    FOREIGN KEY (template_id) REFERENCES action_template (id) ON DELETE SET NULL
 );
 
 CREATE TABLE "release"
@@ -300,6 +382,55 @@ CREATE INDEX release_action_status_release_id_index
 CREATE INDEX release_action_status_filter_id_index
     ON release_action_status (filter_id);
 
+CREATE TABLE action_result
+(
+    id           INTEGER PRIMARY KEY,
+    action_id    INTEGER,
+    action       TEXT NOT NULL,
+    type         TEXT NOT NULL,
+    release_id   INTEGER NOT NULL,
+    release_name TEXT,
+    status       TEXT NOT NULL,
+    error        TEXT,
+    rejections   TEXT []   DEFAULT '{}' NOT NULL,
+    fields       TEXT,
+    response     TEXT,
+    started_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    duration_ms  INTEGER DEFAULT 0,
+    FOREIGN KEY (action_id) REFERENCES action (id) ON DELETE SET NULL,
+    FOREIGN KEY (release_id) REFERENCES "release" (id) ON DELETE CASCADE
+);
+
+CREATE INDEX action_result_release_id_index
+    ON action_result (release_id);
+
+CREATE INDEX action_result_status_index
+    ON action_result (status);
+
+CREATE TABLE pending_resume
+(
+    id           INTEGER PRIMARY KEY,
+    client_id    INTEGER NOT NULL,
+    action_id    INTEGER,
+    torrent_hash TEXT NOT NULL,
+    resume_at    TIMESTAMP NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE CASCADE,
+    FOREIGN KEY (action_id) REFERENCES action (id) ON DELETE SET NULL
+);
+
+CREATE TABLE pending_release
+(
+    id         INTEGER PRIMARY KEY,
+    release_id INTEGER NOT NULL,
+    filter_id  INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    magnet_uri TEXT,
+    vars       TEXT,
+    FOREIGN KEY (release_id) REFERENCES "release" (id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES filter (id) ON DELETE CASCADE
+);
+
 CREATE TABLE notification
 (
 	id         INTEGER PRIMARY KEY,
@@ -321,10 +452,35 @@ CREATE TABLE notification
 	devices    TEXT,
 	topic      TEXT,
 	priority   INTEGER DEFAULT 0,
+	rate_limit INTEGER DEFAULT 0,
+	dispatch_order INTEGER DEFAULT 0,
+	min_interval   INTEGER DEFAULT 0,
+	max_per_hour   INTEGER DEFAULT 0,
+	timeout        INTEGER DEFAULT 0,
+	settle_period  INTEGER DEFAULT 0,
+	suppress_skipped BOOLEAN DEFAULT FALSE,
+	email_from     TEXT,
+	user_agent     TEXT,
+	image_url      TEXT,
+	event_channels TEXT,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE notification_log
+(
+    id              INTEGER PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    event           TEXT NOT NULL,
+    message_id      TEXT,
+    error           TEXT,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (notification_id) REFERENCES notification (id) ON DELETE CASCADE
+);
+
+CREATE INDEX notification_log_notification_id_index
+    ON notification_log (notification_id);
+
 CREATE TABLE feed
 (
 	id            INTEGER PRIMARY KEY,
@@ -1475,5 +1631,309 @@ DROP TABLE feed;
 
 ALTER TABLE feed_dg_tmp
     RENAME TO feed;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN rate_limit INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN min_trackers INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN max_trackers INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN exec_env BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE action
+    ADD COLUMN timeout INTEGER DEFAULT 0;
+`,
+	`CREATE TABLE action_template
+(
+    id         INTEGER PRIMARY KEY,
+    name       TEXT NOT NULL,
+    config     TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE action
+    ADD COLUMN template_id INTEGER
+        REFERENCES action_template (id) ON DELETE SET NULL;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN event_channels TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN dedup_window INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN dedup_key TEXT DEFAULT '';
+`,
+	`ALTER TABLE action
+    ADD COLUMN grpc_method TEXT;
+`,
+	`CREATE TABLE action_result
+(
+    id           INTEGER PRIMARY KEY,
+    action_id    INTEGER,
+    action       TEXT NOT NULL,
+    type         TEXT NOT NULL,
+    release_id   INTEGER NOT NULL,
+    release_name TEXT,
+    status       TEXT NOT NULL,
+    error        TEXT,
+    rejections   TEXT []   DEFAULT '{}' NOT NULL,
+    fields       TEXT,
+    response     TEXT,
+    started_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    duration_ms  INTEGER DEFAULT 0,
+    FOREIGN KEY (action_id) REFERENCES action (id) ON DELETE SET NULL,
+    FOREIGN KEY (release_id) REFERENCES "release" (id) ON DELETE CASCADE
+);
+
+CREATE INDEX action_result_release_id_index
+    ON action_result (release_id);
+
+CREATE INDEX action_result_status_index
+    ON action_result (status);
+`,
+	`ALTER TABLE action
+    ADD COLUMN reannounce_target_peers INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN priority TEXT;
+
+ALTER TABLE action
+    ADD COLUMN pp_script TEXT;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN dispatch_order INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN verify_start BOOLEAN DEFAULT false;
+`,
+	`ALTER TABLE action
+    ADD COLUMN recheck_resume BOOLEAN DEFAULT false;
+`,
+	`CREATE TABLE indexer_macro_override
+(
+    id          INTEGER PRIMARY KEY,
+    indexer     TEXT NOT NULL,
+    field       TEXT NOT NULL,
+    match_value TEXT,
+    value       TEXT,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX indexer_macro_override_indexer_index
+    ON indexer_macro_override (indexer);
+`,
+	`ALTER TABLE action
+    ADD COLUMN webhook_validate_json BOOLEAN DEFAULT false;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN min_interval INTEGER DEFAULT 0;
+
+ALTER TABLE notification
+    ADD COLUMN max_per_hour INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN path_os TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN exec_workdir TEXT;
+
+ALTER TABLE action
+    ADD COLUMN exec_shell BOOLEAN DEFAULT false;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN email_from TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN preset TEXT;
+
+UPDATE action
+SET preset = label
+WHERE type = 'PORLA';
+`,
+	`ALTER TABLE irc_network
+    ADD COLUMN proxy TEXT;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN match_subtitles TEXT []   DEFAULT '{}';
+
+ALTER TABLE filter
+    ADD COLUMN except_subtitles TEXT []   DEFAULT '{}';
+`,
+	`ALTER TABLE action
+    ADD COLUMN webhook_success_when TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN top_of_queue BOOLEAN DEFAULT false;
+
+ALTER TABLE action
+    ADD COLUMN queue_position INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN stop_on_match BOOLEAN DEFAULT TRUE;
+`,
+	`ALTER TABLE action
+    ADD COLUMN archive_path TEXT;
+
+ALTER TABLE action
+    ADD COLUMN archive_filename TEXT;
+
+ALTER TABLE action
+    ADD COLUMN archive_mode TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN min_free_space TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN auto_tmm BOOLEAN DEFAULT false;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN max_active_downloads INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN webhook_file_field TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN comment TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN exec_concurrency INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN resume_delay INTEGER DEFAULT 0;
+`,
+	`CREATE TABLE pending_resume
+(
+    id           INTEGER PRIMARY KEY,
+    client_id    INTEGER NOT NULL,
+    action_id    INTEGER,
+    torrent_hash TEXT NOT NULL,
+    resume_at    TIMESTAMP NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE CASCADE,
+    FOREIGN KEY (action_id) REFERENCES action (id) ON DELETE SET NULL
+);
+`,
+	`ALTER TABLE notification
+    ADD COLUMN user_agent TEXT;
+`,
+	`ALTER TABLE action
+    ADD COLUMN rescan_client_id INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN delay INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN disable_after_failures INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN timeout INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN min_seeders INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN max_seeders INTEGER DEFAULT 0;
+
+ALTER TABLE filter
+    ADD COLUMN reject_unknown_seeders BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE action
+    ADD COLUMN bandwidth_priority TEXT DEFAULT '' NOT NULL;
+
+ALTER TABLE action
+    ADD COLUMN peer_limit INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN require_download BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE action
+    ADD COLUMN cleanup_min_age INTEGER DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN cleanup_min_ratio REAL DEFAULT 0;
+
+ALTER TABLE action
+    ADD COLUMN cleanup_delete_data BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE action
+    ADD COLUMN cleanup_dry_run BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN match_audio TEXT []   DEFAULT '{}';
+
+ALTER TABLE filter
+    ADD COLUMN except_audio TEXT []   DEFAULT '{}';
+`,
+	`CREATE TABLE notification_log
+(
+    id              INTEGER PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    event           TEXT NOT NULL,
+    message_id      TEXT,
+    error           TEXT,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (notification_id) REFERENCES notification (id) ON DELETE CASCADE
+);
+
+CREATE INDEX notification_log_notification_id_index
+    ON notification_log (notification_id);
+`,
+	`ALTER TABLE action
+    ADD COLUMN move_completed BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE action
+    ADD COLUMN move_completed_path TEXT DEFAULT '' NOT NULL;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN require_approval BOOLEAN DEFAULT FALSE;
+
+CREATE TABLE pending_release
+(
+    id         INTEGER PRIMARY KEY,
+    release_id INTEGER NOT NULL,
+    filter_id  INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release" (id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES filter (id) ON DELETE CASCADE
+);
+`,
+	`ALTER TABLE notification
+    ADD COLUMN settle_period INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE filter_external
+    ADD COLUMN webhook_timeout INTEGER;
+
+ALTER TABLE filter_external
+    ADD COLUMN webhook_fail_open BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN suppress_skipped BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter
+    ADD COLUMN min_group_tier INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE action
+    ADD COLUMN stop_condition TEXT DEFAULT '' NOT NULL;
+`,
+	`ALTER TABLE action
+    ADD COLUMN grpc_tls_skip_verify BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE action
+    ADD COLUMN template_overrides TEXT []   DEFAULT '{}';
+`,
+	`ALTER TABLE pending_release
+    ADD COLUMN magnet_uri TEXT;
+
+ALTER TABLE pending_release
+    ADD COLUMN vars TEXT;
+`,
+	`ALTER TABLE notification
+    ADD COLUMN image_url TEXT;
 `,
 }
@@ -5,10 +5,17 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/grpcjson"
+	"github.com/autobrr/autobrr/pkg/sabnzbd"
+
+	"github.com/dustin/go-humanize"
 )
 
 type ActionRepo interface {
@@ -20,51 +27,125 @@ type ActionRepo interface {
 	Delete(ctx context.Context, req *DeleteActionRequest) error
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	ToggleEnabled(actionID int) error
+	Clone(ctx context.Context, req *CloneActionRequest) ([]*Action, error)
 }
 
 type Action struct {
-	ID                       int                 `json:"id"`
-	Name                     string              `json:"name"`
-	Type                     ActionType          `json:"type"`
-	Enabled                  bool                `json:"enabled"`
-	ExecCmd                  string              `json:"exec_cmd,omitempty"`
-	ExecArgs                 string              `json:"exec_args,omitempty"`
-	WatchFolder              string              `json:"watch_folder,omitempty"`
-	Category                 string              `json:"category,omitempty"`
-	Tags                     string              `json:"tags,omitempty"`
-	Label                    string              `json:"label,omitempty"`
-	SavePath                 string              `json:"save_path,omitempty"`
-	Paused                   bool                `json:"paused,omitempty"`
-	IgnoreRules              bool                `json:"ignore_rules,omitempty"`
-	SkipHashCheck            bool                `json:"skip_hash_check,omitempty"`
-	ContentLayout            ActionContentLayout `json:"content_layout,omitempty"`
-	LimitUploadSpeed         int64               `json:"limit_upload_speed,omitempty"`
-	LimitDownloadSpeed       int64               `json:"limit_download_speed,omitempty"`
-	LimitRatio               float64             `json:"limit_ratio,omitempty"`
-	LimitSeedTime            int64               `json:"limit_seed_time,omitempty"`
-	ReAnnounceSkip           bool                `json:"reannounce_skip,omitempty"`
-	ReAnnounceDelete         bool                `json:"reannounce_delete,omitempty"`
-	ReAnnounceInterval       int64               `json:"reannounce_interval,omitempty"`
-	ReAnnounceMaxAttempts    int64               `json:"reannounce_max_attempts,omitempty"`
-	WebhookHost              string              `json:"webhook_host,omitempty"`
-	WebhookType              string              `json:"webhook_type,omitempty"`
-	WebhookMethod            string              `json:"webhook_method,omitempty"`
-	WebhookData              string              `json:"webhook_data,omitempty"`
-	WebhookHeaders           []string            `json:"webhook_headers,omitempty"`
-	ExternalDownloadClientID int32               `json:"external_download_client_id,omitempty"`
-	FilterID                 int                 `json:"filter_id,omitempty"`
-	ClientID                 int32               `json:"client_id,omitempty"`
-	Client                   *DownloadClient     `json:"client,omitempty"`
-}
-
-// ParseMacros parse all macros on action
-func (a *Action) ParseMacros(release *Release) error {
+	ID                       int                     `json:"id"`
+	Name                     string                  `json:"name"`
+	Type                     ActionType              `json:"type"`
+	Enabled                  bool                    `json:"enabled"`
+	ExecCmd                  string                  `json:"exec_cmd,omitempty"`
+	ExecArgs                 string                  `json:"exec_args,omitempty"`
+	ExecArgv                 []string                `json:"-"`
+	ExecEnv                  bool                    `json:"exec_env,omitempty"`
+	ExecWorkDir              string                  `json:"exec_workdir,omitempty"`
+	ExecShell                bool                    `json:"exec_shell,omitempty"`
+	ExecConcurrency          int                     `json:"exec_concurrency,omitempty"`
+	WatchFolder              string                  `json:"watch_folder,omitempty"`
+	ArchivePath              string                  `json:"archive_path,omitempty"`
+	ArchiveFilename          string                  `json:"archive_filename,omitempty"`
+	ArchiveMode              ActionArchiveMode       `json:"archive_mode,omitempty"`
+	Category                 string                  `json:"category,omitempty"`
+	Tags                     string                  `json:"tags,omitempty"`
+	Label                    string                  `json:"label,omitempty"`
+	Preset                   string                  `json:"preset,omitempty"`
+	SavePath                 string                  `json:"save_path,omitempty"`
+	Paused                   bool                    `json:"paused,omitempty"`
+	ResumeDelay              int                     `json:"resume_delay,omitempty"`
+	RescanClientID           int32                   `json:"rescan_client_id,omitempty"`
+	IgnoreRules              bool                    `json:"ignore_rules,omitempty"`
+	SkipHashCheck            bool                    `json:"skip_hash_check,omitempty"`
+	ContentLayout            ActionContentLayout     `json:"content_layout,omitempty"`
+	StopCondition            ActionStopCondition     `json:"stop_condition,omitempty"`
+	TopOfQueue               bool                    `json:"top_of_queue,omitempty"`
+	QueuePosition            int                     `json:"queue_position,omitempty"`
+	MinFreeSpace             string                  `json:"min_free_space,omitempty"`
+	AutoTMM                  bool                    `json:"auto_tmm,omitempty"`
+	LimitUploadSpeed         int64                   `json:"limit_upload_speed,omitempty"`
+	LimitDownloadSpeed       int64                   `json:"limit_download_speed,omitempty"`
+	LimitRatio               float64                 `json:"limit_ratio,omitempty"`
+	LimitSeedTime            int64                   `json:"limit_seed_time,omitempty"`
+	ReAnnounceSkip           bool                    `json:"reannounce_skip,omitempty"`
+	ReAnnounceDelete         bool                    `json:"reannounce_delete,omitempty"`
+	ReAnnounceInterval       int64                   `json:"reannounce_interval,omitempty"`
+	ReAnnounceMaxAttempts    int64                   `json:"reannounce_max_attempts,omitempty"`
+	ReAnnounceTargetPeers    int64                   `json:"reannounce_target_peers,omitempty"`
+	VerifyStart              bool                    `json:"verify_start,omitempty"`
+	RecheckResume            bool                    `json:"recheck_resume,omitempty"`
+	Timeout                  int                     `json:"timeout,omitempty"`
+	Delay                    int                     `json:"delay,omitempty"`
+	DisableAfterFailures     int                     `json:"disable_after_failures,omitempty"`
+	BandwidthPriority        ActionBandwidthPriority `json:"bandwidth_priority,omitempty"`
+	PeerLimit                int64                   `json:"peer_limit,omitempty"`
+	CleanupMinAge            int64                   `json:"cleanup_min_age,omitempty"`
+	CleanupMinRatio          float64                 `json:"cleanup_min_ratio,omitempty"`
+	CleanupDeleteData        bool                    `json:"cleanup_delete_data,omitempty"`
+	CleanupDryRun            bool                    `json:"cleanup_dry_run,omitempty"`
+	MoveCompleted            bool                    `json:"move_completed,omitempty"`
+	MoveCompletedPath        string                  `json:"move_completed_path,omitempty"`
+	WebhookHost              string                  `json:"webhook_host,omitempty"`
+	WebhookType              string                  `json:"webhook_type,omitempty"`
+	WebhookMethod            string                  `json:"webhook_method,omitempty"`
+	WebhookData              string                  `json:"webhook_data,omitempty"`
+	GrpcMethod               string                  `json:"grpc_method,omitempty"`
+	GrpcTLSSkipVerify        bool                    `json:"grpc_tls_skip_verify,omitempty"`
+	Priority                 string                  `json:"priority,omitempty"`
+	PostProcessScript        string                  `json:"pp_script,omitempty"`
+	WebhookHeaders           []string                `json:"webhook_headers,omitempty"`
+	WebhookValidateJSON      bool                    `json:"webhook_validate_json,omitempty"`
+	WebhookSuccessWhen       string                  `json:"webhook_success_when,omitempty"`
+	WebhookFileField         string                  `json:"webhook_file_field,omitempty"`
+	Comment                  string                  `json:"comment,omitempty"`
+	PathOS                   ActionPathOS            `json:"path_os,omitempty"`
+	ExternalDownloadClientID int32                   `json:"external_download_client_id,omitempty"`
+	FilterID                 int                     `json:"filter_id,omitempty"`
+	TemplateID               int                     `json:"template_id,omitempty"`
+	TemplateOverrides        []string                `json:"template_overrides,omitempty"`
+	ClientID                 int32                   `json:"client_id,omitempty"`
+	Client                   *DownloadClient         `json:"client,omitempty"`
+}
+
+var ErrActionTimeout = errors.New("action timed out")
+
+// ErrMaxActiveDownloads is returned by an action when the download client has reached the max active downloads of the filter
+var ErrMaxActiveDownloads = errors.New("max active downloads of filter reached")
+
+// Copy returns a deep copy of the action without its id and filter id
+func (a *Action) Copy() Action {
+	c := *a
+	c.ID = 0
+	c.FilterID = 0
+
+	if a.ExecArgv != nil {
+		c.ExecArgv = append([]string(nil), a.ExecArgv...)
+	}
+
+	if a.WebhookHeaders != nil {
+		c.WebhookHeaders = append([]string(nil), a.WebhookHeaders...)
+	}
+
+	if a.TemplateOverrides != nil {
+		c.TemplateOverrides = append([]string(nil), a.TemplateOverrides...)
+	}
+
+	if a.Client != nil {
+		client := *a.Client
+		c.Client = &client
+	}
+
+	return c
+}
+
+// ParseMacros parse all macros on action, indexer overrides are applied to the macro values first
+func (a *Action) ParseMacros(release *Release, overrides ...MacroOverride) error {
 	var err error
 
 	if release.TorrentTmpFile == "" &&
-		(strings.Contains(a.ExecArgs, "TorrentPathName") || strings.Contains(a.ExecArgs, "TorrentDataRawBytes") ||
-			strings.Contains(a.WebhookData, "TorrentPathName") || strings.Contains(a.WebhookData, "TorrentDataRawBytes") ||
-			strings.Contains(a.SavePath, "TorrentPathName") || a.Type == ActionTypeWatchFolder) {
+		(a.containsMacro("TorrentPathName") || a.containsMacro("TorrentDataRawBytes") ||
+			a.Type == ActionTypeWatchFolder || a.Type == ActionTypeArchiveTorrent ||
+			(a.Type == ActionTypeWebhook && a.WebhookFileField != "") ||
+			(release.TorrentHash == "" && a.containsMacro("InfoHash")) || a.containsMacro("TrackerCount")) {
 		if err := release.DownloadTorrentFile(); err != nil {
 			return errors.Wrap(err, "webhook: could not download torrent file for release: %v", release.TorrentName)
 		}
@@ -72,52 +153,438 @@ func (a *Action) ParseMacros(release *Release) error {
 
 	// if webhook data contains TorrentDataRawBytes, lets read the file into bytes we can then use in the macro
 	if len(release.TorrentDataRawBytes) == 0 &&
-		(strings.Contains(a.ExecArgs, "TorrentDataRawBytes") || strings.Contains(a.WebhookData, "TorrentDataRawBytes") ||
-			a.Type == ActionTypeWatchFolder) {
+		(a.containsMacro("TorrentDataRawBytes") || a.Type == ActionTypeWatchFolder) {
 		t, err := os.ReadFile(release.TorrentTmpFile)
 		if err != nil {
-			return errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
+			// the tmp file might have been removed by cleanup, re-download it once before giving up
+			tmpFile := release.TorrentTmpFile
+			release.TorrentTmpFile = ""
+
+			if dlErr := release.DownloadTorrentFile(); dlErr != nil {
+				return errors.Wrap(err, "could not read torrent file: %v and re-download failed: %v", tmpFile, dlErr)
+			}
+
+			t, err = os.ReadFile(release.TorrentTmpFile)
+			if err != nil {
+				return errors.Wrap(err, "could not read re-downloaded torrent file: %v", release.TorrentTmpFile)
+			}
 		}
 
 		release.TorrentDataRawBytes = t
 	}
 
-	m := NewMacro(*release)
+	// compute the infohash from the downloaded torrent file if needed
+	if release.TorrentHash == "" && a.containsMacro("InfoHash") {
+		if err := release.ComputeInfoHash(); err != nil {
+			return errors.Wrap(err, "could not compute infohash for release: %v", release.TorrentName)
+		}
+	}
+
+	if a.containsMacro("TrackerCount") {
+		if err := release.ComputeTrackerCount(); err != nil {
+			return errors.Wrap(err, "could not count trackers for release: %v", release.TorrentName)
+		}
+	}
+
+	// infohash is exported to exec actions, compute it if the torrent file is already available
+	if a.ExecEnv && release.TorrentHash == "" && (release.TorrentTmpFile != "" || len(release.TorrentDataRawBytes) > 0) {
+		if err := release.ComputeInfoHash(); err != nil {
+			return errors.Wrap(err, "could not compute infohash for release: %v", release.TorrentName)
+		}
+	}
+
+	m := NewMacro(*release).WithPathOS(a.PathOS)
+	m.ApplyOverrides(overrides)
+
+	// parse the save path first so the other fields can use the resolved path
+	if a.Client != nil {
+		m.ClientSavePath = a.Client.Settings.SavePath
+		m.Client.Name = a.Client.Name
+	}
+
+	a.SavePath, err = m.PathSafe().Parse(a.SavePath)
+	if err != nil {
+		return errors.Wrap(err, "could not parse macros for action: %v", a.Name)
+	}
+
+	m.SavePath = ResolveSavePath(m.ClientSavePath, a.SavePath)
+
+	// split exec args before expanding macros so values with spaces or quotes stay a single argument.
+	// templates spanning multiple arguments can't be split up front, so fall back to splitting the parsed string.
+	if argv, err := m.ParseArgs(a.ExecArgs); err == nil {
+		a.ExecArgv = argv
+	} else if a.ExecShell {
+		// the shell gets the args as positional parameters, the parsed string must never reach it
+		return errors.Wrap(err, "could not split exec args for action: %v", a.Name)
+	}
 
 	a.ExecArgs, err = m.Parse(a.ExecArgs)
-	a.WatchFolder, err = m.Parse(a.WatchFolder)
+	a.WatchFolder, err = m.PathSafe().Parse(a.WatchFolder)
+	a.ArchivePath, err = m.PathSafe().Parse(a.ArchivePath)
+	a.ArchiveFilename, err = m.PathSafe().Parse(a.ArchiveFilename)
+	a.MoveCompletedPath, err = m.PathSafe().Parse(a.MoveCompletedPath)
 	a.Category, err = m.Parse(a.Category)
 	a.Tags, err = m.Parse(a.Tags)
 	a.Label, err = m.Parse(a.Label)
-	a.SavePath, err = m.Parse(a.SavePath)
+	a.Preset, err = m.Parse(a.Preset)
 	a.WebhookData, err = m.Parse(a.WebhookData)
+	a.Priority, err = m.Parse(a.Priority)
+	a.PostProcessScript, err = m.Parse(a.PostProcessScript)
+	a.Comment, err = m.Parse(a.Comment)
 
 	if err != nil {
 		return errors.Wrap(err, "could not parse macros for action: %v", a.Name)
 	}
 
+	// a macro could have expanded to nothing, the client would keep the data on the download disk
+	if a.MoveCompleted && strings.TrimSpace(a.MoveCompletedPath) == "" {
+		return errors.New("move completed path is empty after parsing macros for action: %v", a.Name)
+	}
+
+	if a.Type == ActionTypeWebhook && a.WebhookHost != "" {
+		host, err := m.ParseURL(a.WebhookHost)
+		if err != nil {
+			return errors.Wrap(err, "could not parse macros in webhook url for action: %v", a.Name)
+		}
+		a.WebhookHost = host
+
+		// a macro could have expanded to something that isn't a url
+		if u, err := url.Parse(a.WebhookHost); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("invalid webhook url after parsing macros for action %v: %s", a.Name, a.WebhookHost)
+		}
+	}
+
+	// only header values are templated, build a new slice so a shared template is not modified
+	if len(a.WebhookHeaders) > 0 {
+		headers := make([]string, 0, len(a.WebhookHeaders))
+		for _, header := range a.WebhookHeaders {
+			name, value, found := strings.Cut(header, "=")
+			if found {
+				value, err = m.Parse(value)
+				if err != nil {
+					return errors.Wrap(err, "could not parse macros for header %s for action: %v", name, a.Name)
+				}
+				header = name + "=" + value
+			}
+
+			headers = append(headers, header)
+		}
+
+		a.WebhookHeaders = headers
+	}
+
 	return nil
 }
 
+// WebhookSuccessRule checks a value in the json response of a webhook, written as path=value or path!=value.
+// The path is dot separated with array indexes as numbers, eg. status=accepted or $.results.0.ok=true
+type WebhookSuccessRule struct {
+	Path   []string
+	Value  string
+	Negate bool
+}
+
+func ParseWebhookSuccessRule(rule string) (*WebhookSuccessRule, error) {
+	negate := true
+	key, value, found := strings.Cut(rule, "!=")
+	if !found {
+		negate = false
+		key, value, found = strings.Cut(rule, "=")
+	}
+	if !found {
+		return nil, errors.New("invalid webhook success rule %q, expected path=value", rule)
+	}
+
+	// accept the JSONPath root prefix
+	key = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(key), "$"), ".")
+	if key == "" {
+		return nil, errors.New("invalid webhook success rule %q, missing path", rule)
+	}
+
+	return &WebhookSuccessRule{
+		Path:   strings.Split(key, "."),
+		Value:  strings.Trim(strings.TrimSpace(value), `"`),
+		Negate: negate,
+	}, nil
+}
+
+// Match reports if the json body satisfies the rule. A missing path never equals the value.
+func (r *WebhookSuccessRule) Match(body []byte) (bool, error) {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return false, errors.Wrap(err, "response is not valid json")
+	}
+
+	found := true
+	for _, key := range r.Path {
+		switch node := v.(type) {
+		case map[string]any:
+			v, found = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if found = err == nil && i >= 0 && i < len(node); found {
+				v = node[i]
+			}
+		default:
+			found = false
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	equal := false
+	if found {
+		if s, ok := v.(string); ok {
+			equal = s == r.Value
+		} else if raw, err := json.Marshal(v); err == nil {
+			equal = string(raw) == r.Value
+		}
+	}
+
+	return equal != r.Negate, nil
+}
+
+// ResolveSavePath returns where the client places the files: an absolute action save path is used as is,
+// a relative one is joined with the client base path, and without one the client base path is used.
+func ResolveSavePath(clientPath, savePath string) string {
+	if savePath == "" {
+		return clientPath
+	}
+
+	if clientPath == "" || isAbsPath(savePath) {
+		return savePath
+	}
+
+	// the client might not run on the same OS as autobrr, so join with the separator of the base path
+	sep := "/"
+	if strings.Contains(clientPath, `\`) && !strings.Contains(clientPath, "/") {
+		sep = `\`
+	}
+
+	return strings.TrimRight(clientPath, `/\`) + sep + strings.TrimLeft(savePath, `/\`)
+}
+
+// isAbsPath checks for both posix and windows absolute paths
+func isAbsPath(path string) bool {
+	if strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\`) {
+		return true
+	}
+
+	// drive letter, eg. D:\downloads or D:/downloads
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/')
+}
+
+// templatedFields returns the fields ParseMacros parses, keep it in sync when a field gets macro support
+func (a *Action) templatedFields() []string {
+	fields := []string{
+		a.SavePath,
+		a.ExecArgs,
+		a.WatchFolder,
+		a.ArchivePath,
+		a.ArchiveFilename,
+		a.MoveCompletedPath,
+		a.Category,
+		a.Tags,
+		a.Label,
+		a.Preset,
+		a.WebhookData,
+		a.Priority,
+		a.PostProcessScript,
+		a.Comment,
+		a.WebhookHost,
+	}
+
+	return append(fields, a.WebhookHeaders...)
+}
+
+// containsMacro checks if any of the fields that support macros references the macro
+func (a *Action) containsMacro(macro string) bool {
+	for _, field := range a.templatedFields() {
+		if strings.Contains(field, macro) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TagList returns the tags of the action after macro expansion, split on commas and newlines so a macro can
+// expand to several tags like {{ range .Categories }}{{ . }},{{ end }}. Empty and duplicate tags are dropped.
+func (a *Action) TagList() []string {
+	return SplitTags(a.Tags)
+}
+
+// SplitTags splits a comma or newline separated list of tags, trims them and drops empty and duplicate tags
+func SplitTags(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	seen := make(map[string]struct{}, len(fields))
+	tags := make([]string, 0, len(fields))
+
+	for _, tag := range fields {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// Validate checks the action config that can be verified before it's run
+func (a *Action) Validate() error {
+	if a.MinFreeSpace != "" {
+		if _, err := humanize.ParseBytes(a.MinFreeSpace); err != nil {
+			return errors.Wrap(err, "validation error: action %q invalid min free space", a.Name)
+		}
+	}
+
+	if a.ResumeDelay < 0 {
+		return errors.New("validation error: action %q resume delay can't be negative", a.Name)
+	}
+
+	if a.Delay < 0 {
+		return errors.New("validation error: action %q delay can't be negative", a.Name)
+	}
+
+	if a.DisableAfterFailures < 0 {
+		return errors.New("validation error: action %q disable after failures can't be negative", a.Name)
+	}
+
+	if a.PeerLimit < 0 {
+		return errors.New("validation error: action %q peer limit can't be negative", a.Name)
+	}
+
+	switch a.BandwidthPriority {
+	case "", ActionBandwidthPriorityHigh, ActionBandwidthPriorityNormal, ActionBandwidthPriorityLow:
+	default:
+		return errors.New("validation error: action %q invalid bandwidth priority: %s", a.Name, a.BandwidthPriority)
+	}
+
+	if a.ResumeDelay > 0 && !a.Paused {
+		return errors.New("validation error: action %q resume delay requires the torrent to be added paused", a.Name)
+	}
+
+	switch a.Type {
+	case ActionTypeGRPC:
+		if _, err := grpcjson.ParseTarget(a.WebhookHost); err != nil {
+			return errors.Wrap(err, "validation error: action %q", a.Name)
+		}
+		if _, err := grpcjson.ParseMethod(a.GrpcMethod); err != nil {
+			return errors.Wrap(err, "validation error: action %q", a.Name)
+		}
+
+	case ActionTypeWebhook:
+		if a.WebhookSuccessWhen != "" {
+			if _, err := ParseWebhookSuccessRule(a.WebhookSuccessWhen); err != nil {
+				return errors.Wrap(err, "validation error: action %q", a.Name)
+			}
+		}
+
+	case ActionTypeQbittorrent:
+		// qBittorrent ignores the save path when the category decides where the torrent goes
+		if a.AutoTMM && a.SavePath != "" {
+			return errors.New("validation error: action %q save path can't be used with automatic torrent management, the category save path is used instead", a.Name)
+		}
+
+		switch a.StopCondition {
+		case "", ActionStopConditionMetadataReceived, ActionStopConditionFilesChecked:
+		default:
+			return errors.New("validation error: action %q invalid stop condition: %s", a.Name, a.StopCondition)
+		}
+
+	case ActionTypeDelugeV1, ActionTypeDelugeV2:
+		if a.MoveCompleted && strings.TrimSpace(a.MoveCompletedPath) == "" {
+			return errors.New("validation error: action %q move completed requires a path", a.Name)
+		}
+
+	case ActionTypeCleanup:
+		if a.CleanupMinAge < 0 || a.CleanupMinRatio < 0 {
+			return errors.New("validation error: action %q cleanup min age and min ratio can't be negative", a.Name)
+		}
+
+		// without criteria every torrent in the client would be removed
+		if !a.HasCleanupCriteria() {
+			return errors.New("validation error: action %q cleanup needs at least one of category, tags, label, min age or min ratio", a.Name)
+		}
+
+	case ActionTypeArchiveTorrent:
+		if a.ArchivePath == "" {
+			return errors.New("validation error: action %q missing archive path", a.Name)
+		}
+
+		switch a.ArchiveMode {
+		case "", ActionArchiveModeCopy, ActionArchiveModeHardlink:
+		default:
+			return errors.New("validation error: action %q invalid archive mode: %s", a.Name, a.ArchiveMode)
+		}
+
+	case ActionTypeSabnzbd:
+		// priority with macros can only be checked once parsed
+		if a.Priority != "" && !strings.Contains(a.Priority, "{{") {
+			if _, err := sabnzbd.ParsePriority(a.Priority); err != nil {
+				return errors.Wrap(err, "validation error: action %q", a.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HasCleanupCriteria checks if at least one of the cleanup criteria is set.
+// Without criteria every torrent in the client matches the cleanup action.
+func (a *Action) HasCleanupCriteria() bool {
+	return strings.TrimSpace(a.Category) != "" || len(a.TagList()) > 0 || strings.TrimSpace(a.Label) != "" || a.CleanupMinAge > 0 || a.CleanupMinRatio > 0
+}
+
+// ApplyClientDefaults sets ratio and seed time limits from the download client defaults
+// unless the action overrides them
+func (a *Action) ApplyClientDefaults(client *DownloadClient) {
+	if client == nil {
+		return
+	}
+
+	if a.LimitRatio == 0 {
+		a.LimitRatio = client.Settings.LimitRatio
+	}
+	if a.LimitSeedTime == 0 {
+		a.LimitSeedTime = client.Settings.LimitSeedTime
+	}
+}
+
 type ActionType string
 
 const (
-	ActionTypeTest         ActionType = "TEST"
-	ActionTypeExec         ActionType = "EXEC"
-	ActionTypeQbittorrent  ActionType = "QBITTORRENT"
-	ActionTypeDelugeV1     ActionType = "DELUGE_V1"
-	ActionTypeDelugeV2     ActionType = "DELUGE_V2"
-	ActionTypeRTorrent     ActionType = "RTORRENT"
-	ActionTypeTransmission ActionType = "TRANSMISSION"
-	ActionTypePorla        ActionType = "PORLA"
-	ActionTypeWatchFolder  ActionType = "WATCH_FOLDER"
-	ActionTypeWebhook      ActionType = "WEBHOOK"
-	ActionTypeRadarr       ActionType = "RADARR"
-	ActionTypeSonarr       ActionType = "SONARR"
-	ActionTypeLidarr       ActionType = "LIDARR"
-	ActionTypeWhisparr     ActionType = "WHISPARR"
-	ActionTypeReadarr      ActionType = "READARR"
-	ActionTypeSabnzbd      ActionType = "SABNZBD"
+	ActionTypeTest           ActionType = "TEST"
+	ActionTypeExec           ActionType = "EXEC"
+	ActionTypeQbittorrent    ActionType = "QBITTORRENT"
+	ActionTypeDelugeV1       ActionType = "DELUGE_V1"
+	ActionTypeDelugeV2       ActionType = "DELUGE_V2"
+	ActionTypeRTorrent       ActionType = "RTORRENT"
+	ActionTypeTransmission   ActionType = "TRANSMISSION"
+	ActionTypePorla          ActionType = "PORLA"
+	ActionTypeWatchFolder    ActionType = "WATCH_FOLDER"
+	ActionTypeArchiveTorrent ActionType = "ARCHIVE_TORRENT"
+	ActionTypeWebhook        ActionType = "WEBHOOK"
+	ActionTypeGRPC           ActionType = "GRPC"
+	ActionTypeRadarr         ActionType = "RADARR"
+	ActionTypeSonarr         ActionType = "SONARR"
+	ActionTypeLidarr         ActionType = "LIDARR"
+	ActionTypeWhisparr       ActionType = "WHISPARR"
+	ActionTypeReadarr        ActionType = "READARR"
+	ActionTypeSabnzbd        ActionType = "SABNZBD"
+	ActionTypeCleanup        ActionType = "CLEANUP"
 )
 
 type ActionContentLayout string
@@ -128,6 +595,39 @@ const (
 	ActionContentLayoutSubfolderCreate ActionContentLayout = "SUBFOLDER_CREATE"
 )
 
+// ActionStopCondition is when qBittorrent stops a newly added torrent, empty means it's not stopped
+type ActionStopCondition string
+
+const (
+	ActionStopConditionMetadataReceived ActionStopCondition = "MetadataReceived"
+	ActionStopConditionFilesChecked     ActionStopCondition = "FilesChecked"
+)
+
+// ActionArchiveMode decides how the archive torrent action stores the torrent file
+type ActionArchiveMode string
+
+const (
+	ActionArchiveModeCopy     ActionArchiveMode = "COPY"
+	ActionArchiveModeHardlink ActionArchiveMode = "HARDLINK"
+)
+
+// ActionBandwidthPriority is the bandwidth priority of the torrent in Transmission
+type ActionBandwidthPriority string
+
+const (
+	ActionBandwidthPriorityHigh   ActionBandwidthPriority = "HIGH"
+	ActionBandwidthPriorityNormal ActionBandwidthPriority = "NORMAL"
+	ActionBandwidthPriorityLow    ActionBandwidthPriority = "LOW"
+)
+
+// ActionPathOS is the OS of the client the save path is used on, it decides what sanitizePath replaces
+type ActionPathOS string
+
+const (
+	ActionPathOSPosix   ActionPathOS = "POSIX"
+	ActionPathOSWindows ActionPathOS = "WINDOWS"
+)
+
 type GetActionRequest struct {
 	Id int
 }
@@ -135,3 +635,10 @@ type GetActionRequest struct {
 type DeleteActionRequest struct {
 	ActionId int
 }
+
+// CloneActionRequest copies the action onto the filters, Name overrides the name of the copies when set
+type CloneActionRequest struct {
+	ActionID  int    `json:"-"`
+	FilterIDs []int  `json:"filter_ids"`
+	Name      string `json:"name,omitempty"`
+}
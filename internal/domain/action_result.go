@@ -0,0 +1,93 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ActionResultRepo interface {
+	Store(ctx context.Context, result *ActionResult) error
+	Find(ctx context.Context, params ActionResultQueryParams) ([]ActionResult, error)
+}
+
+type ActionResultStatus string
+
+const (
+	ActionResultStatusSuccess ActionResultStatus = "SUCCESS"
+	ActionResultStatusSkipped ActionResultStatus = "SKIPPED"
+	ActionResultStatusFailed  ActionResultStatus = "FAILED"
+)
+
+// ActionResult is the outcome of a single action run for a release
+type ActionResult struct {
+	ID          int64              `json:"id"`
+	ActionID    int64              `json:"action_id"`
+	Action      string             `json:"action"`
+	Type        ActionType         `json:"type"`
+	ReleaseID   int64              `json:"release_id"`
+	ReleaseName string             `json:"release_name"`
+	Status      ActionResultStatus `json:"status"`
+	Error       string             `json:"error,omitempty"`
+	Rejections  []string           `json:"rejections"`
+	Fields      map[string]string  `json:"fields,omitempty"`
+	Response    string             `json:"response,omitempty"`
+	StartedAt   time.Time          `json:"started_at"`
+	Duration    time.Duration      `json:"duration"`
+}
+
+type ActionResultQueryParams struct {
+	ReleaseID int64
+	Status    ActionResultStatus
+	Limit     uint64
+}
+
+// NewActionResult starts a result for the action run
+func NewActionResult(action *Action, release *Release) *ActionResult {
+	return &ActionResult{
+		ActionID:    int64(action.ID),
+		Action:      action.Name,
+		Type:        action.Type,
+		ReleaseID:   release.ID,
+		ReleaseName: release.TorrentName,
+		Rejections:  []string{},
+		StartedAt:   time.Now(),
+	}
+}
+
+// ResolvedFields returns the action fields after macros are parsed. The download url of the release is masked
+// since it often contains the passkey, and so is everything after the host of webhook urls.
+func (a *Action) ResolvedFields(release *Release) map[string]string {
+	fields := make(map[string]string)
+
+	set := func(key, value string) {
+		if value != "" {
+			fields[key] = RedactDownloadURL(value, release.DownloadURL)
+		}
+	}
+
+	set("exec_cmd", a.ExecCmd)
+	set("exec_args", a.ExecArgs)
+	set("watch_folder", a.WatchFolder)
+	set("category", a.Category)
+	set("tags", a.Tags)
+	set("label", a.Label)
+	set("save_path", a.SavePath)
+	set("webhook_data", a.WebhookData)
+	set("grpc_method", a.GrpcMethod)
+	set("priority", a.Priority)
+	set("pp_script", a.PostProcessScript)
+
+	// webhook urls can have tokens in the path or query, grpc targets are only a host and port
+	if a.WebhookHost != "" {
+		if a.Type == ActionTypeGRPC {
+			fields["webhook_host"] = a.WebhookHost
+		} else {
+			fields["webhook_host"] = RedactURL(a.WebhookHost)
+		}
+	}
+
+	return fields
+}
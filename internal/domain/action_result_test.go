@@ -0,0 +1,68 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAction_ResolvedFields(t *testing.T) {
+	release := &Release{DownloadURL: "https://tracker.example.com/download/123?passkey=secret"}
+
+	tests := []struct {
+		name   string
+		action Action
+		want   map[string]string
+	}{
+		{
+			name: "exec_args",
+			action: Action{
+				Type:     ActionTypeExec,
+				ExecCmd:  "/usr/bin/notify",
+				ExecArgs: `--url "https://tracker.example.com/download/123?passkey=secret" --category tv`,
+			},
+			want: map[string]string{
+				"exec_cmd":  "/usr/bin/notify",
+				"exec_args": `--url "https://tracker.example.com/<redacted>" --category tv`,
+			},
+		},
+		{
+			name: "webhook",
+			action: Action{
+				Type:        ActionTypeWebhook,
+				WebhookHost: "https://hooks.example.com/api/push?token=secret&url=" + url.QueryEscape(release.DownloadURL),
+				WebhookData: `{"url":"https://tracker.example.com/download/123?passkey=secret"}`,
+			},
+			want: map[string]string{
+				"webhook_host": "https://hooks.example.com/<redacted>",
+				"webhook_data": `{"url":"https://tracker.example.com/<redacted>"}`,
+			},
+		},
+		{
+			name: "grpc_target",
+			action: Action{
+				Type:        ActionTypeGRPC,
+				WebhookHost: "localhost:50051",
+				GrpcMethod:  "autobrr.Releases/Push",
+			},
+			want: map[string]string{
+				"webhook_host": "localhost:50051",
+				"grpc_method":  "autobrr.Releases/Push",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.action.ResolvedFields(release)
+			assert.Equal(t, tt.want, got)
+
+			for _, value := range got {
+				assert.NotContains(t, value, "secret")
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ActionTemplateRepo interface {
+	List(ctx context.Context) ([]ActionTemplate, error)
+	FindByID(ctx context.Context, id int) (*ActionTemplate, error)
+	Store(ctx context.Context, template *ActionTemplate) error
+	Update(ctx context.Context, template *ActionTemplate) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ActionTemplate is a named action config that can be referenced by actions on multiple filters.
+type ActionTemplate struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Action    Action    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ApplyTemplate fills in the action config from the template.
+// Fields set on the action itself are kept as per-filter overrides. A false bool can't be told apart from
+// unset, so bools are taken from the template unless they are listed in TemplateOverrides by json name.
+// The action keeps its own type, the template type is only used when the action has none.
+func (a *Action) ApplyTemplate(t *ActionTemplate) {
+	tmpl := t.Action
+
+	if a.Type == "" {
+		a.Type = tmpl.Type
+	}
+
+	if a.ExecCmd == "" {
+		a.ExecCmd = tmpl.ExecCmd
+	}
+	if a.ExecArgs == "" {
+		a.ExecArgs = tmpl.ExecArgs
+	}
+	a.inheritBool(&a.ExecEnv, tmpl.ExecEnv, "exec_env")
+	if a.ExecWorkDir == "" {
+		a.ExecWorkDir = tmpl.ExecWorkDir
+	}
+	a.inheritBool(&a.ExecShell, tmpl.ExecShell, "exec_shell")
+	if a.ExecConcurrency == 0 {
+		a.ExecConcurrency = tmpl.ExecConcurrency
+	}
+	if a.WatchFolder == "" {
+		a.WatchFolder = tmpl.WatchFolder
+	}
+	if a.MinFreeSpace == "" {
+		a.MinFreeSpace = tmpl.MinFreeSpace
+	}
+	if a.ArchivePath == "" {
+		a.ArchivePath = tmpl.ArchivePath
+	}
+	if a.ArchiveFilename == "" {
+		a.ArchiveFilename = tmpl.ArchiveFilename
+	}
+	if a.ArchiveMode == "" {
+		a.ArchiveMode = tmpl.ArchiveMode
+	}
+	if a.Category == "" {
+		a.Category = tmpl.Category
+	}
+	if a.Tags == "" {
+		a.Tags = tmpl.Tags
+	}
+	if a.Label == "" {
+		a.Label = tmpl.Label
+	}
+	if a.Preset == "" {
+		a.Preset = tmpl.Preset
+	}
+	if a.SavePath == "" {
+		a.SavePath = tmpl.SavePath
+	}
+	a.inheritBool(&a.Paused, tmpl.Paused, "paused")
+	if a.ResumeDelay == 0 {
+		a.ResumeDelay = tmpl.ResumeDelay
+	}
+	if a.RescanClientID == 0 {
+		a.RescanClientID = tmpl.RescanClientID
+	}
+	if a.Delay == 0 {
+		a.Delay = tmpl.Delay
+	}
+	if a.DisableAfterFailures == 0 {
+		a.DisableAfterFailures = tmpl.DisableAfterFailures
+	}
+	if a.BandwidthPriority == "" {
+		a.BandwidthPriority = tmpl.BandwidthPriority
+	}
+	if a.PeerLimit == 0 {
+		a.PeerLimit = tmpl.PeerLimit
+	}
+	if a.CleanupMinAge == 0 {
+		a.CleanupMinAge = tmpl.CleanupMinAge
+	}
+	if a.CleanupMinRatio == 0 {
+		a.CleanupMinRatio = tmpl.CleanupMinRatio
+	}
+	a.inheritBool(&a.CleanupDeleteData, tmpl.CleanupDeleteData, "cleanup_delete_data")
+	a.inheritBool(&a.CleanupDryRun, tmpl.CleanupDryRun, "cleanup_dry_run")
+	a.inheritBool(&a.MoveCompleted, tmpl.MoveCompleted, "move_completed")
+	if a.MoveCompletedPath == "" {
+		a.MoveCompletedPath = tmpl.MoveCompletedPath
+	}
+	a.inheritBool(&a.IgnoreRules, tmpl.IgnoreRules, "ignore_rules")
+	a.inheritBool(&a.SkipHashCheck, tmpl.SkipHashCheck, "skip_hash_check")
+	if a.ContentLayout == "" {
+		a.ContentLayout = tmpl.ContentLayout
+	}
+	if a.StopCondition == "" {
+		a.StopCondition = tmpl.StopCondition
+	}
+	a.inheritBool(&a.TopOfQueue, tmpl.TopOfQueue, "top_of_queue")
+	if a.QueuePosition == 0 {
+		a.QueuePosition = tmpl.QueuePosition
+	}
+	a.inheritBool(&a.AutoTMM, tmpl.AutoTMM, "auto_tmm")
+	if a.LimitUploadSpeed == 0 {
+		a.LimitUploadSpeed = tmpl.LimitUploadSpeed
+	}
+	if a.LimitDownloadSpeed == 0 {
+		a.LimitDownloadSpeed = tmpl.LimitDownloadSpeed
+	}
+	if a.LimitRatio == 0 {
+		a.LimitRatio = tmpl.LimitRatio
+	}
+	if a.LimitSeedTime == 0 {
+		a.LimitSeedTime = tmpl.LimitSeedTime
+	}
+	a.inheritBool(&a.ReAnnounceSkip, tmpl.ReAnnounceSkip, "reannounce_skip")
+	a.inheritBool(&a.ReAnnounceDelete, tmpl.ReAnnounceDelete, "reannounce_delete")
+	if a.ReAnnounceInterval == 0 {
+		a.ReAnnounceInterval = tmpl.ReAnnounceInterval
+	}
+	if a.ReAnnounceMaxAttempts == 0 {
+		a.ReAnnounceMaxAttempts = tmpl.ReAnnounceMaxAttempts
+	}
+	if a.ReAnnounceTargetPeers == 0 {
+		a.ReAnnounceTargetPeers = tmpl.ReAnnounceTargetPeers
+	}
+	a.inheritBool(&a.VerifyStart, tmpl.VerifyStart, "verify_start")
+	a.inheritBool(&a.RecheckResume, tmpl.RecheckResume, "recheck_resume")
+	if a.Timeout == 0 {
+		a.Timeout = tmpl.Timeout
+	}
+	if a.WebhookHost == "" {
+		a.WebhookHost = tmpl.WebhookHost
+	}
+	if a.WebhookType == "" {
+		a.WebhookType = tmpl.WebhookType
+	}
+	if a.WebhookMethod == "" {
+		a.WebhookMethod = tmpl.WebhookMethod
+	}
+	if a.WebhookData == "" {
+		a.WebhookData = tmpl.WebhookData
+	}
+	if a.GrpcMethod == "" {
+		a.GrpcMethod = tmpl.GrpcMethod
+	}
+	a.inheritBool(&a.GrpcTLSSkipVerify, tmpl.GrpcTLSSkipVerify, "grpc_tls_skip_verify")
+	if a.Priority == "" {
+		a.Priority = tmpl.Priority
+	}
+	if a.PostProcessScript == "" {
+		a.PostProcessScript = tmpl.PostProcessScript
+	}
+	if len(a.WebhookHeaders) == 0 {
+		a.WebhookHeaders = tmpl.WebhookHeaders
+	}
+	a.inheritBool(&a.WebhookValidateJSON, tmpl.WebhookValidateJSON, "webhook_validate_json")
+	if a.WebhookSuccessWhen == "" {
+		a.WebhookSuccessWhen = tmpl.WebhookSuccessWhen
+	}
+	if a.WebhookFileField == "" {
+		a.WebhookFileField = tmpl.WebhookFileField
+	}
+	if a.Comment == "" {
+		a.Comment = tmpl.Comment
+	}
+	if a.PathOS == "" {
+		a.PathOS = tmpl.PathOS
+	}
+	if a.ExternalDownloadClientID == 0 {
+		a.ExternalDownloadClientID = tmpl.ExternalDownloadClientID
+	}
+	if a.ClientID == 0 {
+		a.ClientID = tmpl.ClientID
+	}
+}
+
+// inheritBool sets the bool from the template unless the action overrides it
+func (a *Action) inheritBool(field *bool, value bool, name string) {
+	for _, override := range a.TemplateOverrides {
+		if override == name {
+			return
+		}
+	}
+
+	*field = value
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAction_ApplyTemplate(t *testing.T) {
+	template := &ActionTemplate{
+		Name: "qbit movies",
+		Action: Action{
+			Type:          ActionTypeQbittorrent,
+			Category:      "movies",
+			SavePath:      "/downloads/movies",
+			Paused:        true,
+			AutoTMM:       true,
+			MoveCompleted: true,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		action Action
+		want   Action
+	}{
+		{
+			name:   "inherit",
+			action: Action{Type: ActionTypeQbittorrent},
+			want: Action{
+				Type:          ActionTypeQbittorrent,
+				Category:      "movies",
+				SavePath:      "/downloads/movies",
+				Paused:        true,
+				AutoTMM:       true,
+				MoveCompleted: true,
+			},
+		},
+		{
+			name:   "no_type",
+			action: Action{},
+			want: Action{
+				Type:          ActionTypeQbittorrent,
+				Category:      "movies",
+				SavePath:      "/downloads/movies",
+				Paused:        true,
+				AutoTMM:       true,
+				MoveCompleted: true,
+			},
+		},
+		{
+			name:   "keep_type",
+			action: Action{Type: ActionTypeDelugeV2},
+			want: Action{
+				Type:          ActionTypeDelugeV2,
+				Category:      "movies",
+				SavePath:      "/downloads/movies",
+				Paused:        true,
+				AutoTMM:       true,
+				MoveCompleted: true,
+			},
+		},
+		{
+			name: "override_bools_off",
+			action: Action{
+				Type:              ActionTypeQbittorrent,
+				SavePath:          "/downloads/override",
+				TemplateOverrides: []string{"paused", "auto_tmm", "move_completed"},
+			},
+			want: Action{
+				Type:              ActionTypeQbittorrent,
+				Category:          "movies",
+				SavePath:          "/downloads/override",
+				TemplateOverrides: []string{"paused", "auto_tmm", "move_completed"},
+			},
+		},
+		{
+			name: "bools_not_overridden",
+			action: Action{
+				Type:          ActionTypeQbittorrent,
+				SkipHashCheck: true,
+			},
+			want: Action{
+				Type:          ActionTypeQbittorrent,
+				Category:      "movies",
+				SavePath:      "/downloads/movies",
+				Paused:        true,
+				AutoTMM:       true,
+				MoveCompleted: true,
+			},
+		},
+		{
+			name: "override_bool_on",
+			action: Action{
+				Type:              ActionTypeQbittorrent,
+				SkipHashCheck:     true,
+				TemplateOverrides: []string{"skip_hash_check"},
+			},
+			want: Action{
+				Type:              ActionTypeQbittorrent,
+				Category:          "movies",
+				SavePath:          "/downloads/movies",
+				Paused:            true,
+				AutoTMM:           true,
+				MoveCompleted:     true,
+				SkipHashCheck:     true,
+				TemplateOverrides: []string{"skip_hash_check"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := tt.action
+			action.ApplyTemplate(template)
+			assert.Equal(t, tt.want, action)
+		})
+	}
+}
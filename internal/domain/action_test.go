@@ -0,0 +1,441 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAction_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  Action
+		wantErr bool
+	}{
+		{
+			name:   "grpc_valid",
+			action: Action{Name: "grpc", Type: ActionTypeGRPC, WebhookHost: "localhost:50051", GrpcMethod: "autobrr.Releases/Push"},
+		},
+		{
+			name:    "grpc_missing_target",
+			action:  Action{Name: "grpc", Type: ActionTypeGRPC, GrpcMethod: "autobrr.Releases/Push"},
+			wantErr: true,
+		},
+		{
+			name:    "grpc_target_without_port",
+			action:  Action{Name: "grpc", Type: ActionTypeGRPC, WebhookHost: "grpc.domain.ltd", GrpcMethod: "autobrr.Releases/Push"},
+			wantErr: true,
+		},
+		{
+			name:    "grpc_invalid_method",
+			action:  Action{Name: "grpc", Type: ActionTypeGRPC, WebhookHost: "localhost:50051", GrpcMethod: "Push"},
+			wantErr: true,
+		},
+		{
+			name:   "qbit_auto_tmm",
+			action: Action{Name: "qbit", Type: ActionTypeQbittorrent, AutoTMM: true, Category: "tv"},
+		},
+		{
+			name:    "qbit_auto_tmm_with_save_path",
+			action:  Action{Name: "qbit", Type: ActionTypeQbittorrent, AutoTMM: true, Category: "tv", SavePath: "/data/tv"},
+			wantErr: true,
+		},
+		{
+			name:   "min_free_space_valid",
+			action: Action{Name: "qbit", Type: ActionTypeQbittorrent, MinFreeSpace: "50 GiB"},
+		},
+		{
+			name:    "min_free_space_invalid",
+			action:  Action{Name: "qbit", Type: ActionTypeQbittorrent, MinFreeSpace: "lots"},
+			wantErr: true,
+		},
+		{
+			name:   "resume_delay_paused",
+			action: Action{Name: "qbit", Type: ActionTypeQbittorrent, Paused: true, ResumeDelay: 30},
+		},
+		{
+			name:    "resume_delay_not_paused",
+			action:  Action{Name: "qbit", Type: ActionTypeQbittorrent, ResumeDelay: 30},
+			wantErr: true,
+		},
+		{
+			name:   "transmission_bandwidth_priority",
+			action: Action{Name: "transmission", Type: ActionTypeTransmission, BandwidthPriority: ActionBandwidthPriorityHigh, PeerLimit: 50},
+		},
+		{
+			name:    "transmission_invalid_bandwidth_priority",
+			action:  Action{Name: "transmission", Type: ActionTypeTransmission, BandwidthPriority: "HIGHEST"},
+			wantErr: true,
+		},
+		{
+			name:    "transmission_negative_peer_limit",
+			action:  Action{Name: "transmission", Type: ActionTypeTransmission, PeerLimit: -1},
+			wantErr: true,
+		},
+		{
+			name:   "qbittorrent_stop_condition",
+			action: Action{Name: "qbit", Type: ActionTypeQbittorrent, StopCondition: ActionStopConditionMetadataReceived},
+		},
+		{
+			name:    "qbittorrent_invalid_stop_condition",
+			action:  Action{Name: "qbit", Type: ActionTypeQbittorrent, StopCondition: "metadata"},
+			wantErr: true,
+		},
+		{
+			name:   "deluge_move_completed",
+			action: Action{Name: "deluge", Type: ActionTypeDelugeV2, MoveCompleted: true, MoveCompletedPath: "/mnt/bulk/{{ .Indexer }}"},
+		},
+		{
+			name:    "deluge_move_completed_missing_path",
+			action:  Action{Name: "deluge", Type: ActionTypeDelugeV1, MoveCompleted: true, MoveCompletedPath: " "},
+			wantErr: true,
+		},
+		{
+			name:   "cleanup_valid",
+			action: Action{Name: "cleanup", Type: ActionTypeCleanup, Category: "tv", CleanupMinRatio: 2},
+		},
+		{
+			name:    "cleanup_no_criteria",
+			action:  Action{Name: "cleanup", Type: ActionTypeCleanup, CleanupDeleteData: true},
+			wantErr: true,
+		},
+		{
+			name:    "cleanup_negative_min_age",
+			action:  Action{Name: "cleanup", Type: ActionTypeCleanup, Category: "tv", CleanupMinAge: -10},
+			wantErr: true,
+		},
+		{
+			name:   "archive_torrent_valid",
+			action: Action{Name: "archive", Type: ActionTypeArchiveTorrent, ArchivePath: "/archive/{{ .Indexer }}", ArchiveMode: ActionArchiveModeHardlink},
+		},
+		{
+			name:    "archive_torrent_missing_path",
+			action:  Action{Name: "archive", Type: ActionTypeArchiveTorrent},
+			wantErr: true,
+		},
+		{
+			name:    "archive_torrent_invalid_mode",
+			action:  Action{Name: "archive", Type: ActionTypeArchiveTorrent, ArchivePath: "/archive", ArchiveMode: "MOVE"},
+			wantErr: true,
+		},
+		{
+			name:   "sabnzbd_priority_name",
+			action: Action{Name: "sab", Type: ActionTypeSabnzbd, Priority: "High"},
+		},
+		{
+			name:   "sabnzbd_priority_value",
+			action: Action{Name: "sab", Type: ActionTypeSabnzbd, Priority: "-100"},
+		},
+		{
+			name:   "sabnzbd_priority_macro",
+			action: Action{Name: "sab", Type: ActionTypeSabnzbd, Priority: `{{ if .Freeleech }}force{{ else }}normal{{ end }}`},
+		},
+		{
+			name:    "sabnzbd_invalid_priority",
+			action:  Action{Name: "sab", Type: ActionTypeSabnzbd, Priority: "urgent"},
+			wantErr: true,
+		},
+		{
+			name:   "webhook_not_validated",
+			action: Action{Name: "webhook", Type: ActionTypeWebhook},
+		},
+		{
+			name:   "webhook_success_when",
+			action: Action{Name: "webhook", Type: ActionTypeWebhook, WebhookSuccessWhen: "status=accepted"},
+		},
+		{
+			name:    "webhook_invalid_success_when",
+			action:  Action{Name: "webhook", Type: ActionTypeWebhook, WebhookSuccessWhen: "status"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.action.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWebhookSuccessRule_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		body    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "equal", rule: "status=accepted", body: `{"status":"accepted"}`, want: true},
+		{name: "not_equal", rule: "status=accepted", body: `{"status":"rejected"}`, want: false},
+		{name: "negate", rule: "status!=rejected", body: `{"status":"accepted"}`, want: true},
+		{name: "negate_missing", rule: "status!=rejected", body: `{}`, want: true},
+		{name: "jsonpath_nested", rule: "$.result.ok = true", body: `{"result":{"ok":true}}`, want: true},
+		{name: "array_index", rule: "items.1.id=2", body: `{"items":[{"id":1},{"id":2}]}`, want: true},
+		{name: "array_out_of_range", rule: "items.5.id=2", body: `{"items":[{"id":1}]}`, want: false},
+		{name: "quoted_value", rule: `status="ok"`, body: `{"status":"ok"}`, want: true},
+		{name: "missing", rule: "status=accepted", body: `{"result":"accepted"}`, want: false},
+		{name: "not_json", rule: "status=accepted", body: `accepted`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseWebhookSuccessRule(tt.rule)
+			assert.NoError(t, err)
+
+			got, err := rule.Match([]byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveSavePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientPath string
+		savePath   string
+		want       string
+	}{
+		{name: "empty", clientPath: "", savePath: "", want: ""},
+		{name: "client_only", clientPath: "/downloads", savePath: "", want: "/downloads"},
+		{name: "action_only", clientPath: "", savePath: "movies", want: "movies"},
+		{name: "relative", clientPath: "/downloads/", savePath: "movies", want: "/downloads/movies"},
+		{name: "absolute", clientPath: "/downloads", savePath: "/mnt/movies", want: "/mnt/movies"},
+		{name: "windows_relative", clientPath: `D:\downloads`, savePath: "movies", want: `D:\downloads\movies`},
+		{name: "windows_absolute", clientPath: `D:\downloads`, savePath: `E:\movies`, want: `E:\movies`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ResolveSavePath(tt.clientPath, tt.savePath))
+		})
+	}
+}
+
+func TestAction_ParseMacros_savePath(t *testing.T) {
+	release := &Release{TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", Category: "movies", TorrentTmpFile: "/tmp/file"}
+
+	t.Run("attached_client", func(t *testing.T) {
+		action := &Action{
+			Type:     ActionTypeQbittorrent,
+			SavePath: "{{ .Category }}",
+			ExecArgs: "{{ .ClientSavePath }} {{ .SavePath }}",
+			Client:   &DownloadClient{Type: DownloadClientTypeQbittorrent, Settings: DownloadClientSettings{SavePath: "/downloads"}},
+		}
+
+		assert.NoError(t, action.ParseMacros(release))
+		assert.Equal(t, "movies", action.SavePath)
+		assert.Equal(t, []string{"/downloads", "/downloads/movies"}, action.ExecArgv)
+	})
+
+	t.Run("detached_client", func(t *testing.T) {
+		action := &Action{
+			Type:     ActionTypeExec,
+			ExecArgs: `"{{ .ClientSavePath }}" "{{ .SavePath }}"`,
+		}
+
+		assert.NoError(t, action.ParseMacros(release))
+		assert.Equal(t, []string{"", ""}, action.ExecArgv)
+	})
+}
+
+func TestAction_ParseMacros_clientName(t *testing.T) {
+	release := &Release{TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", TorrentTmpFile: "/tmp/file"}
+
+	t.Run("attached_client", func(t *testing.T) {
+		action := &Action{
+			Type:        ActionTypeWebhook,
+			WebhookData: `{"client":"{{ .Client.Name }}"}`,
+			WatchFolder: "/watch/{{ .Client.Name }}",
+			Client:      &DownloadClient{Name: "seedbox/1", Type: DownloadClientTypeQbittorrent},
+		}
+
+		assert.NoError(t, action.ParseMacros(release))
+		assert.Equal(t, `{"client":"seedbox/1"}`, action.WebhookData)
+		assert.Equal(t, "/watch/seedbox_1", action.WatchFolder)
+	})
+
+	t.Run("detached_client", func(t *testing.T) {
+		action := &Action{
+			Type:        ActionTypeWebhook,
+			WebhookData: `{"client":"{{ .Client.Name }}"}`,
+		}
+
+		assert.NoError(t, action.ParseMacros(release))
+		assert.Equal(t, `{"client":""}`, action.WebhookData)
+	})
+}
+
+func TestAction_ParseMacros_webhookURL(t *testing.T) {
+	release := &Release{TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", Indexer: "mock indexer", TorrentTmpFile: "/tmp/file"}
+
+	t.Run("expanded", func(t *testing.T) {
+		action := &Action{Type: ActionTypeWebhook, WebhookHost: "http://localhost:3000/{{ .Indexer }}?name={{ .TorrentName }}"}
+
+		assert.NoError(t, action.ParseMacros(release))
+		assert.Equal(t, "http://localhost:3000/mock%20indexer?name=That.Movie.2023.1080p.BluRay.x264-GROUP", action.WebhookHost)
+	})
+
+	t.Run("template_spanning_actions", func(t *testing.T) {
+		action := &Action{Type: ActionTypeWebhook, WebhookHost: "http://localhost:3000/{{ if .Indexer }}{{ .Indexer }}{{ else }}other{{ end }}"}
+
+		assert.NoError(t, action.ParseMacros(release))
+		assert.Equal(t, "http://localhost:3000/mock%20indexer", action.WebhookHost)
+	})
+
+	t.Run("invalid_template", func(t *testing.T) {
+		action := &Action{Type: ActionTypeWebhook, WebhookHost: "http://localhost:3000/{{ if .Indexer }}{{ .Indexer }}"}
+
+		assert.ErrorContains(t, action.ParseMacros(release), "could not parse macros in webhook url")
+	})
+
+	t.Run("invalid_after_expansion", func(t *testing.T) {
+		action := &Action{Type: ActionTypeWebhook, WebhookHost: "{{ .Indexer }}"}
+
+		assert.ErrorContains(t, action.ParseMacros(release), "invalid webhook url")
+	})
+}
+
+func TestAction_ParseMacros_infoHash(t *testing.T) {
+	release := &Release{
+		TorrentName:    "archlinux-2011.08.19-netinstall-i686.iso",
+		TorrentTmpFile: "testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent",
+	}
+	action := &Action{
+		ExecArgs: `--hash {{ .InfoHash }}`,
+	}
+
+	err := action.ParseMacros(release)
+	assert.NoError(t, err)
+	assert.Equal(t, "--hash 500f29c0c537f5e41c6af676b7633de9d080d237", action.ExecArgs)
+	assert.Equal(t, "500f29c0c537f5e41c6af676b7633de9d080d237", release.TorrentHash)
+}
+
+func TestAction_ParseMacros_infoHashFields(t *testing.T) {
+	const hash = "500f29c0c537f5e41c6af676b7633de9d080d237"
+
+	tests := []struct {
+		name   string
+		action Action
+		field  func(a *Action) string
+		want   string
+	}{
+		{
+			name:   "webhook_host",
+			action: Action{Type: ActionTypeWebhook, WebhookHost: "http://localhost:8080/{{ .InfoHash }}"},
+			field:  func(a *Action) string { return a.WebhookHost },
+			want:   "http://localhost:8080/" + hash,
+		},
+		{
+			name:   "comment",
+			action: Action{Comment: "hash {{ .InfoHash }}"},
+			field:  func(a *Action) string { return a.Comment },
+			want:   "hash " + hash,
+		},
+		{
+			name:   "archive_filename",
+			action: Action{ArchiveFilename: "{{ .InfoHash }}.torrent"},
+			field:  func(a *Action) string { return a.ArchiveFilename },
+			want:   hash + ".torrent",
+		},
+		{
+			name:   "move_completed_path",
+			action: Action{MoveCompletedPath: "/done/{{ .InfoHash }}"},
+			field:  func(a *Action) string { return a.MoveCompletedPath },
+			want:   "/done/" + hash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &Release{
+				TorrentName:    "archlinux-2011.08.19-netinstall-i686.iso",
+				TorrentTmpFile: "testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent",
+			}
+
+			err := tt.action.ParseMacros(release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.field(&tt.action))
+			assert.Equal(t, hash, release.TorrentHash)
+		})
+	}
+}
+
+func TestAction_ParseMacros_missingTmpFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := os.ReadFile("testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent")
+		w.Header().Set("Content-Type", "application/x-bittorrent")
+		w.Write(payload)
+	}))
+	defer ts.Close()
+
+	missing := filepath.Join(t.TempDir(), "autobrr-removed")
+
+	t.Run("redownload", func(t *testing.T) {
+		release := &Release{
+			TorrentName:    "archlinux-2011.08.19-netinstall-i686.iso",
+			Protocol:       ReleaseProtocolTorrent,
+			DownloadURL:    ts.URL + "/file.torrent",
+			TorrentTmpFile: missing,
+		}
+		action := &Action{
+			ExecArgs: `{{ .TorrentPathName }}`,
+			Type:     ActionTypeWatchFolder,
+		}
+
+		err := action.ParseMacros(release)
+		assert.NoError(t, err)
+		defer os.Remove(release.TorrentTmpFile)
+
+		assert.NotEqual(t, missing, release.TorrentTmpFile)
+		assert.Equal(t, release.TorrentTmpFile, action.ExecArgs)
+		assert.NotEmpty(t, release.TorrentDataRawBytes)
+	})
+
+	t.Run("download_fails", func(t *testing.T) {
+		release := &Release{
+			TorrentName:    "archlinux-2011.08.19-netinstall-i686.iso",
+			Protocol:       ReleaseProtocolTorrent,
+			TorrentTmpFile: missing,
+		}
+		action := &Action{
+			Type: ActionTypeWatchFolder,
+		}
+
+		err := action.ParseMacros(release)
+		assert.ErrorContains(t, err, missing)
+	})
+}
+
+func TestSplitTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags string
+		want []string
+	}{
+		{name: "empty", tags: "", want: []string{}},
+		{name: "single", tags: "tv", want: []string{"tv"}},
+		{name: "empty_and_duplicate", tags: "4k,,hdr, hdr", want: []string{"4k", "hdr"}},
+		{name: "newlines", tags: "tv\nhd\r\ntv,", want: []string{"tv", "hd"}},
+		{name: "only_separators", tags: " , ,\n", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SplitTags(tt.tags))
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/autobrr/autobrr/pkg/errors"
 
@@ -45,6 +47,73 @@ type DownloadClientSettings struct {
 	Basic                    BasicAuth           `json:"basic,omitempty"`
 	Rules                    DownloadClientRules `json:"rules,omitempty"`
 	ExternalDownloadClientId int                 `json:"external_download_client_id,omitempty"`
+	LimitRatio               float64             `json:"limit_ratio,omitempty"`
+	LimitSeedTime            int64               `json:"limit_seed_time,omitempty"`
+	Proxy                    string              `json:"proxy,omitempty"`
+	SavePath                 string              `json:"save_path,omitempty"`
+	RequirePreset            bool                `json:"require_preset,omitempty"`
+	TLSCAFile                string              `json:"tls_ca_file,omitempty"`
+	TLSCertFile              string              `json:"tls_cert_file,omitempty"`
+	TLSKeyFile               string              `json:"tls_key_file,omitempty"`
+	UserAgent                string              `json:"user_agent,omitempty"`
+	ExtraHeaders             ExtraHeaders        `json:"extra_headers,omitempty"`
+}
+
+// ExtraHeaders are sent with every request to the client, eg. the token of an auth proxy
+type ExtraHeaders map[string]string
+
+// ExtraHeaderRedacted replaces the header values in api responses. A value sent back unchanged keeps the stored one.
+const ExtraHeaderRedacted = "<redacted>"
+
+// Redacted returns a copy with the values replaced by ExtraHeaderRedacted
+func (h ExtraHeaders) Redacted() ExtraHeaders {
+	if h == nil {
+		return nil
+	}
+
+	redacted := make(ExtraHeaders, len(h))
+	for name := range h {
+		redacted[name] = ExtraHeaderRedacted
+	}
+
+	return redacted
+}
+
+// Restore sets the values that are still redacted to the stored values
+func (h ExtraHeaders) Restore(stored ExtraHeaders) {
+	for name, value := range h {
+		if value == ExtraHeaderRedacted {
+			h[name] = stored[name]
+		}
+	}
+}
+
+// IsRedacted reports if any value is redacted
+func (h ExtraHeaders) IsRedacted() bool {
+	for _, value := range h {
+		if value == ExtraHeaderRedacted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String lists the header names with the values redacted, so logging the client settings doesn't leak them
+func (h ExtraHeaders) String() string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+ExtraHeaderRedacted)
+	}
+
+	return "map[" + strings.Join(parts, " ") + "]"
 }
 
 type DownloadClientRules struct {
@@ -95,9 +164,51 @@ func (c DownloadClient) Validate() error {
 		return errors.New("validation error: missing type")
 	}
 
+	if c.Settings.Proxy != "" {
+		if c.isDeluge() {
+			return errors.New("validation error: proxy is not supported for %s", c.Type)
+		}
+
+		if u, err := url.Parse(c.Settings.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("validation error: invalid proxy url")
+		}
+	}
+
+	if c.Settings.UserAgent != "" && c.isDeluge() {
+		return errors.New("validation error: user agent is not supported for %s", c.Type)
+	}
+
+	if c.Settings.TLSCAFile != "" || c.Settings.TLSCertFile != "" || c.Settings.TLSKeyFile != "" {
+		if c.isDeluge() {
+			return errors.New("validation error: custom tls certificates are not supported for %s", c.Type)
+		}
+
+		if (c.Settings.TLSCertFile == "") != (c.Settings.TLSKeyFile == "") {
+			return errors.New("validation error: tls client cert and key must be set together")
+		}
+	}
+
+	if len(c.Settings.ExtraHeaders) > 0 {
+		if c.isDeluge() {
+			return errors.New("validation error: extra headers are not supported for %s", c.Type)
+		}
+
+		for name, value := range c.Settings.ExtraHeaders {
+			if name == "" || strings.ContainsAny(name, " :\r\n") || strings.ContainsAny(value, "\r\n") {
+				return errors.New("validation error: invalid extra header: %q", name)
+			}
+		}
+	}
+
 	return nil
 }
 
+// isDeluge reports if the client is Deluge. It's reached over the Deluge daemon rpc and not http,
+// so the client transport with its proxy, tls certificates, user agent and headers doesn't apply.
+func (c DownloadClient) isDeluge() bool {
+	return c.Type == DownloadClientTypeDelugeV1 || c.Type == DownloadClientTypeDelugeV2
+}
+
 func (c DownloadClient) BuildLegacyHost() string {
 	if c.Type == DownloadClientTypeQbittorrent {
 		return c.qbitBuildLegacyHost()
@@ -3,27 +3,46 @@
 
 package domain
 
+import (
+	"fmt"
+	"runtime"
+)
+
 type Config struct {
-	Version             string
-	ConfigPath          string
-	Host                string `toml:"host"`
-	Port                int    `toml:"port"`
-	LogLevel            string `toml:"logLevel"`
-	LogPath             string `toml:"logPath"`
-	LogMaxSize          int    `toml:"logMaxSize"`
-	LogMaxBackups       int    `toml:"logMaxBackups"`
-	BaseURL             string `toml:"baseUrl"`
-	SessionSecret       string `toml:"sessionSecret"`
-	CustomDefinitions   string `toml:"customDefinitions"`
-	CheckForUpdates     bool   `toml:"checkForUpdates"`
-	DatabaseType        string `toml:"databaseType"`
-	PostgresHost        string `toml:"postgresHost"`
-	PostgresPort        int    `toml:"postgresPort"`
-	PostgresDatabase    string `toml:"postgresDatabase"`
-	PostgresUser        string `toml:"postgresUser"`
-	PostgresPass        string `toml:"postgresPass"`
-	PostgresSSLMode     string `toml:"postgresSSLMode"`
-	PostgresExtraParams string `toml:"postgresExtraParams"`
+	Version              string
+	ConfigPath           string
+	Host                 string         `toml:"host"`
+	Port                 int            `toml:"port"`
+	LogLevel             string         `toml:"logLevel"`
+	LogPath              string         `toml:"logPath"`
+	LogMaxSize           int            `toml:"logMaxSize"`
+	LogMaxBackups        int            `toml:"logMaxBackups"`
+	BaseURL              string         `toml:"baseUrl"`
+	SessionSecret        string         `toml:"sessionSecret"`
+	CustomDefinitions    string         `toml:"customDefinitions"`
+	CheckForUpdates      bool           `toml:"checkForUpdates"`
+	NotificationDispatch string         `toml:"notificationDispatch"`
+	HealthCheckTTL       int            `toml:"healthCheckTTL"`
+	FeedJitter           int            `toml:"feedJitter"`
+	ExecConcurrency      int            `toml:"execConcurrency"`
+	DownloadRetries      int            `toml:"downloadRetries"`
+	DownloadRetryDelay   int            `toml:"downloadRetryDelay"`
+	IndexerConcurrency   int            `toml:"indexerConcurrency"`
+	IndexerRateLimit     int            `toml:"indexerRateLimit"`
+	GroupTiers           map[string]int `toml:"groupTiers"`
+	GroupTierDefault     int            `toml:"groupTierDefault"`
+	ExecAllowedDirs      []string       `toml:"execAllowedDirs"`
+	WebhookAllowedHosts  []string       `toml:"webhookAllowedHosts"`
+	WebhookDeniedHosts   []string       `toml:"webhookDeniedHosts"`
+	WebhookBlockPrivate  bool           `toml:"webhookBlockPrivate"`
+	DatabaseType         string         `toml:"databaseType"`
+	PostgresHost         string         `toml:"postgresHost"`
+	PostgresPort         int            `toml:"postgresPort"`
+	PostgresDatabase     string         `toml:"postgresDatabase"`
+	PostgresUser         string         `toml:"postgresUser"`
+	PostgresPass         string         `toml:"postgresPass"`
+	PostgresSSLMode      string         `toml:"postgresSSLMode"`
+	PostgresExtraParams  string         `toml:"postgresExtraParams"`
 }
 
 type ConfigUpdate struct {
@@ -34,3 +53,12 @@ type ConfigUpdate struct {
 	BaseURL         *string `json:"base_url,omitempty"`
 	CheckForUpdates *bool   `json:"check_for_updates,omitempty"`
 }
+
+// UserAgent returns the custom user agent, or the default autobrr one with the version if it's empty
+func UserAgent(custom string, version string) string {
+	if custom != "" {
+		return custom
+	}
+
+	return fmt.Sprintf("autobrr/%s (%s %s)", version, runtime.GOOS, runtime.GOARCH)
+}
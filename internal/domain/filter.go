@@ -60,6 +60,17 @@ const (
 	FilterMaxDownloadsEver  FilterMaxDownloadsUnit = "EVER"
 )
 
+type FilterDedupKey string
+
+const (
+	// FilterDedupKeyNameSize matches releases by normalized name and size
+	FilterDedupKeyNameSize FilterDedupKey = "NAME_SIZE"
+	// FilterDedupKeyName matches releases by normalized name only
+	FilterDedupKeyName FilterDedupKey = "NAME"
+	// FilterDedupKeyInfoHash matches releases by infohash, falling back to name and size when it's not available
+	FilterDedupKeyInfoHash FilterDedupKey = "INFOHASH"
+)
+
 type FilterQueryParams struct {
 	Sort    map[string]string
 	Filters struct {
@@ -101,6 +112,8 @@ type Filter struct {
 	Containers           []string               `json:"containers,omitempty"`
 	MatchHDR             []string               `json:"match_hdr,omitempty"`
 	ExceptHDR            []string               `json:"except_hdr,omitempty"`
+	MatchAudio           []string               `json:"match_audio,omitempty"`
+	ExceptAudio          []string               `json:"except_audio,omitempty"`
 	MatchOther           []string               `json:"match_other,omitempty"`
 	ExceptOther          []string               `json:"except_other,omitempty"`
 	Years                string                 `json:"years,omitempty"`
@@ -121,6 +134,8 @@ type Filter struct {
 	ExceptUploaders      string                 `json:"except_uploaders,omitempty"`
 	MatchLanguage        []string               `json:"match_language,omitempty"`
 	ExceptLanguage       []string               `json:"except_language,omitempty"`
+	MatchSubtitles       []string               `json:"match_subtitles,omitempty"`
+	ExceptSubtitles      []string               `json:"except_subtitles,omitempty"`
 	Tags                 string                 `json:"tags,omitempty"`
 	ExceptTags           string                 `json:"except_tags,omitempty"`
 	TagsAny              string                 `json:"tags_any,omitempty"`
@@ -133,6 +148,18 @@ type Filter struct {
 	MatchDescription     string                 `json:"match_description,omitempty"`
 	ExceptDescription    string                 `json:"except_description,omitempty"`
 	UseRegexDescription  bool                   `json:"use_regex_description,omitempty"`
+	MinTrackers          int                    `json:"min_trackers,omitempty"`
+	MaxTrackers          int                    `json:"max_trackers,omitempty"`
+	DedupWindow          int                    `json:"dedup_window,omitempty"`
+	DedupKey             FilterDedupKey         `json:"dedup_key,omitempty"`
+	StopOnMatch          bool                   `json:"stop_on_match"`
+	MaxActiveDownloads   int                    `json:"max_active_downloads,omitempty"`
+	MinSeeders           int                    `json:"min_seeders,omitempty"`
+	MaxSeeders           int                    `json:"max_seeders,omitempty"`
+	RejectUnknownSeeders bool                   `json:"reject_unknown_seeders,omitempty"`
+	RequireDownload      bool                   `json:"require_download,omitempty"`
+	RequireApproval      bool                   `json:"require_approval,omitempty"`
+	MinGroupTier         int                    `json:"min_group_tier,omitempty"`
 	ActionsCount         int                    `json:"actions_count"`
 	ActionsEnabledCount  int                    `json:"actions_enabled_count"`
 	Actions              []*Action              `json:"actions,omitempty"`
@@ -159,6 +186,8 @@ type FilterExternal struct {
 	WebhookRetryStatus       string             `json:"webhook_retry_status,omitempty"`
 	WebhookRetryAttempts     int                `json:"webhook_retry_attempts,omitempty"`
 	WebhookRetryDelaySeconds int                `json:"webhook_retry_delay_seconds,omitempty"`
+	WebhookTimeout           int                `json:"webhook_timeout,omitempty"`
+	WebhookFailOpen          bool               `json:"webhook_fail_open,omitempty"`
 	FilterId                 int                `json:"-"`
 }
 
@@ -167,6 +196,9 @@ type FilterExternalType string
 const (
 	ExternalFilterTypeExec    FilterExternalType = "EXEC"
 	ExternalFilterTypeWebhook FilterExternalType = "WEBHOOK"
+
+	// ExternalFilterTypeWebhookApproval posts the release to a policy service that answers if it's approved
+	ExternalFilterTypeWebhookApproval FilterExternalType = "WEBHOOK_APPROVAL"
 )
 
 type FilterUpdate struct {
@@ -206,6 +238,8 @@ type FilterUpdate struct {
 	Containers                       *[]string               `json:"containers,omitempty"`
 	MatchHDR                         *[]string               `json:"match_hdr,omitempty"`
 	ExceptHDR                        *[]string               `json:"except_hdr,omitempty"`
+	MatchAudio                       *[]string               `json:"match_audio,omitempty"`
+	ExceptAudio                      *[]string               `json:"except_audio,omitempty"`
 	MatchOther                       *[]string               `json:"match_other,omitempty"`
 	ExceptOther                      *[]string               `json:"except_other,omitempty"`
 	Years                            *string                 `json:"years,omitempty"`
@@ -226,12 +260,26 @@ type FilterUpdate struct {
 	ExceptUploaders                  *string                 `json:"except_uploaders,omitempty"`
 	MatchLanguage                    *[]string               `json:"match_language,omitempty"`
 	ExceptLanguage                   *[]string               `json:"except_language,omitempty"`
+	MatchSubtitles                   *[]string               `json:"match_subtitles,omitempty"`
+	ExceptSubtitles                  *[]string               `json:"except_subtitles,omitempty"`
 	Tags                             *string                 `json:"tags,omitempty"`
 	ExceptTags                       *string                 `json:"except_tags,omitempty"`
 	TagsAny                          *string                 `json:"tags_any,omitempty"`
 	ExceptTagsAny                    *string                 `json:"except_tags_any,omitempty"`
 	TagsMatchLogic                   *string                 `json:"tags_match_logic,omitempty"`
 	ExceptTagsMatchLogic             *string                 `json:"except_tags_match_logic,omitempty"`
+	MinTrackers                      *int                    `json:"min_trackers,omitempty"`
+	MaxTrackers                      *int                    `json:"max_trackers,omitempty"`
+	DedupWindow                      *int                    `json:"dedup_window,omitempty"`
+	DedupKey                         *FilterDedupKey         `json:"dedup_key,omitempty"`
+	StopOnMatch                      *bool                   `json:"stop_on_match,omitempty"`
+	MaxActiveDownloads               *int                    `json:"max_active_downloads,omitempty"`
+	MinSeeders                       *int                    `json:"min_seeders,omitempty"`
+	MaxSeeders                       *int                    `json:"max_seeders,omitempty"`
+	RejectUnknownSeeders             *bool                   `json:"reject_unknown_seeders,omitempty"`
+	RequireDownload                  *bool                   `json:"require_download,omitempty"`
+	RequireApproval                  *bool                   `json:"require_approval,omitempty"`
+	MinGroupTier                     *int                    `json:"min_group_tier,omitempty"`
 	ExternalScriptEnabled            *bool                   `json:"external_script_enabled,omitempty"`
 	ExternalScriptCmd                *string                 `json:"external_script_cmd,omitempty"`
 	ExternalScriptArgs               *string                 `json:"external_script_args,omitempty"`
@@ -253,7 +301,36 @@ func (f *Filter) Validate() error {
 		return errors.New("validation: name can't be empty")
 	}
 
-	if _, _, err := f.parsedSizeLimits(); err != nil {
+	minBytes, maxBytes, err := f.parsedSizeLimits()
+	if err != nil {
+		return fmt.Errorf("error validating filter size limits: %w", err)
+	}
+
+	if minBytes != nil && maxBytes != nil && *minBytes >= *maxBytes {
+		return fmt.Errorf("error validating filter size limits: min size %s must be smaller than max size %s", f.MinSize, f.MaxSize)
+	}
+
+	for _, action := range f.Actions {
+		if err := action.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the size limits of a partial update, the other limit might not be part of the update
+// so they are not compared.
+func (f *FilterUpdate) Validate() error {
+	limits := Filter{}
+	if f.MinSize != nil {
+		limits.MinSize = *f.MinSize
+	}
+	if f.MaxSize != nil {
+		limits.MaxSize = *f.MaxSize
+	}
+
+	if _, _, err := limits.parsedSizeLimits(); err != nil {
 		return fmt.Errorf("error validating filter size limits: %w", err)
 	}
 
@@ -262,7 +339,7 @@ func (f *Filter) Validate() error {
 
 func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 	// max downloads check. If reached return early
-	if f.MaxDownloads > 0 && !f.checkMaxDownloads(f.MaxDownloads, f.MaxDownloadsUnit) {
+	if f.MaxDownloadsReached() {
 		f.addRejectionF("max downloads (%d) this (%v) reached", f.MaxDownloads, f.MaxDownloadsUnit)
 		return f.Rejections, false
 	}
@@ -279,6 +356,14 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 		f.addRejectionF("freeleech percent not matching. got: %v want: %v", r.FreeleechPercent, f.FreeleechPercent)
 	}
 
+	if f.Scene && !r.IsScene {
+		f.addRejection("wanted: scene")
+	}
+
+	if f.MinSeeders > 0 || f.MaxSeeders > 0 {
+		f.checkSeeders(r)
+	}
+
 	if len(f.Origins) > 0 && !containsSlice(r.Origin, f.Origins) {
 		f.addRejectionF("origin not matching. got: %v want: %v", r.Origin, f.Origins)
 	}
@@ -328,6 +413,12 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 		f.addRejectionF("unwanted release group. got: %v unwanted: %v", r.Group, f.ExceptReleaseGroups)
 	}
 
+	if f.MinGroupTier > 0 {
+		if tier := r.GroupTier(); tier < f.MinGroupTier {
+			f.addRejectionF("release group tier too low. got: %d (%v) want: >=%d", tier, r.Group, f.MinGroupTier)
+		}
+	}
+
 	// check raw releaseTags string
 	if f.UseRegexReleaseTags {
 		if f.MatchReleaseTags != "" && !matchRegex(r.ReleaseTags, f.MatchReleaseTags) {
@@ -364,6 +455,14 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 		f.addRejectionF("language unwanted. got: %v want: %v", r.Language, f.ExceptLanguage)
 	}
 
+	if len(f.MatchSubtitles) > 0 && !sliceContainsSlice(r.Subtitles, f.MatchSubtitles) {
+		f.addRejectionF("subtitles not matching. got: %v want: %v", r.Subtitles, f.MatchSubtitles)
+	}
+
+	if len(f.ExceptSubtitles) > 0 && sliceContainsSlice(r.Subtitles, f.ExceptSubtitles) {
+		f.addRejectionF("subtitles unwanted. got: %v want: %v", r.Subtitles, f.ExceptSubtitles)
+	}
+
 	if len(f.Resolutions) > 0 && !containsSlice(r.Resolution, f.Resolutions) {
 		f.addRejectionF("resolution not matching. got: %v want: %v", r.Resolution, f.Resolutions)
 	}
@@ -381,15 +480,23 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 	}
 
 	// HDR is parsed into the Codec slice from rls
-	if len(f.MatchHDR) > 0 && !matchHDR(r.HDR, f.MatchHDR) {
+	if len(f.MatchHDR) > 0 && !matchCombinedTags(r.HDR, f.MatchHDR) {
 		f.addRejectionF("hdr not matching. got: %v want: %v", r.HDR, f.MatchHDR)
 	}
 
 	// HDR is parsed into the Codec slice from rls
-	if len(f.ExceptHDR) > 0 && matchHDR(r.HDR, f.ExceptHDR) {
+	if len(f.ExceptHDR) > 0 && matchCombinedTags(r.HDR, f.ExceptHDR) {
 		f.addRejectionF("hdr unwanted. got: %v want: %v", r.HDR, f.ExceptHDR)
 	}
 
+	if len(f.MatchAudio) > 0 && !matchCombinedTags(r.Audio, f.MatchAudio) {
+		f.addRejectionF("audio not matching. got: %v want: %v", r.Audio, f.MatchAudio)
+	}
+
+	if len(f.ExceptAudio) > 0 && matchCombinedTags(r.Audio, f.ExceptAudio) {
+		f.addRejectionF("audio unwanted. got: %v want: %v", r.Audio, f.ExceptAudio)
+	}
+
 	// Other is parsed into the Other slice from rls
 	if len(f.MatchOther) > 0 && !sliceContainsSlice(r.Other, f.MatchOther) {
 		f.addRejectionF("match other not matching. got: %v want: %v", r.Other, f.MatchOther)
@@ -514,6 +621,11 @@ func (f *Filter) CheckFilter(r *Release) ([]string, bool) {
 	return nil, true
 }
 
+// MaxDownloadsReached checks the downloads of the filter against its max downloads
+func (f *Filter) MaxDownloadsReached() bool {
+	return f.MaxDownloads > 0 && !f.checkMaxDownloads(f.MaxDownloads, f.MaxDownloadsUnit)
+}
+
 func (f *Filter) checkMaxDownloads(max int, perTimeUnit FilterMaxDownloadsUnit) bool {
 	if f.Downloads == nil {
 		return false
@@ -594,6 +706,46 @@ func (f *Filter) checkSizeFilter(r *Release) bool {
 	return true
 }
 
+// CheckTrackerCount compares the filter tracker limits to the number of trackers in the torrent file.
+func (f *Filter) CheckTrackerCount(count int) bool {
+	if f.MinTrackers > 0 && count < f.MinTrackers {
+		f.addRejectionF("tracker count not matching. got: %d want min: %d", count, f.MinTrackers)
+		return false
+	}
+
+	if f.MaxTrackers > 0 && count > f.MaxTrackers {
+		f.addRejectionF("tracker count not matching. got: %d want max: %d", count, f.MaxTrackers)
+		return false
+	}
+
+	return true
+}
+
+// checkSeeders compares the filter seeder limits to the seeders from the announce.
+// Announces without seeders pass unless the filter rejects unknown seeders.
+func (f *Filter) checkSeeders(r *Release) bool {
+	if !r.HasSeeders {
+		if f.RejectUnknownSeeders {
+			f.addRejection("seeders not matching. got: unknown")
+			return false
+		}
+
+		return true
+	}
+
+	if f.MinSeeders > 0 && r.Seeders < f.MinSeeders {
+		f.addRejectionF("seeders not matching. got: %d want min: %d", r.Seeders, f.MinSeeders)
+		return false
+	}
+
+	if f.MaxSeeders > 0 && r.Seeders > f.MaxSeeders {
+		f.addRejectionF("seeders not matching. got: %d want max: %d", r.Seeders, f.MaxSeeders)
+		return false
+	}
+
+	return true
+}
+
 func (f *Filter) addRejection(reason string) {
 	f.Rejections = append(f.Rejections, reason)
 }
@@ -925,42 +1077,33 @@ func checkFreeleechPercent(announcePercent int, filterPercent string) bool {
 	return false
 }
 
-func matchHDR(releaseValues []string, filterValues []string) bool {
-
+// matchCombinedTags matches the release tags against the filter values, case-insensitive.
+// Values with spaces like "DV HDR10" or "TrueHD Atmos" only match when the release has every tag.
+func matchCombinedTags(releaseValues []string, filterValues []string) bool {
 	for _, filter := range filterValues {
-		if filter == "" {
+		parts := strings.Fields(strings.ToLower(filter))
+		if len(parts) == 0 {
 			continue
 		}
-		filter = strings.ToLower(filter)
-		filter = strings.Trim(filter, " ")
 
-		parts := strings.Split(filter, " ")
-		if len(parts) == 2 {
-			partsMatched := 0
-			for _, part := range parts {
-				for _, tag := range releaseValues {
-					if tag == "" {
-						continue
-					}
-					tag = strings.ToLower(tag)
-					if tag == part {
-						partsMatched++
-					}
-					if len(parts) == partsMatched {
-						return true
-					}
-				}
-			}
-		} else {
+		matched := true
+		for _, part := range parts {
+			found := false
 			for _, tag := range releaseValues {
-				if tag == "" {
-					continue
-				}
-				tag = strings.ToLower(tag)
-				if tag == filter {
-					return true
+				if strings.ToLower(tag) == part {
+					found = true
+					break
 				}
 			}
+
+			if !found {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
 		}
 	}
 
@@ -1007,11 +1150,15 @@ func (f *Filter) parsedSizeLimits() (*uint64, *uint64, error) {
 
 // parseBytes parses a string representation of a file size into a number of
 // bytes. It returns a *uint64 where "nil" represents "none" (corresponding to
-// the empty string)
+// the empty string). Units are decimal (GB) or binary (GiB), eg. "4.5 GB" or "700MiB".
 func parseBytes(s string) (*uint64, error) {
+	s = strings.TrimSpace(s)
 	if s == "" {
 		return nil, nil
 	}
 	b, err := humanize.ParseBytes(s)
-	return &b, err
+	if err != nil {
+		return nil, errors.New("invalid size %q, expected a number with a unit like 4.5 GB or 700 MiB", s)
+	}
+	return &b, nil
 }
@@ -2175,7 +2175,7 @@ func Test_checkSizeFilter(t *testing.T) {
 		{name: "test_6", filter: Filter{MinSize: "1GB", MaxSize: "2GB"}, releaseSize: 2000000000, want: false},
 		{name: "test_7", filter: Filter{MaxSize: "2GB"}, releaseSize: 2500000000, want: false},
 		{name: "test_8", filter: Filter{MaxSize: "20GB"}, releaseSize: 2500000000, want: true},
-		{name: "test_9", filter: Filter{MinSize: "unparseable", MaxSize: "20GB"}, releaseSize: 2500000000, want: false, wantErr: "could not parse filter min size: strconv.ParseFloat: parsing \"\": invalid syntax"},
+		{name: "test_9", filter: Filter{MinSize: "unparseable", MaxSize: "20GB"}, releaseSize: 2500000000, want: false, wantErr: "could not parse filter min size: invalid size \"unparseable\", expected a number with a unit like 4.5 GB or 700 MiB"},
 	}
 	for _, tt := range tests {
 
@@ -2188,3 +2188,142 @@ func Test_checkSizeFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_parsedSizeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		minSize string
+		maxSize string
+		wantMin *uint64
+		wantMax *uint64
+		wantErr string
+	}{
+		{name: "no_limits"},
+		{name: "decimal_units", minSize: "4.5 GB", maxSize: "10GB", wantMin: uint64Ptr(4_500_000_000), wantMax: uint64Ptr(10_000_000_000)},
+		{name: "binary_units", minSize: "700MiB", maxSize: "4.5 GiB", wantMin: uint64Ptr(700 * 1024 * 1024), wantMax: uint64Ptr(4.5 * 1024 * 1024 * 1024)},
+		{name: "lowercase_and_spaces", minSize: " 700 mb ", wantMin: uint64Ptr(700_000_000)},
+		{name: "plain_bytes", maxSize: "1024", wantMax: uint64Ptr(1024)},
+		{name: "garbage_min", minSize: "big", wantErr: `could not parse filter min size: invalid size "big", expected a number with a unit like 4.5 GB or 700 MiB`},
+		{name: "garbage_max", maxSize: "4.5 GQ", wantErr: `could not parse filter max size: invalid size "4.5 GQ", expected a number with a unit like 4.5 GB or 700 MiB`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{MinSize: tt.minSize, MaxSize: tt.maxSize}
+
+			gotMin, gotMax, err := f.parsedSizeLimits()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMin, gotMin)
+			assert.Equal(t, tt.wantMax, gotMax)
+		})
+	}
+}
+
+func TestFilter_Validate_sizeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{name: "valid", filter: Filter{Name: "test", MinSize: "700 MiB", MaxSize: "4.5 GB"}},
+		{name: "garbage", filter: Filter{Name: "test", MinSize: "700 parsecs"}, wantErr: true},
+		{name: "min_above_max", filter: Filter{Name: "test", MinSize: "5 GiB", MaxSize: "5 GB"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	garbage := "lots"
+	assert.Error(t, (&FilterUpdate{MaxSize: &garbage}).Validate())
+}
+
+func TestFilter_CheckTrackerCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		count  int
+		want   bool
+	}{
+		{name: "no_limits", filter: Filter{}, count: 1, want: true},
+		{name: "reject_single_tracker", filter: Filter{MinTrackers: 2}, count: 1, want: false},
+		{name: "allow_multi_tracker", filter: Filter{MinTrackers: 2}, count: 3, want: true},
+		{name: "reject_multi_tracker", filter: Filter{MaxTrackers: 1}, count: 3, want: false},
+		{name: "allow_single_tracker", filter: Filter{MaxTrackers: 1}, count: 1, want: true},
+		{name: "within_range", filter: Filter{MinTrackers: 2, MaxTrackers: 5}, count: 3, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.CheckTrackerCount(tt.count))
+			if !tt.want {
+				assert.NotEmpty(t, tt.filter.Rejections)
+			}
+		})
+	}
+}
+
+func TestFilter_CheckFilter_subtitles(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      Filter
+		torrentName string
+		want        bool
+	}{
+		{name: "match_subtitles", filter: Filter{MatchSubtitles: []string{"VOSTFR"}}, torrentName: "Le.Film.2023.VOSTFR.1080p.WEB.H264-GROUP", want: true},
+		{name: "match_subtitles_missing", filter: Filter{MatchSubtitles: []string{"VOSTFR"}}, torrentName: "Le.Film.2023.MULTi.1080p.BluRay.x264-GROUP", want: false},
+		{name: "except_subtitles", filter: Filter{ExceptSubtitles: []string{"SUBBED"}}, torrentName: "That.Show.S01E01.SUBBED.1080p.WEB.H264-GROUP", want: false},
+		{name: "except_subtitles_other", filter: Filter{ExceptSubtitles: []string{"SUBBED"}}, torrentName: "Le.Film.2023.MULTi.1080p.BluRay.x264-GROUP", want: true},
+		{name: "match_language_and_subtitles", filter: Filter{MatchLanguage: []string{"MULTi"}, ExceptSubtitles: []string{"VOSTFR"}}, torrentName: "Le.Film.2023.MULTi.1080p.BluRay.x264-GROUP", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.torrentName)
+
+			rejections, got := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.want, got, rejections)
+		})
+	}
+}
+
+func TestFilter_CheckFilter_hdrAudio(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      Filter
+		torrentName string
+		want        bool
+	}{
+		{name: "match_dv_hdr10", filter: Filter{MatchHDR: []string{"DV HDR10"}}, torrentName: "That.Movie.2023.2160p.UHD.BluRay.DV.HDR10.TrueHD.Atmos.7.1.x265-GROUP", want: true},
+		{name: "match_dv_hdr10_dv_only", filter: Filter{MatchHDR: []string{"DV HDR10"}}, torrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.Atmos.DV.H.265-GROUP", want: false},
+		{name: "except_dv", filter: Filter{ExceptHDR: []string{"DV"}}, torrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.Atmos.DV.H.265-GROUP", want: false},
+		{name: "except_dv_hdr10_plus", filter: Filter{ExceptHDR: []string{"DV"}}, torrentName: "That.Show.S01E01.2160p.WEB-DL.DD+5.1.HDR10+.H.265-GROUP", want: true},
+		{name: "match_atmos", filter: Filter{MatchAudio: []string{"Atmos"}}, torrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.Atmos.DV.H.265-GROUP", want: true},
+		{name: "match_truehd_atmos", filter: Filter{MatchAudio: []string{"TrueHD Atmos"}}, torrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.Atmos.DV.H.265-GROUP", want: false},
+		{name: "match_audio_any", filter: Filter{MatchAudio: []string{"TrueHD Atmos", "DTS-HD.MA"}}, torrentName: "That.Movie.2023.1080p.BluRay.DTS-HD.MA.5.1.x264-GROUP", want: true},
+		{name: "except_aac", filter: Filter{ExceptAudio: []string{"AAC"}}, torrentName: "That.Movie.2023.2160p.WEB-DL.AAC2.0.HLG.H.265-GROUP", want: false},
+		{name: "match_audio_missing", filter: Filter{MatchAudio: []string{"Atmos"}}, torrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.torrentName)
+
+			rejections, got := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.want, got, rejections)
+		})
+	}
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+)
+
+// groupTiers maps the lowercase release group to its trust tier, a higher tier is more trusted
+var groupTiers = map[string]int{}
+
+// groupTierDefault is the tier of groups without a tier of their own
+var groupTierDefault = 0
+
+// SetGroupTiers sets the trust tier of release groups, groups are matched case-insensitively.
+// Groups not in the mapping, and releases without a group, get the default tier.
+func SetGroupTiers(tiers map[string]int, defaultTier int) {
+	m := make(map[string]int, len(tiers))
+	for group, tier := range tiers {
+		m[strings.ToLower(strings.TrimSpace(group))] = tier
+	}
+
+	groupTiers = m
+	groupTierDefault = defaultTier
+}
+
+// GroupTier returns the trust tier of the release group
+func GroupTier(group string) int {
+	if group == "" {
+		return groupTierDefault
+	}
+
+	if tier, ok := groupTiers[strings.ToLower(group)]; ok {
+		return tier
+	}
+
+	return groupTierDefault
+}
+
+// GroupTier returns the trust tier of the group of the release.
+// Releases not parsed from a title, like from the api, get the group from the name.
+func (r *Release) GroupTier() int {
+	group := r.Group
+	if group == "" {
+		group = ParseReleaseGroup(r.TorrentName)
+	}
+
+	return GroupTier(group)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setTestGroupTiers(t *testing.T) {
+	SetGroupTiers(map[string]int{"GROUP": 3, "OtherGroup": 1}, 2)
+	t.Cleanup(func() {
+		SetGroupTiers(nil, 0)
+	})
+}
+
+func TestGroupTier(t *testing.T) {
+	setTestGroupTiers(t)
+
+	tests := []struct {
+		name  string
+		group string
+		want  int
+	}{
+		{name: "known", group: "GROUP", want: 3},
+		{name: "case_insensitive", group: "group", want: 3},
+		{name: "known_low", group: "OTHERGROUP", want: 1},
+		{name: "unknown_default", group: "NEWGROUP", want: 2},
+		{name: "no_group_default", group: "", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GroupTier(tt.group))
+		})
+	}
+}
+
+func TestMacros_GroupTier(t *testing.T) {
+	setTestGroupTiers(t)
+
+	tests := []struct {
+		name    string
+		release Release
+		text    string
+		want    string
+	}{
+		{name: "parsed_group", release: Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", Group: "GROUP"}, text: "{{ .GroupTier }}", want: "3"},
+		{name: "group_from_name", release: Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-OtherGroup"}, text: "{{ .GroupTier }}", want: "1"},
+		{name: "unknown_group", release: Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-NEWGROUP"}, text: "{{ .GroupTier }}", want: "2"},
+		{name: "routing", release: Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"}, text: "{{ if ge .GroupTier 3 }}trusted{{ else }}other{{ end }}", want: "trusted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMacro(tt.release).Parse(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilter_CheckFilter_minGroupTier(t *testing.T) {
+	setTestGroupTiers(t)
+
+	tests := []struct {
+		name         string
+		minGroupTier int
+		torrentName  string
+		wantMatch    bool
+	}{
+		{name: "disabled", minGroupTier: 0, torrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-OTHERGROUP", wantMatch: true},
+		{name: "above_min", minGroupTier: 2, torrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", wantMatch: true},
+		{name: "default_equals_min", minGroupTier: 2, torrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-NEWGROUP", wantMatch: true},
+		{name: "below_min", minGroupTier: 2, torrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-OTHERGROUP", wantMatch: false},
+		{name: "default_below_min", minGroupTier: 3, torrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-NEWGROUP", wantMatch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.torrentName)
+
+			f := &Filter{Name: "tiers", Enabled: true, MinGroupTier: tt.minGroupTier}
+
+			rejections, match := f.CheckFilter(r)
+			assert.Equal(t, tt.wantMatch, match)
+
+			if !tt.wantMatch {
+				assert.Len(t, rejections, 1)
+				assert.Contains(t, rejections[0], "release group tier too low")
+			}
+		})
+	}
+}
@@ -232,6 +232,21 @@ type IndexerIRCParseMatched struct {
 	TorrentName string
 }
 
+// CapturedVars returns the vars captured by the parse lines, settings like passkeys are left out
+func (p *IndexerIRCParse) CapturedVars(vars map[string]string) map[string]string {
+	captured := map[string]string{}
+
+	for _, line := range p.Lines {
+		for _, name := range line.Vars {
+			if value, ok := vars[name]; ok {
+				captured[name] = value
+			}
+		}
+	}
+
+	return captured
+}
+
 func (p *IndexerIRCParse) ParseMatch(baseURL string, vars map[string]string) (*IndexerIRCParseMatched, error) {
 	matched := &IndexerIRCParseMatched{}
 
@@ -46,6 +46,7 @@ type IrcNetwork struct {
 	UseBouncer     bool         `json:"use_bouncer"`
 	BouncerAddr    string       `json:"bouncer_addr"`
 	BotMode        bool         `json:"bot_mode"`
+	Proxy          string       `json:"proxy"`
 	Channels       []IrcChannel `json:"channels"`
 	Connected      bool         `json:"connected"`
 	ConnectedSince *time.Time   `json:"connected_since"`
@@ -65,6 +66,7 @@ type IrcNetworkWithHealth struct {
 	UseBouncer       bool                `json:"use_bouncer"`
 	BouncerAddr      string              `json:"bouncer_addr"`
 	BotMode          bool                `json:"bot_mode"`
+	Proxy            string              `json:"proxy"`
 	CurrentNick      string              `json:"current_nick"`
 	PreferredNick    string              `json:"preferred_nick"`
 	Channels         []ChannelWithHealth `json:"channels"`
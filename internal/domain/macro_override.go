@@ -0,0 +1,66 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type MacroOverrideRepo interface {
+	List(ctx context.Context) ([]MacroOverride, error)
+	FindByIndexer(ctx context.Context, indexer string) ([]MacroOverride, error)
+	Store(ctx context.Context, override *MacroOverride) error
+	Delete(ctx context.Context, id int) error
+}
+
+// MacroOverride remaps a macro value for releases from a single indexer before action macros are parsed.
+// An empty Match replaces the value unconditionally.
+type MacroOverride struct {
+	ID        int       `json:"id"`
+	Indexer   string    `json:"indexer"`
+	Field     string    `json:"field"`
+	Match     string    `json:"match"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (o MacroOverride) Validate() error {
+	if o.Indexer == "" {
+		return errors.New("indexer is required")
+	}
+
+	field, ok := reflect.TypeOf(Macro{}).FieldByName(o.Field)
+	if !ok || !field.IsExported() || field.Type.Kind() != reflect.String {
+		return errors.New("invalid macro field: %s", o.Field)
+	}
+
+	return nil
+}
+
+// ApplyOverrides sets the macro fields from the overrides matching the release indexer, in order.
+func (m *Macro) ApplyOverrides(overrides []MacroOverride) {
+	v := reflect.ValueOf(m).Elem()
+
+	for _, o := range overrides {
+		if !strings.EqualFold(o.Indexer, m.Indexer) {
+			continue
+		}
+
+		field := v.FieldByName(o.Field)
+		if !field.IsValid() || !field.CanSet() || field.Kind() != reflect.String {
+			continue
+		}
+
+		if o.Match != "" && !strings.EqualFold(field.String(), o.Match) {
+			continue
+		}
+
+		field.SetString(o.Value)
+	}
+}
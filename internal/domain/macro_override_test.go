@@ -0,0 +1,74 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMacro_ApplyOverrides(t *testing.T) {
+	overrides := []MacroOverride{
+		{Indexer: "mock", Field: "Category", Match: "Movies/HD-1080p", Value: "movies"},
+		{Indexer: "mock", Field: "Resolution", Match: "", Value: "1080p"},
+		{Indexer: "other", Field: "Category", Match: "TV", Value: "tv"},
+	}
+
+	tests := []struct {
+		name           string
+		release        Release
+		wantCategory   string
+		wantResolution string
+	}{
+		{
+			name:           "matching_indexer",
+			release:        Release{Indexer: "mock", Category: "movies/hd-1080p", Resolution: "FHD"},
+			wantCategory:   "movies",
+			wantResolution: "1080p",
+		},
+		{
+			name:           "matching_indexer_other_value",
+			release:        Release{Indexer: "mock", Category: "Movies/SD", Resolution: "FHD"},
+			wantCategory:   "Movies/SD",
+			wantResolution: "1080p",
+		},
+		{
+			name:           "other_indexer",
+			release:        Release{Indexer: "another", Category: "Movies/HD-1080p", Resolution: "FHD"},
+			wantCategory:   "Movies/HD-1080p",
+			wantResolution: "FHD",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMacro(tt.release)
+			m.ApplyOverrides(overrides)
+
+			assert.Equal(t, tt.wantCategory, m.Category)
+			assert.Equal(t, tt.wantResolution, m.Resolution)
+		})
+	}
+}
+
+func TestAction_ParseMacros_overrides(t *testing.T) {
+	release := &Release{Indexer: "mock", Category: "Movies/HD-1080p"}
+	overrides := []MacroOverride{
+		{Indexer: "mock", Field: "Category", Match: "Movies/HD-1080p", Value: "movies"},
+	}
+
+	action := &Action{Category: "{{ .Category }}"}
+	assert.NoError(t, action.ParseMacros(release, overrides...))
+	assert.Equal(t, "movies", action.Category)
+
+	// the release itself is left untouched
+	assert.Equal(t, "Movies/HD-1080p", release.Category)
+}
+
+func TestMacroOverride_Validate(t *testing.T) {
+	assert.NoError(t, MacroOverride{Indexer: "mock", Field: "Category"}.Validate())
+	assert.Error(t, MacroOverride{Field: "Category"}.Validate())
+	assert.Error(t, MacroOverride{Indexer: "mock", Field: "Unknown"}.Validate())
+	assert.Error(t, MacroOverride{Indexer: "mock", Field: "Size"}.Validate())
+}
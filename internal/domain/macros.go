@@ -5,50 +5,111 @@ package domain
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"text/template/parse"
 	"time"
 
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/dustin/go-humanize"
+	"github.com/mattn/go-shellwords"
+	"github.com/moistari/rls"
+)
+
+var (
+	macroActionRegex      = regexp.MustCompile(`{{.*?}}`)
+	macroPlaceholderRegex = regexp.MustCompile("\x00(\\d+)\x00")
+	pathUnsafeCharsRegex  = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]`)
+	posixUnsafeCharsRegex = regexp.MustCompile(`[/\x00]`)
+	windowsReservedRegex  = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[0-9]|lpt[0-9])(\..*)?$`)
 )
 
 type Macro struct {
 	TorrentName         string
 	TorrentPathName     string
 	TorrentHash         string
+	InfoHash            string
 	TorrentID           string
+	ClientTorrentID     string
 	TorrentUrl          string
 	TorrentDataRawBytes []byte
 	MagnetURI           string
 	GroupID             string
 	DownloadUrl         string
+	DownloadURL         string
+	ProxiedDownloadURL  string
 	InfoUrl             string
 	Indexer             string
 	Title               string
+	ReleaseGroup        string
+	GroupTier           int
 	Category            string
 	Categories          []string
 	Resolution          string
 	Source              string
 	HDR                 string
+	Audio               string
+	AudioChannels       string
+	Languages           string
+	Subtitles           string
 	FilterName          string
+	FilterID            int
+	Freeleech           bool
+	FreeleechPercent    int
+	Origin              string
+	Uploader            string
+	Seeders             int
+	Leechers            int
+	Vars                map[string]string
+	IsScene             bool
 	Size                uint64
+	TrackerCount        int
 	SizeString          string
 	Season              int
 	Episode             int
+	LastEpisode         int
+	SeasonPadded        string
+	EpisodePadded       string
+	SeasonEpisode       string
 	Year                int
+	IsMultiDisc         bool
 	CurrentYear         int
 	CurrentMonth        int
 	CurrentDay          int
 	CurrentHour         int
 	CurrentMinute       int
 	CurrentSecond       int
+	Weekday             string
+	AnnouncedAt         string
+	AgeSeconds          int64
+	ClientSavePath      string
+	SavePath            string
+	Client              MacroClient
+
+	// pathOS decides what the sanitizePath template function replaces
+	pathOS ActionPathOS
+}
+
+// MacroClient is the download client attached to the action, empty when there is none
+type MacroClient struct {
+	Name string
 }
 
 func NewMacro(release Release) Macro {
-	currentTime := time.Now()
+	return newMacro(release, time.Now)
+}
+
+// newMacro builds the macro with the time values taken from the given clock
+func newMacro(release Release, now func() time.Time) Macro {
+	currentTime := now()
 
 	ma := Macro{
 		TorrentName:         release.TorrentName,
@@ -56,35 +117,474 @@ func NewMacro(release Release) Macro {
 		TorrentPathName:     release.TorrentTmpFile,
 		TorrentDataRawBytes: release.TorrentDataRawBytes,
 		TorrentHash:         release.TorrentHash,
+		InfoHash:            release.TorrentHash,
 		TorrentID:           release.TorrentID,
+		ClientTorrentID:     release.ClientTorrentID,
 		MagnetURI:           release.MagnetURI,
 		GroupID:             release.GroupID,
 		InfoUrl:             release.InfoURL,
 		DownloadUrl:         release.DownloadURL,
+		DownloadURL:         release.DownloadURL,
+		ProxiedDownloadURL:  release.ProxiedDownloadURL,
 		Indexer:             release.Indexer,
 		Title:               release.Title,
+		ReleaseGroup:        release.Group,
 		Category:            release.Category,
 		Categories:          release.Categories,
 		Resolution:          release.Resolution,
 		Source:              release.Source,
 		HDR:                 strings.Join(release.HDR, ", "),
+		Audio:               strings.Join(release.Audio, ", "),
+		AudioChannels:       release.AudioChannels,
+		Languages:           strings.Join(release.Languages, ", "),
+		Subtitles:           strings.Join(release.Subtitles, ", "),
 		FilterName:          release.FilterName,
+		FilterID:            release.FilterID,
+		Freeleech:           release.Freeleech,
+		FreeleechPercent:    release.FreeleechPercent,
+		Origin:              release.Origin,
+		Uploader:            release.Uploader,
+		Seeders:             release.Seeders,
+		Leechers:            release.Leechers,
+		Vars:                release.Vars,
+		IsScene:             release.IsScene,
 		Size:                release.Size,
 		SizeString:          humanize.Bytes(release.Size),
+		TrackerCount:        release.TrackerCount,
 		Season:              release.Season,
 		Episode:             release.Episode,
+		LastEpisode:         release.LastEpisode,
 		Year:                release.Year,
+		IsMultiDisc:         release.IsMultiDisc,
 		CurrentYear:         currentTime.Year(),
 		CurrentMonth:        int(currentTime.Month()),
 		CurrentDay:          currentTime.Day(),
 		CurrentHour:         currentTime.Hour(),
 		CurrentMinute:       currentTime.Minute(),
 		CurrentSecond:       currentTime.Second(),
+		Weekday:             currentTime.Weekday().String(),
+	}
+
+	ma.SeasonPadded, ma.EpisodePadded, ma.SeasonEpisode = seasonEpisode(release)
+
+	// releases not parsed from a title, like from the api, only have the name
+	if ma.ReleaseGroup == "" {
+		ma.ReleaseGroup = ParseReleaseGroup(release.TorrentName)
+	}
+	ma.GroupTier = release.GroupTier()
+
+	// release timestamp is set when the announce is captured
+	if !release.Timestamp.IsZero() {
+		ma.AnnouncedAt = release.Timestamp.Format(time.RFC3339)
+
+		if age := currentTime.Sub(release.Timestamp); age > 0 {
+			ma.AgeSeconds = int64(age / time.Second)
+		}
 	}
 
 	return ma
 }
 
+// seasonEpisode returns the zero-padded season and episode and the combined S01E05 form.
+// Season packs only have a season, multi-episode releases get the range like S01E05-E07.
+func seasonEpisode(release Release) (season string, episode string, combined string) {
+	if release.Episode > 0 {
+		episode = fmt.Sprintf("%02d", release.Episode)
+	}
+
+	// episodes without a season, like absolute anime numbering, have no combined form
+	if release.Season == 0 {
+		return "", episode, ""
+	}
+
+	season = fmt.Sprintf("%02d", release.Season)
+	combined = "S" + season
+
+	if episode != "" {
+		combined += "E" + episode
+
+		if release.LastEpisode > release.Episode {
+			combined += fmt.Sprintf("-E%02d", release.LastEpisode)
+		}
+	}
+
+	return season, episode, combined
+}
+
+// TorrentDataRawBytesGzipB64 returns the torrent data gzipped and base64 encoded, for endpoints that take a compressed torrent.
+// It's a method so the torrent is only compressed when a template uses it.
+func (m Macro) TorrentDataRawBytesGzipB64() (string, error) {
+	if len(m.TorrentDataRawBytes) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(m.TorrentDataRawBytes); err != nil {
+		return "", errors.Wrap(err, "could not gzip torrent data")
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", errors.Wrap(err, "could not gzip torrent data")
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// PathSafe returns a copy of the macro where release values can't contain path separators,
+// so they can be used to build file names without escaping the target directory.
+// Every string value is sanitized, except the paths autobrr sets itself: the torrent tmp file and the save paths.
+func (m Macro) PathSafe() Macro {
+	m.TorrentName = SanitizeFilename(m.TorrentName)
+	m.TorrentHash = SanitizeFilename(m.TorrentHash)
+	m.InfoHash = SanitizeFilename(m.InfoHash)
+	m.TorrentID = SanitizeFilename(m.TorrentID)
+	m.ClientTorrentID = SanitizeFilename(m.ClientTorrentID)
+	m.TorrentUrl = SanitizeFilename(m.TorrentUrl)
+	m.MagnetURI = SanitizeFilename(m.MagnetURI)
+	m.GroupID = SanitizeFilename(m.GroupID)
+	m.DownloadUrl = SanitizeFilename(m.DownloadUrl)
+	m.DownloadURL = SanitizeFilename(m.DownloadURL)
+	m.ProxiedDownloadURL = SanitizeFilename(m.ProxiedDownloadURL)
+	m.InfoUrl = SanitizeFilename(m.InfoUrl)
+	m.Indexer = SanitizeFilename(m.Indexer)
+	m.Title = SanitizeFilename(m.Title)
+	m.ReleaseGroup = SanitizeFilename(m.ReleaseGroup)
+	m.Category = SanitizeFilename(m.Category)
+	m.Resolution = SanitizeFilename(m.Resolution)
+	m.Source = SanitizeFilename(m.Source)
+	m.HDR = SanitizeFilename(m.HDR)
+	m.Audio = SanitizeFilename(m.Audio)
+	m.AudioChannels = SanitizeFilename(m.AudioChannels)
+	m.Languages = SanitizeFilename(m.Languages)
+	m.Subtitles = SanitizeFilename(m.Subtitles)
+	m.FilterName = SanitizeFilename(m.FilterName)
+	m.Origin = SanitizeFilename(m.Origin)
+	m.Uploader = SanitizeFilename(m.Uploader)
+	m.SizeString = SanitizeFilename(m.SizeString)
+	m.SeasonPadded = SanitizeFilename(m.SeasonPadded)
+	m.EpisodePadded = SanitizeFilename(m.EpisodePadded)
+	m.SeasonEpisode = SanitizeFilename(m.SeasonEpisode)
+	m.Weekday = SanitizeFilename(m.Weekday)
+	m.AnnouncedAt = SanitizeFilename(m.AnnouncedAt)
+	m.Client.Name = SanitizeFilename(m.Client.Name)
+
+	categories := make([]string, 0, len(m.Categories))
+	for _, c := range m.Categories {
+		categories = append(categories, SanitizeFilename(c))
+	}
+	m.Categories = categories
+
+	// copy the vars, the macro shares the map with the release
+	if m.Vars != nil {
+		vars := make(map[string]string, len(m.Vars))
+		for k, v := range m.Vars {
+			vars[k] = SanitizeFilename(v)
+		}
+		m.Vars = vars
+	}
+
+	return m
+}
+
+// SanitizeFilename replaces path separators and characters not allowed in file names
+func SanitizeFilename(name string) string {
+	name = pathUnsafeCharsRegex.ReplaceAllString(name, "_")
+
+	if name == "." || name == ".." {
+		return strings.Repeat("_", len(name))
+	}
+
+	return name
+}
+
+// WithPathOS returns a copy of the macro where sanitizePath makes values safe for the given OS
+func (m Macro) WithPathOS(os ActionPathOS) Macro {
+	m.pathOS = os
+	return m
+}
+
+// SanitizePath replaces the characters in a single path element that are not allowed on the target OS.
+// Windows additionally strips trailing dots and spaces and prefixes reserved device names.
+func SanitizePath(name string, os ActionPathOS) string {
+	switch os {
+	case ActionPathOSWindows:
+		name = pathUnsafeCharsRegex.ReplaceAllString(name, "_")
+
+		// a name of only dots would end up empty
+		if trimmed := strings.TrimRight(name, ". "); trimmed != "" || name == "" {
+			name = trimmed
+		} else {
+			name = "_"
+		}
+
+		if windowsReservedRegex.MatchString(name) {
+			name = "_" + name
+		}
+
+	default:
+		name = posixUnsafeCharsRegex.ReplaceAllString(name, "_")
+	}
+
+	if name == "." || name == ".." {
+		return strings.Repeat("_", len(name))
+	}
+
+	return name
+}
+
+// ParseReleaseGroup returns the -GROUP suffix of a release name, or an empty string if it has none
+func ParseReleaseGroup(name string) string {
+	return rls.ParseString(name).Group
+}
+
+// StripReleaseGroup returns the release name without the -GROUP suffix and anything trailing it, like [rarbg].
+// Names without a group are returned unchanged.
+func StripReleaseGroup(name string) string {
+	group := ParseReleaseGroup(name)
+	if group == "" {
+		return name
+	}
+
+	idx := strings.LastIndex(name, "-"+group)
+	if idx < 0 {
+		return name
+	}
+
+	return strings.TrimRight(name[:idx], " .")
+}
+
+// funcMap returns the template functions available in macros.
+// The sprig functions like default, coalesce and ternary allow building paths that don't break on missing fields,
+// and lower, upper and title change the casing of values.
+// add, sub, mul and div replace the sprig ones to work on floats too and to error on division by zero.
+// urlpath escapes a value for a url path segment, next to the urlquery builtin for query values.
+func (m Macro) funcMap() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	funcs["stripGroup"] = StripReleaseGroup
+	funcs["sanitizePath"] = func(name string) string {
+		return SanitizePath(name, m.pathOS)
+	}
+	funcs["regexReplace"] = regexReplace
+	funcs["add"] = macroAdd
+	funcs["sub"] = macroSub
+	funcs["mul"] = macroMul
+	funcs["div"] = macroDiv
+	funcs["urlpath"] = urlPath
+
+	return funcs
+}
+
+// urlPath path escapes the values like urlquery does for queries, so a slash stays inside the segment
+func urlPath(values ...interface{}) string {
+	return url.PathEscape(fmt.Sprint(values...))
+}
+
+// macroNumber is a macro value converted for arithmetic, ints stay ints unless a float is involved
+type macroNumber struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func toMacroNumber(v interface{}) (macroNumber, error) {
+	switch n := v.(type) {
+	case int:
+		return macroNumber{i: int64(n), f: float64(n)}, nil
+	case int32:
+		return macroNumber{i: int64(n), f: float64(n)}, nil
+	case int64:
+		return macroNumber{i: n, f: float64(n)}, nil
+	case uint:
+		return macroNumber{i: int64(n), f: float64(n)}, nil
+	case uint32:
+		return macroNumber{i: int64(n), f: float64(n)}, nil
+	case uint64:
+		return macroNumber{i: int64(n), f: float64(n)}, nil
+	case float32:
+		return macroNumber{f: float64(n), isFloat: true}, nil
+	case float64:
+		return macroNumber{f: n, isFloat: true}, nil
+	case string:
+		if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+			return macroNumber{i: i, f: float64(i)}, nil
+		}
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return macroNumber{f: f, isFloat: true}, nil
+		}
+	}
+
+	return macroNumber{}, errors.New("not a number: %v", v)
+}
+
+// macroArithmetic folds the values with the int or float operation, the result is a float64 if any value is a float
+func macroArithmetic(name string, values []interface{}, intOp func(a, b int64) (int64, error), floatOp func(a, b float64) (float64, error)) (interface{}, error) {
+	if len(values) < 2 {
+		return nil, errors.New("%s: needs at least two values", name)
+	}
+
+	acc, err := toMacroNumber(values[0])
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	for _, v := range values[1:] {
+		n, err := toMacroNumber(v)
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+
+		if acc.isFloat || n.isFloat {
+			if acc.f, err = floatOp(acc.f, n.f); err != nil {
+				return nil, errors.Wrap(err, name)
+			}
+			acc.isFloat = true
+			continue
+		}
+
+		if acc.i, err = intOp(acc.i, n.i); err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		acc.f = float64(acc.i)
+	}
+
+	if acc.isFloat {
+		return acc.f, nil
+	}
+
+	return acc.i, nil
+}
+
+var errMacroDivideByZero = errors.New("division by zero")
+
+func macroAdd(values ...interface{}) (interface{}, error) {
+	return macroArithmetic("add", values,
+		func(a, b int64) (int64, error) { return a + b, nil },
+		func(a, b float64) (float64, error) { return a + b, nil })
+}
+
+func macroSub(a, b interface{}) (interface{}, error) {
+	return macroArithmetic("sub", []interface{}{a, b},
+		func(a, b int64) (int64, error) { return a - b, nil },
+		func(a, b float64) (float64, error) { return a - b, nil })
+}
+
+func macroMul(values ...interface{}) (interface{}, error) {
+	return macroArithmetic("mul", values,
+		func(a, b int64) (int64, error) { return a * b, nil },
+		func(a, b float64) (float64, error) { return a * b, nil })
+}
+
+// macroDiv divides a by b, ints are truncated like in go, pass a float like 1e9 for a fractional result
+func macroDiv(a, b interface{}) (interface{}, error) {
+	return macroArithmetic("div", []interface{}{a, b},
+		func(a, b int64) (int64, error) {
+			if b == 0 {
+				return 0, errMacroDivideByZero
+			}
+			return a / b, nil
+		},
+		func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errMacroDivideByZero
+			}
+			return a / b, nil
+		})
+}
+
+// regexReplace replaces all matches of pattern in s with repl, repl can use $1 style submatches.
+// Unlike sprig's regexReplaceAll the value comes first and an invalid pattern is an error instead of a panic.
+func regexReplace(s string, pattern string, repl string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	return re.ReplaceAllString(s, repl), nil
+}
+
+// newMacroTemplate parses the macro template and checks the patterns passed to regexReplace,
+// so an invalid regex fails when the template is parsed and not only when a release is matched.
+func (m Macro) newMacroTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("macro").Funcs(m.funcMap()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRegexReplacePatterns(tmpl.Tree.Root); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// checkRegexReplacePatterns compiles the string literal patterns of regexReplace calls in the template tree
+func checkRegexReplacePatterns(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkRegexReplacePatterns(child); err != nil {
+				return err
+			}
+		}
+
+	case *parse.ActionNode:
+		return checkRegexReplacePatterns(n.Pipe)
+
+	case *parse.IfNode:
+		return checkRegexReplaceBranch(&n.BranchNode)
+
+	case *parse.RangeNode:
+		return checkRegexReplaceBranch(&n.BranchNode)
+
+	case *parse.WithNode:
+		return checkRegexReplaceBranch(&n.BranchNode)
+
+	case *parse.TemplateNode:
+		return checkRegexReplacePatterns(n.Pipe)
+
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := checkRegexReplacePatterns(cmd); err != nil {
+				return err
+			}
+		}
+
+	case *parse.CommandNode:
+		if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "regexReplace" && len(n.Args) == 4 {
+			if pattern, ok := n.Args[2].(*parse.StringNode); ok {
+				if _, err := regexp.Compile(pattern.Text); err != nil {
+					return errors.Wrap(err, "invalid regexReplace pattern: %s", pattern.Text)
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			if err := checkRegexReplacePatterns(arg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkRegexReplaceBranch(n *parse.BranchNode) error {
+	if err := checkRegexReplacePatterns(n.Pipe); err != nil {
+		return err
+	}
+	if err := checkRegexReplacePatterns(n.List); err != nil {
+		return err
+	}
+
+	return checkRegexReplacePatterns(n.ElseList)
+}
+
 // Parse takes a string and replaces valid vars
 func (m Macro) Parse(text string) (string, error) {
 	if text == "" {
@@ -92,7 +592,7 @@ func (m Macro) Parse(text string) (string, error) {
 	}
 
 	// setup template
-	tmpl, err := template.New("macro").Funcs(sprig.TxtFuncMap()).Parse(text)
+	tmpl, err := m.newMacroTemplate(text)
 	if err != nil {
 		return "", errors.Wrap(err, "could parse macro template")
 	}
@@ -113,7 +613,7 @@ func (m Macro) MustParse(text string) string {
 	}
 
 	// setup template
-	tmpl, err := template.New("macro").Funcs(sprig.TxtFuncMap()).Parse(text)
+	tmpl, err := m.newMacroTemplate(text)
 	if err != nil {
 		return ""
 	}
@@ -126,3 +626,141 @@ func (m Macro) MustParse(text string) string {
 
 	return tpl.String()
 }
+
+// ParseArgs splits text into shell-like arguments and replaces valid vars in each argument.
+// Arguments are split before the macros are expanded, so values with spaces or quotes,
+// like release names, are always passed as a single argument.
+func (m Macro) ParseArgs(text string) ([]string, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	// mask template actions so whitespace and quotes inside them are not split on
+	var actions []string
+	masked := macroActionRegex.ReplaceAllStringFunc(text, func(action string) string {
+		actions = append(actions, action)
+		return fmt.Sprintf("\x00%d\x00", len(actions)-1)
+	})
+
+	p := shellwords.NewParser()
+	p.ParseBacktick = true
+	args, err := p.Parse(masked)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse into shell-words")
+	}
+
+	for i, arg := range args {
+		restored := macroPlaceholderRegex.ReplaceAllStringFunc(arg, func(placeholder string) string {
+			idx, err := strconv.Atoi(strings.Trim(placeholder, "\x00"))
+			if err != nil || idx >= len(actions) {
+				return placeholder
+			}
+			return actions[idx]
+		})
+
+		args[i], err = m.Parse(restored)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// ParseURL replaces valid vars in a url. Values in the path and fragment are path escaped and values in the
+// query are query escaped, so release values with spaces, slashes or ampersands stay a single segment.
+// Values in the scheme and host are not escaped, neither are actions that already escape with urlquery or urlpath.
+// The escaping is added to every action that writes output, including the ones inside if, range and with.
+func (m Macro) ParseURL(text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := m.newMacroTemplate(text)
+	if err != nil {
+		return "", errors.Wrap(err, "could parse macro template")
+	}
+
+	// blank out the actions so only the literal parts decide where the path and query start
+	masked := macroActionRegex.ReplaceAllStringFunc(text, func(action string) string {
+		return strings.Repeat("\x00", len(action))
+	})
+
+	// the path starts after the host, the query after the first ?
+	pathStart := 0
+	if idx := strings.Index(masked, "://"); idx >= 0 {
+		pathStart = idx + len("://")
+		if end := strings.IndexAny(masked[pathStart:], "/?#"); end >= 0 {
+			pathStart += end
+		} else {
+			pathStart = len(masked)
+		}
+	}
+	queryStart := strings.Index(masked, "?")
+	fragmentStart := strings.Index(masked, "#")
+
+	escapeURLActions(tmpl.Tree.Root, func(pos int) string {
+		switch {
+		case pos < pathStart:
+			return ""
+		case queryStart >= 0 && pos > queryStart && (fragmentStart < 0 || pos < fragmentStart):
+			return "urlquery"
+		default:
+			return "urlpath"
+		}
+	})
+
+	var tpl bytes.Buffer
+	if err := tmpl.Execute(&tpl, m); err != nil {
+		return "", errors.Wrap(err, "could not parse macro")
+	}
+
+	return tpl.String(), nil
+}
+
+// escapeURLActions appends the escape function returned for the position of each action that writes output.
+// Variable declarations and actions that already call urlquery or urlpath are left as is.
+func escapeURLActions(node parse.Node, escaper func(pos int) string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeURLActions(child, escaper)
+		}
+
+	case *parse.IfNode:
+		escapeURLActions(n.List, escaper)
+		escapeURLActions(n.ElseList, escaper)
+
+	case *parse.RangeNode:
+		escapeURLActions(n.List, escaper)
+		escapeURLActions(n.ElseList, escaper)
+
+	case *parse.WithNode:
+		escapeURLActions(n.List, escaper)
+		escapeURLActions(n.ElseList, escaper)
+
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		for _, cmd := range n.Pipe.Cmds {
+			if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && (ident.Ident == "urlquery" || ident.Ident == "urlpath") {
+				return
+			}
+		}
+
+		fn := escaper(int(n.Pos))
+		if fn == "" {
+			return
+		}
+
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(fn).SetPos(n.Pos)},
+		})
+	}
+}
@@ -4,7 +4,15 @@
 package domain
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -214,6 +222,41 @@ func TestMacros_Parse(t *testing.T) {
 			want:    "DownloadUrl: https://test.local/this/page/1001",
 			wantErr: false,
 		},
+		{
+			name: "test_default_empty",
+			release: Release{
+				TorrentName: "That.Movie.2023.BluRay.x264-GROUP",
+			},
+			args:    args{text: "movies/{{ default \"unknown\" .Resolution }}/{{ .TorrentName }}"},
+			want:    "movies/unknown/That.Movie.2023.BluRay.x264-GROUP",
+			wantErr: false,
+		},
+		{
+			name: "test_default_set",
+			release: Release{
+				TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP",
+				Resolution:  "1080p",
+			},
+			args:    args{text: "movies/{{ default \"unknown\" .Resolution }}/{{ .TorrentName }}"},
+			want:    "movies/1080p/That.Movie.2023.1080p.BluRay.x264-GROUP",
+			wantErr: false,
+		},
+		{
+			name: "test_if_freeleech",
+			release: Release{
+				Freeleech: true,
+			},
+			args:    args{text: "{{ if .Freeleech }}freeleech{{ else }}ratio{{ end }}"},
+			want:    "freeleech",
+			wantErr: false,
+		},
+		{
+			name:    "test_if_not_freeleech",
+			release: Release{},
+			args:    args{text: "{{ if .Freeleech }}freeleech{{ else }}ratio{{ end }}"},
+			want:    "ratio",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -230,3 +273,658 @@ func TestMacros_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestMacros_Age(t *testing.T) {
+	now := time.Date(2023, 11, 4, 18, 30, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		release Release
+		text    string
+		want    string
+	}{
+		{
+			name:    "announced_at",
+			release: Release{Timestamp: time.Date(2023, 11, 4, 18, 25, 30, 0, time.UTC)},
+			text:    "{{ .AnnouncedAt }}",
+			want:    "2023-11-04T18:25:30Z",
+		},
+		{
+			name:    "age_seconds",
+			release: Release{Timestamp: now.Add(-4*time.Minute - 30*time.Second - 500*time.Millisecond)},
+			text:    "{{ .AgeSeconds }}",
+			want:    "270",
+		},
+		{
+			name:    "age_condition",
+			release: Release{Timestamp: now.Add(-10 * time.Minute)},
+			text:    `{{ if gt .AgeSeconds 300 }}low{{ else }}high{{ end }}`,
+			want:    "low",
+		},
+		{
+			name:    "future_timestamp",
+			release: Release{Timestamp: now.Add(5 * time.Second)},
+			text:    "{{ .AgeSeconds }}",
+			want:    "0",
+		},
+		{
+			name:    "missing_timestamp",
+			release: Release{},
+			text:    "[{{ .AnnouncedAt }}] {{ .AgeSeconds }}",
+			want:    "[] 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMacro(tt.release, clock)
+
+			got, err := m.Parse(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, 18, m.CurrentHour)
+		})
+	}
+}
+
+func TestMacros_Weekday(t *testing.T) {
+	// 2023-11-04 is a Saturday
+	clock := func() time.Time { return time.Date(2023, 11, 4, 18, 30, 0, 0, time.UTC) }
+
+	m := newMacro(Release{TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP"}, clock)
+	assert.Equal(t, "Saturday", m.Weekday)
+
+	got, err := m.Parse("/grabs/{{ .Weekday }}/")
+	assert.NoError(t, err)
+	assert.Equal(t, "/grabs/Saturday/", got)
+
+	got, err = m.Parse(`/grabs/{{ .Weekday | lower }}/{{ .CurrentYear }}-{{ printf "%02d" .CurrentMonth }}-{{ printf "%02d" .CurrentDay }}/`)
+	assert.NoError(t, err)
+	assert.Equal(t, "/grabs/saturday/2023-11-04/", got)
+
+	// weekday is taken from the clock, not fixed to a day
+	m = newMacro(Release{}, func() time.Time { return time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC) })
+	assert.Equal(t, "Monday", m.Weekday)
+}
+
+func TestMacros_SeasonEpisode(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrentName string
+		want        string
+	}{
+		{name: "single_episode", torrentName: "That.Show.S01E05.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: "S01E05|01|05|1|5|S01E05"},
+		{name: "single_episode_x", torrentName: "That.Show.2x05.720p.HDTV.x264-GROUP", want: "S02E05|02|05|2|5|S02E05"},
+		{name: "multi_episode", torrentName: "That.Show.S01E05E06.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: "S01E05-E06|01|05|1|5|S01E05"},
+		{name: "multi_episode_range", torrentName: "That.Show.S03E01-E03.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: "S03E01-E03|03|01|3|1|S03E01"},
+		{name: "multi_episode_dash", torrentName: "That.Show.S03E09-10.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: "S03E09-E10|03|09|3|9|S03E09"},
+		{name: "full_season", torrentName: "That.Show.S02.1080p.BluRay.x264-GROUP", want: "S02|02||2|0|S02E00"},
+		{name: "movie", torrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", want: "|||0|0|S00E00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Release{}
+			r.ParseString(tt.torrentName)
+
+			got, err := NewMacro(r).Parse(`{{ .SeasonEpisode }}|{{ .SeasonPadded }}|{{ .EpisodePadded }}|{{ .Season }}|{{ .Episode }}|{{ printf "S%02dE%02d" .Season .Episode }}`)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacros_Arithmetic(t *testing.T) {
+	r := Release{TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", Size: 1500000000, Year: 2023, FreeleechPercent: 50}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{name: "size_in_gb", text: `{{ div .Size 1e9 }}`, want: "1.5"},
+		{name: "size_in_gb_int", text: `{{ div .Size 1000000000 }}`, want: "1"},
+		{name: "size_in_gb_printf", text: `{{ div .Size 1073741824.0 | printf "%.2f" }}`, want: "1.40"},
+		{name: "seed_time", text: `{{ mul 24 60 60 }}`, want: "86400"},
+		{name: "add", text: `{{ add .Year 1 }}`, want: "2024"},
+		{name: "add_float", text: `{{ add .FreeleechPercent 0.5 }}`, want: "50.5"},
+		{name: "sub", text: `{{ sub .CurrentYear .CurrentYear }}`, want: "0"},
+		{name: "nested", text: `{{ div (mul .FreeleechPercent .Size) 100 }}`, want: "750000000"},
+		{name: "string_number", text: `{{ add "2" 3 }}`, want: "5"},
+		{name: "div_by_zero", text: `{{ div .Size 0 }}`, wantErr: "div: division by zero"},
+		{name: "div_by_zero_float", text: `{{ div .Size 0.0 }}`, wantErr: "div: division by zero"},
+		{name: "not_a_number", text: `{{ add .TorrentName 1 }}`, wantErr: "add: not a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMacro(r).Parse(tt.text)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacros_IsMultiDisc(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrentName string
+		want        string
+	}{
+		{name: "multi_cd", torrentName: "Artist-Album-(Deluxe_Edition)-3CD-2019-GRP", want: "box set"},
+		{name: "cd_numbered", torrentName: "Artist - Album CD1 CD2 FLAC", want: "box set"},
+		{name: "disc_numbered", torrentName: "Artist - Album (Disc 1) FLAC", want: "box set"},
+		{name: "single_album", torrentName: "Artist - Album 2021 FLAC", want: "album"},
+		{name: "single_cd", torrentName: "Artist - Greatest Hits 1999 CD FLAC", want: "album"},
+		{name: "web", torrentName: "Artist-Album-WEB-FLAC-2021-GRP", want: "album"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Release{}
+			r.ParseString(tt.torrentName)
+
+			got, err := NewMacro(r).Parse(`{{ if .IsMultiDisc }}box set{{ else }}album{{ end }}`)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacros_ParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		release Release
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			release: Release{TorrentName: "This movie 2021"},
+			text:    "",
+			want:    nil,
+		},
+		{
+			name:    "name_with_spaces_unquoted",
+			release: Release{TorrentName: "This movie 2021", Indexer: "mock1"},
+			text:    "--name {{ .TorrentName }} --indexer {{ .Indexer }}",
+			want:    []string{"--name", "This movie 2021", "--indexer", "mock1"},
+		},
+		{
+			name:    "name_with_spaces_quoted",
+			release: Release{TorrentName: "This movie 2021"},
+			text:    `--name "{{ .TorrentName }}"`,
+			want:    []string{"--name", "This movie 2021"},
+		},
+		{
+			name:    "name_with_embedded_double_quotes",
+			release: Release{TorrentName: `This "movie" 2021`},
+			text:    `--name "{{ .TorrentName }}"`,
+			want:    []string{"--name", `This "movie" 2021`},
+		},
+		{
+			name:    "name_with_embedded_single_quotes",
+			release: Release{TorrentName: `Sally's Movie 2021`},
+			text:    `--name '{{ .TorrentName }}'`,
+			want:    []string{"--name", `Sally's Movie 2021`},
+		},
+		{
+			name:    "name_with_backticks",
+			release: Release{TorrentName: "This `movie` 2021"},
+			text:    `{{ .TorrentName }}`,
+			want:    []string{"This `movie` 2021"},
+		},
+		{
+			name:    "json_data",
+			release: Release{TorrentName: `Sally's "Mall" S04E29`},
+			text:    `--data '{"release":"{{ .TorrentName }}"}' http://localhost:3000/api/release`,
+			want:    []string{"--data", `{"release":"Sally's "Mall" S04E29"}`, "http://localhost:3000/api/release"},
+		},
+		{
+			name:    "template_with_quoted_func_args",
+			release: Release{TorrentName: "This movie 2021", Categories: []string{"Movies", "HD"}},
+			text:    `--categories {{ join ", " .Categories }} "{{ .TorrentName }}"`,
+			want:    []string{"--categories", "Movies, HD", "This movie 2021"},
+		},
+		{
+			name:    "template_spanning_args",
+			release: Release{TorrentName: "This movie 2021", HDR: []string{"DV"}},
+			text:    `{{ if .HDR }}--hdr {{ .HDR }}{{ end }}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMacro(tt.release)
+			got, err := m.ParseArgs(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacros_ParseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		release Release
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no_macros",
+			release: Release{Indexer: "mock"},
+			text:    "http://localhost:3000/api/release",
+			want:    "http://localhost:3000/api/release",
+		},
+		{
+			name:    "indexer_in_path",
+			release: Release{Indexer: "mock indexer/one"},
+			text:    "http://localhost:3000/api/{{ .Indexer }}/release",
+			want:    "http://localhost:3000/api/mock%20indexer%2Fone/release",
+		},
+		{
+			name:    "values_in_query",
+			release: Release{TorrentName: "Sally's Movie & Friends 2021", Category: "movies/hd"},
+			text:    "http://localhost:3000/api/{{ .Category }}?name={{ .TorrentName }}&cat={{ .Category }}",
+			want:    "http://localhost:3000/api/movies%2Fhd?name=Sally%27s+Movie+%26+Friends+2021&cat=movies%2Fhd",
+		},
+		{
+			name:    "host_not_escaped",
+			release: Release{Indexer: "mock"},
+			text:    "http://{{ .Indexer }}.localhost:3000/api",
+			want:    "http://mock.localhost:3000/api",
+		},
+		{
+			name:    "already_escaped",
+			release: Release{TorrentName: "This movie 2021"},
+			text:    "http://localhost:3000/api?name={{ .TorrentName | urlquery }}",
+			want:    "http://localhost:3000/api?name=This+movie+2021",
+		},
+		{
+			name:    "already_path_escaped",
+			release: Release{TorrentName: "This movie/2021"},
+			text:    "http://localhost:3000/api/{{ urlpath .TorrentName }}",
+			want:    "http://localhost:3000/api/This%20movie%2F2021",
+		},
+		{
+			name:    "if_in_path",
+			release: Release{Indexer: "mock indexer/one"},
+			text:    "http://localhost:3000/{{ if .Indexer }}{{ .Indexer }}{{ else }}none{{ end }}/release",
+			want:    "http://localhost:3000/mock%20indexer%2Fone/release",
+		},
+		{
+			name:    "if_in_query",
+			release: Release{TorrentName: "Sally's Movie & Friends 2021"},
+			text:    "http://localhost:3000/api?{{ if .TorrentName }}name={{ .TorrentName }}{{ end }}",
+			want:    "http://localhost:3000/api?name=Sally%27s+Movie+%26+Friends+2021",
+		},
+		{
+			name:    "range_in_query",
+			release: Release{Categories: []string{"tv/hd", "a&b"}},
+			text:    "http://localhost:3000/api?{{ range .Categories }}cat={{ . }}&{{ end }}",
+			want:    "http://localhost:3000/api?cat=tv%2Fhd&cat=a%26b&",
+		},
+		{
+			name:    "variable_declaration",
+			release: Release{Indexer: "mock indexer"},
+			text:    "http://localhost:3000/{{ $indexer := .Indexer }}{{ $indexer }}",
+			want:    "http://localhost:3000/mock%20indexer",
+		},
+		{
+			name:    "invalid_template",
+			release: Release{Indexer: "mock"},
+			text:    "http://localhost:3000/{{ if .Indexer }}{{ .Indexer }}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMacro(tt.release)
+			got, err := m.ParseURL(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "That.Show.S01E01.1080p.WEB-DL-GROUP", want: "That.Show.S01E01.1080p.WEB-DL-GROUP"},
+		{name: "separators", in: "../../etc/passwd", want: ".._.._etc_passwd"},
+		{name: "windows", in: `..\C:\evil`, want: ".._C__evil"},
+		{name: "dot", in: ".", want: "_"},
+		{name: "dotdot", in: "..", want: "__"},
+		{name: "control", in: "name\x00with\nnewline", want: "name_with_newline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeFilename(tt.in))
+		})
+	}
+}
+
+func TestSanitizePath(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		os   ActionPathOS
+		want string
+	}{
+		{name: "posix_slash", in: "Movies/HD", os: ActionPathOSPosix, want: "Movies_HD"},
+		{name: "posix_colon", in: "TV: Anime", os: ActionPathOSPosix, want: "TV: Anime"},
+		{name: "posix_trailing_dots", in: "Misc...", os: ActionPathOSPosix, want: "Misc..."},
+		{name: "posix_dotdot", in: "..", os: ActionPathOSPosix, want: "__"},
+		{name: "posix_default", in: "Movies/HD", os: "", want: "Movies_HD"},
+		{name: "windows_slash", in: `Movies/HD\Remux`, os: ActionPathOSWindows, want: "Movies_HD_Remux"},
+		{name: "windows_colon", in: "TV: Anime", os: ActionPathOSWindows, want: "TV_ Anime"},
+		{name: "windows_trailing_dots", in: "Misc... ", os: ActionPathOSWindows, want: "Misc"},
+		{name: "windows_only_dots", in: "..", os: ActionPathOSWindows, want: "_"},
+		{name: "windows_reserved", in: "con", os: ActionPathOSWindows, want: "_con"},
+		{name: "windows_reserved_ext", in: "LPT1.txt", os: ActionPathOSWindows, want: "_LPT1.txt"},
+		{name: "windows_empty", in: "", os: ActionPathOSWindows, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizePath(tt.in, tt.os))
+		})
+	}
+}
+
+func TestAction_ParseMacros_sanitizePath(t *testing.T) {
+	release := &Release{TorrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", Indexer: "mock", Category: "Movies/HD: Remux.", TorrentTmpFile: "/tmp/file"}
+
+	tests := []struct {
+		name            string
+		os              ActionPathOS
+		wantSavePath    string
+		wantWatchFolder string
+	}{
+		{name: "posix", os: ActionPathOSPosix, wantSavePath: "/data/mock/Movies_HD_ Remux.", wantWatchFolder: "/watch/Movies_HD_ Remux."},
+		{name: "windows", os: ActionPathOSWindows, wantSavePath: "D:/data/mock/Movies_HD_ Remux", wantWatchFolder: "/watch/Movies_HD_ Remux"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			savePath := "/data/{{ .Indexer }}/{{ sanitizePath .Category }}"
+			if tt.os == ActionPathOSWindows {
+				savePath = "D:" + savePath
+			}
+
+			action := &Action{Type: ActionTypeQbittorrent, PathOS: tt.os, SavePath: savePath}
+			assert.NoError(t, action.ParseMacros(release))
+			assert.Equal(t, tt.wantSavePath, action.SavePath)
+
+			// values are already path safe, sanitizePath still applies the OS rules
+			watch := &Action{Type: ActionTypeExec, PathOS: tt.os, WatchFolder: "/watch/{{ sanitizePath .Category }}"}
+			assert.NoError(t, watch.ParseMacros(release))
+			assert.Equal(t, tt.wantWatchFolder, watch.WatchFolder)
+		})
+	}
+}
+
+func TestAction_ParseMacros_pathTraversal(t *testing.T) {
+	torrentFile := filepath.Join(t.TempDir(), "release.torrent")
+	assert.NoError(t, os.WriteFile(torrentFile, []byte("d4:infod4:name4:testee"), 0644))
+
+	vars := map[string]string{"genre": "../../root/.ssh"}
+
+	tests := []struct {
+		name   string
+		action Action
+		field  func(a *Action) string
+	}{
+		{
+			name:   "watch_folder",
+			action: Action{Type: ActionTypeWatchFolder, WatchFolder: `/watch/{{ .Uploader }}/{{ .AudioChannels }}/{{ index .Vars "genre" }}`},
+			field:  func(a *Action) string { return a.WatchFolder },
+		},
+		{
+			name:   "save_path",
+			action: Action{Type: ActionTypeQbittorrent, SavePath: `/data/{{ .Uploader }}/{{ .AudioChannels }}/{{ index .Vars "genre" }}`},
+			field:  func(a *Action) string { return a.SavePath },
+		},
+		{
+			name:   "archive_path",
+			action: Action{Type: ActionTypeArchiveTorrent, ArchivePath: `/archive/{{ .Uploader }}/{{ .AudioChannels }}/{{ index .Vars "genre" }}`},
+			field:  func(a *Action) string { return a.ArchivePath },
+		},
+		{
+			name:   "move_completed_path",
+			action: Action{Type: ActionTypeDelugeV2, MoveCompleted: true, MoveCompletedPath: `/done/{{ .Uploader }}/{{ .AudioChannels }}/{{ index .Vars "genre" }}`},
+			field:  func(a *Action) string { return a.MoveCompletedPath },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &Release{
+				TorrentName:    "That.Movie.2023.1080p.BluRay.x264-GROUP",
+				Indexer:        "mock",
+				TorrentTmpFile: torrentFile,
+				Uploader:       "../../../etc",
+				AudioChannels:  "../..",
+				Vars:           vars,
+			}
+
+			action := tt.action
+			assert.NoError(t, action.ParseMacros(release))
+
+			got := tt.field(&action)
+			assert.Len(t, strings.Split(got, "/"), 5, got)
+			assert.NotContains(t, strings.Split(got, "/"), "..")
+		})
+	}
+
+	// the vars of the release are left untouched
+	assert.Equal(t, "../../root/.ssh", vars["genre"])
+}
+
+func TestMacros_Languages(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrentName string
+		text        string
+		want        string
+	}{
+		{name: "languages", torrentName: "Le.Film.2023.MULTi.1080p.BluRay.x264-GROUP", text: "/movies/{{ default \"VO\" .Languages }}", want: "/movies/MULTi"},
+		{name: "subtitles", torrentName: "Le.Film.2023.VOSTFR.1080p.WEB.H264-GROUP", text: "/movies/{{ default \"VO\" .Languages }}{{ if .Subtitles }}/{{ .Subtitles }}{{ end }}", want: "/movies/VO/VOSTFR"},
+		{name: "none", torrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", text: "/movies/{{ default \"VO\" .Languages }}{{ if .Subtitles }}/{{ .Subtitles }}{{ end }}", want: "/movies/VO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Release{}
+			r.ParseString(tt.torrentName)
+
+			got, err := NewMacro(r).Parse(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacros_HDRAudio(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrentName string
+		text        string
+		want        string
+	}{
+		{name: "dv_hdr10_atmos", torrentName: "That.Movie.2023.2160p.UHD.BluRay.DV.HDR10.TrueHD.Atmos.7.1.x265-GROUP", text: "{{ .HDR }} - {{ .Audio }} {{ .AudioChannels }}", want: "DV, HDR10 - TrueHD, Atmos 7.1"},
+		{name: "path_by_format", torrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.Atmos.DV.H.265-GROUP", text: "/tv/{{ if contains \"DV\" .HDR }}dv{{ else }}sdr{{ end }}{{ if contains \"Atmos\" .Audio }}/atmos{{ end }}", want: "/tv/dv/atmos"},
+		{name: "sdr", torrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP", text: "/movies/{{ default \"SDR\" .HDR }}", want: "/movies/SDR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Release{}
+			r.ParseString(tt.torrentName)
+
+			got, err := NewMacro(r).Parse(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacros_DownloadURL(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := Release{
+		ID:          42,
+		DownloadURL: "https://tracker.example/torrent/download/123?passkey=secretkey",
+	}
+	r.ProxiedDownloadURL = ProxiedDownloadURL("http://127.0.0.1:7474", "session-secret", r.ID, expires)
+
+	got, err := NewMacro(r).Parse("{{ .DownloadURL }} {{ .ProxiedDownloadURL }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://tracker.example/torrent/download/123?passkey=secretkey http://127.0.0.1:7474/api/release/42/download?expires=1893456000&signature="+signReleaseDownload("session-secret", 42, expires.Unix()), got)
+}
+
+func TestMacros_TorrentDataRawBytesGzipB64(t *testing.T) {
+	torrentFile := "testdata/single-tracker.torrent"
+	raw, err := os.ReadFile(torrentFile)
+	assert.NoError(t, err)
+
+	// the torrent file is read by the same gate as TorrentDataRawBytes
+	a := Action{Type: ActionTypeWebhook, WebhookData: `{"torrent":"{{ .TorrentDataRawBytesGzipB64 }}"}`}
+	r := Release{TorrentName: "Movie.2023.1080p.BluRay.x264-GROUP", TorrentTmpFile: torrentFile}
+	assert.NoError(t, a.ParseMacros(&r))
+
+	var payload struct {
+		Torrent string `json:"torrent"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(a.WebhookData), &payload))
+
+	compressed, err := base64.StdEncoding.DecodeString(payload.Torrent)
+	assert.NoError(t, err)
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	assert.NoError(t, err)
+
+	got, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, got)
+
+	empty, err := NewMacro(Release{}).Parse("{{ .TorrentDataRawBytesGzipB64 }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "", empty)
+}
+
+func TestMacros_ReleaseGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrentName string
+		wantGroup   string
+		wantName    string
+	}{
+		{name: "scene_tv", torrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", wantGroup: "GROUP", wantName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264"},
+		{name: "group_with_digits", torrentName: "That.Movie.2023.1080p.BluRay.x264-D3G", wantGroup: "D3G", wantName: "That.Movie.2023.1080p.BluRay.x264"},
+		{name: "group_leading_digit", torrentName: "That Movie 2023 1080p BluRay x264-4FR", wantGroup: "4FR", wantName: "That Movie 2023 1080p BluRay x264"},
+		{name: "trailing_tag", torrentName: "That.Movie.2023.1080p.WEB-DL.DD5.1.H.264-NTb[rarbg]", wantGroup: "NTb", wantName: "That.Movie.2023.1080p.WEB-DL.DD5.1.H.264"},
+		{name: "hyphenated_name", torrentName: "Spider-Man.No.Way.Home.2021.2160p.WEB-DL.DDP5.1.Atmos.HDR.HEVC-GRP", wantGroup: "GRP", wantName: "Spider-Man.No.Way.Home.2021.2160p.WEB-DL.DDP5.1.Atmos.HDR.HEVC"},
+		{name: "no_group", torrentName: "That.Show.S01E01.720p.HDTV.x264", wantGroup: "", wantName: "That.Show.S01E01.720p.HDTV.x264"},
+		{name: "no_group_web_dl", torrentName: "That.Show.S01.1080p.WEB-DL", wantGroup: "", wantName: "That.Show.S01.1080p.WEB-DL"},
+		{name: "music", torrentName: "Artist - Album (2020) [FLAC] [WEB]", wantGroup: "", wantName: "Artist - Album (2020) [FLAC] [WEB]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// release group is parsed from the name even when the release wasn't
+			m := NewMacro(Release{TorrentName: tt.torrentName})
+			assert.Equal(t, tt.wantGroup, m.ReleaseGroup)
+
+			got, err := m.Parse("{{ stripGroup .TorrentName }}")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantName, got)
+		})
+	}
+
+	r := Release{}
+	r.ParseString("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP")
+
+	got, err := NewMacro(r).Parse("/tv/{{ default \"unknown\" .ReleaseGroup }}/{{ stripGroup .TorrentName }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "/tv/GROUP/That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264", got)
+}
+
+func TestMacro_Parse_stringHelpers(t *testing.T) {
+	r := Release{Category: "TV/HD", Resolution: "1080p"}
+	r.ParseString("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-group")
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "lower", text: "/data/{{ lower .Category }}", want: "/data/tv/hd"},
+		{name: "upper", text: "{{ upper .Resolution }}", want: "1080P"},
+		{name: "title", text: "{{ title .ReleaseGroup }}", want: "Group"},
+		{name: "regex_replace_dots", text: `{{ regexReplace .TorrentName "\\." " " }}`, want: "That Show S01E01 1080p WEB-DL DDP5 1 H 264-group"},
+		{name: "regex_replace_submatch", text: `{{ regexReplace .TorrentName "^(.+)\\.S(\\d+)E\\d+.*$" "$1/Season $2" }}`, want: "That.Show/Season 01"},
+		{name: "regex_replace_in_pipeline", text: `{{ regexReplace (stripGroup .TorrentName) "[.-]" "_" | lower }}`, want: "that_show_s01e01_1080p_web_dl_ddp5_1_h_264"},
+		{name: "invalid_regex", text: `{{ regexReplace .TorrentName "(" " " }}`, wantErr: true},
+		{name: "invalid_regex_in_if", text: `{{ if .Resolution }}{{ regexReplace .TorrentName "[" "" }}{{ end }}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMacro(r).Parse(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "could parse macro template")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMacro_Parse_urlHelpers(t *testing.T) {
+	r := Release{TorrentName: "That Show & Friends/S01", Category: "tv/hd & more"}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "urlquery", text: "{{ urlquery .Category }}", want: "tv%2Fhd+%26+more"},
+		{name: "urlpath", text: "{{ urlpath .TorrentName }}", want: "That%20Show%20&%20Friends%2FS01"},
+		{name: "urlpath_in_pipeline", text: "{{ .TorrentName | lower | urlpath }}", want: "that%20show%20&%20friends%2Fs01"},
+		{name: "webhook_data", text: `{"url":"http://localhost/{{ urlpath .TorrentName }}?cat={{ urlquery .Category }}"}`, want: `{"url":"http://localhost/That%20Show%20&%20Friends%2FS01?cat=tv%2Fhd+%26+more"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMacro(r).Parse(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_ParseMacros_webhookURLHelpers(t *testing.T) {
+	a := Action{
+		Name:        "webhook",
+		Type:        ActionTypeWebhook,
+		WebhookHost: "http://localhost:3000/{{ if .Category }}{{ urlpath .Category }}{{ end }}?name={{ urlquery .TorrentName }}",
+		WebhookData: `{"name":"{{ urlquery .TorrentName }}"}`,
+	}
+
+	err := a.ParseMacros(&Release{TorrentName: "That Show & Friends/S01", Category: "tv/hd"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:3000/tv%2Fhd?name=That+Show+%26+Friends%2FS01", a.WebhookHost)
+	assert.Equal(t, `{"name":"That+Show+%26+Friends%2FS01"}`, a.WebhookData)
+}
@@ -15,35 +15,87 @@ type NotificationRepo interface {
 	Store(ctx context.Context, notification Notification) (*Notification, error)
 	Update(ctx context.Context, notification Notification) (*Notification, error)
 	Delete(ctx context.Context, notificationID int) error
+	StoreLog(ctx context.Context, log *NotificationLog) error
+	FindLogs(ctx context.Context, notificationID int, limit int) ([]NotificationLog, error)
 }
 
 type NotificationSender interface {
-	Send(event NotificationEvent, payload NotificationPayload) error
+	Send(event NotificationEvent, payload NotificationPayload) (NotificationResult, error)
 	CanSend(event NotificationEvent) bool
 }
 
+// NotificationResult is what the target returned for a sent message.
+// MessageID is empty for targets that don't return one.
+type NotificationResult struct {
+	MessageID string
+	// Suppressed is set when the message was dropped or held back by a rate limit or throttle
+	Suppressed bool
+}
+
+// NotificationLog records a message sent to a notification target
+type NotificationLog struct {
+	ID             int64             `json:"id"`
+	NotificationID int               `json:"notification_id"`
+	Event          NotificationEvent `json:"event"`
+	MessageID      string            `json:"message_id"`
+	Error          string            `json:"error"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
 type Notification struct {
-	ID        int              `json:"id"`
-	Name      string           `json:"name"`
-	Type      NotificationType `json:"type"`
-	Enabled   bool             `json:"enabled"`
-	Events    []string         `json:"events"`
-	Token     string           `json:"token"`
-	APIKey    string           `json:"api_key"`
-	Webhook   string           `json:"webhook"`
-	Title     string           `json:"title"`
-	Icon      string           `json:"icon"`
-	Username  string           `json:"username"`
-	Host      string           `json:"host"`
-	Password  string           `json:"password"`
-	Channel   string           `json:"channel"`
-	Rooms     string           `json:"rooms"`
-	Targets   string           `json:"targets"`
-	Devices   string           `json:"devices"`
-	Priority  int32            `json:"priority"`
-	Topic     string           `json:"topic"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	Type            NotificationType  `json:"type"`
+	Enabled         bool              `json:"enabled"`
+	Events          []string          `json:"events"`
+	Token           string            `json:"token"`
+	APIKey          string            `json:"api_key"`
+	Webhook         string            `json:"webhook"`
+	Title           string            `json:"title"`
+	Icon            string            `json:"icon"`
+	Username        string            `json:"username"`
+	Host            string            `json:"host"`
+	Password        string            `json:"password"`
+	Channel         string            `json:"channel"`
+	Rooms           string            `json:"rooms"`
+	Targets         string            `json:"targets"`
+	Devices         string            `json:"devices"`
+	Priority        int32             `json:"priority"`
+	Topic           string            `json:"topic"`
+	RateLimit       int               `json:"rate_limit"`
+	DispatchOrder   int               `json:"dispatch_order"`
+	MinInterval     int               `json:"min_interval"`
+	MaxPerHour      int               `json:"max_per_hour"`
+	Timeout         int               `json:"timeout"`
+	SettlePeriod    int               `json:"settle_period"`
+	SuppressSkipped bool              `json:"suppress_skipped"`
+	EmailFrom       string            `json:"email_from"`
+	UserAgent       string            `json:"user_agent"`
+	ImageURL        string            `json:"image_url,omitempty"`
+	EventChannels   map[string]string `json:"event_channels,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// DefaultNotificationTimeout is how long senders wait for the target when the notification has no timeout set
+const DefaultNotificationTimeout = 30 * time.Second
+
+// SendTimeout returns the timeout of requests to the notification target
+func (n Notification) SendTimeout() time.Duration {
+	if n.Timeout > 0 {
+		return time.Duration(n.Timeout) * time.Second
+	}
+
+	return DefaultNotificationTimeout
+}
+
+// EventChannel returns the channel override for the event, or the fallback if none is set
+func (n Notification) EventChannel(event NotificationEvent, fallback string) string {
+	if channel := n.EventChannels[string(event)]; channel != "" {
+		return channel
+	}
+
+	return fallback
 }
 
 type NotificationPayload struct {
@@ -53,13 +105,17 @@ type NotificationPayload struct {
 	ReleaseName    string
 	Filter         string
 	Indexer        string
+	InfoURL        string // details page of the release on the tracker
+	Network        string
 	InfoHash       string
 	Size           uint64
 	Status         ReleasePushStatus
 	Action         string
 	ActionType     ActionType
 	ActionClient   string
+	ActionOutcome  ActionResultStatus // outcome of the action run, empty for events not sent by an action
 	Rejections     []string
+	ActionStatuses []ReleaseActionStatus // outcome of every action that ran for the release and filter, set when there is more than one
 	Protocol       ReleaseProtocol       // torrent, usenet
 	Implementation ReleaseImplementation // irc, rss, api
 	Timestamp      time.Time
@@ -81,6 +137,8 @@ const (
 	NotificationTypeTelegram   NotificationType = "TELEGRAM"
 	NotificationTypeGotify     NotificationType = "GOTIFY"
 	NotificationTypeLunaSea    NotificationType = "LUNASEA"
+	NotificationTypeEmail      NotificationType = "EMAIL"
+	NotificationTypeTeams      NotificationType = "TEAMS"
 )
 
 type NotificationEvent string
@@ -92,11 +150,24 @@ const (
 	NotificationEventPushError          NotificationEvent = "PUSH_ERROR"
 	NotificationEventIRCDisconnected    NotificationEvent = "IRC_DISCONNECTED"
 	NotificationEventIRCReconnected     NotificationEvent = "IRC_RECONNECTED"
+	NotificationEventIRCAuthFailed      NotificationEvent = "IRC_AUTH_FAILED"
+	NotificationEventReleaseUpgrade     NotificationEvent = "RELEASE_UPGRADE"
+	NotificationEventTorrentStalled     NotificationEvent = "TORRENT_STALLED"
+	NotificationEventActionDisabled     NotificationEvent = "ACTION_DISABLED"
+	NotificationEventReleasePending     NotificationEvent = "RELEASE_PENDING"
 	NotificationEventTest               NotificationEvent = "TEST"
 )
 
 type NotificationEventArr []NotificationEvent
 
+// NotificationDispatchMode decides what happens with the remaining senders when one fails
+type NotificationDispatchMode string
+
+const (
+	NotificationDispatchBestEffort NotificationDispatchMode = "best-effort"
+	NotificationDispatchFailFast   NotificationDispatchMode = "fail-fast"
+)
+
 type NotificationQueryParams struct {
 	Limit   uint64
 	Offset  uint64
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type PendingReleaseRepo interface {
+	List(ctx context.Context) ([]PendingRelease, error)
+	FindByID(ctx context.Context, id int) (*PendingRelease, error)
+	Store(ctx context.Context, pending *PendingRelease) error
+	Delete(ctx context.Context, id int) error
+}
+
+// PendingRelease is a matched release of a filter that requires approval.
+// The actions of the filter run once it is approved, rejecting it discards them.
+type PendingRelease struct {
+	ID          int       `json:"id"`
+	ReleaseID   int64     `json:"release_id"`
+	FilterID    int       `json:"filter_id"`
+	TorrentName string    `json:"torrent_name"`
+	Indexer     string    `json:"indexer"`
+	FilterName  string    `json:"filter_name"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// MagnetURI and Vars aren't stored with the release but the actions need them
+	MagnetURI string            `json:"-"`
+	Vars      map[string]string `json:"-"`
+}
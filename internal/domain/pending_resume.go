@@ -0,0 +1,26 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type PendingResumeRepo interface {
+	List(ctx context.Context) ([]PendingResume, error)
+	Store(ctx context.Context, resume *PendingResume) error
+	Delete(ctx context.Context, id int) error
+}
+
+// PendingResume is a torrent added paused that is resumed in the client at ResumeAt.
+// They are stored so resumes still happen after a restart.
+type PendingResume struct {
+	ID          int       `json:"id"`
+	ClientID    int32     `json:"client_id"`
+	ActionID    int       `json:"action_id"`
+	TorrentHash string    `json:"torrent_hash"`
+	ResumeAt    time.Time `json:"resume_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
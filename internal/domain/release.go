@@ -37,6 +37,7 @@ type ReleaseRepo interface {
 	Stats(ctx context.Context) (*ReleaseStats, error)
 	Delete(ctx context.Context, req *DeleteReleaseRequest) error
 	CanDownloadShow(ctx context.Context, title string, season int, episode int) (bool, error)
+	FindGrabbedEpisodes(ctx context.Context, title string, season int, episode int) ([]*Release, error)
 
 	GetActionStatus(ctx context.Context, req *GetReleaseActionStatusRequest) (*ReleaseActionStatus, error)
 	StoreReleaseActionStatus(ctx context.Context, status *ReleaseActionStatus) error
@@ -53,12 +54,15 @@ type Release struct {
 	Timestamp                   time.Time             `json:"timestamp"`
 	InfoURL                     string                `json:"info_url"`
 	DownloadURL                 string                `json:"download_url"`
+	ProxiedDownloadURL          string                `json:"-"` // signed url to download the torrent through autobrr
 	MagnetURI                   string                `json:"-"`
 	GroupID                     string                `json:"group_id"`
 	TorrentID                   string                `json:"torrent_id"`
 	TorrentTmpFile              string                `json:"-"`
 	TorrentDataRawBytes         []byte                `json:"-"`
 	TorrentHash                 string                `json:"-"`
+	ClientTorrentID             string                `json:"-"` // id assigned by the download client after a successful add
+	TrackerCount                int                   `json:"-"`
 	TorrentName                 string                `json:"torrent_name"` // full release name
 	Size                        uint64                `json:"size"`
 	Title                       string                `json:"title"` // Parsed title
@@ -67,6 +71,7 @@ type Release struct {
 	Categories                  []string              `json:"categories,omitempty"`
 	Season                      int                   `json:"season"`
 	Episode                     int                   `json:"episode"`
+	LastEpisode                 int                   `json:"-"` // last episode of a multi-episode release
 	Year                        int                   `json:"year"`
 	Resolution                  string                `json:"resolution"`
 	Source                      string                `json:"source"`
@@ -78,22 +83,31 @@ type Release struct {
 	Group                       string                `json:"group"`
 	Region                      string                `json:"-"`
 	Language                    []string              `json:"-"`
+	Languages                   []string              `json:"-"`
+	Subtitles                   []string              `json:"-"`
 	Proper                      bool                  `json:"proper"`
 	Repack                      bool                  `json:"repack"`
 	Website                     string                `json:"website"`
 	Artists                     string                `json:"-"`
 	Type                        string                `json:"type"` // Album,Single,EP
+	IsMultiDisc                 bool                  `json:"-"`
 	LogScore                    int                   `json:"-"`
 	Origin                      string                `json:"origin"` // P2P, Internal
+	IsScene                     bool                  `json:"-"`
 	Tags                        []string              `json:"-"`
 	ReleaseTags                 string                `json:"-"`
 	Freeleech                   bool                  `json:"-"`
 	FreeleechPercent            int                   `json:"-"`
 	Bonus                       []string              `json:"-"`
 	Uploader                    string                `json:"uploader"`
+	Seeders                     int                   `json:"-"`
+	Leechers                    int                   `json:"-"`
+	HasSeeders                  bool                  `json:"-"`
+	Vars                        map[string]string     `json:"-"`
 	PreTime                     string                `json:"pre_time"`
 	Other                       []string              `json:"-"`
 	RawCookie                   string                `json:"-"`
+	DownloadRetry               DownloadRetryPolicy   `json:"-"`
 	AdditionalSizeCheckRequired bool                  `json:"-"`
 	FilterID                    int                   `json:"-"`
 	Filter                      *Filter               `json:"-"`
@@ -160,6 +174,7 @@ const (
 	ReleasePushStatusApproved ReleasePushStatus = "PUSH_APPROVED"
 	ReleasePushStatusRejected ReleasePushStatus = "PUSH_REJECTED"
 	ReleasePushStatusErr      ReleasePushStatus = "PUSH_ERROR"
+	ReleasePushStatusTimeout  ReleasePushStatus = "PUSH_TIMEOUT"
 )
 
 func (r ReleasePushStatus) String() string {
@@ -172,6 +187,8 @@ func (r ReleasePushStatus) String() string {
 		return "Rejected"
 	case ReleasePushStatusErr:
 		return "Error"
+	case ReleasePushStatusTimeout:
+		return "Timeout"
 	default:
 		return "Unknown"
 	}
@@ -187,6 +204,8 @@ func ValidReleasePushStatus(s string) bool {
 		return true
 	case string(ReleasePushStatusErr):
 		return true
+	case string(ReleasePushStatusTimeout):
+		return true
 	default:
 		return false
 	}
@@ -299,6 +318,8 @@ func (r *Release) ParseString(title string) {
 	r.Other = rel.Other
 	r.Artists = rel.Artist
 	r.Language = rel.Language
+	r.Languages, r.Subtitles = splitLanguageTags(rel.Language)
+	r.IsMultiDisc = isMultiDisc(rel.Disc)
 
 	if r.Title == "" {
 		r.Title = rel.Title
@@ -311,6 +332,17 @@ func (r *Release) ParseString(title string) {
 		r.Episode = rel.Episode
 	}
 
+	// rls doesn't parse multi-episode releases like S01E05E06
+	if season, episode, last := parseMultiEpisode(title); last > episode {
+		if r.Season == 0 {
+			r.Season = season
+		}
+		if r.Episode == 0 {
+			r.Episode = episode
+		}
+		r.LastEpisode = last
+	}
+
 	if r.Year == 0 {
 		r.Year = rel.Year
 	}
@@ -322,6 +354,57 @@ func (r *Release) ParseString(title string) {
 	r.ParseReleaseTagsString(r.ReleaseTags)
 }
 
+// multiEpisodeRegex matches S01E05E06, S01E05-E07 and S01E05-07, the last repetition captures the last episode
+var multiEpisodeRegex = regexp.MustCompile(`(?i)\bS(\d{1,4})E(\d{1,4})(?:(?:-?E|-)(\d{1,4})\b)+`)
+
+// parseMultiEpisode returns the season and the first and last episode of a multi-episode release name
+func parseMultiEpisode(title string) (season int, episode int, last int) {
+	m := multiEpisodeRegex.FindStringSubmatch(title)
+	if m == nil {
+		return 0, 0, 0
+	}
+
+	season, _ = strconv.Atoi(m[1])
+	episode, _ = strconv.Atoi(m[2])
+	last, _ = strconv.Atoi(m[3])
+
+	return season, episode, last
+}
+
+// isMultiDisc checks the parsed disc tag, either a disc count like 2x from 2CD
+// or a numbered disc like CD1 or D01 which is part of a set
+func isMultiDisc(disc string) bool {
+	if disc == "" {
+		return false
+	}
+
+	if count, found := strings.CutSuffix(disc, "x"); found {
+		n, err := strconv.Atoi(count)
+		return err == nil && n > 1
+	}
+
+	return true
+}
+
+// splitLanguageTags splits the parsed language tags into spoken languages like MULTi or FRENCH
+// and subtitle tags like VOSTFR, SUBBED or SWESUB
+func splitLanguageTags(tags []string) (languages []string, subtitles []string) {
+	for _, tag := range tags {
+		upper := strings.ToUpper(tag)
+
+		switch {
+		case upper == "UNSUBBED":
+			languages = append(languages, tag)
+		case strings.Contains(upper, "SUB"), strings.HasPrefix(upper, "VOST"), upper == "HARDCODED":
+			subtitles = append(subtitles, tag)
+		default:
+			languages = append(languages, tag)
+		}
+	}
+
+	return languages, subtitles
+}
+
 var ErrUnrecoverableError = errors.New("unrecoverable error")
 
 func (r *Release) ParseReleaseTagsString(tags string) {
@@ -375,6 +458,40 @@ func (r *Release) ParseSizeBytesString(size string) {
 	}
 }
 
+// DownloadRetryPolicy sets how torrent file downloads are retried on server errors and timeouts.
+// The delay doubles after every attempt up to the max delay.
+type DownloadRetryPolicy struct {
+	Attempts int
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// DefaultDownloadRetryPolicy returns the policy used when the config doesn't set one
+func DefaultDownloadRetryPolicy() DownloadRetryPolicy {
+	return DownloadRetryPolicy{
+		Attempts: 3,
+		Delay:    3 * time.Second,
+		MaxDelay: 30 * time.Second,
+	}
+}
+
+// withDefaults returns the policy with zero values set to the default
+func (p DownloadRetryPolicy) withDefaults() DownloadRetryPolicy {
+	def := DefaultDownloadRetryPolicy()
+
+	if p.Attempts <= 0 {
+		p.Attempts = def.Attempts
+	}
+	if p.Delay <= 0 {
+		p.Delay = def.Delay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = def.MaxDelay
+	}
+
+	return p
+}
+
 func (r *Release) DownloadTorrentFileCtx(ctx context.Context) error {
 	return r.downloadTorrentFile(ctx)
 }
@@ -430,6 +547,8 @@ func (r *Release) downloadTorrentFile(ctx context.Context) error {
 	}
 	defer tmpFile.Close()
 
+	policy := r.DownloadRetry.withDefaults()
+
 	errFunc := retry.Do(func() error {
 		// Get the data
 		resp, err := client.Do(req)
@@ -444,24 +563,29 @@ func (r *Release) downloadTorrentFile(ctx context.Context) error {
 			// Continue processing the response
 		//case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
 		//	// Handle redirect
-		//	return retry.Unrecoverable(errors.New("redirect encountered for torrent (%s) file (%s) - status code: %d - check indexer keys for %s", r.TorrentName, r.DownloadURL, resp.StatusCode, r.Indexer))
+		//	return retry.Unrecoverable(errors.New("redirect encountered for torrent (%s) file (%s) - status code: %d - check indexer keys for %s", r.TorrentName, RedactURL(r.DownloadURL), resp.StatusCode, r.Indexer))
 
 		case http.StatusUnauthorized, http.StatusForbidden:
-			return retry.Unrecoverable(errors.New("unrecoverable error downloading torrent (%s) file (%s) - status code: %d - check indexer keys for %s", r.TorrentName, r.DownloadURL, resp.StatusCode, r.Indexer))
+			return retry.Unrecoverable(errors.New("unrecoverable error downloading torrent (%s) file (%s) - status code: %d - check indexer keys for %s", r.TorrentName, RedactURL(r.DownloadURL), resp.StatusCode, r.Indexer))
 
 		case http.StatusMethodNotAllowed:
-			return retry.Unrecoverable(errors.New("unrecoverable error downloading torrent (%s) file (%s) from '%s' - status code: %d. Check if the request method is correct", r.TorrentName, r.DownloadURL, r.Indexer, resp.StatusCode))
+			return retry.Unrecoverable(errors.New("unrecoverable error downloading torrent (%s) file (%s) from '%s' - status code: %d. Check if the request method is correct", r.TorrentName, RedactURL(r.DownloadURL), r.Indexer, resp.StatusCode))
 
 		case http.StatusNotFound:
-			return errors.New("torrent %s not found on %s (%d) - retrying", r.TorrentName, r.Indexer, resp.StatusCode)
+			return retry.Unrecoverable(errors.New("torrent %s not found on %s (%d)", r.TorrentName, r.Indexer, resp.StatusCode))
 
-		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
-			return errors.New("server error (%d) encountered while downloading torrent (%s) file (%s) from '%s' - retrying", resp.StatusCode, r.TorrentName, r.DownloadURL, r.Indexer)
+		case http.StatusRequestTimeout:
+			return errors.New("request timeout (%d) encountered while downloading torrent (%s) file (%s) from '%s' - retrying", resp.StatusCode, r.TorrentName, RedactURL(r.DownloadURL), r.Indexer)
 
 		case http.StatusInternalServerError:
-			return errors.New("server error (%d) encountered while downloading torrent (%s) file (%s) - check indexer keys for %s", resp.StatusCode, r.TorrentName, r.DownloadURL, r.Indexer)
+			return errors.New("server error (%d) encountered while downloading torrent (%s) file (%s) - check indexer keys for %s", resp.StatusCode, r.TorrentName, RedactURL(r.DownloadURL), r.Indexer)
 
 		default:
+			// trackers under load respond with 502, 503 and friends, those are worth another try
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return errors.New("server error (%d) encountered while downloading torrent (%s) file (%s) from '%s' - retrying", resp.StatusCode, r.TorrentName, RedactURL(r.DownloadURL), r.Indexer)
+			}
+
 			return retry.Unrecoverable(errors.New("unexpected status code %d: check indexer keys for %s", resp.StatusCode, r.Indexer))
 		}
 
@@ -514,18 +638,77 @@ func (r *Release) downloadTorrentFile(ctx context.Context) error {
 
 		r.TorrentTmpFile = tmpFile.Name()
 		r.TorrentHash = meta.HashInfoBytes().String()
+		r.TrackerCount = len(meta.UpvertedAnnounceList().DistinctValues())
 		r.Size = uint64(torrentMetaInfo.TotalLength())
 
 		return nil
 	},
-		retry.Delay(time.Second*3),
-		retry.Attempts(3),
-		retry.MaxJitter(time.Second*1),
+		retry.Context(ctx),
+		retry.Attempts(uint(policy.Attempts)),
+		retry.Delay(policy.Delay),
+		retry.MaxDelay(policy.MaxDelay),
+		retry.MaxJitter(policy.Delay/3),
 	)
 
 	return errFunc
 }
 
+// ComputeInfoHash computes the v1 infohash from the bencoded info dict of the downloaded torrent file.
+// The result is stored as TorrentHash so it's only computed once.
+func (r *Release) ComputeInfoHash() error {
+	if r.TorrentHash != "" {
+		return nil
+	}
+
+	meta, err := r.loadMetaInfo()
+	if err != nil {
+		return err
+	}
+
+	r.TorrentHash = meta.HashInfoBytes().HexString()
+
+	return nil
+}
+
+// ComputeTrackerCount counts the distinct trackers in the announce list of the downloaded torrent file.
+func (r *Release) ComputeTrackerCount() error {
+	if r.TrackerCount > 0 {
+		return nil
+	}
+
+	meta, err := r.loadMetaInfo()
+	if err != nil {
+		return err
+	}
+
+	r.TrackerCount = len(meta.UpvertedAnnounceList().DistinctValues())
+
+	return nil
+}
+
+// loadMetaInfo loads the metainfo from the raw torrent bytes or the downloaded tmp file
+func (r *Release) loadMetaInfo() (*metainfo.MetaInfo, error) {
+	if len(r.TorrentDataRawBytes) > 0 {
+		meta, err := metainfo.Load(bytes.NewReader(r.TorrentDataRawBytes))
+		if err != nil {
+			return nil, errors.Wrap(err, "metainfo could not load torrent data: %s", r.TorrentName)
+		}
+
+		return meta, nil
+	}
+
+	if r.TorrentTmpFile == "" {
+		return nil, errors.New("torrent file not downloaded: %s", r.TorrentName)
+	}
+
+	meta, err := metainfo.LoadFromFile(r.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "metainfo could not load file: %s", r.TorrentTmpFile)
+	}
+
+	return meta, nil
+}
+
 func (r *Release) CleanupTemporaryFiles() {
 	if len(r.TorrentTmpFile) == 0 {
 		return
@@ -535,6 +718,31 @@ func (r *Release) CleanupTemporaryFiles() {
 	r.TorrentTmpFile = ""
 }
 
+var releaseResolutionRank = map[string]int{
+	"480p":  1,
+	"576p":  2,
+	"720p":  3,
+	"1080i": 4,
+	"1080p": 5,
+	"2160p": 6,
+	"4320p": 7,
+}
+
+var releaseSourceRank = map[string]int{
+	"HDTV":       1,
+	"DVD":        1,
+	"WEBRip":     2,
+	"WEB":        3,
+	"WEB-DL":     3,
+	"BluRay":     4,
+	"UHD.BluRay": 5,
+}
+
+// QualityRank orders releases by resolution and then source, unknown values rank lowest
+func (r *Release) QualityRank() int {
+	return releaseResolutionRank[r.Resolution]*10 + releaseSourceRank[r.Source]
+}
+
 // HasMagnetUri check uf MagnetURI is set or empty
 func (r *Release) HasMagnetUri() bool {
 	return r.MagnetURI != ""
@@ -708,6 +916,20 @@ func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) erro
 		r.Uploader = uploader
 	}
 
+	// seeders and leechers are only announced by some indexers, keep track of it so 0 seeders isn't mistaken for unknown
+	if seeders, err := getStringMapValue(varMap, "seeders"); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(seeders)); err == nil {
+			r.Seeders = n
+			r.HasSeeders = true
+		}
+	}
+
+	if leechers, err := getStringMapValue(varMap, "leechers"); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(leechers)); err == nil {
+			r.Leechers = n
+		}
+	}
+
 	if torrentSize, err := getStringMapValue(varMap, "torrentSize"); err == nil {
 		// handling for indexer who doesn't explicitly set which size unit is used like (AR)
 		if def.IRC != nil && def.IRC.Parse != nil && def.IRC.Parse.ForceSizeUnit != "" {
@@ -721,9 +943,12 @@ func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) erro
 		r.Size = size
 	}
 
+	// the scene flag can be a bool or the scene/p2p type of the release
 	if scene, err := getStringMapValue(varMap, "scene"); err == nil {
-		if StringEqualFoldMulti(scene, "true", "yes", "1") {
+		if StringEqualFoldMulti(scene, "true", "yes", "1", "scene") {
 			r.Origin = "SCENE"
+		} else if strings.EqualFold(scene, "p2p") {
+			r.Origin = "P2P"
 		}
 	}
 
@@ -738,6 +963,8 @@ func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) erro
 		}
 	}
 
+	r.IsScene = strings.EqualFold(r.Origin, "SCENE")
+
 	if yearVal, err := getStringMapValue(varMap, "year"); err == nil {
 		year, err := strconv.Atoi(yearVal)
 		if err != nil {
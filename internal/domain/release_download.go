@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProxiedDownloadTTL is how long a proxied download url stays valid
+const ProxiedDownloadTTL = 24 * time.Hour
+
+// ProxiedDownloadURL returns the url to download the torrent of the release through autobrr.
+// The url is signed with the secret so it can be used without the indexer keys or an autobrr api key.
+func ProxiedDownloadURL(serverURL string, secret string, releaseID int64, expires time.Time) string {
+	exp := expires.Unix()
+
+	return fmt.Sprintf("%s/api/release/%d/download?expires=%d&signature=%s", serverURL, releaseID, exp, signReleaseDownload(secret, releaseID, exp))
+}
+
+// VerifyProxiedDownload checks the signature and expiry of a proxied download url
+func VerifyProxiedDownload(secret string, releaseID int64, expires string, signature string, now time.Time) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(signReleaseDownload(secret, releaseID, exp)))
+}
+
+func signReleaseDownload(secret string, releaseID int64, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d:%d", releaseID, expires)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RedactURL hides everything but the scheme and host of the url, download urls often have the passkey in the path or query
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "<redacted>"
+	}
+
+	return fmt.Sprintf("%s://%s/<redacted>", u.Scheme, u.Host)
+}
+
+// RedactDownloadURL masks the download url in the text, including the escaped forms macros in urls produce
+func RedactDownloadURL(text string, downloadURL string) string {
+	if downloadURL == "" {
+		return text
+	}
+
+	redacted := RedactURL(downloadURL)
+
+	for _, raw := range []string{downloadURL, url.QueryEscape(downloadURL), url.PathEscape(downloadURL)} {
+		text = strings.ReplaceAll(text, raw, redacted)
+	}
+
+	return text
+}
@@ -264,6 +264,7 @@ func TestRelease_Parse(t *testing.T) {
 				Group:         "GROUP1",
 				Season:        1,
 				Language:      []string{"ENGLiSH"},
+				Languages:     []string{"ENGLiSH"},
 			},
 		},
 	}
@@ -921,6 +922,80 @@ func TestRelease_DownloadTorrentFile(t *testing.T) {
 	}
 }
 
+func TestRelease_DownloadTorrentFile_retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "503_then_200",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantRequests: 2,
+		},
+		{
+			name:         "504_until_attempts_run_out",
+			statuses:     []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusOK},
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "404_not_retried",
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[requests]
+				requests++
+
+				if status != http.StatusOK {
+					w.WriteHeader(status)
+					return
+				}
+
+				payload, _ := os.ReadFile("testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent")
+				w.Header().Set("Content-Type", "application/x-bittorrent")
+				w.Write(payload)
+			}))
+			defer ts.Close()
+
+			r := &Release{
+				Indexer:     "mock-indexer",
+				TorrentName: "Test.Release-GROUP",
+				DownloadURL: ts.URL + "/file.torrent",
+				Protocol:    ReleaseProtocolTorrent,
+				DownloadRetry: DownloadRetryPolicy{
+					Attempts: 3,
+					Delay:    10 * time.Millisecond,
+					MaxDelay: 50 * time.Millisecond,
+				},
+			}
+
+			err := r.DownloadTorrentFile()
+			if r.TorrentTmpFile != "" {
+				defer os.Remove(r.TorrentTmpFile)
+			}
+
+			assert.Equal(t, tt.wantRequests, requests)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, r.TorrentTmpFile)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, r.TorrentTmpFile)
+			assert.NotEmpty(t, r.TorrentHash)
+		})
+	}
+}
+
 func Test_getUniqueTags(t *testing.T) {
 	type args struct {
 		target []string
@@ -970,3 +1045,164 @@ func Test_getUniqueTags(t *testing.T) {
 		})
 	}
 }
+
+func TestRelease_ComputeInfoHash(t *testing.T) {
+	const archlinuxInfoHash = "500f29c0c537f5e41c6af676b7633de9d080d237"
+
+	rawBytes, err := os.ReadFile("testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		release Release
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "from_tmp_file",
+			release: Release{
+				TorrentName:    "archlinux-2011.08.19-netinstall-i686.iso",
+				TorrentTmpFile: "testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent",
+			},
+			want: archlinuxInfoHash,
+		},
+		{
+			name: "from_raw_bytes",
+			release: Release{
+				TorrentName:         "archlinux-2011.08.19-netinstall-i686.iso",
+				TorrentDataRawBytes: rawBytes,
+			},
+			want: archlinuxInfoHash,
+		},
+		{
+			name: "cached",
+			release: Release{
+				TorrentName:    "archlinux-2011.08.19-netinstall-i686.iso",
+				TorrentTmpFile: "testdata/does-not-exist.torrent",
+				TorrentHash:    archlinuxInfoHash,
+			},
+			want: archlinuxInfoHash,
+		},
+		{
+			name: "not_downloaded",
+			release: Release{
+				TorrentName: "archlinux-2011.08.19-netinstall-i686.iso",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.release.ComputeInfoHash()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.release.TorrentHash)
+
+			m := NewMacro(tt.release)
+			got, err := m.Parse("{{ .InfoHash }}")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRelease_ComputeTrackerCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		release Release
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "single_tracker",
+			release: Release{TorrentName: "testfile.bin", TorrentTmpFile: "testdata/single-tracker.torrent"},
+			want:    1,
+		},
+		{
+			name:    "multi_tracker",
+			release: Release{TorrentName: "testfile.bin", TorrentTmpFile: "testdata/multi-tracker.torrent"},
+			want:    3,
+		},
+		{
+			name:    "not_downloaded",
+			release: Release{TorrentName: "testfile.bin"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.release.ComputeTrackerCount()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.release.TrackerCount)
+
+			m := NewMacro(tt.release)
+			got, err := m.Parse(`{{ if gt .TrackerCount 1 }}multi{{ else }}single{{ end }}`)
+			assert.NoError(t, err)
+			if tt.want > 1 {
+				assert.Equal(t, "multi", got)
+			} else {
+				assert.Equal(t, "single", got)
+			}
+		})
+	}
+}
+
+func TestRelease_ParseString_languages(t *testing.T) {
+	tests := []struct {
+		name          string
+		torrentName   string
+		wantLanguages []string
+		wantSubtitles []string
+	}{
+		{name: "multi", torrentName: "Le.Film.2023.MULTi.1080p.BluRay.x264-GROUP", wantLanguages: []string{"MULTi"}},
+		{name: "vostfr", torrentName: "Le.Film.2023.VOSTFR.1080p.WEB.H264-GROUP", wantSubtitles: []string{"VOSTFR"}},
+		{name: "subbed", torrentName: "That.Show.S01E01.SUBBED.1080p.WEB.H264-GROUP", wantSubtitles: []string{"SUBBED"}},
+		{name: "nordic_subs", torrentName: "Movie.2020.NORDiC.SUBS.1080p.WEB-DL-GROUP", wantLanguages: []string{"NORDiC"}, wantSubtitles: []string{"SUBS"}},
+		{name: "german_dl", torrentName: "Das.Boot.S01.GERMAN.DL.1080p.BluRay.x264-GROUP", wantLanguages: []string{"GERMAN", "DL"}},
+		{name: "none", torrentName: "That.Movie.2023.1080p.BluRay.x264-GROUP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Release{}
+			r.ParseString(tt.torrentName)
+
+			assert.Equal(t, tt.wantLanguages, r.Languages)
+			assert.Equal(t, tt.wantSubtitles, r.Subtitles)
+		})
+	}
+}
+
+func TestRelease_ParseString_hdrAudio(t *testing.T) {
+	tests := []struct {
+		name         string
+		torrentName  string
+		wantHDR      []string
+		wantAudio    []string
+		wantChannels string
+	}{
+		{name: "dv_hdr10_truehd_atmos", torrentName: "That.Movie.2023.2160p.UHD.BluRay.DV.HDR10.TrueHD.Atmos.7.1.x265-GROUP", wantHDR: []string{"DV", "HDR10"}, wantAudio: []string{"TrueHD", "Atmos"}, wantChannels: "7.1"},
+		{name: "spaces_dv_hdr10_dtsx", torrentName: "That Movie 2023 2160p UHD BluRay DV HDR10 DTS-X 7.1 x265-GROUP", wantHDR: []string{"DV", "HDR10"}, wantAudio: []string{"DTS-X"}, wantChannels: "7.1"},
+		{name: "ddp_atmos_dv", torrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.Atmos.DV.H.265-GROUP", wantHDR: []string{"DV"}, wantAudio: []string{"DDP", "Atmos"}, wantChannels: "5.1"},
+		{name: "hdr10_plus", torrentName: "That.Show.S01E01.2160p.WEB-DL.DD+5.1.HDR10+.H.265-GROUP", wantHDR: []string{"HDR10+"}, wantAudio: []string{"DDP"}, wantChannels: "5.1"},
+		{name: "dovi_hdr", torrentName: "That.Movie.2023.2160p.DoVi.HDR.FLAC.2.0.x265-GROUP", wantHDR: []string{"DV", "HDR"}, wantAudio: []string{"FLAC"}, wantChannels: "2.0"},
+		{name: "hlg_aac", torrentName: "That.Movie.2023.2160p.WEB-DL.AAC2.0.HLG.H.265-GROUP", wantHDR: []string{"HLG"}, wantAudio: []string{"AAC"}, wantChannels: "2.0"},
+		{name: "sdr_dts_hd_ma", torrentName: "That.Movie.2023.1080p.BluRay.DTS-HD.MA.5.1.x264-GROUP", wantAudio: []string{"DTS-HD.MA"}, wantChannels: "5.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Release{}
+			r.ParseString(tt.torrentName)
+
+			assert.Equal(t, tt.wantHDR, r.HDR)
+			assert.Equal(t, tt.wantAudio, r.Audio)
+			assert.Equal(t, tt.wantChannels, r.AudioChannels)
+		})
+	}
+}
@@ -68,12 +68,18 @@ func (s *service) testConnection(ctx context.Context, client domain.DownloadClie
 }
 
 func (s *service) testQbittorrentConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	qbtSettings := qbittorrent.Config{
 		Host:          client.BuildLegacyHost(),
 		Username:      client.Username,
 		Password:      client.Password,
 		TLSSkipVerify: client.TLSSkipVerify,
 		Log:           s.subLogger,
+		Transport:     transport,
 	}
 
 	// only set basic auth if enabled
@@ -155,6 +161,11 @@ func (s *service) testDelugeConnection(ctx context.Context, client domain.Downlo
 }
 
 func (s *service) testRTorrentConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	// create client
 	rt := rtorrent.NewClient(rtorrent.Config{
 		Addr:          client.Host,
@@ -162,6 +173,7 @@ func (s *service) testRTorrentConnection(ctx context.Context, client domain.Down
 		BasicUser:     client.Settings.Basic.Username,
 		BasicPass:     client.Settings.Basic.Password,
 		Log:           nil,
+		Transport:     transport,
 	})
 
 	name, err := rt.Name(ctx)
@@ -187,11 +199,17 @@ func (s *service) testTransmissionConnection(ctx context.Context, client domain.
 		return err
 	}
 
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	tbt, err := transmission.New(u, &transmission.Config{
 		UserAgent:     "autobrr",
 		Username:      client.Username,
 		Password:      client.Password,
 		TLSSkipVerify: client.TLSSkipVerify,
+		Transport:     transport,
 	})
 	if err != nil {
 		return errors.Wrap(err, "error logging into client: %v", client.Host)
@@ -214,6 +232,11 @@ func (s *service) testTransmissionConnection(ctx context.Context, client domain.
 }
 
 func (s *service) testRadarrConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	r := radarr.New(radarr.Config{
 		Hostname:  client.Host,
 		APIKey:    client.Settings.APIKey,
@@ -221,6 +244,7 @@ func (s *service) testRadarrConnection(ctx context.Context, client domain.Downlo
 		Username:  client.Settings.Basic.Username,
 		Password:  client.Settings.Basic.Password,
 		Log:       s.subLogger,
+		Transport: transport,
 	})
 
 	if _, err := r.Test(ctx); err != nil {
@@ -233,6 +257,11 @@ func (s *service) testRadarrConnection(ctx context.Context, client domain.Downlo
 }
 
 func (s *service) testSonarrConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	r := sonarr.New(sonarr.Config{
 		Hostname:  client.Host,
 		APIKey:    client.Settings.APIKey,
@@ -240,6 +269,7 @@ func (s *service) testSonarrConnection(ctx context.Context, client domain.Downlo
 		Username:  client.Settings.Basic.Username,
 		Password:  client.Settings.Basic.Password,
 		Log:       s.subLogger,
+		Transport: transport,
 	})
 
 	if _, err := r.Test(ctx); err != nil {
@@ -252,6 +282,11 @@ func (s *service) testSonarrConnection(ctx context.Context, client domain.Downlo
 }
 
 func (s *service) testLidarrConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	r := lidarr.New(lidarr.Config{
 		Hostname:  client.Host,
 		APIKey:    client.Settings.APIKey,
@@ -259,6 +294,7 @@ func (s *service) testLidarrConnection(ctx context.Context, client domain.Downlo
 		Username:  client.Settings.Basic.Username,
 		Password:  client.Settings.Basic.Password,
 		Log:       s.subLogger,
+		Transport: transport,
 	})
 
 	if _, err := r.Test(ctx); err != nil {
@@ -271,6 +307,11 @@ func (s *service) testLidarrConnection(ctx context.Context, client domain.Downlo
 }
 
 func (s *service) testWhisparrConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	r := whisparr.New(whisparr.Config{
 		Hostname:  client.Host,
 		APIKey:    client.Settings.APIKey,
@@ -278,6 +319,7 @@ func (s *service) testWhisparrConnection(ctx context.Context, client domain.Down
 		Username:  client.Settings.Basic.Username,
 		Password:  client.Settings.Basic.Password,
 		Log:       s.subLogger,
+		Transport: transport,
 	})
 
 	if _, err := r.Test(ctx); err != nil {
@@ -290,6 +332,11 @@ func (s *service) testWhisparrConnection(ctx context.Context, client domain.Down
 }
 
 func (s *service) testReadarrConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	r := readarr.New(readarr.Config{
 		Hostname:  client.Host,
 		APIKey:    client.Settings.APIKey,
@@ -297,6 +344,7 @@ func (s *service) testReadarrConnection(ctx context.Context, client domain.Downl
 		Username:  client.Settings.Basic.Username,
 		Password:  client.Settings.Basic.Password,
 		Log:       s.subLogger,
+		Transport: transport,
 	})
 
 	if _, err := r.Test(ctx); err != nil {
@@ -309,9 +357,15 @@ func (s *service) testReadarrConnection(ctx context.Context, client domain.Downl
 }
 
 func (s *service) testPorlaConnection(client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	p := porla.NewClient(porla.Config{
 		Hostname:  client.Host,
 		AuthToken: client.Settings.APIKey,
+		Transport: transport,
 	})
 
 	version, err := p.Version()
@@ -332,8 +386,14 @@ func (s *service) testPorlaConnection(client domain.DownloadClient) error {
 }
 
 func (s *service) testSabnzbdConnection(ctx context.Context, client domain.DownloadClient) error {
+	transport, err := s.newClientTransport(&client)
+	if err != nil {
+		return err
+	}
+
 	opts := sabnzbd.Options{
 		Addr:      client.Host,
+		Transport: transport,
 		ApiKey:    client.Settings.APIKey,
 		BasicUser: client.Settings.Basic.Username,
 		BasicPass: client.Settings.Basic.Password,
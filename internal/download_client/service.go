@@ -6,10 +6,12 @@ package download_client
 import (
 	"context"
 	"log"
+	"net/http"
 	"sync"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/autobrr/go-qbittorrent"
 	"github.com/dcarbone/zadapters/zstdlog"
@@ -24,7 +26,8 @@ type Service interface {
 	Delete(ctx context.Context, clientID int) error
 	Test(ctx context.Context, client domain.DownloadClient) error
 
-	GetCachedClient(ctx context.Context, clientId int32) *domain.DownloadClientCached
+	GetCachedClient(ctx context.Context, clientId int32) (*domain.DownloadClientCached, error)
+	GetTransport(client *domain.DownloadClient) (http.RoundTripper, error)
 }
 
 type service struct {
@@ -32,16 +35,22 @@ type service struct {
 	repo      domain.DownloadClientRepo
 	subLogger *log.Logger
 
+	// version is part of the default user agent
+	version string
+
 	qbitClients map[int32]*domain.DownloadClientCached
+	transports  map[int32]*userAgentTransport
 	m           sync.RWMutex
 }
 
-func NewService(log logger.Logger, repo domain.DownloadClientRepo) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.DownloadClientRepo) Service {
 	s := &service{
-		log:  log.With().Str("module", "download_client").Logger(),
-		repo: repo,
+		log:     log.With().Str("module", "download_client").Logger(),
+		repo:    repo,
+		version: config.Version,
 
 		qbitClients: map[int32]*domain.DownloadClientCached{},
+		transports:  map[int32]*userAgentTransport{},
 		m:           sync.RWMutex{},
 	}
 
@@ -87,6 +96,10 @@ func (s *service) Store(ctx context.Context, client domain.DownloadClient) (*dom
 }
 
 func (s *service) Update(ctx context.Context, client domain.DownloadClient) (*domain.DownloadClient, error) {
+	if err := s.restoreExtraHeaders(ctx, &client); err != nil {
+		return nil, err
+	}
+
 	// basic validation of client
 	if err := client.Validate(); err != nil {
 		return nil, err
@@ -105,6 +118,9 @@ func (s *service) Update(ctx context.Context, client domain.DownloadClient) (*do
 		s.m.Unlock()
 	}
 
+	// proxy and tls settings might have changed
+	s.removeTransport(int32(client.ID))
+
 	return c, err
 }
 
@@ -118,10 +134,16 @@ func (s *service) Delete(ctx context.Context, clientID int) error {
 	delete(s.qbitClients, int32(clientID))
 	s.m.Unlock()
 
+	s.removeTransport(int32(clientID))
+
 	return nil
 }
 
 func (s *service) Test(ctx context.Context, client domain.DownloadClient) error {
+	if err := s.restoreExtraHeaders(ctx, &client); err != nil {
+		return err
+	}
+
 	// basic validation of client
 	if err := client.Validate(); err != nil {
 		return err
@@ -136,7 +158,24 @@ func (s *service) Test(ctx context.Context, client domain.DownloadClient) error
 	return nil
 }
 
-func (s *service) GetCachedClient(ctx context.Context, clientId int32) *domain.DownloadClientCached {
+// restoreExtraHeaders sets the header values the api returned redacted back to the stored values
+func (s *service) restoreExtraHeaders(ctx context.Context, client *domain.DownloadClient) error {
+	if client.ID == 0 || !client.Settings.ExtraHeaders.IsRedacted() {
+		return nil
+	}
+
+	stored, err := s.repo.FindByID(ctx, int32(client.ID))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find download client: %d", client.ID)
+		return err
+	}
+
+	client.Settings.ExtraHeaders.Restore(stored.Settings.ExtraHeaders)
+
+	return nil
+}
+
+func (s *service) GetCachedClient(ctx context.Context, clientId int32) (*domain.DownloadClientCached, error) {
 
 	// check if client exists in cache
 	s.m.RLock()
@@ -144,17 +183,22 @@ func (s *service) GetCachedClient(ctx context.Context, clientId int32) *domain.D
 	s.m.RUnlock()
 
 	if ok {
-		return cached
+		return cached, nil
 	}
 
 	// get client for action
 	client, err := s.FindByID(ctx, clientId)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	if client == nil {
-		return nil
+		return nil, errors.New("could not find client by id: %d", clientId)
+	}
+
+	transport, err := s.GetTransport(client)
+	if err != nil {
+		return nil, err
 	}
 
 	qbtSettings := qbittorrent.Config{
@@ -162,6 +206,7 @@ func (s *service) GetCachedClient(ctx context.Context, clientId int32) *domain.D
 		Username:      client.Username,
 		Password:      client.Password,
 		TLSSkipVerify: client.TLSSkipVerify,
+		Transport:     transport,
 	}
 
 	// setup sub logger adapter which is compatible with *log.Logger
@@ -184,5 +229,47 @@ func (s *service) GetCachedClient(ctx context.Context, clientId int32) *domain.D
 	s.qbitClients[clientId] = cached
 	s.m.Unlock()
 
-	return cached
+	return cached, nil
+}
+
+// GetTransport returns a keep-alive transport for the client, cached by client id so connections are reused between actions.
+// Returns an error if the transport could not be created, e.g. when the custom CA or client certificate can't be loaded.
+func (s *service) GetTransport(client *domain.DownloadClient) (http.RoundTripper, error) {
+	clientID := int32(client.ID)
+
+	s.m.RLock()
+	t, ok := s.transports[clientID]
+	s.m.RUnlock()
+
+	if ok {
+		return t, nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// another action might have created it while waiting for the lock
+	if t, ok := s.transports[clientID]; ok {
+		return t, nil
+	}
+
+	t, err := s.newClientTransport(client)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create transport for client: %s", client.Name)
+	}
+
+	s.transports[clientID] = t
+
+	return t, nil
+}
+
+func (s *service) removeTransport(clientID int32) {
+	s.m.Lock()
+	t, ok := s.transports[clientID]
+	delete(s.transports, clientID)
+	s.m.Unlock()
+
+	if ok {
+		t.CloseIdleConnections()
+	}
 }
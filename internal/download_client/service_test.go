@@ -0,0 +1,52 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDownloadClientRepo struct {
+	domain.DownloadClientRepo
+	stored  domain.DownloadClient
+	updated domain.DownloadClient
+}
+
+func (r *mockDownloadClientRepo) FindByID(ctx context.Context, id int32) (*domain.DownloadClient, error) {
+	client := r.stored
+	return &client, nil
+}
+
+func (r *mockDownloadClientRepo) Update(ctx context.Context, client domain.DownloadClient) (*domain.DownloadClient, error) {
+	r.updated = client
+	return &client, nil
+}
+
+func TestService_Update_restoresExtraHeaders(t *testing.T) {
+	repo := &mockDownloadClientRepo{
+		stored: domain.DownloadClient{
+			ID:       1,
+			Name:     "sonarr",
+			Type:     domain.DownloadClientTypeSonarr,
+			Host:     "http://localhost:8989",
+			Settings: domain.DownloadClientSettings{ExtraHeaders: domain.ExtraHeaders{"X-Auth-Token": "secret", "Cf-Access-Client-Id": "id"}},
+		},
+	}
+
+	s := NewService(logger.Mock(), &domain.Config{}, repo)
+
+	// the client as the api returned it, with one header changed
+	client := repo.stored
+	client.Settings.ExtraHeaders = domain.ExtraHeaders{"X-Auth-Token": domain.ExtraHeaderRedacted, "Cf-Access-Client-Id": "new-id"}
+
+	_, err := s.Update(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ExtraHeaders{"X-Auth-Token": "secret", "Cf-Access-Client-Id": "new-id"}, repo.updated.Settings.ExtraHeaders)
+}
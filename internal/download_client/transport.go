@@ -0,0 +1,123 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	transportMaxIdleConns        = 100
+	transportMaxIdleConnsPerHost = 10
+	transportIdleConnTimeout     = 90 * time.Second
+)
+
+// newTransport builds a keep-alive transport for a download client with its proxy and tls settings
+func newTransport(client *domain.DownloadClient) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = transportMaxIdleConns
+	t.MaxIdleConnsPerHost = transportMaxIdleConnsPerHost
+	t.IdleConnTimeout = transportIdleConnTimeout
+
+	tlsConfig, err := newTLSConfig(client)
+	if err != nil {
+		return nil, err
+	}
+
+	t.TLSClientConfig = tlsConfig
+
+	if client.Settings.Proxy != "" {
+		proxyURL, err := url.Parse(client.Settings.Proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse proxy url for client: %s", client.Name)
+		}
+
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return t, nil
+}
+
+// userAgentTransport sets the user agent and extra headers of the download client on every request.
+// They're added to a clone of the request so errors that print the request don't contain them.
+type userAgentTransport struct {
+	*http.Transport
+	userAgent    string
+	extraHeaders domain.ExtraHeaders
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a round tripper must not modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	for name, value := range t.extraHeaders {
+		req.Header.Set(name, value)
+	}
+
+	return t.Transport.RoundTrip(req)
+}
+
+// newClientTransport builds the transport for the client with the custom or default user agent
+func (s *service) newClientTransport(client *domain.DownloadClient) (*userAgentTransport, error) {
+	t, err := newTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userAgentTransport{
+		Transport:    t,
+		userAgent:    domain.UserAgent(client.Settings.UserAgent, s.version),
+		extraHeaders: client.Settings.ExtraHeaders,
+	}, nil
+}
+
+// newTLSConfig returns the tls config for the client, or nil to use the default config.
+// The custom CA is trusted in addition to the system roots, skip verify still turns off verification.
+func newTLSConfig(client *domain.DownloadClient) (*tls.Config, error) {
+	settings := client.Settings
+
+	if !client.TLSSkipVerify && settings.TLSCAFile == "" && settings.TLSCertFile == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: client.TLSSkipVerify}
+
+	if settings.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(settings.TLSCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read tls ca file for client: %s", client.Name)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("no certificates found in tls ca file for client: %s", client.Name)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if settings.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(settings.TLSCertFile, settings.TLSKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load tls client cert for client: %s", client.Name)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
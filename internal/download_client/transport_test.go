@@ -0,0 +1,441 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package download_client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/radarr"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCountingServer returns a server that counts new connections
+func newCountingServer(t *testing.T) (*httptest.Server, *int32) {
+	var conns int32
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"approved":true,"rejected":false,"rejections":[]}]`))
+	}))
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	return ts, &conns
+}
+
+func TestService_GetTransport_reusesConnections(t *testing.T) {
+	ts, conns := newCountingServer(t)
+
+	s := NewService(logger.Mock(), &domain.Config{}, nil).(*service)
+	client := &domain.DownloadClient{ID: 1, Name: "radarr", Type: domain.DownloadClientTypeRadarr, Host: ts.URL}
+
+	// every action creates its own arr client
+	for i := 0; i < 5; i++ {
+		transport, err := s.GetTransport(client)
+		assert.NoError(t, err)
+
+		arr := radarr.New(radarr.Config{Hostname: client.Host, APIKey: "secret", Transport: transport})
+
+		rejections, err := arr.Push(context.Background(), radarr.Release{Title: "That.Movie.2023.1080p.BluRay.x264-GROUP"})
+		assert.NoError(t, err)
+		assert.Empty(t, rejections)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(conns))
+}
+
+func TestService_GetTransport_withoutCache(t *testing.T) {
+	ts, conns := newCountingServer(t)
+
+	// a fresh transport per request can't reuse the connection
+	for i := 0; i < 3; i++ {
+		transport, err := newTransport(&domain.DownloadClient{ID: 1})
+		assert.NoError(t, err)
+
+		res, err := (&http.Client{Transport: transport}).Get(ts.URL)
+		assert.NoError(t, err)
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(conns))
+}
+
+func TestService_GetTransport_settings(t *testing.T) {
+	s := NewService(logger.Mock(), &domain.Config{}, nil).(*service)
+
+	client := &domain.DownloadClient{
+		ID:            2,
+		Name:          "sab",
+		TLSSkipVerify: true,
+		Settings:      domain.DownloadClientSettings{Proxy: "http://proxy.example.com:3128"},
+	}
+
+	rt, err := s.GetTransport(client)
+	assert.NoError(t, err)
+
+	transport, ok := rt.(*userAgentTransport)
+	assert.True(t, ok)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+	req := httptest.NewRequest(http.MethodGet, "http://sab.example.com/api", nil)
+	proxyURL, err := transport.Proxy(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+
+	// cached per client id
+	cached, err := s.GetTransport(client)
+	assert.NoError(t, err)
+	assert.Same(t, transport, cached)
+
+	// settings changes drop the cached transport
+	s.removeTransport(2)
+	renewed, err := s.GetTransport(client)
+	assert.NoError(t, err)
+	assert.NotSame(t, transport, renewed)
+}
+
+func TestService_GetTransport_userAgent(t *testing.T) {
+	var userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"approved":true,"rejected":false,"rejections":[]}]`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "default", want: fmt.Sprintf("autobrr/v1.2.3 (%s %s)", runtime.GOOS, runtime.GOARCH)},
+		{name: "custom", userAgent: "Mozilla/5.0", want: "Mozilla/5.0"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(logger.Mock(), &domain.Config{Version: "v1.2.3"}, nil)
+			client := &domain.DownloadClient{ID: i + 1, Name: "radarr", Type: domain.DownloadClientTypeRadarr, Host: ts.URL, Settings: domain.DownloadClientSettings{UserAgent: tt.userAgent}}
+
+			transport, err := s.GetTransport(client)
+			assert.NoError(t, err)
+
+			// the arr client sets its own user agent, the one of the download client replaces it
+			arr := radarr.New(radarr.Config{Hostname: client.Host, APIKey: "secret", Transport: transport})
+			_, err = arr.Push(context.Background(), radarr.Release{Title: "That.Movie.2023.1080p.BluRay.x264-GROUP"})
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, userAgent)
+		})
+	}
+}
+
+func TestService_GetTransport_extraHeaders(t *testing.T) {
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"approved":true,"rejected":false,"rejections":[]}]`))
+	}))
+	defer ts.Close()
+
+	s := NewService(logger.Mock(), &domain.Config{}, nil).(*service)
+	client := &domain.DownloadClient{
+		ID:   1,
+		Name: "sonarr",
+		Type: domain.DownloadClientTypeSonarr,
+		Host: ts.URL,
+		Settings: domain.DownloadClientSettings{
+			APIKey:       "secret",
+			ExtraHeaders: domain.ExtraHeaders{"X-Auth-Token": "proxy-token"},
+		},
+	}
+
+	transport, err := s.GetTransport(client)
+	assert.NoError(t, err)
+
+	arr := sonarr.New(sonarr.Config{Hostname: client.Host, APIKey: client.Settings.APIKey, Transport: transport})
+	_, err = arr.Push(context.Background(), sonarr.Release{Title: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "proxy-token", header.Get("X-Auth-Token"))
+	assert.Equal(t, "secret", header.Get("X-Api-Key"))
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed CA if parent is nil
+func newTestCert(t *testing.T, cn string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := tmpl, any(key)
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// writeTestCert writes the certificate and key as pem files
+func writeTestCert(t *testing.T, dir, name string, cert tls.Certificate) (string, string) {
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+".key")
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestNewTransport_tlsCertificates(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCert(t, "private ca", nil)
+	serverCert := newTestCert(t, "reverse proxy", &ca)
+	clientCert := newTestCert(t, "autobrr", &ca)
+
+	caFile, _ := writeTestCert(t, dir, "ca", ca)
+	certFile, keyFile := writeTestCert(t, dir, "client", clientCert)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Leaf)
+
+	// mTLS proxy that only accepts clients signed by the private ca
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		skip     bool
+		settings domain.DownloadClientSettings
+		wantErr  bool
+	}{
+		{name: "ca_and_client_cert", settings: domain.DownloadClientSettings{TLSCAFile: caFile, TLSCertFile: certFile, TLSKeyFile: keyFile}},
+		{name: "skip_verify_and_client_cert", skip: true, settings: domain.DownloadClientSettings{TLSCertFile: certFile, TLSKeyFile: keyFile}},
+		{name: "untrusted_server", settings: domain.DownloadClientSettings{TLSCertFile: certFile, TLSKeyFile: keyFile}, wantErr: true},
+		{name: "missing_client_cert", settings: domain.DownloadClientSettings{TLSCAFile: caFile}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := newTransport(&domain.DownloadClient{ID: 1, Name: "sonarr", TLSSkipVerify: tt.skip, Settings: tt.settings})
+			assert.NoError(t, err)
+
+			res, err := (&http.Client{Transport: transport}).Get(ts.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "autobrr", string(body))
+		})
+	}
+}
+
+func TestNewTransport_tlsErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	notPEM := filepath.Join(dir, "ca.pem")
+	assert.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name     string
+		settings domain.DownloadClientSettings
+	}{
+		{name: "missing_ca_file", settings: domain.DownloadClientSettings{TLSCAFile: filepath.Join(dir, "missing.pem")}},
+		{name: "invalid_ca_file", settings: domain.DownloadClientSettings{TLSCAFile: notPEM}},
+		{name: "invalid_client_cert", settings: domain.DownloadClientSettings{TLSCertFile: notPEM, TLSKeyFile: notPEM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTransport(&domain.DownloadClient{ID: 1, Name: "sonarr", Settings: tt.settings})
+			assert.Error(t, err)
+
+			// the action using the client fails with the cause instead of falling back to the default transport
+			s := NewService(logger.Mock(), &domain.Config{}, nil)
+			_, err = s.GetTransport(&domain.DownloadClient{ID: 1, Name: "sonarr", Settings: tt.settings})
+			assert.ErrorContains(t, err, "could not create transport for client: sonarr")
+		})
+	}
+}
+
+func TestService_testQbittorrentConnection_tlsCertificates(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCert(t, "private ca", nil)
+	serverCert := newTestCert(t, "reverse proxy", &ca)
+	clientCert := newTestCert(t, "autobrr", &ca)
+
+	caFile, _ := writeTestCert(t, dir, "ca", ca)
+	certFile, keyFile := writeTestCert(t, dir, "client", clientCert)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Leaf)
+
+	// qBittorrent behind an mTLS proxy
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test"})
+		_, _ = w.Write([]byte("Ok."))
+	})
+	mux.HandleFunc("/api/v2/torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	})
+
+	ts := httptest.NewUnstartedServer(mux)
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		settings domain.DownloadClientSettings
+		wantErr  bool
+	}{
+		{name: "ca_and_client_cert", settings: domain.DownloadClientSettings{TLSCAFile: caFile, TLSCertFile: certFile, TLSKeyFile: keyFile}},
+		{name: "missing_client_cert", settings: domain.DownloadClientSettings{TLSCAFile: caFile}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(logger.Mock(), &domain.Config{}, nil).(*service)
+
+			err := s.testQbittorrentConnection(context.Background(), domain.DownloadClient{
+				ID:       1,
+				Name:     "qbit",
+				Type:     domain.DownloadClientTypeQbittorrent,
+				Host:     ts.URL,
+				TLS:      true,
+				Settings: tt.settings,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_testRTorrentConnection_proxy(t *testing.T) {
+	var proxied string
+
+	// the proxy answers the xmlrpc call itself
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><string>seedbox</string></value></param></params></methodResponse>`))
+	}))
+	defer proxy.Close()
+
+	s := NewService(logger.Mock(), &domain.Config{}, nil).(*service)
+
+	err := s.testRTorrentConnection(context.Background(), domain.DownloadClient{
+		ID:       1,
+		Name:     "rtorrent",
+		Type:     domain.DownloadClientTypeRTorrent,
+		Host:     "http://rtorrent.domain.ltd/RPC2",
+		Settings: domain.DownloadClientSettings{Proxy: proxy.URL},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://rtorrent.domain.ltd/RPC2", proxied)
+}
+
+func TestService_testRTorrentConnection_userAgent(t *testing.T) {
+	var userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><string>seedbox</string></value></param></params></methodResponse>`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "default", want: fmt.Sprintf("autobrr/v1.2.3 (%s %s)", runtime.GOOS, runtime.GOARCH)},
+		{name: "custom", userAgent: "Mozilla/5.0", want: "Mozilla/5.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(logger.Mock(), &domain.Config{Version: "v1.2.3"}, nil).(*service)
+
+			err := s.testRTorrentConnection(context.Background(), domain.DownloadClient{
+				ID:       1,
+				Name:     "rtorrent",
+				Type:     domain.DownloadClientTypeRTorrent,
+				Host:     ts.URL + "/RPC2",
+				Settings: domain.DownloadClientSettings{UserAgent: tt.userAgent},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, userAgent)
+		})
+	}
+}
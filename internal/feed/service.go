@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -70,9 +72,19 @@ type service struct {
 	cacheRepo  domain.FeedCacheRepo
 	releaseSvc release.Service
 	scheduler  scheduler.Service
+
+	// jitter is the fraction of the interval feed fetches are randomly delayed by
+	jitter float64
+	randMu sync.Mutex
+	rand   *rand.Rand
 }
 
-func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service) Service {
+	jitter := float64(config.FeedJitter) / 100
+	if jitter > 1 {
+		jitter = 1
+	}
+
 	return &service{
 		log:        log.With().Str("module", "feed").Logger(),
 		jobs:       map[string]int{},
@@ -80,6 +92,8 @@ func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCa
 		cacheRepo:  cacheRepo,
 		releaseSvc: releaseSvc,
 		scheduler:  scheduler,
+		jitter:     jitter,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -419,7 +433,7 @@ func (s *service) scheduleJob(fi feedInstance, job cron.Job) error {
 	identifierKey := feedKey{fi.Feed.ID}.ToString()
 
 	// schedule job
-	id, err := s.scheduler.ScheduleJob(job, fi.CronSchedule, identifierKey)
+	id, err := s.scheduler.ScheduleJobWithOffset(job, fi.CronSchedule, s.jitterOffset(fi.CronSchedule), identifierKey)
 	if err != nil {
 		return errors.Wrap(err, "add job %s failed", identifierKey)
 	}
@@ -430,6 +444,19 @@ func (s *service) scheduleJob(fi feedInstance, job cron.Job) error {
 	return nil
 }
 
+// jitterOffset returns a random delay of up to jitter times the interval,
+// so feeds with the same interval are not all fetched at the same time
+func (s *service) jitterOffset(interval time.Duration) time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+
+	s.randMu.Lock()
+	defer s.randMu.Unlock()
+
+	return time.Duration(s.rand.Float64() * s.jitter * float64(interval))
+}
+
 func (s *service) createTorznabJob(f feedInstance) (FeedJob, error) {
 	s.log.Debug().Msgf("create torznab job: %s", f.Name)
 
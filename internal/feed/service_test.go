@@ -0,0 +1,78 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/scheduler"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockScheduler records the offset of every scheduled feed job
+type mockScheduler struct {
+	scheduler.Service
+	offsets []time.Duration
+}
+
+func (s *mockScheduler) ScheduleJobWithOffset(job cron.Job, interval time.Duration, offset time.Duration, identifier string) (int, error) {
+	s.offsets = append(s.offsets, offset)
+	return len(s.offsets), nil
+}
+
+func scheduleFeeds(t *testing.T, jitter float64, seed int64, count int, interval time.Duration) []time.Duration {
+	sched := &mockScheduler{}
+
+	s := &service{
+		jobs:      map[string]int{},
+		scheduler: sched,
+		jitter:    jitter,
+		rand:      rand.New(rand.NewSource(seed)),
+	}
+
+	for i := 1; i <= count; i++ {
+		fi := newFeedInstance(&domain.Feed{ID: i, Name: "feed", Interval: int(interval / time.Minute)})
+		assert.NoError(t, s.scheduleJob(fi, cron.FuncJob(func() {})))
+	}
+
+	return sched.offsets
+}
+
+func Test_service_scheduleJob_jitter(t *testing.T) {
+	const (
+		feeds    = 10
+		interval = 15 * time.Minute
+	)
+
+	offsets := scheduleFeeds(t, 1, 42, feeds, interval)
+	assert.Len(t, offsets, feeds)
+
+	// feeds are spread over the interval instead of all being fetched at the same time
+	buckets := map[time.Duration]bool{}
+	for _, offset := range offsets {
+		assert.GreaterOrEqual(t, offset, time.Duration(0))
+		assert.Less(t, offset, interval)
+
+		buckets[offset/(interval/5)] = true
+	}
+	assert.GreaterOrEqual(t, len(buckets), 3, "offsets %v", offsets)
+
+	// the same seed gives the same spread
+	assert.Equal(t, offsets, scheduleFeeds(t, 1, 42, feeds, interval))
+
+	// jitter is a fraction of the interval
+	for _, offset := range scheduleFeeds(t, 0.1, 42, feeds, interval) {
+		assert.Less(t, offset, interval/10)
+	}
+}
+
+func Test_service_scheduleJob_noJitter(t *testing.T) {
+	offsets := scheduleFeeds(t, 0, 42, 3, 15*time.Minute)
+	assert.Equal(t, []time.Duration{0, 0, 0}, offsets)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// approvalWebhookTimeout is used when the external filter has no timeout set
+const approvalWebhookTimeout = 15 * time.Second
+
+// approvalRequest is the release metadata sent to the policy service.
+// The download url is left out, it contains the passkey of the indexer.
+type approvalRequest struct {
+	Filter      string   `json:"filter"`
+	Indexer     string   `json:"indexer"`
+	TorrentName string   `json:"torrent_name"`
+	Title       string   `json:"title"`
+	Category    string   `json:"category"`
+	Size        uint64   `json:"size"`
+	Season      int      `json:"season"`
+	Episode     int      `json:"episode"`
+	Year        int      `json:"year"`
+	Resolution  string   `json:"resolution"`
+	Source      string   `json:"source"`
+	Codec       []string `json:"codec"`
+	HDR         []string `json:"hdr"`
+	Group       string   `json:"group"`
+	Origin      string   `json:"origin"`
+	Uploader    string   `json:"uploader"`
+	Freeleech   bool     `json:"freeleech"`
+	Tags        []string `json:"tags"`
+}
+
+// approvalResponse is the answer of the policy service, a bare true or false is accepted as well
+type approvalResponse struct {
+	Approved *bool  `json:"approved"`
+	Reason   string `json:"reason"`
+}
+
+// webhookApproval posts the release to the policy service and returns if it approved the release, with the reason if given
+func (s *service) webhookApproval(ctx context.Context, external domain.FilterExternal, release *domain.Release) (bool, string, error) {
+	if external.WebhookHost == "" {
+		return false, "", errors.New("external filter: missing host for approval webhook")
+	}
+
+	if err := s.webhookPolicy.CheckURL(external.WebhookHost); err != nil {
+		return false, "", err
+	}
+
+	timeout := approvalWebhookTimeout
+	if external.WebhookTimeout > 0 {
+		timeout = time.Duration(external.WebhookTimeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(approvalRequest{
+		Filter:      release.FilterName,
+		Indexer:     release.Indexer,
+		TorrentName: release.TorrentName,
+		Title:       release.Title,
+		Category:    release.Category,
+		Size:        release.Size,
+		Season:      release.Season,
+		Episode:     release.Episode,
+		Year:        release.Year,
+		Resolution:  release.Resolution,
+		Source:      release.Source,
+		Codec:       release.Codec,
+		HDR:         release.HDR,
+		Group:       release.Group,
+		Origin:      release.Origin,
+		Uploader:    release.Uploader,
+		Freeleech:   release.Freeleech,
+		Tags:        release.Tags,
+	})
+	if err != nil {
+		return false, "", errors.Wrap(err, "could not marshal release for approval webhook")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, external.WebhookHost, bytes.NewReader(payload))
+	if err != nil {
+		return false, "", errors.Wrap(err, "could not build request for approval webhook")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+	setWebhookHeaders(req, external.WebhookHeaders)
+
+	// same as the other external webhooks, the timeout is set on the context
+	t := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	s.webhookPolicy.Apply(t)
+
+	client := http.Client{Transport: t}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return false, "", errors.Wrap(err, "could not make request for approval webhook")
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if err != nil {
+		return false, "", errors.Wrap(err, "could not read approval webhook response")
+	}
+
+	s.log.Debug().Msgf("filter external approval webhook response status: %d body: %s", res.StatusCode, body)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return false, "", errors.New("approval webhook unexpected status code: %d", res.StatusCode)
+	}
+
+	return parseApprovalResponse(body)
+}
+
+// parseApprovalResponse accepts a bare true or false, or an object with approved and an optional reason
+func parseApprovalResponse(body []byte) (bool, string, error) {
+	switch strings.TrimSpace(string(body)) {
+	case "true":
+		return true, "", nil
+	case "false":
+		return false, "", nil
+	}
+
+	var res approvalResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return false, "", errors.Wrap(err, "could not parse approval webhook response")
+	}
+
+	if res.Approved == nil {
+		return false, "", errors.New("approval webhook response is missing approved")
+	}
+
+	return *res.Approved, res.Reason, nil
+}
+
+// setWebhookHeaders adds the headers of the external filter, formatted as HEADER=value;HEADER2=value2
+func setWebhookHeaders(req *http.Request, headers string) {
+	if headers == "" {
+		return
+	}
+
+	for _, header := range strings.Split(headers, ";") {
+		h := strings.Split(header, "=")
+
+		if len(h) != 2 {
+			continue
+		}
+
+		// add header to req
+		req.Header.Add(h[0], h[1]) // go already canonicalizes the provided header key.
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -52,16 +53,20 @@ type service struct {
 	releaseRepo domain.ReleaseRepo
 	indexerSvc  indexer.Service
 	apiService  indexer.APIService
+
+	// webhookPolicy limits the hosts the approval webhook connects to, like webhook actions
+	webhookPolicy *action.WebhookPolicy
 }
 
-func NewService(log logger.Logger, repo domain.FilterRepo, actionRepo domain.ActionRepo, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.FilterRepo, actionRepo domain.ActionRepo, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service) Service {
 	return &service{
-		log:         log.With().Str("module", "filter").Logger(),
-		repo:        repo,
-		actionRepo:  actionRepo,
-		releaseRepo: releaseRepo,
-		apiService:  apiService,
-		indexerSvc:  indexerSvc,
+		log:           log.With().Str("module", "filter").Logger(),
+		repo:          repo,
+		actionRepo:    actionRepo,
+		releaseRepo:   releaseRepo,
+		apiService:    apiService,
+		indexerSvc:    indexerSvc,
+		webhookPolicy: action.NewWebhookPolicy(config),
 	}
 }
 
@@ -200,6 +205,11 @@ func (s *service) Update(ctx context.Context, filter *domain.Filter) error {
 }
 
 func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error {
+	if err := filter.Validate(); err != nil {
+		s.log.Error().Err(err).Msgf("invalid filter: %v", filter.ID)
+		return err
+	}
+
 	// cleanup
 	if filter.Shows != nil {
 		// replace newline with comma
@@ -392,6 +402,29 @@ func (s *service) CheckFilter(ctx context.Context, f *domain.Filter, release *do
 			}
 		}
 
+		// tracker count is read from the torrent file, so it needs to be downloaded
+		if f.MinTrackers > 0 || f.MaxTrackers > 0 {
+			ok, err := s.trackerCountCheck(ctx, f, release)
+			if err != nil {
+				l.Error().Err(err).Msgf("(%s) tracker count check error", f.Name)
+				return false, err
+			}
+
+			if !ok {
+				l.Debug().Msgf("(%s) tracker count check not matching what filter wanted: %s", f.Name, f.RejectionsString(true))
+				return false, nil
+			}
+		}
+
+		// make sure the torrent can be downloaded before any action runs, the actions reuse the downloaded file
+		if f.RequireDownload && release.Protocol == domain.ReleaseProtocolTorrent && !release.HasMagnetUri() {
+			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+				l.Warn().Err(err).Msgf("(%s) download precheck failed for release: %s", f.Name, release.TorrentName)
+				f.AddRejectionF("download precheck: could not download torrent file: %v", err)
+				return false, nil
+			}
+		}
+
 		// run external filters
 		if f.External != nil {
 			externalOk, err := s.RunExternalFilters(ctx, f, f.External, release)
@@ -472,8 +505,43 @@ func (s *service) AdditionalSizeCheck(ctx context.Context, f *domain.Filter, rel
 	return true, nil
 }
 
+// trackerCountCheck downloads the torrent file to count the trackers in its announce list
+func (s *service) trackerCountCheck(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+		return false, errors.Wrap(err, "could not download torrent file with id: '%s' from: %s", release.TorrentID, release.Indexer)
+	}
+
+	if err := release.ComputeTrackerCount(); err != nil {
+		return false, err
+	}
+
+	return f.CheckTrackerCount(release.TrackerCount), nil
+}
+
 func (s *service) CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error) {
-	return s.releaseRepo.CanDownloadShow(ctx, release.Title, release.Season, release.Episode)
+	canDownload, err := s.releaseRepo.CanDownloadShow(ctx, release.Title, release.Season, release.Episode)
+	if err != nil || !canDownload {
+		return canDownload, err
+	}
+
+	if release.Season == 0 || release.Episode == 0 {
+		return true, nil
+	}
+
+	// an episode that was already grabbed is only allowed again as a quality upgrade
+	grabbed, err := s.releaseRepo.FindGrabbedEpisodes(ctx, release.Title, release.Season, release.Episode)
+	if err != nil {
+		return false, err
+	}
+
+	for _, g := range grabbed {
+		if g.QualityRank() >= release.QualityRank() {
+			s.log.Debug().Msgf("smart episode: %s already grabbed as %s at equal or better quality", release.TorrentName, g.TorrentName)
+			return false, nil
+		}
+	}
+
+	return true, nil
 }
 
 func (s *service) RunExternalFilters(ctx context.Context, f *domain.Filter, externalFilters []domain.FilterExternal, release *domain.Release) (bool, error) {
@@ -522,6 +590,27 @@ func (s *service) RunExternalFilters(ctx context.Context, f *domain.Filter, exte
 				f.AddRejectionF("external webhook unexpected status code. got: %d want: %d", statusCode, external.WebhookExpectStatus)
 				return false, nil
 			}
+
+		case domain.ExternalFilterTypeWebhookApproval:
+			// ask the policy service, errors and timeouts approve or reject depending on the fail mode
+			approved, reason, err := s.webhookApproval(ctx, external, release)
+			if err != nil {
+				// hosts blocked by the policy are never approved
+				if external.WebhookFailOpen && !errors.Is(err, action.ErrActionPolicy) {
+					s.log.Warn().Err(err).Msgf("external approval webhook %s failed, approving release: %s", external.Name, release.TorrentName)
+					continue
+				}
+
+				s.log.Warn().Err(err).Msgf("external approval webhook %s failed, rejecting release: %s", external.Name, release.TorrentName)
+				f.AddRejectionF("external approval webhook failed: %v", err)
+				return false, nil
+			}
+
+			if !approved {
+				s.log.Trace().Msgf("filter.Service.CheckFilter: external approval webhook rejected release: %s", reason)
+				f.AddRejectionF("external approval webhook rejected release: %s", reason)
+				return false, nil
+			}
 		}
 	}
 
@@ -562,12 +651,16 @@ func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, r
 		return 0, errors.Wrap(err, "could not parse macro")
 	}
 
-	// we need to split on space into a string slice, so we can spread the args into exec
-	p := shellwords.NewParser()
-	p.ParseBacktick = true
-	commandArgs, err := p.Parse(parsedArgs)
+	// split args before replacing values so values with spaces or quotes stay a single argument
+	commandArgs, err := m.ParseArgs(external.ExecArgs)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse into shell-words")
+		// we need to split on space into a string slice, so we can spread the args into exec
+		p := shellwords.NewParser()
+		p.ParseBacktick = true
+		commandArgs, err = p.Parse(parsedArgs)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not parse into shell-words")
+		}
 	}
 
 	start := time.Now()
@@ -666,6 +759,9 @@ func (s *service) webhook(ctx context.Context, external domain.FilterExternal, r
 	}
 
 	client := http.Client{Transport: t, Timeout: 120 * time.Second}
+	if external.WebhookTimeout > 0 {
+		client.Timeout = time.Duration(external.WebhookTimeout) * time.Second
+	}
 
 	method := http.MethodPost
 	if external.WebhookMethod != "" {
@@ -680,20 +776,7 @@ func (s *service) webhook(ctx context.Context, external domain.FilterExternal, r
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "autobrr")
 
-	if external.WebhookHeaders != "" {
-		headers := strings.Split(external.WebhookHeaders, ";")
-
-		for _, header := range headers {
-			h := strings.Split(header, "=")
-
-			if len(h) != 2 {
-				continue
-			}
-
-			// add header to req
-			req.Header.Add(h[0], h[1]) // go already canonicalizes the provided header key.
-		}
-	}
+	setWebhookHeaders(req, external.WebhookHeaders)
 
 	var opts []retry.Option
 
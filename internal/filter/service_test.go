@@ -0,0 +1,210 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockReleaseRepo keeps grabbed releases in memory
+type mockReleaseRepo struct {
+	domain.ReleaseRepo
+	grabbed []*domain.Release
+}
+
+func (r *mockReleaseRepo) CanDownloadShow(ctx context.Context, title string, season int, episode int) (bool, error) {
+	return true, nil
+}
+
+func (r *mockReleaseRepo) FindGrabbedEpisodes(ctx context.Context, title string, season int, episode int) ([]*domain.Release, error) {
+	var releases []*domain.Release
+	for _, rls := range r.grabbed {
+		if rls.Title == title && rls.Season == season && rls.Episode == episode {
+			releases = append(releases, rls)
+		}
+	}
+
+	return releases, nil
+}
+
+func TestService_CheckFilter_smartEpisode(t *testing.T) {
+	repo := &mockReleaseRepo{}
+	s := &service{
+		log:         logger.Mock().With().Logger(),
+		releaseRepo: repo,
+	}
+
+	f := &domain.Filter{ID: 1, Name: "tv", Enabled: true, SmartEpisode: true}
+
+	check := func(torrentName string) bool {
+		release := domain.NewRelease("mock")
+		release.ParseString(torrentName)
+
+		match, err := s.CheckFilter(context.Background(), f, release)
+		assert.NoError(t, err)
+
+		if match {
+			repo.grabbed = append(repo.grabbed, release)
+		}
+
+		return match
+	}
+
+	assert.True(t, check("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"), "first grab")
+	assert.False(t, check("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-OTHER"), "same quality")
+	assert.False(t, check("That.Show.S01E01.720p.WEB-DL.DDP5.1.H.264-OTHER"), "lower quality")
+	assert.True(t, check("That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-GROUP"), "upgrade")
+	assert.False(t, check("That.Show.S01E01.1080p.BluRay.x264-OTHER"), "lower than the upgrade")
+	assert.True(t, check("That.Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP"), "next episode")
+}
+
+func TestService_CheckFilter_requireDownload(t *testing.T) {
+	torrent, err := os.ReadFile("../domain/testdata/single-tracker.torrent")
+	assert.NoError(t, err)
+
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+
+		switch r.URL.Path {
+		case "/ok.torrent":
+			_, _ = w.Write(torrent)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		filter    domain.Filter
+		url       string
+		wantMatch bool
+		wantFile  bool
+		wantHits  int32
+	}{
+		{name: "download_ok", filter: domain.Filter{ID: 1, Name: "precheck", Enabled: true, RequireDownload: true}, url: ts.URL + "/ok.torrent", wantMatch: true, wantFile: true, wantHits: 1},
+		{name: "download_fails", filter: domain.Filter{ID: 1, Name: "precheck", Enabled: true, RequireDownload: true}, url: ts.URL + "/gone.torrent", wantMatch: false, wantHits: 1},
+		{name: "precheck_disabled", filter: domain.Filter{ID: 1, Name: "precheck", Enabled: true}, url: ts.URL + "/gone.torrent", wantMatch: true, wantHits: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&hits, 0)
+
+			s := &service{
+				log: logger.Mock().With().Logger(),
+			}
+
+			release := domain.NewRelease("mock")
+			release.ParseString("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP")
+			release.DownloadURL = tt.url
+
+			f := tt.filter
+
+			match, err := s.CheckFilter(context.Background(), &f, release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMatch, match)
+
+			if tt.wantFile {
+				assert.NotEmpty(t, release.TorrentTmpFile)
+				defer os.Remove(release.TorrentTmpFile)
+
+				// the actions reuse the file instead of downloading it again
+				assert.NoError(t, release.DownloadTorrentFileCtx(context.Background()))
+			} else {
+				assert.Empty(t, release.TorrentTmpFile)
+			}
+
+			if !tt.wantMatch {
+				assert.Contains(t, f.RejectionsString(false), "download precheck")
+			}
+
+			assert.Equal(t, tt.wantHits, atomic.LoadInt32(&hits))
+		})
+	}
+}
+
+func TestService_RunExternalFilters_webhookApproval(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req approvalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TorrentName == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/approve":
+			_, _ = w.Write([]byte(`{"approved":true}`))
+		case "/approve_bool":
+			_, _ = w.Write([]byte(`true`))
+		case "/reject":
+			_, _ = w.Write([]byte(`{"approved":false,"reason":"group not allowed"}`))
+		case "/slow":
+			// hold the request until the client gives up
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		failOpen      bool
+		blockPrivate  bool
+		wantMatch     bool
+		wantRejection string
+	}{
+		{name: "approve", path: "/approve", wantMatch: true},
+		{name: "approve_bool", path: "/approve_bool", wantMatch: true},
+		{name: "reject", path: "/reject", wantMatch: false, wantRejection: "external approval webhook rejected release: group not allowed"},
+		{name: "timeout_fail_closed", path: "/slow", wantMatch: false, wantRejection: "external approval webhook failed"},
+		{name: "timeout_fail_open", path: "/slow", failOpen: true, wantMatch: true},
+		{name: "bad_status_fail_closed", path: "/missing", wantMatch: false, wantRejection: "unexpected status code: 404"},
+		{name: "blocked_by_policy", path: "/approve", blockPrivate: true, wantMatch: false, wantRejection: "blocked by action policy"},
+		{name: "blocked_by_policy_fail_open", path: "/approve", blockPrivate: true, failOpen: true, wantMatch: false, wantRejection: "blocked by action policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{
+				log:           logger.Mock().With().Logger(),
+				webhookPolicy: action.NewWebhookPolicy(&domain.Config{WebhookBlockPrivate: tt.blockPrivate}),
+			}
+
+			release := domain.NewRelease("mock")
+			release.ParseString("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP")
+
+			f := &domain.Filter{ID: 1, Name: "policy", Enabled: true}
+			external := []domain.FilterExternal{{
+				Name:            "policy service",
+				Type:            domain.ExternalFilterTypeWebhookApproval,
+				Enabled:         true,
+				WebhookHost:     ts.URL + tt.path,
+				WebhookTimeout:  1,
+				WebhookFailOpen: tt.failOpen,
+			}}
+
+			match, err := s.RunExternalFilters(context.Background(), f, external, release)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMatch, match)
+
+			if tt.wantRejection != "" {
+				assert.Contains(t, f.RejectionsString(false), tt.wantRejection)
+			}
+		})
+	}
+}
@@ -20,6 +20,15 @@ type actionService interface {
 	Store(ctx context.Context, action domain.Action) (*domain.Action, error)
 	Delete(ctx context.Context, req *domain.DeleteActionRequest) error
 	ToggleEnabled(actionID int) error
+	Clone(ctx context.Context, req *domain.CloneActionRequest) ([]*domain.Action, error)
+	ListTemplates(ctx context.Context) ([]domain.ActionTemplate, error)
+	StoreTemplate(ctx context.Context, template *domain.ActionTemplate) error
+	UpdateTemplate(ctx context.Context, template *domain.ActionTemplate) error
+	DeleteTemplate(ctx context.Context, id int) error
+	ListMacroOverrides(ctx context.Context) ([]domain.MacroOverride, error)
+	StoreMacroOverride(ctx context.Context, override *domain.MacroOverride) error
+	DeleteMacroOverride(ctx context.Context, id int) error
+	FindResults(ctx context.Context, params domain.ActionResultQueryParams) ([]domain.ActionResult, error)
 }
 
 type actionHandler struct {
@@ -38,10 +47,29 @@ func (h actionHandler) Routes(r chi.Router) {
 	r.Get("/", h.getActions)
 	r.Post("/", h.storeAction)
 
+	r.Get("/results", h.getResults)
+
+	r.Route("/templates", func(r chi.Router) {
+		r.Get("/", h.getTemplates)
+		r.Post("/", h.storeTemplate)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Put("/", h.updateTemplate)
+			r.Delete("/", h.deleteTemplate)
+		})
+	})
+
+	r.Route("/macro-overrides", func(r chi.Router) {
+		r.Get("/", h.getMacroOverrides)
+		r.Post("/", h.storeMacroOverride)
+		r.Delete("/{id}", h.deleteMacroOverride)
+	})
+
 	r.Route("/{id}", func(r chi.Router) {
 		r.Delete("/", h.deleteAction)
 		r.Put("/", h.updateAction)
 		r.Patch("/toggleEnabled", h.toggleActionEnabled)
+		r.Post("/clone", h.cloneAction)
 	})
 }
 
@@ -125,6 +153,171 @@ func (h actionHandler) toggleActionEnabled(w http.ResponseWriter, r *http.Reques
 	h.encoder.StatusResponse(w, http.StatusCreated, nil)
 }
 
+func (h actionHandler) cloneAction(w http.ResponseWriter, r *http.Request) {
+	actionID, err := parseInt(chi.URLParam(r, "id"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("bad param id"))
+		return
+	}
+
+	var data domain.CloneActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	data.ActionID = actionID
+
+	actions, err := h.service.Clone(r.Context(), &data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, actions)
+}
+
+func (h actionHandler) getResults(w http.ResponseWriter, r *http.Request) {
+	var params domain.ActionResultQueryParams
+
+	if releaseP := r.URL.Query().Get("release_id"); releaseP != "" {
+		releaseID, err := strconv.ParseInt(releaseP, 10, 64)
+		if err != nil {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "release_id parameter is invalid",
+			})
+			return
+		}
+		params.ReleaseID = releaseID
+	}
+
+	if limitP := r.URL.Query().Get("limit"); limitP != "" {
+		limit, err := strconv.ParseUint(limitP, 10, 64)
+		if err != nil {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "limit parameter is invalid",
+			})
+			return
+		}
+		params.Limit = limit
+	}
+
+	params.Status = domain.ActionResultStatus(r.URL.Query().Get("status"))
+
+	results, err := h.service.FindResults(r.Context(), params)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, results)
+}
+
+func (h actionHandler) getTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.ListTemplates(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, templates)
+}
+
+func (h actionHandler) storeTemplate(w http.ResponseWriter, r *http.Request) {
+	var data domain.ActionTemplate
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.StoreTemplate(r.Context(), &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, data)
+}
+
+func (h actionHandler) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := parseInt(chi.URLParam(r, "id"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("bad param id"))
+		return
+	}
+
+	var data domain.ActionTemplate
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	data.ID = templateID
+
+	if err := h.service.UpdateTemplate(r.Context(), &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, data)
+}
+
+func (h actionHandler) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := parseInt(chi.URLParam(r, "id"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("bad param id"))
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), templateID); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
+}
+
+func (h actionHandler) getMacroOverrides(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.service.ListMacroOverrides(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, overrides)
+}
+
+func (h actionHandler) storeMacroOverride(w http.ResponseWriter, r *http.Request) {
+	var data domain.MacroOverride
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.StoreMacroOverride(r.Context(), &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, data)
+}
+
+func (h actionHandler) deleteMacroOverride(w http.ResponseWriter, r *http.Request) {
+	overrideID, err := parseInt(chi.URLParam(r, "id"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("bad param id"))
+		return
+	}
+
+	if err := h.service.DeleteMacroOverride(r.Context(), overrideID); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
+}
+
 func parseInt(s string) (int, error) {
 	u, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
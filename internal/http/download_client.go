@@ -52,6 +52,10 @@ func (h downloadClientHandler) listDownloadClients(w http.ResponseWriter, r *htt
 		return
 	}
 
+	for i := range clients {
+		redactDownloadClient(&clients[i])
+	}
+
 	h.encoder.StatusResponse(w, http.StatusOK, clients)
 }
 
@@ -69,6 +73,8 @@ func (h downloadClientHandler) store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	redactDownloadClient(client)
+
 	h.encoder.StatusResponse(w, http.StatusCreated, client)
 }
 
@@ -102,6 +108,8 @@ func (h downloadClientHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	redactDownloadClient(client)
+
 	h.encoder.StatusResponse(w, http.StatusCreated, client)
 }
 
@@ -126,3 +134,12 @@ func (h downloadClientHandler) delete(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+// redactDownloadClient hides the extra header values, they often hold auth tokens
+func redactDownloadClient(client *domain.DownloadClient) {
+	if client == nil {
+		return
+	}
+
+	client.Settings.ExtraHeaders = client.Settings.ExtraHeaders.Redacted()
+}
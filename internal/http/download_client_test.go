@@ -0,0 +1,47 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *mockDownloadClientService) Update(ctx context.Context, client domain.DownloadClient) (*domain.DownloadClient, error) {
+	return &client, nil
+}
+
+func TestDownloadClientHandler_redactsExtraHeaders(t *testing.T) {
+	svc := &mockDownloadClientService{
+		clients: []domain.DownloadClient{
+			{ID: 1, Name: "sonarr", Type: domain.DownloadClientTypeSonarr, Settings: domain.DownloadClientSettings{ExtraHeaders: domain.ExtraHeaders{"X-Auth-Token": "secret"}}},
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api/download_clients", newDownloadClientHandler(encoder{}, svc).Routes)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/download_clients/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var clients []domain.DownloadClient
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&clients))
+	assert.Equal(t, domain.ExtraHeaders{"X-Auth-Token": domain.ExtraHeaderRedacted}, clients[0].Settings.ExtraHeaders)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/download_clients/", strings.NewReader(`{"id":1,"name":"sonarr","type":"SONARR","settings":{"extra_headers":{"X-Auth-Token":"new-secret"}}}`)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "new-secret")
+}
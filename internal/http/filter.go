@@ -153,8 +153,9 @@ func (h filterHandler) duplicate(w http.ResponseWriter, r *http.Request) {
 
 func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 	var (
-		ctx  = r.Context()
-		data *domain.Filter
+		ctx = r.Context()
+		// filters stop processing further filters on match unless it's disabled
+		data = &domain.Filter{StopOnMatch: true}
 	)
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -172,8 +173,9 @@ func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 
 func (h filterHandler) update(w http.ResponseWriter, r *http.Request) {
 	var (
-		ctx  = r.Context()
-		data *domain.Filter
+		ctx = r.Context()
+		// same default as store so a client that doesn't know the field keeps the default
+		data = &domain.Filter{StopOnMatch: true}
 	)
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
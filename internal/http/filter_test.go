@@ -0,0 +1,63 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFilterService struct {
+	filterService
+	stored  *domain.Filter
+	updated *domain.Filter
+}
+
+func (s *mockFilterService) Store(ctx context.Context, filter *domain.Filter) error {
+	s.stored = filter
+	return nil
+}
+
+func (s *mockFilterService) Update(ctx context.Context, filter *domain.Filter) error {
+	s.updated = filter
+	return nil
+}
+
+func TestFilterHandler_stopOnMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "omitted", body: `{"id":1,"name":"tv"}`, want: true},
+		{name: "enabled", body: `{"id":1,"name":"tv","stop_on_match":true}`, want: true},
+		{name: "disabled", body: `{"id":1,"name":"tv","stop_on_match":false}`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockFilterService{}
+
+			r := chi.NewRouter()
+			r.Route("/api/filters", newFilterHandler(encoder{}, svc).Routes)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/filters/", strings.NewReader(tt.body)))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Equal(t, tt.want, svc.stored.StopOnMatch)
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/filters/1", strings.NewReader(tt.body)))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, svc.updated.StopOnMatch)
+		})
+	}
+}
@@ -4,28 +4,42 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// healthCheckTimeout limits how long a single download client check can take
+const healthCheckTimeout = 10 * time.Second
+
 type healthHandler struct {
 	encoder encoder
 	db      *database.DB
+
+	authenticate func(http.Handler) http.Handler
+	status       *statusChecker
 }
 
-func newHealthHandler(encoder encoder, db *database.DB) *healthHandler {
+func newHealthHandler(encoder encoder, db *database.DB, authenticate func(http.Handler) http.Handler, status *statusChecker) *healthHandler {
 	return &healthHandler{
-		encoder: encoder,
-		db:      db,
+		encoder:      encoder,
+		db:           db,
+		authenticate: authenticate,
+		status:       status,
 	}
 }
 
 func (h healthHandler) Routes(r chi.Router) {
 	r.Get("/liveness", h.handleLiveness)
 	r.Get("/readiness", h.handleReadiness)
+	r.With(h.authenticate).Get("/status", h.handleStatus)
 }
 
 func (h healthHandler) handleLiveness(w http.ResponseWriter, _ *http.Request) {
@@ -41,6 +55,17 @@ func (h healthHandler) handleReadiness(w http.ResponseWriter, _ *http.Request) {
 	writeHealthy(w)
 }
 
+func (h healthHandler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := h.status.Status(r.Context())
+
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+
+	h.encoder.StatusResponse(w, code, status)
+}
+
 func writeHealthy(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(http.StatusOK)
@@ -52,3 +77,138 @@ func writeUnhealthy(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusInternalServerError)
 	w.Write([]byte("Unhealthy. Database unreachable"))
 }
+
+type componentStatus struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type,omitempty"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type systemStatus struct {
+	Healthy         bool              `json:"healthy"`
+	CheckedAt       time.Time         `json:"checked_at"`
+	DownloadClients []componentStatus `json:"download_clients"`
+	IrcNetworks     []componentStatus `json:"irc_networks"`
+}
+
+// statusChecker checks the connectivity of enabled download clients and irc networks.
+// Results are cached for the ttl so frequent scrapes don't hit the clients every time.
+type statusChecker struct {
+	downloadClientService downloadClientService
+	ircService            ircService
+	ttl                   time.Duration
+	now                   func() time.Time
+
+	m      sync.Mutex
+	cached *systemStatus
+}
+
+func newStatusChecker(downloadClientSvc downloadClientService, ircSvc ircService, ttl time.Duration) *statusChecker {
+	return &statusChecker{
+		downloadClientService: downloadClientSvc,
+		ircService:            ircSvc,
+		ttl:                   ttl,
+		now:                   time.Now,
+	}
+}
+
+// Status returns the cached status or runs the checks if it expired.
+// Concurrent callers wait for the running check instead of starting their own.
+func (c *statusChecker) Status(ctx context.Context) *systemStatus {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.cached != nil && c.now().Sub(c.cached.CheckedAt) < c.ttl {
+		return c.cached
+	}
+
+	status := &systemStatus{
+		Healthy:         true,
+		CheckedAt:       c.now(),
+		DownloadClients: c.checkDownloadClients(ctx),
+		IrcNetworks:     c.checkIrcNetworks(ctx),
+	}
+
+	for _, components := range [][]componentStatus{status.DownloadClients, status.IrcNetworks} {
+		for _, component := range components {
+			if !component.Healthy {
+				status.Healthy = false
+			}
+		}
+	}
+
+	c.cached = status
+
+	return status
+}
+
+func (c *statusChecker) checkDownloadClients(ctx context.Context) []componentStatus {
+	statuses := []componentStatus{}
+
+	clients, err := c.downloadClientService.List(ctx)
+	if err != nil {
+		return append(statuses, componentStatus{Name: "download clients", Error: err.Error()})
+	}
+
+	var enabled []domain.DownloadClient
+	for _, client := range clients {
+		if client.Enabled {
+			enabled = append(enabled, client)
+		}
+	}
+
+	statuses = make([]componentStatus, len(enabled))
+
+	var wg sync.WaitGroup
+	for i, client := range enabled {
+		statuses[i] = componentStatus{ID: int64(client.ID), Name: client.Name, Type: string(client.Type)}
+
+		wg.Add(1)
+		go func(status *componentStatus, client domain.DownloadClient) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			if err := c.downloadClientService.Test(ctx, client); err != nil {
+				status.Error = err.Error()
+				return
+			}
+
+			status.Healthy = true
+		}(&statuses[i], client)
+	}
+
+	wg.Wait()
+
+	return statuses
+}
+
+func (c *statusChecker) checkIrcNetworks(ctx context.Context) []componentStatus {
+	statuses := []componentStatus{}
+
+	networks, err := c.ircService.GetNetworksWithHealth(ctx)
+	if err != nil {
+		return append(statuses, componentStatus{Name: "irc networks", Error: err.Error()})
+	}
+
+	for _, network := range networks {
+		if !network.Enabled {
+			continue
+		}
+
+		status := componentStatus{ID: network.ID, Name: network.Name, Healthy: network.Healthy}
+		if !network.Healthy {
+			status.Error = strings.Join(network.ConnectionErrors, ", ")
+			if status.Error == "" && !network.Connected {
+				status.Error = "not connected"
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDownloadClientService struct {
+	downloadClientService
+	clients []domain.DownloadClient
+	failing map[string]error
+	tests   int32
+}
+
+func (s *mockDownloadClientService) List(ctx context.Context) ([]domain.DownloadClient, error) {
+	return s.clients, nil
+}
+
+func (s *mockDownloadClientService) Test(ctx context.Context, client domain.DownloadClient) error {
+	atomic.AddInt32(&s.tests, 1)
+	return s.failing[client.Name]
+}
+
+type mockIrcService struct {
+	ircService
+	networks []domain.IrcNetworkWithHealth
+}
+
+func (s *mockIrcService) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error) {
+	return s.networks, nil
+}
+
+func TestHealthHandler_status(t *testing.T) {
+	clientSvc := &mockDownloadClientService{
+		clients: []domain.DownloadClient{
+			{ID: 1, Name: "qbit", Type: domain.DownloadClientTypeQbittorrent, Enabled: true},
+			{ID: 2, Name: "deluge", Type: domain.DownloadClientTypeDelugeV2, Enabled: true},
+			{ID: 3, Name: "old", Type: domain.DownloadClientTypeTransmission, Enabled: false},
+		},
+		failing: map[string]error{"deluge": errors.New("error logging into client: deluge.domain.ltd")},
+	}
+	ircSvc := &mockIrcService{
+		networks: []domain.IrcNetworkWithHealth{
+			{ID: 1, Name: "network one", Enabled: true, Connected: true, Healthy: true},
+			{ID: 2, Name: "network two", Enabled: true, Connected: false, Healthy: false, ConnectionErrors: []string{"connection refused"}},
+			{ID: 3, Name: "network three", Enabled: false},
+		},
+	}
+
+	checker := newStatusChecker(clientSvc, ircSvc, time.Minute)
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	checker.now = func() time.Time { return now }
+
+	noAuth := func(next http.Handler) http.Handler { return next }
+	r := chi.NewRouter()
+	r.Route("/healthz", newHealthHandler(encoder{}, nil, noAuth, checker).Routes)
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz/status", nil))
+		return w
+	}
+
+	w := get()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{
+		"healthy": false,
+		"checked_at": "2023-10-01T12:00:00Z",
+		"download_clients": [
+			{"id": 1, "name": "qbit", "type": "QBITTORRENT", "healthy": true},
+			{"id": 2, "name": "deluge", "type": "DELUGE_V2", "healthy": false, "error": "error logging into client: deluge.domain.ltd"}
+		],
+		"irc_networks": [
+			{"id": 1, "name": "network one", "healthy": true},
+			{"id": 2, "name": "network two", "healthy": false, "error": "connection refused"}
+		]
+	}`, w.Body.String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&clientSvc.tests))
+
+	// cached within the ttl
+	clientSvc.failing = nil
+	ircSvc.networks = ircSvc.networks[:1]
+
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, get().Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&clientSvc.tests))
+
+	// checked again after the ttl
+	now = now.Add(time.Minute)
+	w = get()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"healthy":true`)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&clientSvc.tests))
+}
@@ -20,6 +20,7 @@ type notificationService interface {
 	Store(ctx context.Context, n domain.Notification) (*domain.Notification, error)
 	Update(ctx context.Context, n domain.Notification) (*domain.Notification, error)
 	Delete(ctx context.Context, id int) error
+	FindLogs(ctx context.Context, notificationID int, limit int) ([]domain.NotificationLog, error)
 	Test(ctx context.Context, notification domain.Notification) error
 }
 
@@ -43,6 +44,7 @@ func (h notificationHandler) Routes(r chi.Router) {
 	r.Route("/{notificationID}", func(r chi.Router) {
 		r.Put("/", h.update)
 		r.Delete("/", h.delete)
+		r.Get("/log", h.findLogs)
 	})
 }
 
@@ -114,6 +116,37 @@ func (h notificationHandler) delete(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
 }
 
+func (h notificationHandler) findLogs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "notificationID parameter is invalid",
+		})
+		return
+	}
+
+	limit := 100
+	if limitP := r.URL.Query().Get("limit"); limitP != "" {
+		limit, err = strconv.Atoi(limitP)
+		if err != nil || limit < 0 {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "limit parameter is invalid",
+			})
+			return
+		}
+	}
+
+	logs, err := h.service.FindLogs(r.Context(), id, limit)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, logs)
+}
+
 func (h notificationHandler) test(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
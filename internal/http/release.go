@@ -6,11 +6,15 @@ package http
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -18,33 +22,55 @@ import (
 type releaseService interface {
 	Find(ctx context.Context, query domain.ReleaseQueryParams) (res []*domain.Release, nextCursor int64, count int64, err error)
 	FindRecent(ctx context.Context) (res []*domain.Release, err error)
+	Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
 	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error
 	Retry(ctx context.Context, req *domain.ReleaseActionRetryReq) error
+	ListPending(ctx context.Context) ([]domain.PendingRelease, error)
+	ApprovePending(ctx context.Context, id int) error
+	RejectPending(ctx context.Context, id int) error
 }
 
 type releaseHandler struct {
 	encoder encoder
 	service releaseService
+
+	authenticate  func(http.Handler) http.Handler
+	sessionSecret string
 }
 
-func newReleaseHandler(encoder encoder, service releaseService) *releaseHandler {
+func newReleaseHandler(encoder encoder, service releaseService, authenticate func(http.Handler) http.Handler, sessionSecret string) *releaseHandler {
 	return &releaseHandler{
-		encoder: encoder,
-		service: service,
+		encoder:       encoder,
+		service:       service,
+		authenticate:  authenticate,
+		sessionSecret: sessionSecret,
 	}
 }
 
 func (h releaseHandler) Routes(r chi.Router) {
-	r.Get("/", h.findReleases)
-	r.Get("/recent", h.findRecentReleases)
-	r.Get("/stats", h.getStats)
-	r.Get("/indexers", h.getIndexerOptions)
-	r.Delete("/", h.deleteReleases)
-
-	r.Route("/{releaseId}", func(r chi.Router) {
-		r.Post("/actions/{actionStatusId}/retry", h.retryAction)
+	// proxied downloads are authenticated by the signature of the url
+	r.Get("/{releaseId}/download", h.downloadRelease)
+
+	r.Group(func(r chi.Router) {
+		r.Use(h.authenticate)
+
+		r.Get("/", h.findReleases)
+		r.Get("/recent", h.findRecentReleases)
+		r.Get("/stats", h.getStats)
+		r.Get("/indexers", h.getIndexerOptions)
+		r.Delete("/", h.deleteReleases)
+
+		r.Route("/pending", func(r chi.Router) {
+			r.Get("/", h.listPending)
+			r.Post("/{pendingId}/approve", h.approvePending)
+			r.Post("/{pendingId}/reject", h.rejectPending)
+		})
+
+		r.Route("/{releaseId}", func(r chi.Router) {
+			r.Post("/actions/{actionStatusId}/retry", h.retryAction)
+		})
 	})
 }
 
@@ -257,3 +283,99 @@ func (h releaseHandler) retryAction(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+// downloadRelease downloads the torrent of the release with the indexer keys and passes it on,
+// so actions can fetch the torrent without the keys being part of the url they get.
+func (h releaseHandler) downloadRelease(w http.ResponseWriter, r *http.Request) {
+	releaseId, err := strconv.Atoi(chi.URLParam(r, "releaseId"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	query := r.URL.Query()
+	if !domain.VerifyProxiedDownload(h.sessionSecret, int64(releaseId), query.Get("expires"), query.Get("signature"), time.Now()) {
+		h.encoder.StatusResponseMessage(w, http.StatusForbidden, "invalid or expired signature")
+		return
+	}
+
+	release, err := h.service.Get(r.Context(), &domain.GetReleaseRequest{Id: releaseId})
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if release == nil {
+		h.encoder.StatusNotFound(w)
+		return
+	}
+
+	if err := release.DownloadTorrentFileCtx(r.Context()); err != nil {
+		h.encoder.StatusError(w, http.StatusBadGateway, err)
+		return
+	}
+	defer release.CleanupTemporaryFiles()
+
+	file, err := os.Open(release.TorrentTmpFile)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/x-bittorrent")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", domain.SanitizeFilename(release.TorrentName)+".torrent"))
+	w.WriteHeader(http.StatusOK)
+
+	io.Copy(w, file)
+}
+
+func (h releaseHandler) listPending(w http.ResponseWriter, r *http.Request) {
+	pending, err := h.service.ListPending(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, pending)
+}
+
+func (h releaseHandler) approvePending(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "pendingId"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.ApprovePending(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) rejectPending(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "pendingId"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.RejectPending(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReleaseService struct {
+	releaseService
+	releases map[int]*domain.Release
+}
+
+func (s *mockReleaseService) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
+	rls, ok := s.releases[req.Id]
+	if !ok {
+		return nil, nil
+	}
+
+	// return a copy like the database would
+	r := *rls
+	return &r, nil
+}
+
+func TestReleaseHandler_downloadRelease(t *testing.T) {
+	torrent, err := os.ReadFile("../domain/testdata/single-tracker.torrent")
+	assert.NoError(t, err)
+
+	tracker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("passkey") != "secretkey" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(torrent)
+	}))
+	defer tracker.Close()
+
+	svc := &mockReleaseService{releases: map[int]*domain.Release{
+		42: {ID: 42, TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP", Protocol: domain.ReleaseProtocolTorrent, DownloadURL: tracker.URL + "/download/1?passkey=secretkey"},
+	}}
+
+	denyAll := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	r := chi.NewRouter()
+	r.Route("/api/release", newReleaseHandler(encoder{}, svc, denyAll, "session-secret").Routes)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		u, err := url.Parse(target)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+		return w
+	}
+
+	expires := time.Now().Add(domain.ProxiedDownloadTTL)
+
+	t.Run("signed", func(t *testing.T) {
+		w := get(domain.ProxiedDownloadURL("http://127.0.0.1:7474", "session-secret", 42, expires))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-bittorrent", w.Header().Get("Content-Type"))
+		assert.Equal(t, torrent, w.Body.Bytes())
+	})
+
+	t.Run("wrong_secret", func(t *testing.T) {
+		w := get(domain.ProxiedDownloadURL("http://127.0.0.1:7474", "other-secret", 42, expires))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		w := get(domain.ProxiedDownloadURL("http://127.0.0.1:7474", "session-secret", 42, time.Now().Add(-time.Minute)))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("unknown_release", func(t *testing.T) {
+		w := get(domain.ProxiedDownloadURL("http://127.0.0.1:7474", "session-secret", 43, expires))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("other_routes_authenticated", func(t *testing.T) {
+		w := get("/api/release/recent")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
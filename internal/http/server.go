@@ -12,6 +12,7 @@ import (
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/web"
 
 	"github.com/go-chi/chi/v5"
@@ -45,6 +46,8 @@ type Server struct {
 	notificationService   notificationService
 	releaseService        releaseService
 	updateService         updateService
+
+	statusChecker *statusChecker
 }
 
 func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, authService authService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, indexerSvc indexerService, ircSvc ircService, notificationSvc notificationService, releaseSvc releaseService, updateSvc updateService) Server {
@@ -70,6 +73,8 @@ func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db
 		notificationService:   notificationSvc,
 		releaseService:        releaseSvc,
 		updateService:         updateSvc,
+
+		statusChecker: newStatusChecker(downloadClientSvc, ircSvc, time.Duration(config.Config.HealthCheckTTL)*time.Second),
 	}
 }
 
@@ -127,7 +132,8 @@ func (s Server) Handler() http.Handler {
 
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/auth", newAuthHandler(encoder, s.log, s.config.Config, s.cookieStore, s.authService).Routes)
-		r.Route("/healthz", newHealthHandler(encoder, s.db).Routes)
+		r.Route("/healthz", newHealthHandler(encoder, s.db, s.IsAuthenticated, s.statusChecker).Routes)
+		r.Route("/release", newReleaseHandler(encoder, s.releaseService, s.IsAuthenticated, s.config.Config.SessionSecret).Routes)
 
 		r.Group(func(r chi.Router) {
 			r.Use(s.IsAuthenticated)
@@ -142,7 +148,6 @@ func (s Server) Handler() http.Handler {
 			r.Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
 			r.Route("/logs", newLogsHandler(s.config).Routes)
 			r.Route("/notification", newNotificationHandler(encoder, s.notificationService).Routes)
-			r.Route("/release", newReleaseHandler(encoder, s.releaseService).Routes)
 			r.Route("/updates", newUpdateHandler(encoder, s.updateService).Routes)
 
 			r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
@@ -160,6 +165,8 @@ func (s Server) Handler() http.Handler {
 		})
 	})
 
+	r.With(s.IsAuthenticated).Handle("/metrics", metrics.Handler())
+
 	// serve the web
 	web.RegisterHandler(r, s.version, s.config.Config.BaseURL)
 
@@ -0,0 +1,208 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/notification"
+
+	"github.com/ergochat/irc-go/ircmsg"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type sentNotification struct {
+	event   domain.NotificationEvent
+	payload domain.NotificationPayload
+}
+
+type mockNotificationService struct {
+	notification.Service
+	sent []sentNotification
+}
+
+func (s *mockNotificationService) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
+	s.sent = append(s.sent, sentNotification{event: event, payload: payload})
+}
+
+func newTestHandler(notificationSvc notification.Service) *Handler {
+	return NewHandler(zerolog.Nop(), nil, domain.IrcNetwork{Name: "P2P-Network", Server: "irc.example.com"}, nil, nil, notificationSvc)
+}
+
+func TestHandler_onDisconnect_notification(t *testing.T) {
+	notificationSvc := &mockNotificationService{}
+	h := newTestHandler(notificationSvc)
+	h.clientState = ircLive
+
+	h.onDisconnect(ircmsg.Message{})
+
+	assert.Len(t, notificationSvc.sent, 1)
+	assert.Equal(t, domain.NotificationEventIRCDisconnected, notificationSvc.sent[0].event)
+	assert.Equal(t, "P2P-Network", notificationSvc.sent[0].payload.Network)
+	assert.True(t, h.haveDisconnected)
+
+	// no notification when we initiated the disconnect
+	h.clientState = ircStopped
+	h.onDisconnect(ircmsg.Message{})
+
+	assert.Len(t, notificationSvc.sent, 1)
+}
+
+func TestHandler_handleSASLFailure_notification(t *testing.T) {
+	notificationSvc := &mockNotificationService{}
+	h := newTestHandler(notificationSvc)
+
+	// reconnect attempts should only notify once
+	h.handleSASLFailure(ircmsg.Message{Command: "904", Params: []string{"autobrr", "SASL authentication failed"}})
+	h.handleSASLFailure(ircmsg.Message{Command: "904", Params: []string{"autobrr", "SASL authentication failed"}})
+
+	assert.Len(t, notificationSvc.sent, 1)
+	assert.Equal(t, domain.NotificationEventIRCAuthFailed, notificationSvc.sent[0].event)
+	assert.Equal(t, "P2P-Network", notificationSvc.sent[0].payload.Network)
+	assert.Contains(t, notificationSvc.sent[0].payload.Message, "authentication failed: SASL")
+	assert.Equal(t, []string{"authentication failed: SASL", "authentication failed: SASL"}, h.connectionErrors)
+}
+
+type socksRequest struct {
+	user, pass string
+	target     string
+}
+
+// startSOCKS5Server runs a stub socks5 server that records the CONNECT target and answers with a PING
+func startSOCKS5Server(t *testing.T) (string, chan socksRequest) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	requests := make(chan socksRequest, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req socksRequest
+
+		// greeting: version, methods
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		methods := make([]byte, header[1])
+		if _, err := io.ReadFull(conn, methods); err != nil {
+			return
+		}
+
+		method := byte(0x00)
+		for _, m := range methods {
+			if m == 0x02 {
+				method = 0x02
+			}
+		}
+		_, _ = conn.Write([]byte{0x05, method})
+
+		// username/password sub negotiation
+		if method == 0x02 {
+			b := make([]byte, 2)
+			if _, err := io.ReadFull(conn, b); err != nil {
+				return
+			}
+			user := make([]byte, b[1])
+			_, _ = io.ReadFull(conn, user)
+			l := make([]byte, 1)
+			_, _ = io.ReadFull(conn, l)
+			pass := make([]byte, l[0])
+			_, _ = io.ReadFull(conn, pass)
+
+			req.user, req.pass = string(user), string(pass)
+			_, _ = conn.Write([]byte{0x01, 0x00})
+		}
+
+		// request: version, cmd, reserved, address type
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(conn, b); err != nil {
+			return
+		}
+
+		var host string
+		switch b[3] {
+		case 0x01:
+			ip := make([]byte, 4)
+			_, _ = io.ReadFull(conn, ip)
+			host = net.IP(ip).String()
+		case 0x03:
+			l := make([]byte, 1)
+			_, _ = io.ReadFull(conn, l)
+			name := make([]byte, l[0])
+			_, _ = io.ReadFull(conn, name)
+			host = string(name)
+		}
+
+		port := make([]byte, 2)
+		_, _ = io.ReadFull(conn, port)
+		req.target = net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+		requests <- req
+
+		_, _ = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+		_, _ = conn.Write([]byte("PING :irc.example.com\r\n"))
+	}()
+
+	return ln.Addr().String(), requests
+}
+
+func Test_proxyDialContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		proxy    string
+		wantUser string
+		wantPass string
+	}{
+		{name: "no_auth", proxy: "socks5://%s"},
+		{name: "auth", proxy: "socks5://bastion:s3cr3t@%s", wantUser: "bastion", wantPass: "s3cr3t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, requests := startSOCKS5Server(t)
+
+			network := domain.IrcNetwork{Name: "P2P-Network", Server: "irc.example.com", Port: 6697, Proxy: fmt.Sprintf(tt.proxy, addr)}
+
+			dial, err := proxyDialContext(network.Proxy)
+			assert.NoError(t, err)
+
+			conn, err := dial(context.Background(), "tcp", fmt.Sprintf("%s:%d", network.Server, network.Port))
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			req := <-requests
+			assert.Equal(t, "irc.example.com:6697", req.target)
+			assert.Equal(t, tt.wantUser, req.user)
+			assert.Equal(t, tt.wantPass, req.pass)
+
+			// the connection is relayed after CONNECT
+			buf := make([]byte, 64)
+			n, err := conn.Read(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, "PING :irc.example.com\r\n", string(buf[:n]))
+		})
+	}
+}
+
+func Test_proxyDialContext_invalid(t *testing.T) {
+	_, err := proxyDialContext("http://proxy.example.com:3128")
+	assert.Error(t, err)
+
+	_, err = proxyDialContext("://bad")
+	assert.Error(t, err)
+}
@@ -214,6 +214,10 @@ func (s *service) checkIfNetworkRestartNeeded(network *domain.IrcNetwork) error
 				restartNeeded = true
 				fieldsChanged = append(fieldsChanged, "bot mode")
 			}
+			if handler.Proxy != network.Proxy {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "proxy")
+			}
 			if handler.Auth.Mechanism != network.Auth.Mechanism {
 				restartNeeded = true
 				fieldsChanged = append(fieldsChanged, "auth mechanism")
@@ -455,6 +459,7 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 			BouncerAddr:      n.BouncerAddr,
 			UseBouncer:       n.UseBouncer,
 			BotMode:          n.BotMode,
+			Proxy:            n.Proxy,
 			Connected:        false,
 			Channels:         []domain.ChannelWithHealth{},
 			ConnectionErrors: []string{},
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "autobrr"
+
+var (
+	releasesMatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "releases_matched_total",
+		Help:      "Releases matched by a filter, by indexer.",
+	}, []string{"indexer"})
+
+	actionsRun = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "actions_total",
+		Help:      "Actions run, by action type and outcome.",
+	}, []string{"type", "outcome"})
+
+	actionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "action_duration_seconds",
+		Help:      "Time taken to run an action, by action type.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"type"})
+
+	notificationsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Notifications sent, by provider and outcome.",
+	}, []string{"provider", "outcome"})
+)
+
+// Registry holds the autobrr metrics together with the go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		releasesMatched,
+		actionsRun,
+		actionDuration,
+		notificationsSent,
+	)
+}
+
+// Handler serves the registry in the prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ReleaseMatched counts a release matched by a filter
+func ReleaseMatched(indexer string) {
+	releasesMatched.WithLabelValues(indexer).Inc()
+}
+
+// ActionRun counts an action run and its duration, outcome is the action result status
+func ActionRun(actionType string, outcome string, duration time.Duration) {
+	actionsRun.WithLabelValues(actionType, outcome).Inc()
+	actionDuration.WithLabelValues(actionType).Observe(duration.Seconds())
+}
+
+// NotificationSent counts a notification sent to a provider
+func NotificationSent(provider string, err error) {
+	outcome := "sent"
+	if err != nil {
+		outcome = "failed"
+	}
+
+	notificationsSent.WithLabelValues(provider, outcome).Inc()
+}
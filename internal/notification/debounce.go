@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+type connectionState string
+
+const (
+	connectionStateConnected    connectionState = "connected"
+	connectionStateDisconnected connectionState = "disconnected"
+)
+
+// transitionEvents are the events that report the state of a connection, every other event is sent right away
+var transitionEvents = map[domain.NotificationEvent]connectionState{
+	domain.NotificationEventIRCDisconnected: connectionStateDisconnected,
+	domain.NotificationEventIRCReconnected:  connectionStateConnected,
+}
+
+// debouncedSender only sends a state transition once the new state held for the settle period.
+// A connection that flaps back to the last notified state within the period sends nothing.
+type debouncedSender struct {
+	log    zerolog.Logger
+	sender domain.NotificationSender
+	settle time.Duration
+
+	// afterFunc can be replaced in tests
+	afterFunc func(d time.Duration, f func())
+
+	m        sync.Mutex
+	notified map[string]connectionState
+	pending  map[string]*pendingTransition
+}
+
+type pendingTransition struct {
+	state   connectionState
+	event   domain.NotificationEvent
+	payload domain.NotificationPayload
+}
+
+func NewDebouncedSender(log zerolog.Logger, settings domain.Notification, sender domain.NotificationSender) domain.NotificationSender {
+	return &debouncedSender{
+		log:    log.With().Str("sender", string(settings.Type)).Str("notification", settings.Name).Logger(),
+		sender: sender,
+		settle: time.Duration(settings.SettlePeriod) * time.Second,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		notified: map[string]connectionState{},
+		pending:  map[string]*pendingTransition{},
+	}
+}
+
+func (s *debouncedSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	state, ok := transitionEvents[event]
+	if !ok {
+		return s.sender.Send(event, payload)
+	}
+
+	key := payload.Network
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// connections are up until told otherwise
+	last, ok := s.notified[key]
+	if !ok {
+		last = connectionStateConnected
+	}
+
+	if state == last {
+		if _, flapped := s.pending[key]; flapped {
+			s.log.Debug().Msgf("network %s went back to %s within the settle period, dropping notification", key, state)
+		}
+
+		delete(s.pending, key)
+
+		return domain.NotificationResult{Suppressed: true}, nil
+	}
+
+	p := &pendingTransition{state: state, event: event, payload: payload}
+	s.pending[key] = p
+
+	s.afterFunc(s.settle, func() {
+		s.settled(key, p)
+	})
+
+	s.log.Debug().Msgf("network %s is %s, waiting %s before notifying", key, state, s.settle)
+
+	return domain.NotificationResult{Suppressed: true}, nil
+}
+
+func (s *debouncedSender) CanSend(event domain.NotificationEvent) bool {
+	return s.sender.CanSend(event)
+}
+
+// settled sends the transition if it's still the latest state of the connection
+func (s *debouncedSender) settled(key string, p *pendingTransition) {
+	s.m.Lock()
+
+	if s.pending[key] != p {
+		s.m.Unlock()
+		return
+	}
+
+	delete(s.pending, key)
+	s.notified[key] = p.state
+	s.m.Unlock()
+
+	if _, err := s.sender.Send(p.event, p.payload); err != nil {
+		s.log.Error().Err(err).Msgf("could not send %s notification", p.event)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDebouncedSender returns a sender with the scheduled settle checks collected instead of run
+func newTestDebouncedSender(sender domain.NotificationSender) (*debouncedSender, *[]func()) {
+	var timers []func()
+
+	s := NewDebouncedSender(zerolog.Nop(), domain.Notification{Name: "discord", Type: domain.NotificationTypeDiscord, SettlePeriod: 60}, sender).(*debouncedSender)
+	s.afterFunc = func(d time.Duration, f func()) { timers = append(timers, f) }
+
+	return s, &timers
+}
+
+func TestDebouncedSender_flapping(t *testing.T) {
+	network := domain.NotificationPayload{Network: "P2P-Network"}
+
+	tests := []struct {
+		name       string
+		events     []domain.NotificationEvent
+		wantEvents []domain.NotificationEvent
+	}{
+		{
+			name:       "settled_disconnect",
+			events:     []domain.NotificationEvent{domain.NotificationEventIRCDisconnected, domain.NotificationEventIRCReconnected, domain.NotificationEventIRCDisconnected},
+			wantEvents: []domain.NotificationEvent{domain.NotificationEventIRCDisconnected},
+		},
+		{
+			name:   "back_to_connected",
+			events: []domain.NotificationEvent{domain.NotificationEventIRCDisconnected, domain.NotificationEventIRCReconnected, domain.NotificationEventIRCDisconnected, domain.NotificationEventIRCReconnected},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingSender{}
+			s, timers := newTestDebouncedSender(rec)
+
+			for _, event := range tt.events {
+				res, err := s.Send(event, network)
+				assert.NoError(t, err)
+				assert.True(t, res.Suppressed)
+			}
+
+			assert.Empty(t, rec.events)
+
+			// the settle period ends for every transition
+			for _, f := range *timers {
+				f()
+			}
+
+			assert.Equal(t, tt.wantEvents, rec.events)
+		})
+	}
+}
+
+func TestDebouncedSender_reconnect(t *testing.T) {
+	rec := &recordingSender{}
+	s, timers := newTestDebouncedSender(rec)
+
+	sendNoError(t, s, domain.NotificationEventIRCDisconnected, domain.NotificationPayload{Network: "P2P-Network"})
+	(*timers)[0]()
+
+	// a different network has its own state
+	sendNoError(t, s, domain.NotificationEventIRCDisconnected, domain.NotificationPayload{Network: "Other-Network"})
+	sendNoError(t, s, domain.NotificationEventIRCReconnected, domain.NotificationPayload{Network: "P2P-Network"})
+	(*timers)[2]()
+
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventIRCDisconnected, domain.NotificationEventIRCReconnected}, rec.events)
+	assert.Equal(t, "P2P-Network", rec.payloads[1].Network)
+}
+
+func TestDebouncedSender_oneShotEvents(t *testing.T) {
+	rec := &recordingSender{}
+	s, timers := newTestDebouncedSender(rec)
+
+	sendNoError(t, s, domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+	sendNoError(t, s, domain.NotificationEventPushError, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventPushApproved, domain.NotificationEventPushError}, rec.events)
+	assert.Empty(t, *timers)
+}
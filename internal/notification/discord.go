@@ -59,7 +59,7 @@ func NewDiscordSender(log zerolog.Logger, settings domain.Notification) domain.N
 	}
 }
 
-func (a *discordSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+func (a *discordSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
 	m := DiscordMessage{
 		Content: nil,
 		Embeds:  []DiscordEmbeds{a.buildEmbed(event, payload)},
@@ -68,17 +68,17 @@ func (a *discordSender) Send(event domain.NotificationEvent, payload domain.Noti
 	jsonData, err := json.Marshal(m)
 	if err != nil {
 		a.log.Error().Err(err).Msgf("discord client could not marshal data: %v", m)
-		return errors.Wrap(err, "could not marshal data: %+v", m)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not marshal data: %+v", m)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, a.Settings.Webhook, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest(http.MethodPost, a.Settings.EventChannel(event, a.Settings.Webhook), bytes.NewBuffer(jsonData))
 	if err != nil {
 		a.log.Error().Err(err).Msgf("discord client request error: %v", event)
-		return errors.Wrap(err, "could not create request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	//req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("User-Agent", a.Settings.UserAgent)
 
 	t := &http.Transport{
 		TLSClientConfig: &tls.Config{
@@ -86,17 +86,17 @@ func (a *discordSender) Send(event domain.NotificationEvent, payload domain.Noti
 		},
 	}
 
-	client := http.Client{Transport: t, Timeout: 30 * time.Second}
+	client := http.Client{Transport: t, Timeout: a.Settings.SendTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
 		a.log.Error().Err(err).Msgf("discord client request error: %v", event)
-		return errors.Wrap(err, "could not make request: %+v", req)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		a.log.Error().Err(err).Msgf("discord client request error: %v", event)
-		return errors.Wrap(err, "could not read data")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
 	}
 
 	defer res.Body.Close()
@@ -106,12 +106,12 @@ func (a *discordSender) Send(event domain.NotificationEvent, payload domain.Noti
 	// discord responds with 204, Notifiarr with 204 so lets take all 200 as ok
 	if res.StatusCode >= 300 {
 		a.log.Error().Err(err).Msgf("discord client request error: %v", string(body))
-		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
 	}
 
 	a.log.Debug().Msg("notification successfully sent to discord")
 
-	return nil
+	return domain.NotificationResult{}, nil
 }
 
 func (a *discordSender) CanSend(event domain.NotificationEvent) bool {
@@ -152,6 +152,16 @@ func (a *discordSender) buildEmbed(event domain.NotificationEvent, payload domai
 		color = RED
 	case domain.NotificationEventIRCReconnected:
 		color = GREEN
+	case domain.NotificationEventIRCAuthFailed:
+		color = RED
+	case domain.NotificationEventReleaseUpgrade:
+		color = GREEN
+	case domain.NotificationEventTorrentStalled:
+		color = RED
+	case domain.NotificationEventActionDisabled:
+		color = RED
+	case domain.NotificationEventReleasePending:
+		color = LIGHT_BLUE
 	case domain.NotificationEventTest:
 		color = LIGHT_BLUE
 	}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscordSender_Send_eventChannels(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	settings := domain.Notification{
+		Enabled: true,
+		Webhook: ts.URL + "/default",
+		EventChannels: map[string]string{
+			string(domain.NotificationEventPushApproved): ts.URL + "/approved",
+			string(domain.NotificationEventPushError):    ts.URL + "/errors",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		event domain.NotificationEvent
+		want  string
+	}{
+		{
+			name:  "approved_override",
+			event: domain.NotificationEventPushApproved,
+			want:  "/approved",
+		},
+		{
+			name:  "error_override",
+			event: domain.NotificationEventPushError,
+			want:  "/errors",
+		},
+		{
+			name:  "default_fallback",
+			event: domain.NotificationEventPushRejected,
+			want:  "/default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			hits = make(map[string]int)
+			mu.Unlock()
+
+			s := NewDiscordSender(zerolog.Nop(), settings)
+
+			_, err := s.Send(tt.event, domain.NotificationPayload{ReleaseName: "Test.Release-GROUP"})
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, map[string]int{tt.want: 1}, hits)
+		})
+	}
+}
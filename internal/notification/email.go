@@ -0,0 +1,257 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// smtpImplicitTLSPort is the submission port that expects TLS from the start, other ports upgrade with STARTTLS if offered
+const smtpImplicitTLSPort = "465"
+
+type emailSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+	builder  NotificationBuilderPlainText
+	timeout  time.Duration
+}
+
+func NewEmailSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &emailSender{
+		log:      log.With().Str("sender", "email").Logger(),
+		Settings: settings,
+		builder:  NotificationBuilderPlainText{},
+		timeout:  settings.SendTimeout(),
+	}
+}
+
+func (s *emailSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	host, port, err := net.SplitHostPort(s.Settings.Host)
+	if err != nil {
+		return domain.NotificationResult{}, errors.Wrap(err, "invalid smtp host, expected host:port: %s", s.Settings.Host)
+	}
+
+	recipients := s.recipients()
+
+	msg, err := s.buildMessage(event, payload, recipients)
+	if err != nil {
+		return domain.NotificationResult{}, errors.Wrap(err, "could not build email")
+	}
+
+	client, err := s.dial(host, port)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("email client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not connect to smtp server: %s", s.Settings.Host)
+	}
+
+	defer client.Close()
+
+	if s.Settings.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return domain.NotificationResult{}, errors.New("smtp server does not support authentication")
+		}
+
+		if err := client.Auth(smtp.PlainAuth("", s.Settings.Username, s.Settings.Password, host)); err != nil {
+			return domain.NotificationResult{}, errors.Wrap(err, "smtp authentication failed")
+		}
+	}
+
+	if err := client.Mail(s.Settings.EmailFrom); err != nil {
+		return domain.NotificationResult{}, errors.Wrap(err, "smtp MAIL FROM failed")
+	}
+
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return domain.NotificationResult{}, errors.Wrap(err, "smtp RCPT TO failed for %s", rcpt)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return domain.NotificationResult{}, errors.Wrap(err, "smtp DATA failed")
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return domain.NotificationResult{}, errors.Wrap(err, "could not write email")
+	}
+
+	if err := w.Close(); err != nil {
+		return domain.NotificationResult{}, errors.Wrap(err, "smtp server rejected email")
+	}
+
+	if err := client.Quit(); err != nil {
+		s.log.Debug().Err(err).Msg("smtp QUIT failed")
+	}
+
+	s.log.Debug().Msg("notification successfully sent to email")
+
+	return domain.NotificationResult{}, nil
+}
+
+// dial connects with implicit TLS on port 465, on other ports STARTTLS is used when the server supports it
+func (s *emailSender) dial(host, port string) (*smtp.Client, error) {
+	tlsConfig := &tls.Config{ServerName: host}
+
+	addr := net.JoinHostPort(host, port)
+	dialer := &net.Dialer{Timeout: s.timeout}
+
+	var conn net.Conn
+	var err error
+
+	if port == smtpImplicitTLSPort {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if port != smtpImplicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, errors.Wrap(err, "STARTTLS failed")
+			}
+		}
+	}
+
+	return client, nil
+}
+
+// buildMessage builds a multipart/alternative message with a plain text and html body
+func (s *emailSender) buildMessage(event domain.NotificationEvent, payload domain.NotificationPayload, recipients []string) ([]byte, error) {
+	title := s.builder.BuildTitle(event)
+	body := s.builder.BuildBody(payload)
+
+	subject := title
+	if payload.ReleaseName != "" {
+		subject = fmt.Sprintf("%s: %s", title, payload.ReleaseName)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []struct{ key, value string }{
+		{"From", s.Settings.EmailFrom},
+		{"To", strings.Join(recipients, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", "autobrr - "+subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", mw.Boundary())},
+	}
+
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h.key, h.value)
+	}
+	buf.WriteString("\r\n")
+
+	htmlBody := fmt.Sprintf("<html><body><h3>%s</h3><p>%s</p></body></html>", html.EscapeString(title), strings.ReplaceAll(html.EscapeString(body), "\n", "<br>\n"))
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", body},
+		{"text/html; charset=utf-8", htmlBody},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// recipients splits the comma separated targets
+func (s *emailSender) recipients() []string {
+	var recipients []string
+	for _, rcpt := range strings.Split(s.Settings.Targets, ",") {
+		if rcpt = strings.TrimSpace(rcpt); rcpt != "" {
+			recipients = append(recipients, rcpt)
+		}
+	}
+
+	return recipients
+}
+
+func (s *emailSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *emailSender) isEnabled() bool {
+	if s.Settings.Enabled {
+		if s.Settings.Host == "" {
+			s.log.Warn().Msg("email missing smtp host")
+			return false
+		}
+
+		if s.Settings.EmailFrom == "" {
+			s.log.Warn().Msg("email missing from address")
+			return false
+		}
+
+		if len(s.recipients()) == 0 {
+			s.log.Warn().Msg("email missing recipients")
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (s *emailSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type smtpEnvelope struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// startSMTPServer runs a minimal plain text smtp server that accepts a single message
+func startSMTPServer(t *testing.T) (string, *smtpEnvelope, *sync.WaitGroup) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	env := &smtpEnvelope{}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+		reply("220 localhost ESMTP stub")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			cmd := strings.ToUpper(line)
+
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(cmd, "AUTH PLAIN"):
+				decoded, _ := base64.StdEncoding.DecodeString(strings.TrimSpace(line[len("AUTH PLAIN"):]))
+				env.auth = string(decoded)
+				reply("235 2.7.0 Authentication successful")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				env.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				env.rcpt = append(env.rcpt, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				env.data = data.String()
+				reply("250 OK queued")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), env, wg
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	addr, env, wg := startSMTPServer(t)
+
+	sender := NewEmailSender(zerolog.Nop(), domain.Notification{
+		Name:      "email",
+		Type:      domain.NotificationTypeEmail,
+		Enabled:   true,
+		Events:    []string{string(domain.NotificationEventPushApproved)},
+		Host:      addr,
+		Username:  "autobrr",
+		Password:  "secret",
+		EmailFrom: "autobrr@example.com",
+		Targets:   "one@example.com, two@example.com,",
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+
+	_, err := sender.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{
+		ReleaseName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		Indexer:     "mock",
+		Filter:      "TV",
+		Status:      domain.ReleasePushStatusApproved,
+	})
+	assert.NoError(t, err)
+
+	wg.Wait()
+
+	// envelope
+	assert.Equal(t, "\x00autobrr\x00secret", env.auth)
+	assert.Equal(t, "autobrr@example.com", env.from)
+	assert.Equal(t, []string{"one@example.com", "two@example.com"}, env.rcpt)
+
+	// headers
+	msg, err := mail.ReadMessage(strings.NewReader(env.data))
+	assert.NoError(t, err)
+	assert.Equal(t, "autobrr@example.com", msg.Header.Get("From"))
+	assert.Equal(t, "one@example.com, two@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "autobrr - Push Approved: That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", msg.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	// plain and html parts
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+
+	var contentTypes []string
+	var bodies []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+
+		data, err := io.ReadAll(part)
+		assert.NoError(t, err)
+
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(data))
+	}
+
+	assert.Equal(t, []string{"text/plain; charset=utf-8", "text/html; charset=utf-8"}, contentTypes)
+	assert.Contains(t, bodies[0], "New release: That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP")
+	assert.Contains(t, bodies[1], "<h3>Push Approved</h3>")
+	assert.Contains(t, bodies[1], "Indexer: mock<br>")
+}
+
+func TestEmailSender_CanSend(t *testing.T) {
+	settings := domain.Notification{
+		Enabled:   true,
+		Events:    []string{string(domain.NotificationEventPushApproved)},
+		Host:      "smtp.example.com:587",
+		EmailFrom: "autobrr@example.com",
+		Targets:   "one@example.com",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(n *domain.Notification)
+		want   bool
+	}{
+		{name: "valid", modify: func(n *domain.Notification) {}, want: true},
+		{name: "missing_host", modify: func(n *domain.Notification) { n.Host = "" }, want: false},
+		{name: "missing_from", modify: func(n *domain.Notification) { n.EmailFrom = "" }, want: false},
+		{name: "missing_recipients", modify: func(n *domain.Notification) { n.Targets = " , " }, want: false},
+		{name: "disabled", modify: func(n *domain.Notification) { n.Enabled = false }, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := settings
+			tt.modify(&n)
+
+			assert.Equal(t, tt.want, NewEmailSender(zerolog.Nop(), n).CanSend(domain.NotificationEventPushApproved))
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -36,7 +35,7 @@ func NewGotifySender(log zerolog.Logger, settings domain.Notification) domain.No
 	}
 }
 
-func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
 	m := gotifyMessage{
 		Message: s.builder.BuildBody(payload),
 		Title:   s.builder.BuildTitle(event),
@@ -50,23 +49,23 @@ func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.Notif
 	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(data.Encode()))
 	if err != nil {
 		s.log.Error().Err(err).Msgf("gotify client request error: %v", event)
-		return errors.Wrap(err, "could not create request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
 
-	client := http.Client{Timeout: 30 * time.Second}
+	client := http.Client{Timeout: s.Settings.SendTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("gotify client request error: %v", event)
-		return errors.Wrap(err, "could not make request: %+v", req)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("gotify client request error: %v", event)
-		return errors.Wrap(err, "could not read data")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
 	}
 
 	defer res.Body.Close()
@@ -75,12 +74,12 @@ func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.Notif
 
 	if res.StatusCode != http.StatusOK {
 		s.log.Error().Err(err).Msgf("gotify client request error: %v", string(body))
-		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
 	}
 
 	s.log.Debug().Msg("notification successfully sent to gotify")
 
-	return nil
+	return domain.NotificationResult{}, nil
 }
 
 func (s *gotifySender) CanSend(event domain.NotificationEvent) bool {
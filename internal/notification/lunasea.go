@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"regexp"
-	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -41,7 +40,7 @@ func NewLunaSeaSender(log zerolog.Logger, settings domain.Notification) domain.N
 	}
 }
 
-func (s *lunaSeaSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+func (s *lunaSeaSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
 	m := LunaSeaMessage{
 		Title: s.builder.BuildTitle(event),
 		Body:  s.builder.BuildBody(payload),
@@ -51,7 +50,7 @@ func (s *lunaSeaSender) Send(event domain.NotificationEvent, payload domain.Noti
 	jsonData, err := json.Marshal(m)
 	if err != nil {
 		s.log.Error().Err(err).Msg("lunasea client could not marshal data")
-		return errors.Wrap(err, "could not marshal data")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not marshal data")
 	}
 
 	rewrittenURL := s.rewriteWebhookURL(s.Settings.Webhook)
@@ -59,28 +58,29 @@ func (s *lunaSeaSender) Send(event domain.NotificationEvent, payload domain.Noti
 	req, err := http.NewRequest(http.MethodPost, rewrittenURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		s.log.Error().Err(err).Msg("lunasea client request error")
-		return errors.Wrap(err, "could not create request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Timeout: s.Settings.SendTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
 		s.log.Error().Err(err).Msg("lunasea client request error")
-		return errors.Wrap(err, "could not make request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request")
 	}
 
 	defer res.Body.Close()
 
 	if res.StatusCode >= 300 {
 		s.log.Error().Msgf("bad status from lunasea: %v", res.StatusCode)
-		return errors.New("bad status: %v", res.StatusCode)
+		return domain.NotificationResult{}, errors.New("bad status: %v", res.StatusCode)
 	}
 
 	s.log.Debug().Msg("notification successfully sent to lunasea")
 
-	return nil
+	return domain.NotificationResult{}, nil
 }
 
 func (s *lunaSeaSender) CanSend(event domain.NotificationEvent) bool {
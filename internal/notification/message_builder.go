@@ -33,9 +33,45 @@ func (b *NotificationBuilderPlainText) BuildBody(payload domain.NotificationPayl
 		parts = append(parts, fmt.Sprintf(" Client: %v", payload.ActionClient))
 	}
 
+	buildPart(len(payload.ActionStatuses) > 0, "\nActions: %v", buildActionSummary(payload.ActionStatuses))
+
 	return strings.Join(parts, "\n")
 }
 
+// buildActionSummary lists the outcome of each action, eg. "qbit (Client: qBittorrent) OK; sonarr FAILED: connection refused"
+func buildActionSummary(statuses []domain.ReleaseActionStatus) string {
+	summary := make([]string, 0, len(statuses))
+
+	for _, status := range statuses {
+		name := status.Action
+		if status.Client != "" {
+			name = fmt.Sprintf("%s (Client: %s)", name, status.Client)
+		}
+
+		switch status.Status {
+		case domain.ReleasePushStatusApproved:
+			summary = append(summary, name+" OK")
+
+		case domain.ReleasePushStatusPending:
+			summary = append(summary, name+" PENDING")
+
+		default:
+			outcome := strings.ToUpper(status.Status.String())
+			if status.Status == domain.ReleasePushStatusErr {
+				outcome = "FAILED"
+			}
+
+			if len(status.Rejections) > 0 {
+				outcome += ": " + strings.Join(status.Rejections, ", ")
+			}
+
+			summary = append(summary, name+" "+outcome)
+		}
+	}
+
+	return strings.Join(summary, "; ")
+}
+
 // BuildTitle constructs the title of the notification message.
 func (b *NotificationBuilderPlainText) BuildTitle(event domain.NotificationEvent) string {
 	titles := map[domain.NotificationEvent]string{
@@ -45,6 +81,11 @@ func (b *NotificationBuilderPlainText) BuildTitle(event domain.NotificationEvent
 		domain.NotificationEventPushError:          "Error",
 		domain.NotificationEventIRCDisconnected:    "IRC Disconnected",
 		domain.NotificationEventIRCReconnected:     "IRC Reconnected",
+		domain.NotificationEventIRCAuthFailed:      "IRC Authentication Failed",
+		domain.NotificationEventReleaseUpgrade:     "Release Upgrade",
+		domain.NotificationEventTorrentStalled:     "Torrent Stalled",
+		domain.NotificationEventActionDisabled:     "Action Disabled",
+		domain.NotificationEventReleasePending:     "Release Pending Approval",
 		domain.NotificationEventTest:               "Test",
 	}
 
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationBuilderPlainText_BuildBody_actionStatuses(t *testing.T) {
+	payload := domain.NotificationPayload{
+		Event:       domain.NotificationEventPushError,
+		ReleaseName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		Status:      domain.ReleasePushStatusErr,
+		Indexer:     "mock",
+		Filter:      "tv",
+		Action:      "sonarr push",
+		ActionType:  domain.ActionTypeSonarr,
+		Rejections:  []string{"connection refused"},
+		ActionStatuses: []domain.ReleaseActionStatus{
+			{Action: "qbit", Type: domain.ActionTypeQbittorrent, Client: "qBittorrent", Status: domain.ReleasePushStatusApproved},
+			{Action: "deluge", Type: domain.ActionTypeDelugeV2, Status: domain.ReleasePushStatusRejected, Rejections: []string{"max active downloads reached, skipping"}},
+			{Action: "sonarr push", Type: domain.ActionTypeSonarr, Status: domain.ReleasePushStatusErr, Rejections: []string{"connection refused"}},
+		},
+	}
+
+	b := NotificationBuilderPlainText{}
+	body := b.BuildBody(payload)
+
+	assert.Contains(t, body, "\nActions: qbit (Client: qBittorrent) OK; deluge REJECTED: max active downloads reached, skipping; sonarr push FAILED: connection refused")
+	assert.Contains(t, body, "\nAction: sonarr push Type: SONARR")
+
+	// a single action has no summary
+	payload.ActionStatuses = nil
+	assert.NotContains(t, b.BuildBody(payload), "Actions:")
+}
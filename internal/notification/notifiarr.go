@@ -55,7 +55,7 @@ func NewNotifiarrSender(log zerolog.Logger, settings domain.Notification) domain
 	}
 }
 
-func (s *notifiarrSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+func (s *notifiarrSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
 	m := notifiarrMessage{
 		Event: string(event),
 		Data:  s.buildMessage(payload),
@@ -64,17 +64,17 @@ func (s *notifiarrSender) Send(event domain.NotificationEvent, payload domain.No
 	jsonData, err := json.Marshal(m)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("notifiarr client could not marshal data: %v", m)
-		return errors.Wrap(err, "could not marshal data: %+v", m)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not marshal data: %+v", m)
 	}
 
 	req, err := http.NewRequest(http.MethodPost, s.baseUrl, bytes.NewBuffer(jsonData))
 	if err != nil {
 		s.log.Error().Err(err).Msgf("notifiarr client request error: %v", event)
-		return errors.Wrap(err, "could not create request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
 	req.Header.Set("X-API-Key", s.Settings.APIKey)
 
 	t := &http.Transport{
@@ -83,17 +83,17 @@ func (s *notifiarrSender) Send(event domain.NotificationEvent, payload domain.No
 		},
 	}
 
-	client := http.Client{Transport: t, Timeout: 30 * time.Second}
+	client := http.Client{Transport: t, Timeout: s.Settings.SendTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("notifiarr client request error: %v", event)
-		return errors.Wrap(err, "could not make request: %+v", req)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("notifiarr client request error: %v", event)
-		return errors.Wrap(err, "could not read data")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
 	}
 
 	defer res.Body.Close()
@@ -102,12 +102,12 @@ func (s *notifiarrSender) Send(event domain.NotificationEvent, payload domain.No
 
 	if res.StatusCode != http.StatusOK {
 		s.log.Error().Err(err).Msgf("notifiarr client request error: %v", string(body))
-		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
 	}
 
 	s.log.Debug().Msg("notification successfully sent to notifiarr")
 
-	return nil
+	return domain.NotificationResult{}, nil
 }
 
 func (s *notifiarrSender) CanSend(event domain.NotificationEvent) bool {
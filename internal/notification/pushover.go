@@ -4,6 +4,8 @@
 package notification
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,6 +28,16 @@ type pushoverMessage struct {
 	Title     string    `json:"title"`
 	Timestamp time.Time `json:"timestamp"`
 	Html      int       `json:"html,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	URLTitle  string    `json:"url_title,omitempty"`
+}
+
+// pushoverMaxAttachmentSize is the largest attachment pushover accepts
+const pushoverMaxAttachmentSize = 5 * 1024 * 1024
+
+type pushoverResponse struct {
+	Status  int    `json:"status"`
+	Request string `json:"request"`
 }
 
 type pushoverSender struct {
@@ -43,7 +55,7 @@ func NewPushoverSender(log zerolog.Logger, settings domain.Notification) domain.
 	}
 }
 
-func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
 
 	title := s.builder.BuildTitle(event)
 	message := s.builder.BuildBody(payload)
@@ -58,6 +70,15 @@ func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.Not
 		Html:      1,
 	}
 
+	// link back to the release on the tracker
+	if payload.InfoURL != "" {
+		m.URL = payload.InfoURL
+		m.URLTitle = "View on " + payload.Indexer
+		if payload.Indexer == "" {
+			m.URLTitle = "View release"
+		}
+	}
+
 	data := url.Values{}
 	data.Set("token", m.Token)
 	data.Set("user", m.User)
@@ -67,6 +88,21 @@ func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.Not
 	data.Set("timestamp", fmt.Sprintf("%v", m.Timestamp.Unix()))
 	data.Set("html", fmt.Sprintf("%v", m.Html))
 
+	if m.URL != "" {
+		data.Set("url", m.URL)
+		data.Set("url_title", m.URLTitle)
+	}
+
+	if s.Settings.ImageURL != "" {
+		attachment, contentType, err := s.fetchAttachment(s.Settings.ImageURL)
+		if err != nil {
+			s.log.Warn().Err(err).Msg("pushover could not attach image, sending without it")
+		} else {
+			data.Set("attachment_base64", attachment)
+			data.Set("attachment_type", contentType)
+		}
+	}
+
 	if m.Priority == 2 {
 		data.Set("expire", "3600")
 		data.Set("retry", "60")
@@ -75,23 +111,23 @@ func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.Not
 	req, err := http.NewRequest(http.MethodPost, s.baseUrl, strings.NewReader(data.Encode()))
 	if err != nil {
 		s.log.Error().Err(err).Msgf("pushover client request error: %v", event)
-		return errors.Wrap(err, "could not create request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
 
-	client := http.Client{Timeout: 30 * time.Second}
+	client := http.Client{Timeout: s.Settings.SendTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("pushover client request error: %v", event)
-		return errors.Wrap(err, "could not make request: %+v", req)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("pushover client request error: %v", event)
-		return errors.Wrap(err, "could not read data")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
 	}
 
 	defer res.Body.Close()
@@ -100,12 +136,56 @@ func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.Not
 
 	if res.StatusCode != http.StatusOK {
 		s.log.Error().Err(err).Msgf("pushover client request error: %v", string(body))
-		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
 	}
 
-	s.log.Debug().Msg("notification successfully sent to pushover")
+	var response pushoverResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		s.log.Warn().Err(err).Msgf("pushover could not parse response: %v", string(body))
+	}
+
+	s.log.Debug().Msgf("notification successfully sent to pushover, request: %s", response.Request)
+
+	return domain.NotificationResult{MessageID: response.Request}, nil
+}
+
+// fetchAttachment downloads the image and returns it base64 encoded with its content type
+func (s *pushoverSender) fetchAttachment(imageURL string) (string, string, error) {
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
+
+	client := http.Client{Timeout: s.Settings.SendTimeout()}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not fetch image: %s", imageURL)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", "", errors.New("bad status fetching image: %d", res.StatusCode)
+	}
+
+	// read one byte more to tell if the image is too large
+	body, err := io.ReadAll(io.LimitReader(res.Body, pushoverMaxAttachmentSize+1))
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not read image")
+	}
+
+	if len(body) > pushoverMaxAttachmentSize {
+		return "", "", errors.New("image is larger than %d bytes", pushoverMaxAttachmentSize)
+	}
+
+	contentType := http.DetectContentType(body)
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", "", errors.New("not an image: %s", contentType)
+	}
 
-	return nil
+	return base64.StdEncoding.EncodeToString(body), contentType, nil
 }
 
 func (s *pushoverSender) CanSend(event domain.NotificationEvent) bool {
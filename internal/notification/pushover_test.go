@@ -0,0 +1,192 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushoverSender_Send_messageID(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		response      string
+		wantMessageID string
+		wantErr       bool
+	}{
+		{
+			name:          "request_id",
+			status:        http.StatusOK,
+			response:      `{"status":1,"request":"647d2300-702c-4b38-8b2f-d56326ae460b"}`,
+			wantMessageID: "647d2300-702c-4b38-8b2f-d56326ae460b",
+		},
+		{
+			name:     "invalid_response",
+			status:   http.StatusOK,
+			response: `ok`,
+		},
+		{
+			name:     "bad_status",
+			status:   http.StatusBadRequest,
+			response: `{"user":"invalid","errors":["user identifier is invalid"],"status":0,"request":"5042853c-402d-4a18-abcb-168734a801de"}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer ts.Close()
+
+			s := NewPushoverSender(zerolog.Nop(), domain.Notification{
+				Enabled: true,
+				Events:  []string{string(domain.NotificationEventPushApproved)},
+				Token:   "user",
+				APIKey:  "key",
+			}).(*pushoverSender)
+			s.baseUrl = ts.URL
+
+			res, err := s.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMessageID, res.MessageID)
+		})
+	}
+}
+
+func TestPushoverSender_Send_url(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      domain.NotificationPayload
+		wantURL      string
+		wantURLTitle string
+	}{
+		{
+			name:         "info_url",
+			payload:      domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP", Indexer: "MockIndexer", InfoURL: "https://tracker.example/torrents.php?id=1234"},
+			wantURL:      "https://tracker.example/torrents.php?id=1234",
+			wantURLTitle: "View on MockIndexer",
+		},
+		{
+			name:    "no_info_url",
+			payload: domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP", Indexer: "MockIndexer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				form = r.PostForm
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":1,"request":"647d2300-702c-4b38-8b2f-d56326ae460b"}`))
+			}))
+			defer ts.Close()
+
+			s := NewPushoverSender(zerolog.Nop(), domain.Notification{
+				Enabled: true,
+				Events:  []string{string(domain.NotificationEventPushApproved)},
+				Token:   "user",
+				APIKey:  "key",
+			}).(*pushoverSender)
+			s.baseUrl = ts.URL
+
+			_, err := s.Send(domain.NotificationEventPushApproved, tt.payload)
+			assert.NoError(t, err)
+
+			if tt.wantURL == "" {
+				assert.NotContains(t, form, "url")
+				assert.NotContains(t, form, "url_title")
+				return
+			}
+
+			assert.Equal(t, tt.wantURL, form.Get("url"))
+			assert.Equal(t, tt.wantURLTitle, form.Get("url_title"))
+		})
+	}
+}
+
+func TestPushoverSender_Send_attachment(t *testing.T) {
+	var img bytes.Buffer
+	assert.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/poster.png":
+			_, _ = w.Write(img.Bytes())
+		case "/page.html":
+			_, _ = w.Write([]byte("<html><body>not an image</body></html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer images.Close()
+
+	tests := []struct {
+		name     string
+		imageURL string
+		want     bool
+	}{
+		{name: "image", imageURL: images.URL + "/poster.png", want: true},
+		{name: "no_image"},
+		{name: "not_an_image", imageURL: images.URL + "/page.html"},
+		{name: "not_found", imageURL: images.URL + "/missing.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				form = r.PostForm
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":1,"request":"647d2300-702c-4b38-8b2f-d56326ae460b"}`))
+			}))
+			defer ts.Close()
+
+			s := NewPushoverSender(zerolog.Nop(), domain.Notification{
+				Enabled:  true,
+				Events:   []string{string(domain.NotificationEventPushApproved)},
+				Token:    "user",
+				APIKey:   "key",
+				ImageURL: tt.imageURL,
+			}).(*pushoverSender)
+			s.baseUrl = ts.URL
+
+			// the message is sent with or without the image
+			_, err := s.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+			assert.NoError(t, err)
+			assert.NotEmpty(t, form.Get("message"))
+
+			if !tt.want {
+				assert.NotContains(t, form, "attachment_base64")
+				assert.NotContains(t, form, "attachment_type")
+				return
+			}
+
+			assert.Equal(t, base64.StdEncoding.EncodeToString(img.Bytes()), form.Get("attachment_base64"))
+			assert.Equal(t, "image/png", form.Get("attachment_type"))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitedSender wraps a sender with a token bucket of RateLimit messages per minute.
+// Messages exceeding the limit are dropped.
+type rateLimitedSender struct {
+	log     zerolog.Logger
+	sender  domain.NotificationSender
+	limiter *rate.Limiter
+	limit   int
+}
+
+func NewRateLimitedSender(log zerolog.Logger, settings domain.Notification, sender domain.NotificationSender) domain.NotificationSender {
+	return &rateLimitedSender{
+		log:     log.With().Str("sender", string(settings.Type)).Str("notification", settings.Name).Logger(),
+		sender:  sender,
+		limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(settings.RateLimit)), settings.RateLimit),
+		limit:   settings.RateLimit,
+	}
+}
+
+func (s *rateLimitedSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	if !s.limiter.Allow() {
+		s.log.Warn().Msgf("rate limit of %d messages per minute reached, dropping notification for event: %s release: %s", s.limit, event, payload.ReleaseName)
+		return domain.NotificationResult{Suppressed: true}, nil
+	}
+
+	return s.sender.Send(event, payload)
+}
+
+func (s *rateLimitedSender) CanSend(event domain.NotificationEvent) bool {
+	return s.sender.CanSend(event)
+}
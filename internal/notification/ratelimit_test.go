@@ -0,0 +1,53 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSender struct {
+	sent int
+}
+
+func (s *mockSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	s.sent++
+	return domain.NotificationResult{}, nil
+}
+
+func (s *mockSender) CanSend(event domain.NotificationEvent) bool {
+	return true
+}
+
+func TestRateLimitedSender_Send(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit int
+		sends     int
+		want      int
+	}{
+		{name: "below_limit", rateLimit: 5, sends: 3, want: 3},
+		{name: "at_limit", rateLimit: 5, sends: 5, want: 5},
+		{name: "above_limit", rateLimit: 5, sends: 20, want: 5},
+		{name: "single", rateLimit: 1, sends: 10, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSender{}
+			sender := NewRateLimitedSender(zerolog.Nop(), domain.Notification{Name: "test", Type: domain.NotificationTypeDiscord, RateLimit: tt.rateLimit}, mock)
+
+			for i := 0; i < tt.sends; i++ {
+				_, err := sender.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, mock.sent)
+		})
+	}
+}
@@ -5,12 +5,15 @@ package notification
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/rs/zerolog"
@@ -22,21 +25,45 @@ type Service interface {
 	Store(ctx context.Context, n domain.Notification) (*domain.Notification, error)
 	Update(ctx context.Context, n domain.Notification) (*domain.Notification, error)
 	Delete(ctx context.Context, id int) error
+	FindLogs(ctx context.Context, notificationID int, limit int) ([]domain.NotificationLog, error)
 	Send(event domain.NotificationEvent, payload domain.NotificationPayload)
 	Test(ctx context.Context, notification domain.Notification) error
 }
 
 type service struct {
-	log     zerolog.Logger
-	repo    domain.NotificationRepo
-	senders []domain.NotificationSender
+	log      zerolog.Logger
+	repo     domain.NotificationRepo
+	dispatch domain.NotificationDispatchMode
+	senders  []registeredSender
+	version  string
 }
 
-func NewService(log logger.Logger, repo domain.NotificationRepo) Service {
+// registeredSender keeps the notification config next to its sender for ordering and results
+type registeredSender struct {
+	notification domain.Notification
+	sender       domain.NotificationSender
+}
+
+// SendResult is the outcome of dispatching an event to a single sender
+type SendResult struct {
+	Name      string
+	Type      domain.NotificationType
+	MessageID string
+	Err       error
+	Skipped   bool
+}
+
+func NewService(log logger.Logger, config *domain.Config, repo domain.NotificationRepo) Service {
 	s := &service{
-		log:     log.With().Str("module", "notification").Logger(),
-		repo:    repo,
-		senders: []domain.NotificationSender{},
+		log:      log.With().Str("module", "notification").Logger(),
+		repo:     repo,
+		dispatch: domain.NotificationDispatchBestEffort,
+		senders:  []registeredSender{},
+		version:  config.Version,
+	}
+
+	if domain.NotificationDispatchMode(config.NotificationDispatch) == domain.NotificationDispatchFailFast {
+		s.dispatch = domain.NotificationDispatchFailFast
 	}
 
 	s.registerSenders()
@@ -72,7 +99,7 @@ func (s *service) Store(ctx context.Context, n domain.Notification) (*domain.Not
 	}
 
 	// reset senders
-	s.senders = []domain.NotificationSender{}
+	s.senders = []registeredSender{}
 
 	// re register senders
 	s.registerSenders()
@@ -88,7 +115,7 @@ func (s *service) Update(ctx context.Context, n domain.Notification) (*domain.No
 	}
 
 	// reset senders
-	s.senders = []domain.NotificationSender{}
+	s.senders = []registeredSender{}
 
 	// re register senders
 	s.registerSenders()
@@ -104,7 +131,7 @@ func (s *service) Delete(ctx context.Context, id int) error {
 	}
 
 	// reset senders
-	s.senders = []domain.NotificationSender{}
+	s.senders = []registeredSender{}
 
 	// re register senders
 	s.registerSenders()
@@ -121,23 +148,57 @@ func (s *service) registerSenders() {
 
 	for _, n := range senders {
 		if n.Enabled {
+			var sender domain.NotificationSender
+
+			n.UserAgent = domain.UserAgent(n.UserAgent, s.version)
+
 			switch n.Type {
 			case domain.NotificationTypeDiscord:
-				s.senders = append(s.senders, NewDiscordSender(s.log, n))
+				sender = NewDiscordSender(s.log, n)
 			case domain.NotificationTypeNotifiarr:
-				s.senders = append(s.senders, NewNotifiarrSender(s.log, n))
+				sender = NewNotifiarrSender(s.log, n)
 			case domain.NotificationTypeTelegram:
-				s.senders = append(s.senders, NewTelegramSender(s.log, n))
+				sender = NewTelegramSender(s.log, n)
 			case domain.NotificationTypePushover:
-				s.senders = append(s.senders, NewPushoverSender(s.log, n))
+				sender = NewPushoverSender(s.log, n)
 			case domain.NotificationTypeGotify:
-				s.senders = append(s.senders, NewGotifySender(s.log, n))
+				sender = NewGotifySender(s.log, n)
 			case domain.NotificationTypeLunaSea:
-				s.senders = append(s.senders, NewLunaSeaSender(s.log, n))
+				sender = NewLunaSeaSender(s.log, n)
+			case domain.NotificationTypeSlack:
+				sender = NewSlackSender(s.log, n)
+			case domain.NotificationTypeEmail:
+				sender = NewEmailSender(s.log, n)
+			case domain.NotificationTypeTeams:
+				sender = NewTeamsSender(s.log, n)
+			default:
+				continue
+			}
+
+			// protect the sender from getting spammed by a runaway filter
+			if n.RateLimit > 0 {
+				sender = NewRateLimitedSender(s.log, n, sender)
+			}
+
+			// coalesce messages to targets with a monthly quota
+			if n.MinInterval > 0 || n.MaxPerHour > 0 {
+				sender = NewThrottledSender(s.log, n, sender)
+			}
+
+			// only notify connection changes that last, the limits above apply to the settled messages
+			if n.SettlePeriod > 0 {
+				sender = NewDebouncedSender(s.log, n, sender)
 			}
+
+			s.senders = append(s.senders, registeredSender{notification: n, sender: sender})
 		}
 	}
 
+	// senders with the same dispatch order keep the repo order
+	sort.SliceStable(s.senders, func(i, j int) bool {
+		return s.senders[i].notification.DispatchOrder < s.senders[j].notification.DispatchOrder
+	})
+
 	return
 }
 
@@ -148,17 +209,119 @@ func (s *service) Send(event domain.NotificationEvent, payload domain.Notificati
 	}
 
 	go func() {
-		for _, sender := range s.senders {
-			// check if sender is active and have notification types
-			if sender.CanSend(event) {
-				sender.Send(event, payload)
-			}
-		}
+		s.logResults(event, s.dispatchEvent(event, payload))
 	}()
 
 	return
 }
 
+// logResults logs the outcome of all senders of the event, a warning if any of them failed
+func (s *service) logResults(event domain.NotificationEvent, results []SendResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	var sent int
+	var failed, skipped []string
+
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			skipped = append(skipped, result.Name)
+		case result.Err != nil:
+			failed = append(failed, result.Name)
+		default:
+			sent++
+		}
+	}
+
+	if len(failed) > 0 {
+		s.log.Warn().Msgf("notification %s sent to %d of %d senders, failed: %s skipped: %s", event, sent, len(results), strings.Join(failed, ", "), strings.Join(skipped, ", "))
+		return
+	}
+
+	s.log.Debug().Msgf("notification %s sent to %d of %d senders", event, sent, len(results))
+}
+
+// dispatchEvent sends the event to the senders in order and collects the result of each.
+// In fail-fast mode the remaining senders are skipped after the first failure.
+func (s *service) dispatchEvent(event domain.NotificationEvent, payload domain.NotificationPayload) []SendResult {
+	var results []SendResult
+	failed := false
+
+	for _, rs := range s.senders {
+		// check if sender is active and have notification types
+		if !rs.sender.CanSend(event) {
+			continue
+		}
+
+		// only real grabs and failures, not every release an action skipped
+		if rs.notification.SuppressSkipped && payload.ActionOutcome == domain.ActionResultStatusSkipped {
+			s.log.Trace().Msgf("notification %s suppressed skipped action %s for release: %s", rs.notification.Name, payload.Action, payload.ReleaseName)
+			continue
+		}
+
+		result := SendResult{Name: rs.notification.Name, Type: rs.notification.Type}
+
+		if failed && s.dispatch == domain.NotificationDispatchFailFast {
+			result.Skipped = true
+			results = append(results, result)
+			continue
+		}
+
+		res, err := rs.sender.Send(event, payload)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not send %s notification to %s", event, rs.notification.Name)
+
+			result.Err = err
+			failed = true
+		}
+
+		metrics.NotificationSent(string(rs.notification.Type), err)
+
+		result.MessageID = res.MessageID
+
+		if !res.Suppressed {
+			s.storeLog(rs.notification, event, res, err)
+		}
+
+		results = append(results, result)
+	}
+
+	if failed && s.dispatch == domain.NotificationDispatchFailFast {
+		s.log.Warn().Msgf("notification %s stopped after failure, remaining senders skipped", event)
+	}
+
+	return results
+}
+
+// storeLog records the sent message with the message id returned by the target
+func (s *service) storeLog(notification domain.Notification, event domain.NotificationEvent, res domain.NotificationResult, sendErr error) {
+	l := &domain.NotificationLog{
+		NotificationID: notification.ID,
+		Event:          event,
+		MessageID:      res.MessageID,
+	}
+
+	if sendErr != nil {
+		l.Error = sendErr.Error()
+	}
+
+	if err := s.repo.StoreLog(context.Background(), l); err != nil {
+		s.log.Error().Err(err).Msgf("could not store notification log for %s", notification.Name)
+	}
+}
+
+func (s *service) FindLogs(ctx context.Context, notificationID int, limit int) ([]domain.NotificationLog, error) {
+	logs, err := s.repo.FindLogs(ctx, notificationID, limit)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find notification logs for: %d", notificationID)
+		return nil, err
+	}
+
+	return logs, nil
+}
+
 func (s *service) Test(ctx context.Context, notification domain.Notification) error {
 	var agent domain.NotificationSender
 
@@ -222,12 +385,21 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Subject:   "IRC Disconnected unexpectedly",
 			Message:   "Network: P2P-Network",
 			Event:     domain.NotificationEventIRCDisconnected,
+			Network:   "P2P-Network",
 			Timestamp: time.Now(),
 		},
 		{
 			Subject:   "IRC Reconnected",
 			Message:   "Network: P2P-Network",
 			Event:     domain.NotificationEventIRCReconnected,
+			Network:   "P2P-Network",
+			Timestamp: time.Now(),
+		},
+		{
+			Subject:   "IRC Authentication Failed",
+			Message:   "Network: P2P-Network\nReason: authentication failed: Bad account credentials",
+			Event:     domain.NotificationEventIRCAuthFailed,
+			Network:   "P2P-Network",
 			Timestamp: time.Now(),
 		},
 		{
@@ -238,6 +410,8 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 		},
 	}
 
+	notification.UserAgent = domain.UserAgent(notification.UserAgent, s.version)
+
 	switch notification.Type {
 	case domain.NotificationTypeDiscord:
 		agent = NewDiscordSender(s.log, notification)
@@ -251,6 +425,12 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 		agent = NewGotifySender(s.log, notification)
 	case domain.NotificationTypeLunaSea:
 		agent = NewLunaSeaSender(s.log, notification)
+	case domain.NotificationTypeSlack:
+		agent = NewSlackSender(s.log, notification)
+	case domain.NotificationTypeEmail:
+		agent = NewEmailSender(s.log, notification)
+	case domain.NotificationTypeTeams:
+		agent = NewTeamsSender(s.log, notification)
 	default:
 		s.log.Error().Msgf("unsupported notification type: %v", notification.Type)
 		return errors.New("unsupported notification type")
@@ -265,7 +445,7 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			continue
 		}
 
-		if err := agent.Send(e.Event, e); err != nil {
+		if _, err := agent.Send(e.Event, e); err != nil {
 			s.log.Error().Err(err).Msgf("error sending test notification: %#v", notification)
 			return err
 		}
@@ -0,0 +1,386 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderedSender struct {
+	name      string
+	calls     *[]string
+	messageID string
+	err       error
+}
+
+func (s *orderedSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	*s.calls = append(*s.calls, s.name)
+	return domain.NotificationResult{MessageID: s.messageID}, s.err
+}
+
+func (s *orderedSender) CanSend(event domain.NotificationEvent) bool {
+	return true
+}
+
+func TestService_dispatchEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      domain.NotificationDispatchMode
+		wantCalls []string
+		want      []SendResult
+	}{
+		{
+			name:      "best_effort",
+			mode:      domain.NotificationDispatchBestEffort,
+			wantCalls: []string{"discord", "telegram", "gotify"},
+			want: []SendResult{
+				{Name: "discord", Type: domain.NotificationTypeDiscord, MessageID: "1234"},
+				{Name: "telegram", Type: domain.NotificationTypeTelegram, Err: errors.New("telegram is down")},
+				{Name: "gotify", Type: domain.NotificationTypeGotify},
+			},
+		},
+		{
+			name:      "fail_fast",
+			mode:      domain.NotificationDispatchFailFast,
+			wantCalls: []string{"discord", "telegram"},
+			want: []SendResult{
+				{Name: "discord", Type: domain.NotificationTypeDiscord, MessageID: "1234"},
+				{Name: "telegram", Type: domain.NotificationTypeTelegram, Err: errors.New("telegram is down")},
+				{Name: "gotify", Type: domain.NotificationTypeGotify, Skipped: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			repo := &mockNotificationRepo{}
+
+			s := &service{
+				log:      zerolog.Nop(),
+				repo:     repo,
+				dispatch: tt.mode,
+				senders: []registeredSender{
+					{notification: domain.Notification{ID: 1, Name: "discord", Type: domain.NotificationTypeDiscord}, sender: &orderedSender{name: "discord", calls: &calls, messageID: "1234"}},
+					{notification: domain.Notification{ID: 2, Name: "telegram", Type: domain.NotificationTypeTelegram}, sender: &orderedSender{name: "telegram", calls: &calls, err: errors.New("telegram is down")}},
+					{notification: domain.Notification{Name: "gotify", Type: domain.NotificationTypeGotify}, sender: &orderedSender{name: "gotify", calls: &calls}},
+				},
+			}
+
+			results := s.dispatchEvent(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Len(t, results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.Name, results[i].Name)
+				assert.Equal(t, want.Type, results[i].Type)
+				assert.Equal(t, want.Skipped, results[i].Skipped)
+				assert.Equal(t, want.MessageID, results[i].MessageID)
+
+				if want.Err != nil {
+					assert.EqualError(t, results[i].Err, want.Err.Error())
+				} else {
+					assert.NoError(t, results[i].Err)
+				}
+			}
+
+			// skipped senders are not logged
+			if assert.GreaterOrEqual(t, len(repo.logs), 2) {
+				assert.Equal(t, domain.NotificationLog{NotificationID: 1, Event: domain.NotificationEventPushApproved, MessageID: "1234"}, repo.logs[0])
+				assert.Equal(t, domain.NotificationLog{NotificationID: 2, Event: domain.NotificationEventPushApproved, Error: "telegram is down"}, repo.logs[1])
+			}
+			assert.Len(t, repo.logs, len(tt.wantCalls))
+		})
+	}
+}
+
+// lockedBuffer is a log writer that can be read while the sender goroutine writes
+type lockedBuffer struct {
+	m   sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.buf.String()
+}
+
+func TestService_Send_logsResults(t *testing.T) {
+	var calls []string
+	var logs lockedBuffer
+
+	s := &service{
+		log:      zerolog.New(&logs),
+		repo:     &mockNotificationRepo{},
+		dispatch: domain.NotificationDispatchFailFast,
+		senders: []registeredSender{
+			{notification: domain.Notification{ID: 1, Name: "discord", Type: domain.NotificationTypeDiscord}, sender: &orderedSender{name: "discord", calls: &calls, messageID: "1234"}},
+			{notification: domain.Notification{ID: 2, Name: "telegram", Type: domain.NotificationTypeTelegram}, sender: &orderedSender{name: "telegram", calls: &calls, err: errors.New("telegram is down")}},
+			{notification: domain.Notification{Name: "gotify", Type: domain.NotificationTypeGotify}, sender: &orderedSender{name: "gotify", calls: &calls}},
+		},
+	}
+
+	s.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "notification PUSH_APPROVED sent to 1 of 3 senders, failed: telegram skipped: gotify")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestService_dispatchEvent_suppressSkipped(t *testing.T) {
+	tests := []struct {
+		name            string
+		suppressSkipped bool
+		event           domain.NotificationEvent
+		outcome         domain.ActionResultStatus
+		wantCalls       []string
+	}{
+		{
+			name:            "skipped_suppressed",
+			suppressSkipped: true,
+			event:           domain.NotificationEventPushRejected,
+			outcome:         domain.ActionResultStatusSkipped,
+			wantCalls:       nil,
+		},
+		{
+			name:            "skipped_delivered",
+			suppressSkipped: false,
+			event:           domain.NotificationEventPushRejected,
+			outcome:         domain.ActionResultStatusSkipped,
+			wantCalls:       []string{"discord"},
+		},
+		{
+			name:            "success_delivered",
+			suppressSkipped: true,
+			event:           domain.NotificationEventPushApproved,
+			outcome:         domain.ActionResultStatusSuccess,
+			wantCalls:       []string{"discord"},
+		},
+		{
+			name:            "failure_delivered",
+			suppressSkipped: true,
+			event:           domain.NotificationEventPushError,
+			outcome:         domain.ActionResultStatusFailed,
+			wantCalls:       []string{"discord"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			repo := &mockNotificationRepo{}
+
+			s := &service{
+				log:      zerolog.Nop(),
+				repo:     repo,
+				dispatch: domain.NotificationDispatchBestEffort,
+				senders: []registeredSender{
+					{notification: domain.Notification{ID: 1, Name: "discord", Type: domain.NotificationTypeDiscord, SuppressSkipped: tt.suppressSkipped}, sender: &orderedSender{name: "discord", calls: &calls}},
+				},
+			}
+
+			s.dispatchEvent(tt.event, domain.NotificationPayload{
+				ReleaseName:   "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+				Action:        "Send to qBittorrent",
+				ActionOutcome: tt.outcome,
+			})
+
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Len(t, repo.logs, len(tt.wantCalls))
+		})
+	}
+}
+
+type mockNotificationRepo struct {
+	domain.NotificationRepo
+	notifications []domain.Notification
+	logs          []domain.NotificationLog
+}
+
+func (r *mockNotificationRepo) List(ctx context.Context) ([]domain.Notification, error) {
+	return r.notifications, nil
+}
+
+func (r *mockNotificationRepo) StoreLog(ctx context.Context, log *domain.NotificationLog) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func TestService_registerSenders_order(t *testing.T) {
+	s := &service{
+		log: zerolog.Nop(),
+		repo: &mockNotificationRepo{
+			notifications: []domain.Notification{
+				{Name: "a-telegram", Type: domain.NotificationTypeTelegram, Enabled: true, DispatchOrder: 2},
+				{Name: "b-discord", Type: domain.NotificationTypeDiscord, Enabled: true},
+				{Name: "c-disabled", Type: domain.NotificationTypeGotify, Enabled: false},
+				{Name: "d-gotify", Type: domain.NotificationTypeGotify, Enabled: true, DispatchOrder: 1},
+				{Name: "e-slack", Type: domain.NotificationTypeSlack, Enabled: true, DispatchOrder: 1},
+			},
+		},
+	}
+
+	s.registerSenders()
+
+	var names []string
+	for _, rs := range s.senders {
+		names = append(names, rs.notification.Name)
+	}
+
+	assert.Equal(t, []string{"b-discord", "d-gotify", "e-slack", "a-telegram"}, names)
+}
+
+func TestService_registerSenders_userAgent(t *testing.T) {
+	userAgents := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents[r.URL.Query().Get("token")] = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	events := []string{string(domain.NotificationEventPushApproved)}
+
+	s := &service{
+		log:      zerolog.Nop(),
+		dispatch: domain.NotificationDispatchBestEffort,
+		version:  "v1.2.3",
+		repo: &mockNotificationRepo{
+			notifications: []domain.Notification{
+				{Name: "default", Type: domain.NotificationTypeGotify, Enabled: true, Events: events, Host: srv.URL, Token: "default"},
+				{Name: "custom", Type: domain.NotificationTypeGotify, Enabled: true, Events: events, Host: srv.URL, Token: "custom", UserAgent: "Mozilla/5.0"},
+			},
+		},
+	}
+
+	s.registerSenders()
+	results := s.dispatchEvent(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, map[string]string{
+		"default": fmt.Sprintf("autobrr/v1.2.3 (%s %s)", runtime.GOOS, runtime.GOARCH),
+		"custom":  "Mozilla/5.0",
+	}, userAgents)
+}
+
+// scrapeMetric returns the value of the series from the metrics endpoint, 0 if it's not there yet
+func scrapeMetric(t *testing.T, series string) float64 {
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if v, ok := strings.CutPrefix(line, series+" "); ok {
+			value, err := strconv.ParseFloat(v, 64)
+			assert.NoError(t, err)
+			return value
+		}
+	}
+
+	return 0
+}
+
+func TestService_dispatchEvent_metrics(t *testing.T) {
+	const (
+		discordSent    = `autobrr_notifications_total{outcome="sent",provider="DISCORD"}`
+		telegramFailed = `autobrr_notifications_total{outcome="failed",provider="TELEGRAM"}`
+	)
+
+	var calls []string
+
+	s := &service{
+		log:      zerolog.Nop(),
+		repo:     &mockNotificationRepo{},
+		dispatch: domain.NotificationDispatchBestEffort,
+		senders: []registeredSender{
+			{notification: domain.Notification{Name: "discord", Type: domain.NotificationTypeDiscord}, sender: &orderedSender{name: "discord", calls: &calls}},
+			{notification: domain.Notification{Name: "telegram", Type: domain.NotificationTypeTelegram}, sender: &orderedSender{name: "telegram", calls: &calls, err: errors.New("telegram is down")}},
+		},
+	}
+
+	beforeSent, beforeFailed := scrapeMetric(t, discordSent), scrapeMetric(t, telegramFailed)
+
+	for i := 0; i < 3; i++ {
+		s.dispatchEvent(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+	}
+
+	assert.Equal(t, beforeSent+3, scrapeMetric(t, discordSent))
+	assert.Equal(t, beforeFailed+3, scrapeMetric(t, telegramFailed))
+}
+
+func TestSenders_timeout(t *testing.T) {
+	done := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	settings := domain.Notification{
+		Enabled: true,
+		Events:  []string{string(domain.NotificationEventPushApproved)},
+		Webhook: ts.URL,
+		Token:   "token",
+		APIKey:  "key",
+		Timeout: 1,
+	}
+
+	pushover := NewPushoverSender(zerolog.Nop(), settings).(*pushoverSender)
+	pushover.baseUrl = ts.URL
+
+	tests := []struct {
+		name   string
+		sender domain.NotificationSender
+	}{
+		{name: "pushover", sender: pushover},
+		{name: "discord", sender: NewDiscordSender(zerolog.Nop(), settings)},
+		{name: "slack", sender: NewSlackSender(zerolog.Nop(), settings)},
+		{name: "teams", sender: NewTeamsSender(zerolog.Nop(), settings)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			_, err := tt.sender.Send(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+
+			var netErr net.Error
+			if assert.True(t, errors.As(err, &netErr), "expected a net error, got: %v", err) {
+				assert.True(t, netErr.Timeout())
+			}
+			assert.ErrorContains(t, err, "deadline exceeded")
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func TestNotification_SendTimeout(t *testing.T) {
+	assert.Equal(t, domain.DefaultNotificationTimeout, domain.Notification{}.SendTimeout())
+	assert.Equal(t, 5*time.Second, domain.Notification{Timeout: 5}.SendTimeout())
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+)
+
+type SlackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []SlackAttachment `json:"attachments"`
+}
+
+type SlackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Fields   []SlackText `json:"fields,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+const (
+	slackColorBlue  = "#58b9ff"
+	slackColorRed   = "#ed4245"
+	slackColorGreen = "#57f287"
+	slackColorGray  = "#99aab5"
+)
+
+type slackSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+	builder  NotificationBuilderPlainText
+}
+
+func NewSlackSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &slackSender{
+		log:      log.With().Str("sender", "slack").Logger(),
+		Settings: settings,
+		builder:  NotificationBuilderPlainText{},
+	}
+}
+
+func (s *slackSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	m := s.buildMessage(event, payload)
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("slack client could not marshal data: %v", m)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not marshal data: %+v", m)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.Settings.EventChannel(event, s.Settings.Webhook), bytes.NewBuffer(jsonData))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("slack client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
+
+	client := http.Client{Timeout: s.Settings.SendTimeout()}
+	res, err := client.Do(req)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("slack client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("slack client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
+	}
+
+	s.log.Trace().Msgf("slack status: %v response: %v", res.StatusCode, string(body))
+
+	if res.StatusCode != http.StatusOK {
+		s.log.Error().Err(err).Msgf("slack client request error: %v", string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to slack")
+
+	return domain.NotificationResult{}, nil
+}
+
+func (s *slackSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *slackSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Webhook != "" {
+		return true
+	}
+	return false
+}
+
+func (s *slackSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *slackSender) buildMessage(event domain.NotificationEvent, payload domain.NotificationPayload) SlackMessage {
+	color := slackColorBlue
+	switch event {
+	case domain.NotificationEventPushApproved:
+		color = slackColorGreen
+	case domain.NotificationEventPushRejected:
+		color = slackColorGray
+	case domain.NotificationEventPushError:
+		color = slackColorRed
+	case domain.NotificationEventIRCDisconnected:
+		color = slackColorRed
+	case domain.NotificationEventIRCReconnected:
+		color = slackColorGreen
+	case domain.NotificationEventIRCAuthFailed:
+		color = slackColorRed
+	case domain.NotificationEventReleaseUpgrade:
+		color = slackColorGreen
+	case domain.NotificationEventTorrentStalled:
+		color = slackColorRed
+	case domain.NotificationEventActionDisabled:
+		color = slackColorRed
+	case domain.NotificationEventReleasePending:
+		color = slackColorBlue
+	}
+
+	title := s.builder.BuildTitle(event)
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{Type: "plain_text", Text: title},
+		},
+	}
+
+	if payload.Subject != "" && payload.Message != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*%v*\n%v", payload.Subject, payload.Message)},
+		})
+	}
+
+	var fields []SlackText
+
+	addField := func(name, value string) {
+		if value != "" {
+			fields = append(fields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*%v*\n%v", name, value)})
+		}
+	}
+
+	addField("Release", payload.ReleaseName)
+	if payload.Status != "" {
+		addField("Status", payload.Status.String())
+	}
+	addField("Indexer", payload.Indexer)
+	addField("Filter", payload.Filter)
+	addField("Action", payload.Action)
+	addField("Action type", string(payload.ActionType))
+	addField("Action client", payload.ActionClient)
+	if payload.Size > 0 {
+		addField("Size", humanize.Bytes(payload.Size))
+	}
+	if len(payload.Rejections) > 0 {
+		addField("Reasons", strings.Join(payload.Rejections, ", "))
+	}
+
+	// slack allows a maximum of 10 fields per section block
+	for len(fields) > 0 {
+		n := len(fields)
+		if n > 10 {
+			n = 10
+		}
+
+		blocks = append(blocks, SlackBlock{Type: "section", Fields: fields[:n]})
+		fields = fields[n:]
+	}
+
+	blocks = append(blocks, SlackBlock{
+		Type:     "context",
+		Elements: []SlackText{{Type: "mrkdwn", Text: fmt.Sprintf("%v | autobrr", title)}},
+	})
+
+	return SlackMessage{
+		Text: title,
+		Attachments: []SlackAttachment{
+			{
+				Color:  color,
+				Blocks: blocks,
+			},
+		},
+	}
+}
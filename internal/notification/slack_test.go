@@ -0,0 +1,85 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackSender_buildMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     domain.NotificationEvent
+		payload   domain.NotificationPayload
+		wantColor string
+		wantTitle string
+	}{
+		{
+			name:  "push_approved",
+			event: domain.NotificationEventPushApproved,
+			payload: domain.NotificationPayload{
+				ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+				Indexer:     "MockIndexer",
+				Filter:      "TV",
+				Status:      domain.ReleasePushStatusApproved,
+			},
+			wantColor: slackColorGreen,
+			wantTitle: "Push Approved",
+		},
+		{
+			name:  "push_error",
+			event: domain.NotificationEventPushError,
+			payload: domain.NotificationPayload{
+				ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+				Indexer:     "MockIndexer",
+				Status:      domain.ReleasePushStatusErr,
+			},
+			wantColor: slackColorRed,
+			wantTitle: "Error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSlackSender(zerolog.Nop(), domain.Notification{}).(*slackSender)
+
+			m := s.buildMessage(tt.event, tt.payload)
+
+			assert.Equal(t, tt.wantTitle, m.Text)
+			assert.Len(t, m.Attachments, 1)
+			assert.Equal(t, tt.wantColor, m.Attachments[0].Color)
+
+			blocks := m.Attachments[0].Blocks
+			assert.Len(t, blocks, 3)
+
+			assert.Equal(t, "header", blocks[0].Type)
+			assert.Equal(t, tt.wantTitle, blocks[0].Text.Text)
+
+			assert.Equal(t, "section", blocks[1].Type)
+			assert.NotEmpty(t, blocks[1].Fields)
+			assert.Equal(t, "*Release*\n"+tt.payload.ReleaseName, blocks[1].Fields[0].Text)
+
+			assert.Equal(t, "context", blocks[2].Type)
+		})
+	}
+}
+
+func TestSlackSender_CanSend(t *testing.T) {
+	settings := domain.Notification{
+		Enabled: true,
+		Events:  []string{string(domain.NotificationEventPushApproved)},
+	}
+
+	s := NewSlackSender(zerolog.Nop(), settings)
+	assert.False(t, s.CanSend(domain.NotificationEventPushApproved))
+
+	settings.Webhook = "https://hooks.slack.com/services/xx/xx/xx"
+	s = NewSlackSender(zerolog.Nop(), settings)
+	assert.True(t, s.CanSend(domain.NotificationEventPushApproved))
+	assert.False(t, s.CanSend(domain.NotificationEventPushError))
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+)
+
+// TeamsMessage is a legacy MessageCard, the format accepted by Teams incoming webhooks
+type TeamsMessage struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Text       string         `json:"text,omitempty"`
+	Sections   []TeamsSection `json:"sections,omitempty"`
+}
+
+type TeamsSection struct {
+	ActivityTitle string      `json:"activityTitle,omitempty"`
+	Text          string      `json:"text,omitempty"`
+	Facts         []TeamsFact `json:"facts,omitempty"`
+}
+
+type TeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+const (
+	teamsColorBlue  = "58B9FF"
+	teamsColorRed   = "ED4245"
+	teamsColorGreen = "57F287"
+	teamsColorGray  = "99AAB5"
+)
+
+type teamsSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+	builder  NotificationBuilderPlainText
+}
+
+func NewTeamsSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &teamsSender{
+		log:      log.With().Str("sender", "teams").Logger(),
+		Settings: settings,
+		builder:  NotificationBuilderPlainText{},
+	}
+}
+
+func (s *teamsSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	m := s.buildMessage(event, payload)
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("teams client could not marshal data: %v", m)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not marshal data: %+v", m)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.Settings.EventChannel(event, s.Settings.Webhook), bytes.NewBuffer(jsonData))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("teams client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
+
+	client := http.Client{Timeout: s.Settings.SendTimeout()}
+	res, err := client.Do(req)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("teams client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("teams client request error: %v", event)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
+	}
+
+	s.log.Trace().Msgf("teams status: %v response: %v", res.StatusCode, string(body))
+
+	// incoming webhooks answer 200, workflow webhooks 202
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
+		s.log.Error().Err(err).Msgf("teams client request error: %v", string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to teams")
+
+	return domain.NotificationResult{}, nil
+}
+
+func (s *teamsSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *teamsSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Webhook != "" {
+		return true
+	}
+	return false
+}
+
+func (s *teamsSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *teamsSender) buildMessage(event domain.NotificationEvent, payload domain.NotificationPayload) TeamsMessage {
+	color := teamsColorBlue
+	switch event {
+	case domain.NotificationEventPushApproved:
+		color = teamsColorGreen
+	case domain.NotificationEventPushRejected:
+		color = teamsColorGray
+	case domain.NotificationEventPushError:
+		color = teamsColorRed
+	case domain.NotificationEventIRCDisconnected:
+		color = teamsColorRed
+	case domain.NotificationEventIRCReconnected:
+		color = teamsColorGreen
+	case domain.NotificationEventIRCAuthFailed:
+		color = teamsColorRed
+	case domain.NotificationEventReleaseUpgrade:
+		color = teamsColorGreen
+	case domain.NotificationEventTorrentStalled:
+		color = teamsColorRed
+	case domain.NotificationEventActionDisabled:
+		color = teamsColorRed
+	case domain.NotificationEventReleasePending:
+		color = teamsColorBlue
+	}
+
+	title := s.builder.BuildTitle(event)
+
+	m := TeamsMessage{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: color,
+		Summary:    title,
+		Title:      title,
+	}
+
+	if payload.Subject != "" && payload.Message != "" {
+		m.Sections = append(m.Sections, TeamsSection{ActivityTitle: payload.Subject, Text: payload.Message})
+	}
+
+	var facts []TeamsFact
+
+	addFact := func(name, value string) {
+		if value != "" {
+			facts = append(facts, TeamsFact{Name: name, Value: value})
+		}
+	}
+
+	addFact("Release", payload.ReleaseName)
+	if payload.Status != "" {
+		addFact("Status", payload.Status.String())
+	}
+	addFact("Indexer", payload.Indexer)
+	addFact("Filter", payload.Filter)
+	addFact("Action", payload.Action)
+	addFact("Action type", string(payload.ActionType))
+	addFact("Action client", payload.ActionClient)
+	if payload.Size > 0 {
+		addFact("Size", humanize.Bytes(payload.Size))
+	}
+	if len(payload.Rejections) > 0 {
+		addFact("Reasons", strings.Join(payload.Rejections, ", "))
+	}
+
+	if len(facts) > 0 {
+		m.Sections = append(m.Sections, TeamsSection{Facts: facts})
+	}
+
+	return m
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsSender_buildMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     domain.NotificationEvent
+		payload   domain.NotificationPayload
+		wantColor string
+		wantTitle string
+		wantFacts []TeamsFact
+	}{
+		{
+			name:  "push_approved",
+			event: domain.NotificationEventPushApproved,
+			payload: domain.NotificationPayload{
+				ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+				Indexer:     "MockIndexer",
+				Filter:      "TV",
+				Status:      domain.ReleasePushStatusApproved,
+			},
+			wantColor: teamsColorGreen,
+			wantTitle: "Push Approved",
+			wantFacts: []TeamsFact{
+				{Name: "Release", Value: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"},
+				{Name: "Status", Value: "Approved"},
+				{Name: "Indexer", Value: "MockIndexer"},
+				{Name: "Filter", Value: "TV"},
+			},
+		},
+		{
+			name:  "push_rejected",
+			event: domain.NotificationEventPushRejected,
+			payload: domain.NotificationPayload{
+				ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+				Status:      domain.ReleasePushStatusRejected,
+				Rejections:  []string{"unknown episode", "already grabbed"},
+			},
+			wantColor: teamsColorGray,
+			wantTitle: "Push Rejected",
+			wantFacts: []TeamsFact{
+				{Name: "Release", Value: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"},
+				{Name: "Status", Value: "Rejected"},
+				{Name: "Reasons", Value: "unknown episode, already grabbed"},
+			},
+		},
+		{
+			name:  "push_error",
+			event: domain.NotificationEventPushError,
+			payload: domain.NotificationPayload{
+				ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+				Indexer:     "MockIndexer",
+				Status:      domain.ReleasePushStatusErr,
+			},
+			wantColor: teamsColorRed,
+			wantTitle: "Error",
+			wantFacts: []TeamsFact{
+				{Name: "Release", Value: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"},
+				{Name: "Status", Value: "Error"},
+				{Name: "Indexer", Value: "MockIndexer"},
+			},
+		},
+		{
+			name:      "app_update_available",
+			event:     domain.NotificationEventAppUpdateAvailable,
+			payload:   domain.NotificationPayload{Subject: "New update available!", Message: "v1.30.0"},
+			wantColor: teamsColorBlue,
+			wantTitle: "Autobrr update available",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTeamsSender(zerolog.Nop(), domain.Notification{}).(*teamsSender)
+
+			m := s.buildMessage(tt.event, tt.payload)
+
+			assert.Equal(t, "MessageCard", m.Type)
+			assert.Equal(t, tt.wantTitle, m.Title)
+			assert.Equal(t, tt.wantTitle, m.Summary)
+			assert.Equal(t, tt.wantColor, m.ThemeColor)
+
+			var facts []TeamsFact
+			for _, section := range m.Sections {
+				facts = append(facts, section.Facts...)
+			}
+			assert.Equal(t, tt.wantFacts, facts)
+
+			data, err := json.Marshal(m)
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), `"@type":"MessageCard"`)
+			assert.Contains(t, string(data), `"themeColor":"`+tt.wantColor+`"`)
+		})
+	}
+}
+
+func TestTeamsSender_CanSend(t *testing.T) {
+	settings := domain.Notification{
+		Enabled: true,
+		Events:  []string{string(domain.NotificationEventPushApproved)},
+	}
+
+	s := NewTeamsSender(zerolog.Nop(), settings)
+	assert.False(t, s.CanSend(domain.NotificationEventPushApproved))
+
+	settings.Webhook = "https://example.webhook.office.com/webhookb2/xx/IncomingWebhook/xx/xx"
+	s = NewTeamsSender(zerolog.Nop(), settings)
+	assert.True(t, s.CanSend(domain.NotificationEventPushApproved))
+	assert.False(t, s.CanSend(domain.NotificationEventPushError))
+}
@@ -10,7 +10,6 @@ import (
 	"io"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -26,6 +25,13 @@ type TelegramMessage struct {
 	MessageThreadID int    `json:"message_thread_id,omitempty"`
 }
 
+type telegramResponse struct {
+	Ok     bool `json:"ok"`
+	Result struct {
+		MessageID int64 `json:"message_id"`
+	} `json:"result"`
+}
+
 type telegramSender struct {
 	log      zerolog.Logger
 	Settings domain.Notification
@@ -49,20 +55,13 @@ func NewTelegramSender(log zerolog.Logger, settings domain.Notification) domain.
 	}
 }
 
-func (s *telegramSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
-	message := s.builder.BuildBody(payload)
-	m := TelegramMessage{
-		ChatID:          s.Settings.Channel,
-		Text:            message,
-		MessageThreadID: s.ThreadID,
-		ParseMode:       "HTML",
-		//ParseMode: "MarkdownV2",
-	}
+func (s *telegramSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	m := s.buildMessage(event, payload)
 
 	jsonData, err := json.Marshal(m)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("telegram client could not marshal data: %v", m)
-		return errors.Wrap(err, "could not marshal data: %+v", m)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not marshal data: %+v", m)
 	}
 
 	url := fmt.Sprintf("https://api.telegram.org/bot%v/sendMessage", s.Settings.Token)
@@ -70,23 +69,23 @@ func (s *telegramSender) Send(event domain.NotificationEvent, payload domain.Not
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		s.log.Error().Err(err).Msgf("telegram client request error: %v", event)
-		return errors.Wrap(err, "could not create request")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not create request")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	//req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("User-Agent", s.Settings.UserAgent)
 
-	client := http.Client{Timeout: 30 * time.Second}
+	client := http.Client{Timeout: s.Settings.SendTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("telegram client request error: %v", event)
-		return errors.Wrap(err, "could not make request: %+v", req)
+		return domain.NotificationResult{}, errors.Wrap(err, "could not make request: %+v", req)
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("telegram client request error: %v", event)
-		return errors.Wrap(err, "could not read data")
+		return domain.NotificationResult{}, errors.Wrap(err, "could not read data")
 	}
 
 	defer res.Body.Close()
@@ -95,11 +94,21 @@ func (s *telegramSender) Send(event domain.NotificationEvent, payload domain.Not
 
 	if res.StatusCode != http.StatusOK {
 		s.log.Error().Err(err).Msgf("telegram client request error: %v", string(body))
-		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+		return domain.NotificationResult{}, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	var response telegramResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		s.log.Warn().Err(err).Msgf("telegram could not parse response: %v", string(body))
 	}
 
-	s.log.Debug().Msg("notification successfully sent to telegram")
-	return nil
+	var messageID string
+	if response.Result.MessageID != 0 {
+		messageID = strconv.FormatInt(response.Result.MessageID, 10)
+	}
+
+	s.log.Debug().Msgf("notification successfully sent to telegram, message id: %s", messageID)
+	return domain.NotificationResult{MessageID: messageID}, nil
 }
 
 func (s *telegramSender) CanSend(event domain.NotificationEvent) bool {
@@ -125,3 +134,13 @@ func (s *telegramSender) isEnabledEvent(event domain.NotificationEvent) bool {
 
 	return false
 }
+
+func (s *telegramSender) buildMessage(event domain.NotificationEvent, payload domain.NotificationPayload) TelegramMessage {
+	return TelegramMessage{
+		ChatID:          s.Settings.EventChannel(event, s.Settings.Channel),
+		Text:            s.builder.BuildBody(payload),
+		MessageThreadID: s.ThreadID,
+		ParseMode:       "HTML",
+		//ParseMode: "MarkdownV2",
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTelegramSender_buildMessage_chatID(t *testing.T) {
+	settings := domain.Notification{
+		Channel: "-100111",
+		EventChannels: map[string]string{
+			string(domain.NotificationEventPushApproved): "-100222",
+			string(domain.NotificationEventPushError):    "-100333",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		event domain.NotificationEvent
+		want  string
+	}{
+		{
+			name:  "approved_override",
+			event: domain.NotificationEventPushApproved,
+			want:  "-100222",
+		},
+		{
+			name:  "error_override",
+			event: domain.NotificationEventPushError,
+			want:  "-100333",
+		},
+		{
+			name:  "default_fallback",
+			event: domain.NotificationEventPushRejected,
+			want:  "-100111",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTelegramSender(zerolog.Nop(), settings).(*telegramSender)
+
+			m := s.buildMessage(tt.event, domain.NotificationPayload{ReleaseName: "Test.Release-GROUP"})
+
+			assert.Equal(t, tt.want, m.ChatID)
+		})
+	}
+}
+
+func TestTelegramSender_buildMessage_threadID(t *testing.T) {
+	tests := []struct {
+		name  string
+		topic string
+		want  map[string]any
+	}{
+		{
+			name:  "topic",
+			topic: "42",
+			want:  map[string]any{"chat_id": "-100111", "parse_mode": "HTML", "message_thread_id": float64(42)},
+		},
+		{
+			name:  "no_topic",
+			topic: "",
+			want:  map[string]any{"chat_id": "-100111", "parse_mode": "HTML"},
+		},
+		{
+			name:  "invalid_topic",
+			topic: "general",
+			want:  map[string]any{"chat_id": "-100111", "parse_mode": "HTML"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTelegramSender(zerolog.Nop(), domain.Notification{Channel: "-100111", Topic: tt.topic}).(*telegramSender)
+
+			m := s.buildMessage(domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Test.Release-GROUP"})
+
+			data, err := json.Marshal(m)
+			assert.NoError(t, err)
+
+			var got map[string]any
+			assert.NoError(t, json.Unmarshal(data, &got))
+
+			delete(got, "text")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
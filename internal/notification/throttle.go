@@ -0,0 +1,165 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// throttledSender enforces a minimum interval and a max messages per hour for a single target.
+// Suppressed messages are counted and sent as one summary as soon as the target allows it again.
+type throttledSender struct {
+	log         zerolog.Logger
+	sender      domain.NotificationSender
+	builder     NotificationBuilderPlainText
+	minInterval time.Duration
+	maxPerHour  int
+
+	// now and afterFunc can be replaced in tests
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func())
+
+	m               sync.Mutex
+	sent            []time.Time
+	suppressed      map[domain.NotificationEvent]int
+	suppressedSince time.Time
+	firstSuppressed domain.NotificationEvent
+	flushScheduled  bool
+}
+
+func NewThrottledSender(log zerolog.Logger, settings domain.Notification, sender domain.NotificationSender) domain.NotificationSender {
+	return &throttledSender{
+		log:         log.With().Str("sender", string(settings.Type)).Str("notification", settings.Name).Logger(),
+		sender:      sender,
+		minInterval: time.Duration(settings.MinInterval) * time.Second,
+		maxPerHour:  settings.MaxPerHour,
+		now:         time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		suppressed: map[domain.NotificationEvent]int{},
+	}
+}
+
+func (s *throttledSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	s.m.Lock()
+
+	now := s.now()
+	if wait := s.nextAllowed(now); wait > 0 {
+		if len(s.suppressed) == 0 {
+			s.suppressedSince = now
+			s.firstSuppressed = event
+		}
+		s.suppressed[event]++
+
+		if !s.flushScheduled {
+			s.flushScheduled = true
+			s.afterFunc(wait, s.flush)
+		}
+
+		s.m.Unlock()
+
+		s.log.Debug().Msgf("notification throttled, event: %s release: %s", event, payload.ReleaseName)
+		return domain.NotificationResult{Suppressed: true}, nil
+	}
+
+	s.sent = append(s.sent, now)
+	s.m.Unlock()
+
+	return s.sender.Send(event, payload)
+}
+
+func (s *throttledSender) CanSend(event domain.NotificationEvent) bool {
+	return s.sender.CanSend(event)
+}
+
+// nextAllowed returns how long until the next message can be sent. Must be called with the lock held.
+func (s *throttledSender) nextAllowed(now time.Time) time.Duration {
+	// forget messages older than an hour
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(s.sent) && !s.sent[i].After(cutoff) {
+		i++
+	}
+	s.sent = s.sent[i:]
+
+	var wait time.Duration
+
+	if s.minInterval > 0 && len(s.sent) > 0 {
+		if d := s.sent[len(s.sent)-1].Add(s.minInterval).Sub(now); d > wait {
+			wait = d
+		}
+	}
+
+	if s.maxPerHour > 0 && len(s.sent) >= s.maxPerHour {
+		if d := s.sent[len(s.sent)-s.maxPerHour].Add(time.Hour).Sub(now); d > wait {
+			wait = d
+		}
+	}
+
+	return wait
+}
+
+// flush sends the summary of suppressed messages, or reschedules itself if the target is still limited.
+func (s *throttledSender) flush() {
+	s.m.Lock()
+
+	if len(s.suppressed) == 0 {
+		s.flushScheduled = false
+		s.m.Unlock()
+		return
+	}
+
+	now := s.now()
+	if wait := s.nextAllowed(now); wait > 0 {
+		s.afterFunc(wait, s.flush)
+		s.m.Unlock()
+		return
+	}
+
+	event := s.firstSuppressed
+	payload := s.summary(now)
+
+	s.sent = append(s.sent, now)
+	s.suppressed = map[domain.NotificationEvent]int{}
+	s.flushScheduled = false
+	s.m.Unlock()
+
+	if _, err := s.sender.Send(event, payload); err != nil {
+		s.log.Error().Err(err).Msg("could not send throttled notification summary")
+	}
+}
+
+// summary builds the payload for the suppressed messages. Must be called with the lock held.
+func (s *throttledSender) summary(now time.Time) domain.NotificationPayload {
+	total := 0
+	lines := make([]string, 0, len(s.suppressed))
+
+	for event, count := range s.suppressed {
+		total += count
+		lines = append(lines, fmt.Sprintf("%s: %d", s.builder.BuildTitle(event), count))
+	}
+
+	sort.Strings(lines)
+
+	subject := fmt.Sprintf("%d notifications suppressed in the last %s", total, now.Sub(s.suppressedSince).Round(time.Second))
+	if grabbed := s.suppressed[domain.NotificationEventPushApproved]; grabbed == total {
+		subject = fmt.Sprintf("%d releases grabbed in the last %s", grabbed, now.Sub(s.suppressedSince).Round(time.Second))
+	}
+
+	return domain.NotificationPayload{
+		Subject:   subject,
+		Message:   strings.Join(lines, "\n"),
+		Event:     s.firstSuppressed,
+		Timestamp: now,
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSender struct {
+	events   []domain.NotificationEvent
+	payloads []domain.NotificationPayload
+}
+
+func (s *recordingSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) (domain.NotificationResult, error) {
+	s.events = append(s.events, event)
+	s.payloads = append(s.payloads, payload)
+	return domain.NotificationResult{}, nil
+}
+
+func (s *recordingSender) CanSend(event domain.NotificationEvent) bool {
+	return true
+}
+
+func sendNoError(t *testing.T, s domain.NotificationSender, event domain.NotificationEvent, payload domain.NotificationPayload) {
+	t.Helper()
+
+	_, err := s.Send(event, payload)
+	assert.NoError(t, err)
+}
+
+// newTestThrottledSender returns a sender with a fake clock and the scheduled flushes collected instead of run
+func newTestThrottledSender(settings domain.Notification, sender domain.NotificationSender) (*throttledSender, *time.Time, *[]func()) {
+	now := time.Date(2023, 11, 4, 12, 0, 0, 0, time.UTC)
+	var flushes []func()
+
+	s := NewThrottledSender(zerolog.Nop(), settings, sender).(*throttledSender)
+	s.now = func() time.Time { return now }
+	s.afterFunc = func(d time.Duration, f func()) { flushes = append(flushes, f) }
+
+	return s, &now, &flushes
+}
+
+func TestThrottledSender_maxPerHour(t *testing.T) {
+	rec := &recordingSender{}
+	s, now, flushes := newTestThrottledSender(domain.Notification{Name: "pushover", Type: domain.NotificationTypePushover, MaxPerHour: 3}, rec)
+
+	for i := 0; i < 15; i++ {
+		sendNoError(t, s, domain.NotificationEventPushApproved, domain.NotificationPayload{ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"})
+		*now = now.Add(time.Minute)
+	}
+
+	assert.Len(t, rec.payloads, 3)
+	assert.Len(t, *flushes, 1)
+
+	// still limited, the flush reschedules itself
+	(*flushes)[0]()
+	assert.Len(t, rec.payloads, 3)
+	assert.Len(t, *flushes, 2)
+
+	*now = now.Add(time.Hour)
+	(*flushes)[1]()
+
+	assert.Len(t, rec.payloads, 4)
+	assert.Equal(t, "12 releases grabbed in the last 1h12m0s", rec.payloads[3].Subject)
+	assert.Equal(t, "Push Approved: 12", rec.payloads[3].Message)
+
+	// nothing left to coalesce
+	assert.Len(t, *flushes, 2)
+	assert.Empty(t, s.suppressed)
+}
+
+func TestThrottledSender_minInterval(t *testing.T) {
+	rec := &recordingSender{}
+	s, now, flushes := newTestThrottledSender(domain.Notification{Name: "pushover", Type: domain.NotificationTypePushover, MinInterval: 60}, rec)
+
+	// five events within the same window
+	sendNoError(t, s, domain.NotificationEventPushApproved, domain.NotificationPayload{})
+	for i := 0; i < 3; i++ {
+		*now = now.Add(10 * time.Second)
+		sendNoError(t, s, domain.NotificationEventPushApproved, domain.NotificationPayload{})
+	}
+	sendNoError(t, s, domain.NotificationEventPushRejected, domain.NotificationPayload{})
+
+	assert.Len(t, rec.payloads, 1)
+	assert.Len(t, *flushes, 1)
+
+	*now = now.Add(30 * time.Second)
+	(*flushes)[0]()
+
+	assert.Len(t, rec.payloads, 2)
+	assert.Equal(t, "4 notifications suppressed in the last 50s", rec.payloads[1].Subject)
+	assert.Equal(t, "Push Approved: 3\nPush Rejected: 1", rec.payloads[1].Message)
+	assert.Equal(t, domain.NotificationEventPushApproved, rec.payloads[1].Event)
+
+	// the summary counts as a sent message
+	sendNoError(t, s, domain.NotificationEventPushApproved, domain.NotificationPayload{})
+	assert.Len(t, rec.payloads, 2)
+
+	*now = now.Add(time.Minute)
+	sendNoError(t, s, domain.NotificationEventPushApproved, domain.NotificationPayload{})
+	assert.Len(t, rec.payloads, 3)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+var (
+	activeDownloadsRetryDelay    = 15 * time.Second
+	activeDownloadsMaxRetryDelay = 2 * time.Minute
+	activeDownloadsMaxAttempts   = 5
+)
+
+// deferActiveDownloads runs the actions again once the delay passed, the download client had reached the max active
+// downloads of the filter. Nothing is held while waiting, so other releases of the filter keep processing.
+// The delay doubles after every attempt up to the max delay, the release is skipped after max attempts.
+func (s *service) deferActiveDownloads(f *domain.Filter, release *domain.Release, actions []*domain.Action, attempt int) {
+	if attempt >= activeDownloadsMaxAttempts {
+		s.log.Info().Msgf("release %s (%s) skipped, max active downloads of filter still reached after %d attempts", release.TorrentName, release.FilterName, attempt)
+		return
+	}
+
+	delay := activeDownloadsRetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay > activeDownloadsMaxRetryDelay {
+			delay = activeDownloadsMaxRetryDelay
+			break
+		}
+	}
+
+	s.log.Debug().Msgf("max active downloads of filter reached for release %s (%s), attempt %d/%d, retrying in %s", release.TorrentName, release.FilterName, attempt, activeDownloadsMaxAttempts, delay)
+
+	time.AfterFunc(delay, func() {
+		s.retryActiveDownloads(f, release, actions, attempt+1)
+	})
+}
+
+// retryActiveDownloads runs the deferred actions under the filter lock, unless the filter grabbed the release
+// or reached its max downloads in the meantime
+func (s *service) retryActiveDownloads(f *domain.Filter, release *domain.Release, actions []*domain.Action, attempt int) {
+	// the torrent file is downloaded again if an action needs it
+	defer release.CleanupTemporaryFiles()
+
+	ctx := context.Background()
+
+	unlock := s.filterLocks.lock(f)
+	defer unlock()
+
+	l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+	ok, err := s.checkGrabs(ctx, f, release)
+	if err != nil {
+		l.Info().Err(err).Msgf("release %s (%s) skipped", release.TorrentName, release.FilterName)
+		return
+	}
+
+	if !ok {
+		l.Info().Msgf("release %s (%s) skipped, already grabbed within %d minutes", release.TorrentName, release.FilterName, f.DedupWindow)
+		return
+	}
+
+	if _, deferred := s.runFilterActions(ctx, l, f, release, actions, map[actionClientTypeKey]struct{}{}); len(deferred) > 0 {
+		s.deferActiveDownloads(f, release, deferred, attempt)
+	}
+}
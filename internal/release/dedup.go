@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// dedupCache keeps track of releases recently matched per filter to skip the same release announced by multiple indexers
+type dedupCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	now     func() time.Time
+}
+
+func newDedupCache() *dedupCache {
+	return &dedupCache{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Seen returns true if an identical release was grabbed by the filter within its dedup window
+func (c *dedupCache) Seen(filter *domain.Filter, release *domain.Release) bool {
+	key := c.key(filter, release)
+	if key == "" {
+		return false
+	}
+
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// prune expired entries
+	for k, expires := range c.entries {
+		if now.After(expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	_, ok := c.entries[key]
+
+	return ok
+}
+
+// Record remembers the release for the dedup window of the filter. It's called once an action grabbed
+// the release, so a release whose actions failed doesn't block the same release from another indexer.
+func (c *dedupCache) Record(filter *domain.Filter, release *domain.Release) {
+	key := c.key(filter, release)
+	if key == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = c.now().Add(time.Duration(filter.DedupWindow) * time.Minute)
+}
+
+// key returns the cache key of the release for the filter, empty if the filter doesn't dedup
+func (c *dedupCache) key(filter *domain.Filter, release *domain.Release) string {
+	if filter.DedupWindow <= 0 {
+		return ""
+	}
+
+	key := dedupKey(filter.DedupKey, release)
+	if key == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%d:%s", filter.ID, key)
+}
+
+// dedupKey builds the key used to compare releases for the given strategy
+func dedupKey(strategy domain.FilterDedupKey, release *domain.Release) string {
+	name := upgradeKeyRegex.ReplaceAllString(strings.ToLower(release.TorrentName), "")
+	if name == "" {
+		return ""
+	}
+
+	switch strategy {
+	case domain.FilterDedupKeyName:
+		return name
+
+	case domain.FilterDedupKeyInfoHash:
+		if release.TorrentHash != "" {
+			return "hash-" + strings.ToLower(release.TorrentHash)
+		}
+	}
+
+	return fmt.Sprintf("%s-%d", name, release.Size)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupCache_Seen(t *testing.T) {
+	type rls struct {
+		name string
+		size uint64
+		hash string
+	}
+	tests := []struct {
+		name     string
+		filter   domain.Filter
+		first    rls
+		second   rls
+		wantDupe bool
+	}{
+		{
+			name:     "same_release_other_indexer",
+			filter:   domain.Filter{ID: 1, DedupWindow: 10},
+			first:    rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1500000000},
+			second:   rls{name: "That Show S01E01 1080p WEB-DL DDP5 1 H 264-GROUP", size: 1500000000},
+			wantDupe: true,
+		},
+		{
+			name:     "different_size",
+			filter:   domain.Filter{ID: 1, DedupWindow: 10},
+			first:    rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1500000000},
+			second:   rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1600000000},
+			wantDupe: false,
+		},
+		{
+			name:     "different_size_name_strategy",
+			filter:   domain.Filter{ID: 1, DedupWindow: 10, DedupKey: domain.FilterDedupKeyName},
+			first:    rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1500000000},
+			second:   rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1600000000},
+			wantDupe: true,
+		},
+		{
+			name:     "same_infohash",
+			filter:   domain.Filter{ID: 1, DedupWindow: 10, DedupKey: domain.FilterDedupKeyInfoHash},
+			first:    rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", hash: "ABCDEF0123456789"},
+			second:   rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP.REPACK", hash: "abcdef0123456789"},
+			wantDupe: true,
+		},
+		{
+			name:     "different_infohash",
+			filter:   domain.Filter{ID: 1, DedupWindow: 10, DedupKey: domain.FilterDedupKeyInfoHash},
+			first:    rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", hash: "abcdef0123456789"},
+			second:   rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", hash: "9876543210fedcba"},
+			wantDupe: false,
+		},
+		{
+			name:     "dedup_disabled",
+			filter:   domain.Filter{ID: 1},
+			first:    rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1500000000},
+			second:   rls{name: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", size: 1500000000},
+			wantDupe: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newDedupCache()
+
+			first := &domain.Release{TorrentName: tt.first.name, Size: tt.first.size, TorrentHash: tt.first.hash}
+			second := &domain.Release{TorrentName: tt.second.name, Size: tt.second.size, TorrentHash: tt.second.hash}
+
+			assert.False(t, c.Seen(&tt.filter, first))
+			c.Record(&tt.filter, first)
+
+			assert.Equal(t, tt.wantDupe, c.Seen(&tt.filter, second))
+		})
+	}
+}
+
+func TestDedupCache_Seen_window(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	c := newDedupCache()
+	c.now = func() time.Time { return now }
+
+	filter := &domain.Filter{ID: 1, DedupWindow: 10}
+	otherFilter := &domain.Filter{ID: 2, DedupWindow: 10}
+	release := &domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", Size: 1500000000}
+
+	assert.False(t, c.Seen(filter, release))
+
+	// only grabbed releases are recorded
+	assert.False(t, c.Seen(filter, release))
+	c.Record(filter, release)
+
+	// other filters keep their own window
+	assert.False(t, c.Seen(otherFilter, release))
+
+	now = now.Add(5 * time.Minute)
+	assert.True(t, c.Seen(filter, release))
+
+	// outside the window it's treated as a new release again
+	now = now.Add(6 * time.Minute)
+	assert.False(t, c.Seen(filter, release))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// filterLocks serializes releases processed at the same time per filter. Max downloads, smart episode and dedup
+// check earlier grabs when the filter is checked, and the grab is only recorded once the actions ran,
+// so two releases processed at the same time could both pass the check.
+type filterLocks struct {
+	m     sync.Mutex
+	locks map[int]*sync.Mutex
+}
+
+func newFilterLocks() *filterLocks {
+	return &filterLocks{
+		locks: map[int]*sync.Mutex{},
+	}
+}
+
+// lock locks the filter if it decides on earlier grabs, the returned func unlocks it
+func (l *filterLocks) lock(f *domain.Filter) func() {
+	if l == nil || (f.MaxDownloads <= 0 && !f.SmartEpisode && f.DedupWindow <= 0) {
+		return func() {}
+	}
+
+	l.m.Lock()
+	mu, ok := l.locks[f.ID]
+	if !ok {
+		mu = &sync.Mutex{}
+		l.locks[f.ID] = mu
+	}
+	l.m.Unlock()
+
+	mu.Lock()
+
+	return mu.Unlock
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// indexerSlots holds a slot for every release of the indexer being processed
+type indexerSlots struct {
+	slots   chan struct{}
+	limiter *rate.Limiter
+}
+
+// indexerLimiter limits how many releases of a single indexer are processed at the same time and how many start per minute.
+// Every indexer has its own slots, so a flood of announces from one busy indexer waits on itself instead of starving the others.
+type indexerLimiter struct {
+	concurrency int
+	rateLimit   int
+
+	m        sync.Mutex
+	indexers map[string]*indexerSlots
+}
+
+// newIndexerLimiter returns a limiter allowing concurrency releases per indexer at the same time and rateLimit per minute.
+// It returns nil if both are 0, which means unlimited.
+func newIndexerLimiter(concurrency int, rateLimit int) *indexerLimiter {
+	if concurrency <= 0 && rateLimit <= 0 {
+		return nil
+	}
+
+	return &indexerLimiter{
+		concurrency: concurrency,
+		rateLimit:   rateLimit,
+		indexers:    map[string]*indexerSlots{},
+	}
+}
+
+// indexerSlots returns the slots of the indexer, they are created on the first release of the indexer
+func (l *indexerLimiter) indexerSlots(indexer string) *indexerSlots {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	sem, ok := l.indexers[indexer]
+	if !ok {
+		sem = &indexerSlots{}
+		if l.concurrency > 0 {
+			sem.slots = make(chan struct{}, l.concurrency)
+		}
+		if l.rateLimit > 0 {
+			sem.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(l.rateLimit)), l.rateLimit)
+		}
+		l.indexers[indexer] = sem
+	}
+
+	return sem
+}
+
+// acquire waits for a free slot of the indexer and for the rate limit. The returned func must be called when the release is done.
+func (l *indexerLimiter) acquire(ctx context.Context, indexer string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	sem := l.indexerSlots(indexer)
+
+	if sem.limiter != nil {
+		if err := sem.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if sem.slots != nil {
+		select {
+		case sem.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		if sem.slots != nil {
+			<-sem.slots
+		}
+	}, nil
+}
@@ -5,6 +5,8 @@ package release
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,7 +14,10 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/rs/zerolog"
 )
 
@@ -29,6 +34,9 @@ type Service interface {
 	Process(release *domain.Release)
 	ProcessMultiple(releases []*domain.Release)
 	Retry(ctx context.Context, req *domain.ReleaseActionRetryReq) error
+	ListPending(ctx context.Context) ([]domain.PendingRelease, error)
+	ApprovePending(ctx context.Context, id int) error
+	RejectPending(ctx context.Context, id int) error
 }
 
 type actionClientTypeKey struct {
@@ -37,20 +45,45 @@ type actionClientTypeKey struct {
 }
 
 type service struct {
-	log  zerolog.Logger
-	repo domain.ReleaseRepo
+	log         zerolog.Logger
+	repo        domain.ReleaseRepo
+	pendingRepo domain.PendingReleaseRepo
+	bus         EventBus.Bus
 
 	actionSvc action.Service
 	filterSvc filter.Service
+
+	upgrades    *upgradeTracker
+	dedup       *dedupCache
+	limiter     *indexerLimiter
+	filterLocks *filterLocks
+
+	// downloadRetry is set on the releases so their torrent file downloads use the configured policy
+	downloadRetry domain.DownloadRetryPolicy
 }
 
-func NewService(log logger.Logger, repo domain.ReleaseRepo, actionSvc action.Service, filterSvc filter.Service) Service {
-	return &service{
-		log:       log.With().Str("module", "release").Logger(),
-		repo:      repo,
-		actionSvc: actionSvc,
-		filterSvc: filterSvc,
+func NewService(log logger.Logger, config *domain.Config, repo domain.ReleaseRepo, pendingRepo domain.PendingReleaseRepo, actionSvc action.Service, filterSvc filter.Service, bus EventBus.Bus) Service {
+	s := &service{
+		log:         log.With().Str("module", "release").Logger(),
+		repo:        repo,
+		pendingRepo: pendingRepo,
+		bus:         bus,
+		actionSvc:   actionSvc,
+		filterSvc:   filterSvc,
+		dedup:       newDedupCache(),
+		limiter:     newIndexerLimiter(config.IndexerConcurrency, config.IndexerRateLimit),
+		filterLocks: newFilterLocks(),
+		downloadRetry: domain.DownloadRetryPolicy{
+			Attempts: config.DownloadRetries,
+			Delay:    time.Duration(config.DownloadRetryDelay) * time.Second,
+		},
 	}
+
+	s.upgrades = newUpgradeTracker(func(ctx context.Context, release *domain.Release) ([]*domain.Release, error) {
+		return s.repo.FindGrabbedEpisodes(ctx, release.Title, release.Season, release.Episode)
+	})
+
+	return s
 }
 
 func (s *service) Find(ctx context.Context, query domain.ReleaseQueryParams) (res []*domain.Release, nextCursor int64, count int64, err error) {
@@ -62,7 +95,16 @@ func (s *service) FindRecent(ctx context.Context) (res []*domain.Release, err er
 }
 
 func (s *service) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
-	return s.repo.Get(ctx, req)
+	release, err := s.repo.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if release != nil {
+		release.DownloadRetry = s.downloadRetry
+	}
+
+	return release, nil
 }
 
 func (s *service) GetActionStatus(ctx context.Context, req *domain.GetReleaseActionStatusRequest) (*domain.ReleaseActionStatus, error) {
@@ -104,8 +146,18 @@ func (s *service) Process(release *domain.Release) {
 
 	defer release.CleanupTemporaryFiles()
 
+	release.DownloadRetry = s.downloadRetry
+
 	ctx := context.Background()
 
+	// releases of a busy indexer wait for their own slots, other indexers keep processing
+	done, err := s.limiter.acquire(ctx, release.Indexer)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.Process: could not acquire slot for indexer: %s", release.Indexer)
+		return
+	}
+	defer done()
+
 	// TODO check in config for "Save all releases"
 	// TODO cross-seed check
 	// TODO dupe checks
@@ -135,122 +187,372 @@ func (s *service) processFilters(ctx context.Context, filters []*domain.Filter,
 	// save both client type and client id to potentially try another client of same type
 	triedActionClients := map[actionClientTypeKey]struct{}{}
 
+	// highest priority first, filters with the same priority keep their order
+	sort.SliceStable(filters, func(i, j int) bool {
+		return filters[i].Priority > filters[j].Priority
+	})
+
 	// loop over and check filters
 	for _, f := range filters {
-		f := f
+		stop, err := s.processFilter(ctx, f, release, triedActionClients)
+		if err != nil {
+			return err
+		}
+
+		if stop {
+			break
+		}
+	}
 
-		l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+	return nil
+}
 
-		// save filter on release
-		release.Filter = f
-		release.FilterName = f.Name
-		release.FilterID = f.ID
+// processFilter checks the release against the filter and runs its actions.
+// It returns true if no further filters should be checked.
+func (s *service) processFilter(ctx context.Context, f *domain.Filter, release *domain.Release, triedActionClients map[actionClientTypeKey]struct{}) (bool, error) {
+	// filters deciding on earlier grabs check and record them for one release at a time
+	unlock := s.filterLocks.lock(f)
+	defer unlock()
 
-		// test filter
-		match, err := s.filterSvc.CheckFilter(ctx, f, release)
-		if err != nil {
-			l.Error().Err(err).Msg("release.Process: error checking filter")
-			return err
+	l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+	// save filter on release
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	// test filter
+	match, err := s.filterSvc.CheckFilter(ctx, f, release)
+	if err != nil {
+		l.Error().Err(err).Msg("release.Process: error checking filter")
+		return false, err
+	}
+
+	if !match {
+		l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s, no match. rejections: %s", release.Indexer, release.FilterName, release.TorrentName, f.RejectionsString(false))
+
+		l.Debug().Msgf("filter %s rejected release: %s", f.Name, f.RejectionsString(true))
+		return false, nil
+	}
+
+	if s.dedup.Seen(f, release) {
+		l.Info().Msgf("release.Process: skipping duplicate '%s' (%s) for %s, already grabbed within %d minutes", release.TorrentName, release.FilterName, release.Indexer, f.DedupWindow)
+		return false, nil
+	}
+
+	l.Info().Msgf("Matched '%s' (%s) for %s", release.TorrentName, release.FilterName, release.Indexer)
+
+	metrics.ReleaseMatched(release.Indexer)
+
+	// found matching filter, lets find the filter actions and attach
+	active := true
+	actions, err := s.actionSvc.FindByFilterID(ctx, f.ID, &active)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.Process: error finding actions for filter: %s", f.Name)
+		return false, err
+	}
+
+	// if no actions, continue to next filter
+	if len(actions) == 0 {
+		s.log.Warn().Msgf("release.Process: no active actions found for filter '%s', trying next one..", f.Name)
+		return false, nil
+	}
+
+	// sleep for the delay period specified in the filter before running actions
+	delay := release.Filter.Delay
+	if delay > 0 {
+		l.Debug().Msgf("release.Process: delaying processing of '%s' (%s) for %s by %d seconds as specified in the filter", release.TorrentName, release.FilterName, release.Indexer, delay)
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	// save release here to only save those with rejections from actions instead of all releases
+	if release.ID == 0 {
+		release.FilterStatus = domain.ReleaseStatusFilterApproved
+
+		if err = s.Store(ctx, release); err != nil {
+			l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
+			return false, err
 		}
+	}
+
+	// hold the release until it is approved, the actions run then
+	if f.RequireApproval {
+		if err := s.queuePending(ctx, release); err != nil {
+			l.Error().Err(err).Msgf("release.Process: error adding release to pending queue: %s", release.TorrentName)
+			return false, err
+		}
+
+		return f.StopOnMatch, nil
+	}
 
-		if !match {
-			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s, no match. rejections: %s", release.Indexer, release.FilterName, release.TorrentName, f.RejectionsString(false))
+	stop, deferred := s.runFilterActions(ctx, l, f, release, actions, triedActionClients)
+	if len(deferred) > 0 {
+		s.deferActiveDownloads(f, release, deferred, 1)
+	}
+
+	return stop, nil
+}
+
+// runFilterActions runs the actions of the matched filter for the release.
+// It returns true if no further filters should be checked. If the download client of an action reached the max active
+// downloads of the filter, that action and the ones after it are returned to run again later.
+func (s *service) runFilterActions(ctx context.Context, l zerolog.Logger, f *domain.Filter, release *domain.Release, actions []*domain.Action, triedActionClients map[actionClientTypeKey]struct{}) (bool, []*domain.Action) {
+	var rejections []string
+	var grabbed bool
+
+	// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
+	for i, a := range actions {
+		act := a
 
-			l.Debug().Msgf("filter %s rejected release: %s", f.Name, f.RejectionsString(true))
+		// only run enabled actions
+		if !act.Enabled {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer, release.FilterName, release.TorrentName, act.Name)
 			continue
 		}
 
-		l.Info().Msgf("Matched '%s' (%s) for %s", release.TorrentName, release.FilterName, release.Indexer)
+		l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer, release.FilterName, release.TorrentName, act.Name)
+
+		// keep track of action clients to avoid sending the same thing all over again
+		_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
+		if tried {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer, release.FilterName, release.TorrentName)
+			continue
+		}
+
+		// run action
+		status, err := s.runAction(ctx, act, release)
+		if errors.Is(err, domain.ErrMaxActiveDownloads) {
+			if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+				s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
+			}
+
+			// the release is held for this filter, the other filters don't get it in the meantime
+			return true, actions[i:]
+		}
 
-		// found matching filter, lets find the filter actions and attach
-		active := true
-		actions, err := s.actionSvc.FindByFilterID(ctx, f.ID, &active)
 		if err != nil {
-			s.log.Error().Err(err).Msgf("release.Process: error finding actions for filter: %s", f.Name)
-			return err
+			l.Error().Err(err).Msgf("release.Process: error running actions for filter: %s", release.FilterName)
+			//continue
 		}
 
-		// if no actions, continue to next filter
-		if len(actions) == 0 {
-			s.log.Warn().Msgf("release.Process: no active actions found for filter '%s', trying next one..", f.Name)
-			continue
+		// the notification of the next action summarizes the actions that ran before it
+		release.ActionStatus = append(release.ActionStatus, *status)
+
+		rejections = status.Rejections
+
+		if status.Status == domain.ReleasePushStatusApproved {
+			grabbed = true
 		}
 
-		// sleep for the delay period specified in the filter before running actions
-		delay := release.Filter.Delay
-		if delay > 0 {
-			l.Debug().Msgf("release.Process: delaying processing of '%s' (%s) for %s by %d seconds as specified in the filter", release.TorrentName, release.FilterName, release.Indexer, delay)
-			time.Sleep(time.Duration(delay) * time.Second)
+		if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+			s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
 		}
 
-		// save release here to only save those with rejections from actions instead of all releases
-		if release.ID == 0 {
-			release.FilterStatus = domain.ReleaseStatusFilterApproved
+		if len(rejections) > 0 {
+			// if we get action rejection, remember which action client it was from
+			triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
 
-			if err = s.Store(ctx, release); err != nil {
-				l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
-				return err
-			}
+			// log something and fire events
+			l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
 		}
 
-		var rejections []string
+		// if no rejections consider action approved, run next
+		continue
+	}
 
-		// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
-		for _, a := range actions {
-			act := a
+	// if we have rejections from arr, continue to next filter
+	if len(rejections) > 0 {
+		return false, nil
+	}
 
-			// only run enabled actions
-			if !act.Enabled {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer, release.FilterName, release.TorrentName, act.Name)
-				continue
-			}
+	if grabbed {
+		s.dedup.Record(f, release)
+		s.checkUpgrade(ctx, release)
+	}
 
-			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer, release.FilterName, release.TorrentName, act.Name)
+	// all actions run, decide to stop or continue here
+	if f.StopOnMatch {
+		return true, nil
+	}
 
-			// keep track of action clients to avoid sending the same thing all over again
-			_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
-			if tried {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer, release.FilterName, release.TorrentName)
-				continue
-			}
+	l.Debug().Msgf("release.Process: filter '%s' matched without stop on match, continue with next filter", f.Name)
 
-			// run action
-			status, err := s.runAction(ctx, act, release)
-			if err != nil {
-				l.Error().Err(err).Msgf("release.Process: error running actions for filter: %s", release.FilterName)
-				//continue
-			}
+	return false, nil
+}
 
-			rejections = status.Rejections
+// queuePending stores the release as pending approval and announces it
+func (s *service) queuePending(ctx context.Context, release *domain.Release) error {
+	pending := &domain.PendingRelease{
+		ReleaseID:   release.ID,
+		FilterID:    release.FilterID,
+		TorrentName: release.TorrentName,
+		Indexer:     release.Indexer,
+		FilterName:  release.FilterName,
+		MagnetURI:   release.MagnetURI,
+		Vars:        release.Vars,
+	}
 
-			if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
-				s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
-			}
+	if err := s.pendingRepo.Store(ctx, pending); err != nil {
+		return err
+	}
 
-			if len(rejections) > 0 {
-				// if we get action rejection, remember which action client it was from
-				triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
+	s.log.Info().Msgf("release %s (%s) is pending approval", release.TorrentName, release.FilterName)
+
+	payload := &domain.NotificationPayload{
+		Subject:        "Release pending approval",
+		Message:        fmt.Sprintf("Approve or reject pending release %d to continue", pending.ID),
+		Event:          domain.NotificationEventReleasePending,
+		ReleaseName:    release.TorrentName,
+		Filter:         release.FilterName,
+		Indexer:        release.Indexer,
+		InfoURL:        release.InfoURL,
+		Size:           release.Size,
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+	}
 
-				// log something and fire events
-				l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
-			}
+	s.bus.Publish("events:notification", &payload.Event, payload)
 
-			// if no rejections consider action approved, run next
-			continue
+	return nil
+}
+
+func (s *service) ListPending(ctx context.Context) ([]domain.PendingRelease, error) {
+	return s.pendingRepo.List(ctx)
+}
+
+// ApprovePending removes the release from the pending queue and runs the actions of its filter
+func (s *service) ApprovePending(ctx context.Context, id int) error {
+	pending, err := s.pendingRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	release, err := s.Get(ctx, &domain.GetReleaseRequest{Id: int(pending.ReleaseID)})
+	if err != nil {
+		return err
+	}
+
+	// the approved release may download the torrent file again
+	defer release.CleanupTemporaryFiles()
+
+	// the stored release only has the announced fields, parse the rest from the name again
+	release.ParseString(release.TorrentName)
+	release.MagnetURI = pending.MagnetURI
+	release.Vars = pending.Vars
+
+	f, err := s.filterSvc.FindByID(ctx, pending.FilterID)
+	if err != nil {
+		return err
+	}
+
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	active := true
+	actions, err := s.actionSvc.FindByFilterID(ctx, f.ID, &active)
+	if err != nil {
+		return err
+	}
+
+	// approved releases share the filter lock with live releases, so max downloads and dedup see every grab
+	unlock := s.filterLocks.lock(f)
+	defer unlock()
+
+	ok, err := s.checkGrabs(ctx, f, release)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		s.log.Info().Msgf("release %s (%s) already grabbed within %d minutes, removing it from the pending queue", release.TorrentName, release.FilterName, f.DedupWindow)
+		return s.pendingRepo.Delete(ctx, pending.ID)
+	}
+
+	// remove it before running so the actions can't run twice
+	if err := s.pendingRepo.Delete(ctx, pending.ID); err != nil {
+		return err
+	}
+
+	s.log.Info().Msgf("release %s (%s) approved, running actions", release.TorrentName, release.FilterName)
+
+	l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+	if _, deferred := s.runFilterActions(ctx, l, f, release, actions, map[actionClientTypeKey]struct{}{}); len(deferred) > 0 {
+		s.deferActiveDownloads(f, release, deferred, 1)
+	}
+
+	return nil
+}
+
+// checkGrabs checks a release that matched the filter earlier, like an approved or deferred release, against the grabs
+// made by the filter since. It returns false if the release was grabbed already and an error if max downloads is reached.
+// The caller holds the filter lock.
+func (s *service) checkGrabs(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	if s.dedup.Seen(f, release) {
+		return false, nil
+	}
+
+	if f.MaxDownloads > 0 {
+		downloads, err := s.filterSvc.GetDownloadsByFilterId(ctx, f.ID)
+		if err != nil {
+			return false, err
 		}
+		f.Downloads = downloads
 
-		// if we have rejections from arr, continue to next filter
-		if len(rejections) > 0 {
-			continue
+		if f.MaxDownloadsReached() {
+			return false, errors.New("max downloads (%d) this (%v) reached for filter: %s", f.MaxDownloads, f.MaxDownloadsUnit, f.Name)
 		}
+	}
 
-		// all actions run, decide to stop or continue here
-		break
+	return true, nil
+}
+
+// RejectPending removes the release from the pending queue without running any actions
+func (s *service) RejectPending(ctx context.Context, id int) error {
+	if err := s.pendingRepo.Delete(ctx, id); err != nil {
+		return err
 	}
 
+	s.log.Info().Msgf("pending release %d rejected", id)
+
 	return nil
 }
 
+// checkUpgrade sends an upgrade notification if the release supersedes a previous grab
+func (s *service) checkUpgrade(ctx context.Context, release *domain.Release) {
+	prev, upgrade, err := s.upgrades.Track(ctx, release)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not check if release is an upgrade: %s", release.TorrentName)
+		return
+	}
+
+	if !upgrade {
+		return
+	}
+
+	current := grab{TorrentName: release.TorrentName, Resolution: release.Resolution, Source: release.Source}
+
+	s.log.Info().Msgf("release %s is an upgrade of %s", release.TorrentName, prev.TorrentName)
+
+	payload := &domain.NotificationPayload{
+		Subject:        "Release upgraded",
+		Message:        fmt.Sprintf("Before: %s\nAfter: %s", prev, current),
+		Event:          domain.NotificationEventReleaseUpgrade,
+		ReleaseName:    release.TorrentName,
+		Filter:         release.FilterName,
+		Indexer:        release.Indexer,
+		InfoURL:        release.InfoURL,
+		Size:           release.Size,
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+	}
+
+	s.bus.Publish("events:notification", &payload.Event, payload)
+}
+
 func (s *service) ProcessMultiple(releases []*domain.Release) {
 	s.log.Debug().Msgf("process (%d) new releases from feed", len(releases))
 
@@ -272,12 +574,23 @@ func (s *service) runAction(ctx context.Context, action *domain.Action, release
 	}
 
 	rejections, err := s.actionSvc.RunAction(ctx, action, release)
+	if errors.Is(err, domain.ErrMaxActiveDownloads) {
+		status.Status = domain.ReleasePushStatusRejected
+		status.Rejections = []string{domain.ErrMaxActiveDownloads.Error()}
+
+		return status, err
+	}
+
 	if err != nil {
 		s.log.Error().Err(err).Msgf("release.runAction: error running actions for filter: %s", release.FilterName)
 
 		status.Status = domain.ReleasePushStatusErr
 		status.Rejections = []string{err.Error()}
 
+		if errors.Is(err, domain.ErrActionTimeout) {
+			status.Status = domain.ReleasePushStatusTimeout
+		}
+
 		return status, err
 	}
 
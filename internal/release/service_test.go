@@ -0,0 +1,669 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReleaseRepo struct {
+	domain.ReleaseRepo
+}
+
+func (r *mockReleaseRepo) Store(ctx context.Context, release *domain.Release) error {
+	release.ID = 1
+	return nil
+}
+
+// Get returns the release like it's stored, only the name is set and not the parsed fields
+func (r *mockReleaseRepo) Get(ctx context.Context, req *domain.GetReleaseRequest) (*domain.Release, error) {
+	release := domain.NewRelease("mock")
+	release.ID = int64(req.Id)
+	release.TorrentName = "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"
+
+	return release, nil
+}
+
+func (r *mockReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
+	return nil
+}
+
+// mockFilterService matches every filter and records the order they were checked in
+type mockFilterService struct {
+	filter.Service
+	m         sync.Mutex
+	filters   []*domain.Filter
+	checked   []string
+	downloads *domain.FilterDownloads
+}
+
+func (s *mockFilterService) FindByIndexerIdentifier(ctx context.Context, indexer string) ([]*domain.Filter, error) {
+	return s.filters, nil
+}
+
+func (s *mockFilterService) FindByID(ctx context.Context, filterID int) (*domain.Filter, error) {
+	for _, f := range s.filters {
+		if f.ID == filterID {
+			return f, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func (s *mockFilterService) CheckFilter(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.checked = append(s.checked, f.Name)
+	return true, nil
+}
+
+func (s *mockFilterService) GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error) {
+	return s.downloads, nil
+}
+
+type mockPendingReleaseRepo struct {
+	pending []domain.PendingRelease
+}
+
+func (r *mockPendingReleaseRepo) List(ctx context.Context) ([]domain.PendingRelease, error) {
+	return r.pending, nil
+}
+
+func (r *mockPendingReleaseRepo) FindByID(ctx context.Context, id int) (*domain.PendingRelease, error) {
+	for _, p := range r.pending {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockPendingReleaseRepo) Store(ctx context.Context, pending *domain.PendingRelease) error {
+	pending.ID = len(r.pending) + 1
+	r.pending = append(r.pending, *pending)
+	return nil
+}
+
+func (r *mockPendingReleaseRepo) Delete(ctx context.Context, id int) error {
+	for i, p := range r.pending {
+		if p.ID == id {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			return nil
+		}
+	}
+
+	return domain.ErrRecordNotFound
+}
+
+// mockActionService returns one test action per filter and records the filters that ran it
+type mockActionService struct {
+	action.Service
+	m        sync.Mutex
+	ran      []string
+	releases []*domain.Release
+}
+
+func (s *mockActionService) FindByFilterID(ctx context.Context, filterID int, active *bool) ([]*domain.Action, error) {
+	return []*domain.Action{{ID: filterID, Name: "test", Type: domain.ActionTypeTest, Enabled: true, FilterID: filterID}}, nil
+}
+
+func (s *mockActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.ran = append(s.ran, release.FilterName)
+	s.releases = append(s.releases, release)
+	return nil, nil
+}
+
+func Test_service_processFilters_priority(t *testing.T) {
+	tests := []struct {
+		name        string
+		stopOnMatch bool
+		wantChecked []string
+		wantRan     []string
+	}{
+		{
+			name:        "stop_on_match",
+			stopOnMatch: true,
+			wantChecked: []string{"high"},
+			wantRan:     []string{"high"},
+		},
+		{
+			name:        "continue_on_match",
+			stopOnMatch: false,
+			wantChecked: []string{"high", "low"},
+			wantRan:     []string{"high", "low"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// returned in the wrong order to make sure priority decides
+			filterSvc := &mockFilterService{
+				filters: []*domain.Filter{
+					{ID: 1, Name: "low", Enabled: true, Priority: 1, StopOnMatch: true},
+					{ID: 2, Name: "high", Enabled: true, Priority: 10, StopOnMatch: tt.stopOnMatch},
+				},
+			}
+			actionSvc := &mockActionService{}
+
+			s := &service{
+				log:       logger.Mock().With().Logger(),
+				repo:      &mockReleaseRepo{},
+				bus:       EventBus.New(),
+				actionSvc: actionSvc,
+				filterSvc: filterSvc,
+				upgrades:  newUpgradeTracker(nil),
+				dedup:     newDedupCache(),
+			}
+
+			release := domain.NewRelease("mock")
+			release.ParseString("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP")
+
+			s.Process(release)
+
+			assert.Equal(t, tt.wantChecked, filterSvc.checked)
+			assert.Equal(t, tt.wantRan, actionSvc.ran)
+		})
+	}
+}
+
+func Test_service_requireApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		approve bool
+		wantRan []string
+	}{
+		{
+			name:    "approve",
+			approve: true,
+			wantRan: []string{"approval"},
+		},
+		{
+			name:    "reject",
+			approve: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filterSvc := &mockFilterService{
+				filters: []*domain.Filter{
+					{ID: 1, Name: "approval", Enabled: true, RequireApproval: true},
+				},
+			}
+			actionSvc := &mockActionService{}
+			pendingRepo := &mockPendingReleaseRepo{}
+			bus := EventBus.New()
+
+			var events []domain.NotificationEvent
+			err := bus.Subscribe("events:notification", func(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
+				events = append(events, *event)
+			})
+			assert.NoError(t, err)
+
+			s := &service{
+				log:         logger.Mock().With().Logger(),
+				repo:        &mockReleaseRepo{},
+				pendingRepo: pendingRepo,
+				bus:         bus,
+				actionSvc:   actionSvc,
+				filterSvc:   filterSvc,
+				upgrades:    newUpgradeTracker(nil),
+				dedup:       newDedupCache(),
+			}
+
+			release := domain.NewRelease("mock")
+			release.ParseString("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP")
+			release.MagnetURI = "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567"
+			release.Vars = map[string]string{"freeleech": "true"}
+
+			s.Process(release)
+
+			// held in the queue without running any actions
+			assert.Empty(t, actionSvc.ran)
+			assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventReleasePending}, events)
+
+			pending, err := s.ListPending(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, pending, 1)
+			assert.Equal(t, release.ID, pending[0].ReleaseID)
+			assert.Equal(t, 1, pending[0].FilterID)
+
+			if tt.approve {
+				assert.NoError(t, s.ApprovePending(context.Background(), pending[0].ID))
+			} else {
+				assert.NoError(t, s.RejectPending(context.Background(), pending[0].ID))
+			}
+
+			assert.Equal(t, tt.wantRan, actionSvc.ran)
+
+			// the actions get the release as announced, not just the stored fields
+			if tt.approve && assert.Len(t, actionSvc.releases, 1) {
+				approved := actionSvc.releases[0]
+				assert.Equal(t, "That Show", approved.Title)
+				assert.Equal(t, 1, approved.Season)
+				assert.Equal(t, "GROUP", approved.Group)
+				assert.Equal(t, "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567", approved.MagnetURI)
+				assert.Equal(t, map[string]string{"freeleech": "true"}, approved.Vars)
+			}
+
+			pending, err = s.ListPending(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, pending)
+
+			// already handled
+			assert.ErrorIs(t, s.ApprovePending(context.Background(), 1), domain.ErrRecordNotFound)
+		})
+	}
+}
+
+func Test_service_ApprovePending_filterChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      *domain.Filter
+		downloads   *domain.FilterDownloads
+		grabbed     bool
+		wantErr     bool
+		wantPending int
+	}{
+		{
+			name:   "max_downloads_reached",
+			filter: &domain.Filter{ID: 1, Name: "approval", Enabled: true, RequireApproval: true, MaxDownloads: 1, MaxDownloadsUnit: domain.FilterMaxDownloadsDay},
+			downloads: &domain.FilterDownloads{
+				DayCount: 1,
+			},
+			wantErr:     true,
+			wantPending: 1,
+		},
+		{
+			name:    "already_grabbed",
+			filter:  &domain.Filter{ID: 1, Name: "approval", Enabled: true, RequireApproval: true, DedupWindow: 10, DedupKey: domain.FilterDedupKeyName},
+			grabbed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filterSvc := &mockFilterService{
+				filters:   []*domain.Filter{tt.filter},
+				downloads: tt.downloads,
+			}
+			actionSvc := &mockActionService{}
+
+			s := &service{
+				log:         logger.Mock().With().Logger(),
+				repo:        &mockReleaseRepo{},
+				pendingRepo: &mockPendingReleaseRepo{},
+				bus:         EventBus.New(),
+				actionSvc:   actionSvc,
+				filterSvc:   filterSvc,
+				upgrades:    newUpgradeTracker(nil),
+				dedup:       newDedupCache(),
+				filterLocks: newFilterLocks(),
+			}
+
+			release := domain.NewRelease("mock")
+			release.TorrentName = "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"
+			release.ParseString(release.TorrentName)
+
+			s.Process(release)
+
+			// a live release of the same filter was grabbed while this one was pending
+			if tt.grabbed {
+				s.dedup.Record(tt.filter, release)
+			}
+
+			err := s.ApprovePending(context.Background(), 1)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Empty(t, actionSvc.ran)
+
+			pending, err := s.ListPending(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, pending, tt.wantPending)
+		})
+	}
+}
+
+// slowActionService blocks the actions of the slow indexer until unblocked, other indexers run right away
+type slowActionService struct {
+	mockActionService
+	slowIndexer string
+	unblock     chan struct{}
+	started     chan string
+}
+
+func (s *slowActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.started <- release.TorrentName
+
+	if release.Indexer == s.slowIndexer {
+		<-s.unblock
+	}
+
+	return s.mockActionService.RunAction(ctx, action, release)
+}
+
+func Test_service_Process_indexerLimit(t *testing.T) {
+	filterSvc := &mockFilterService{
+		filters: []*domain.Filter{{ID: 1, Name: "all", Enabled: true}},
+	}
+	actionSvc := &slowActionService{
+		slowIndexer: "busy",
+		unblock:     make(chan struct{}),
+		started:     make(chan string, 10),
+	}
+
+	s := &service{
+		log:       logger.Mock().With().Logger(),
+		repo:      &mockReleaseRepo{},
+		bus:       EventBus.New(),
+		actionSvc: actionSvc,
+		filterSvc: filterSvc,
+		upgrades:  newUpgradeTracker(nil),
+		dedup:     newDedupCache(),
+		limiter:   newIndexerLimiter(1, 0),
+	}
+
+	newRelease := func(indexer string, name string) *domain.Release {
+		release := domain.NewRelease(indexer)
+		release.ParseString(name)
+		return release
+	}
+
+	var wg sync.WaitGroup
+	process := func(release *domain.Release) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Process(release)
+		}()
+	}
+
+	waitStarted := func() string {
+		select {
+		case name := <-actionSvc.started:
+			return name
+		case <-time.After(time.Second):
+			return ""
+		}
+	}
+
+	// the first release of the busy indexer holds its only slot, the next one has to wait for it
+	process(newRelease("busy", "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+	assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", waitStarted())
+
+	process(newRelease("busy", "That.Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+
+	// another indexer is not held up by the busy one
+	process(newRelease("quiet", "Other.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+	assert.Equal(t, "Other.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", waitStarted())
+
+	select {
+	case name := <-actionSvc.started:
+		t.Fatalf("release %s of the busy indexer started before the slot was free", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(actionSvc.unblock)
+	assert.Equal(t, "That.Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP", waitStarted())
+
+	wg.Wait()
+	assert.Len(t, actionSvc.ran, 3)
+}
+
+func TestIndexerLimiter_acquire_rateLimit(t *testing.T) {
+	l := newIndexerLimiter(0, 60)
+
+	// the burst is the limit per minute, the next release of the indexer has to wait
+	for i := 0; i < 60; i++ {
+		done, err := l.acquire(context.Background(), "busy")
+		assert.NoError(t, err)
+		done()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := l.acquire(ctx, "busy")
+	assert.Error(t, err)
+
+	// other indexers have their own limit
+	done, err := l.acquire(ctx, "quiet")
+	assert.NoError(t, err)
+	done()
+}
+
+// maxDownloadsFilterService rejects releases once the actions ran as many times as the max downloads of the filter
+type maxDownloadsFilterService struct {
+	mockFilterService
+	actionSvc *mockActionService
+}
+
+func (s *maxDownloadsFilterService) CheckFilter(ctx context.Context, f *domain.Filter, release *domain.Release) (bool, error) {
+	s.actionSvc.m.Lock()
+	defer s.actionSvc.m.Unlock()
+
+	return len(s.actionSvc.ran) < f.MaxDownloads, nil
+}
+
+// delayedActionService takes a while to run the action, like adding to a download client
+type delayedActionService struct {
+	mockActionService
+}
+
+func (s *delayedActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	time.Sleep(20 * time.Millisecond)
+
+	return s.mockActionService.RunAction(ctx, action, release)
+}
+
+func Test_service_ProcessMultiple_maxDownloads(t *testing.T) {
+	actionSvc := &delayedActionService{}
+	filterSvc := &maxDownloadsFilterService{
+		mockFilterService: mockFilterService{
+			filters: []*domain.Filter{{ID: 1, Name: "max", Enabled: true, MaxDownloads: 2, MaxDownloadsUnit: domain.FilterMaxDownloadsHour}},
+		},
+		actionSvc: &actionSvc.mockActionService,
+	}
+
+	s := &service{
+		log:         logger.Mock().With().Logger(),
+		repo:        &mockReleaseRepo{},
+		bus:         EventBus.New(),
+		actionSvc:   actionSvc,
+		filterSvc:   filterSvc,
+		upgrades:    newUpgradeTracker(nil),
+		dedup:       newDedupCache(),
+		filterLocks: newFilterLocks(),
+	}
+
+	// feeds refreshing at the same time process their releases at the same time,
+	// but only the first two may pass the check of the filter
+	var wg sync.WaitGroup
+	for _, feed := range []string{"feed-1", "feed-2"} {
+		var releases []*domain.Release
+		for i := 1; i <= 5; i++ {
+			release := domain.NewRelease(feed)
+			release.ParseString(fmt.Sprintf("That.Show.S01E%02d.1080p.WEB-DL.DDP5.1.H.264-GROUP", i))
+			releases = append(releases, release)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.ProcessMultiple(releases)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, actionSvc.ran, 2)
+}
+
+// failingActionService fails the actions of the releases from the failing indexer
+type failingActionService struct {
+	mockActionService
+	failingIndexer string
+}
+
+func (s *failingActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.ran = append(s.ran, release.Indexer)
+
+	if release.Indexer == s.failingIndexer {
+		return nil, fmt.Errorf("client unavailable")
+	}
+
+	return nil, nil
+}
+
+func Test_service_Process_dedupAfterGrab(t *testing.T) {
+	filterSvc := &mockFilterService{
+		filters: []*domain.Filter{{ID: 1, Name: "dedup", Enabled: true, DedupWindow: 10, DedupKey: domain.FilterDedupKeyName}},
+	}
+	actionSvc := &failingActionService{failingIndexer: "first"}
+
+	s := &service{
+		log:         logger.Mock().With().Logger(),
+		repo:        &mockReleaseRepo{},
+		bus:         EventBus.New(),
+		actionSvc:   actionSvc,
+		filterSvc:   filterSvc,
+		upgrades:    newUpgradeTracker(nil),
+		dedup:       newDedupCache(),
+		filterLocks: newFilterLocks(),
+	}
+
+	for _, indexer := range []string{"first", "second", "third"} {
+		release := domain.NewRelease(indexer)
+		release.TorrentName = "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"
+		release.ParseString(release.TorrentName)
+
+		s.Process(release)
+	}
+
+	// the failed grab from the first indexer doesn't block the second one, the grab of the second blocks the third
+	assert.Equal(t, []string{"first", "second"}, actionSvc.ran)
+}
+
+// activeLimitActionService reports the max active downloads of the filter as reached for the first busy runs
+type activeLimitActionService struct {
+	mockActionService
+	busy int
+	runs int
+}
+
+func (s *activeLimitActionService) RunAction(ctx context.Context, action *domain.Action, release *domain.Release) ([]string, error) {
+	s.m.Lock()
+	s.runs++
+	busy := s.runs <= s.busy
+	s.m.Unlock()
+
+	if busy {
+		return nil, domain.ErrMaxActiveDownloads
+	}
+
+	return s.mockActionService.RunAction(ctx, action, release)
+}
+
+func (s *activeLimitActionService) counts() (int, int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.runs, len(s.ran)
+}
+
+func Test_service_Process_deferMaxActiveDownloads(t *testing.T) {
+	delay, maxDelay, maxAttempts := activeDownloadsRetryDelay, activeDownloadsMaxRetryDelay, activeDownloadsMaxAttempts
+	activeDownloadsRetryDelay, activeDownloadsMaxRetryDelay, activeDownloadsMaxAttempts = 10*time.Millisecond, 20*time.Millisecond, 3
+	t.Cleanup(func() {
+		activeDownloadsRetryDelay, activeDownloadsMaxRetryDelay, activeDownloadsMaxAttempts = delay, maxDelay, maxAttempts
+	})
+
+	newService := func(actionSvc action.Service) *service {
+		return &service{
+			log:  logger.Mock().With().Logger(),
+			repo: &mockReleaseRepo{},
+			bus:  EventBus.New(),
+			filterSvc: &mockFilterService{
+				filters: []*domain.Filter{{ID: 1, Name: "tv", Enabled: true, MaxActiveDownloads: 1, DedupWindow: 10, DedupKey: domain.FilterDedupKeyName}},
+			},
+			actionSvc:   actionSvc,
+			upgrades:    newUpgradeTracker(nil),
+			dedup:       newDedupCache(),
+			filterLocks: newFilterLocks(),
+		}
+	}
+
+	newRelease := func(name string) *domain.Release {
+		release := domain.NewRelease("mock")
+		release.TorrentName = name
+		release.ParseString(name)
+		return release
+	}
+
+	t.Run("added_after_retry", func(t *testing.T) {
+		actionSvc := &activeLimitActionService{busy: 1}
+		s := newService(actionSvc)
+
+		// the deferred release doesn't hold the filter, the next one is added right away
+		s.Process(newRelease("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+		s.Process(newRelease("That.Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+
+		runs, ran := actionSvc.counts()
+		assert.Equal(t, 2, runs)
+		assert.Equal(t, 1, ran)
+
+		assert.Eventually(t, func() bool {
+			_, ran := actionSvc.counts()
+			return ran == 2
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("skipped_after_max_attempts", func(t *testing.T) {
+		actionSvc := &activeLimitActionService{busy: 100}
+		s := newService(actionSvc)
+
+		s.Process(newRelease("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+
+		assert.Eventually(t, func() bool {
+			runs, _ := actionSvc.counts()
+			return runs == activeDownloadsMaxAttempts
+		}, time.Second, 5*time.Millisecond)
+
+		// no more attempts after the last one
+		time.Sleep(50 * time.Millisecond)
+
+		runs, ran := actionSvc.counts()
+		assert.Equal(t, activeDownloadsMaxAttempts, runs)
+		assert.Equal(t, 0, ran)
+	})
+}
+
+func Test_service_Get_downloadRetry(t *testing.T) {
+	s := NewService(logger.Mock(), &domain.Config{DownloadRetries: 5, DownloadRetryDelay: 2}, &mockReleaseRepo{}, nil, nil, nil, EventBus.New())
+
+	release, err := s.Get(context.Background(), &domain.GetReleaseRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DownloadRetryPolicy{Attempts: 5, Delay: 2 * time.Second}, release.DownloadRetry)
+}
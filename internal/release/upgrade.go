@@ -0,0 +1,150 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var upgradeKeyRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// upgradeTrackerTTL is how long a grab stays cached, after that it's looked up in the release table again
+const upgradeTrackerTTL = 24 * time.Hour
+
+// grab is a previously grabbed release used to detect upgrades
+type grab struct {
+	TorrentName string
+	Resolution  string
+	Source      string
+	rank        int
+	expires     time.Time
+}
+
+func (g grab) String() string {
+	return fmt.Sprintf("%s (%s)", g.TorrentName, strings.TrimSpace(g.Resolution+" "+g.Source))
+}
+
+// grabLookup returns the grabbed releases with the same title, season and episode as the release
+type grabLookup func(ctx context.Context, release *domain.Release) ([]*domain.Release, error)
+
+// upgradeTracker keeps track of prior grabs by normalized key to detect when a release supersedes an earlier one.
+// Grabs are cached for upgradeTrackerTTL, on a miss the prior grabs are looked up so they survive a restart.
+type upgradeTracker struct {
+	mu     sync.Mutex
+	grabs  map[string]grab
+	lookup grabLookup
+	now    func() time.Time
+}
+
+func newUpgradeTracker(lookup grabLookup) *upgradeTracker {
+	return &upgradeTracker{
+		grabs:  make(map[string]grab),
+		lookup: lookup,
+		now:    time.Now,
+	}
+}
+
+// Track stores the grab and returns the previous grab if the release is an upgrade of it
+func (t *upgradeTracker) Track(ctx context.Context, release *domain.Release) (*grab, bool, error) {
+	key := upgradeKey(release)
+	if key == "" {
+		return nil, false, nil
+	}
+
+	now := t.now()
+
+	current := grab{
+		TorrentName: release.TorrentName,
+		Resolution:  release.Resolution,
+		Source:      release.Source,
+		rank:        release.QualityRank(),
+		expires:     now.Add(upgradeTrackerTTL),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// prune expired entries
+	for k, g := range t.grabs {
+		if now.After(g.expires) {
+			delete(t.grabs, k)
+		}
+	}
+
+	prev, ok := t.grabs[key]
+	if !ok {
+		var err error
+		prev, ok, err = t.lookupGrab(ctx, release, now)
+		if err != nil {
+			t.grabs[key] = current
+			return nil, false, err
+		}
+	}
+
+	if !ok {
+		t.grabs[key] = current
+		return nil, false, nil
+	}
+
+	if current.rank <= prev.rank {
+		t.grabs[key] = prev
+		return nil, false, nil
+	}
+
+	t.grabs[key] = current
+
+	return &prev, true, nil
+}
+
+// lookupGrab returns the best prior grab of the release, the release itself is skipped
+func (t *upgradeTracker) lookupGrab(ctx context.Context, release *domain.Release, now time.Time) (grab, bool, error) {
+	if t.lookup == nil {
+		return grab{}, false, nil
+	}
+
+	releases, err := t.lookup(ctx, release)
+	if err != nil {
+		return grab{}, false, errors.Wrap(err, "could not find prior grabs of: %s", release.TorrentName)
+	}
+
+	var best grab
+	found := false
+
+	for _, r := range releases {
+		if (release.ID != 0 && r.ID == release.ID) || r.TorrentName == release.TorrentName {
+			continue
+		}
+
+		if rank := r.QualityRank(); !found || rank > best.rank {
+			best = grab{
+				TorrentName: r.TorrentName,
+				Resolution:  r.Resolution,
+				Source:      r.Source,
+				rank:        rank,
+				expires:     now.Add(upgradeTrackerTTL),
+			}
+			found = true
+		}
+	}
+
+	return best, found, nil
+}
+
+// upgradeKey normalizes title, year, season and episode so different releases of the same content share a key
+func upgradeKey(release *domain.Release) string {
+	title := upgradeKeyRegex.ReplaceAllString(strings.ToLower(release.Title), "")
+	if title == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s-%d-s%de%d", title, release.Year, release.Season, release.Episode)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgradeTracker_Track(t *testing.T) {
+	tests := []struct {
+		name        string
+		first       string
+		second      string
+		wantUpgrade bool
+	}{
+		{
+			name:        "upgrade_resolution",
+			first:       "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+			second:      "That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-OTHER",
+			wantUpgrade: true,
+		},
+		{
+			name:        "same_quality",
+			first:       "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+			second:      "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-OTHER",
+			wantUpgrade: false,
+		},
+		{
+			name:        "downgrade",
+			first:       "That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-GROUP",
+			second:      "That.Show.S01E01.720p.WEB-DL.DDP5.1.H.264-OTHER",
+			wantUpgrade: false,
+		},
+		{
+			name:        "different_episode",
+			first:       "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+			second:      "That.Show.S01E02.2160p.WEB-DL.DDP5.1.H.265-GROUP",
+			wantUpgrade: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newUpgradeTracker(nil)
+
+			first := domain.NewRelease("mock")
+			first.TorrentName = tt.first
+			first.ParseString(tt.first)
+
+			second := domain.NewRelease("mock")
+			second.TorrentName = tt.second
+			second.ParseString(tt.second)
+
+			prev, upgrade, err := tracker.Track(context.Background(), first)
+			assert.NoError(t, err)
+			assert.False(t, upgrade)
+			assert.Nil(t, prev)
+
+			prev, upgrade, err = tracker.Track(context.Background(), second)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUpgrade, upgrade)
+
+			if tt.wantUpgrade {
+				assert.Equal(t, tt.first, prev.TorrentName)
+			} else {
+				assert.Nil(t, prev)
+			}
+		})
+	}
+}
+
+func newTestRelease(name string) *domain.Release {
+	r := domain.NewRelease("mock")
+	r.TorrentName = name
+	r.ParseString(name)
+
+	return r
+}
+
+func TestUpgradeTracker_Track_expires(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker := newUpgradeTracker(nil)
+	tracker.now = func() time.Time { return now }
+
+	_, upgrade, err := tracker.Track(context.Background(), newTestRelease("That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+	assert.NoError(t, err)
+	assert.False(t, upgrade)
+
+	// the other episode prunes the expired grab
+	now = now.Add(upgradeTrackerTTL + time.Minute)
+
+	_, _, err = tracker.Track(context.Background(), newTestRelease("That.Show.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP"))
+	assert.NoError(t, err)
+	assert.Len(t, tracker.grabs, 1)
+
+	_, upgrade, err = tracker.Track(context.Background(), newTestRelease("That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-OTHER"))
+	assert.NoError(t, err)
+	assert.False(t, upgrade)
+}
+
+func TestUpgradeTracker_Track_lookup(t *testing.T) {
+	// grabs from before a restart are only in the release table
+	lookups := 0
+	tracker := newUpgradeTracker(func(ctx context.Context, release *domain.Release) ([]*domain.Release, error) {
+		lookups++
+		assert.Equal(t, "That Show", release.Title)
+
+		return []*domain.Release{
+			{ID: 1, TorrentName: "That.Show.S01E01.720p.WEB-DL.DDP5.1.H.264-GROUP", Resolution: "720p", Source: "WEB-DL"},
+			{ID: 2, TorrentName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", Resolution: "1080p", Source: "WEB-DL"},
+			{ID: 3, TorrentName: "That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-OTHER", Resolution: "2160p", Source: "WEB-DL"},
+		}, nil
+	})
+
+	release := newTestRelease("That.Show.S01E01.2160p.WEB-DL.DDP5.1.H.265-OTHER")
+	release.ID = 3
+
+	prev, upgrade, err := tracker.Track(context.Background(), release)
+	assert.NoError(t, err)
+	assert.True(t, upgrade)
+	assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", prev.TorrentName)
+
+	// the grab is cached now
+	_, upgrade, err = tracker.Track(context.Background(), newTestRelease("That.Show.S01E01.1080p.BluRay.DDP5.1.H.264-GROUP"))
+	assert.NoError(t, err)
+	assert.False(t, upgrade)
+	assert.Equal(t, 1, lookups)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package scheduler
+
+import (
+	"time"
+)
+
+// offsetSchedule runs every interval like cron.Every, with the first run delayed by offset.
+// Runs stay aligned to the first run so the offset is kept for every following run.
+type offsetSchedule struct {
+	first    time.Time
+	interval time.Duration
+}
+
+func newOffsetSchedule(now time.Time, interval time.Duration, offset time.Duration) offsetSchedule {
+	// cron runs at second granularity
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	return offsetSchedule{
+		first:    now.Add(interval + offset).Truncate(time.Second),
+		interval: interval.Truncate(time.Second),
+	}
+}
+
+// Next returns the first run after t
+func (s offsetSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.first) {
+		return s.first
+	}
+
+	runs := t.Sub(s.first)/s.interval + 1
+
+	return s.first.Add(runs * s.interval)
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_offsetSchedule_Next(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	s := newOffsetSchedule(now, 15*time.Minute, 4*time.Minute)
+
+	// the first run is delayed by the offset
+	next := s.Next(now)
+	assert.Equal(t, now.Add(19*time.Minute), next)
+
+	// following runs keep the offset
+	next = s.Next(next)
+	assert.Equal(t, now.Add(34*time.Minute), next)
+
+	// a late check still lands on the schedule
+	assert.Equal(t, now.Add(49*time.Minute), s.Next(now.Add(40*time.Minute)))
+}
@@ -21,6 +21,7 @@ type Service interface {
 	Start()
 	Stop()
 	ScheduleJob(job cron.Job, interval time.Duration, identifier string) (int, error)
+	ScheduleJobWithOffset(job cron.Job, interval time.Duration, offset time.Duration, identifier string) (int, error)
 	AddJob(job cron.Job, spec string, identifier string) (int, error)
 	RemoveJobByIdentifier(id string) error
 	GetNextRun(id string) (time.Time, error)
@@ -102,6 +103,22 @@ func (s *service) ScheduleJob(job cron.Job, interval time.Duration, identifier s
 	return int(id), nil
 }
 
+// ScheduleJobWithOffset adds a job running every interval, with the runs shifted by offset
+func (s *service) ScheduleJobWithOffset(job cron.Job, interval time.Duration, offset time.Duration, identifier string) (int, error) {
+	schedule := newOffsetSchedule(time.Now(), interval, offset)
+
+	id := s.cron.Schedule(schedule, cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(job))
+
+	s.log.Debug().Msgf("scheduler.ScheduleJobWithOffset: job successfully added: %s id %d offset %s", identifier, id, offset)
+
+	s.m.Lock()
+	// add to job map
+	s.jobs[identifier] = id
+	s.m.Unlock()
+
+	return int(id), nil
+}
+
 // AddJob takes a cron schedule and adds a job
 func (s *service) AddJob(job cron.Job, spec string, identifier string) (int, error) {
 	id, err := s.cron.AddJob(spec, cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(job))
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/feed"
 	"github.com/autobrr/autobrr/internal/indexer"
@@ -23,6 +24,7 @@ type Server struct {
 	log    zerolog.Logger
 	config *domain.Config
 
+	actionService  action.Service
 	indexerService indexer.Service
 	ircService     irc.Service
 	feedService    feed.Service
@@ -33,10 +35,11 @@ type Server struct {
 	lock   sync.Mutex
 }
 
-func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
+func NewServer(log logger.Logger, config *domain.Config, actionSvc action.Service, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
 	return &Server{
 		log:            log.With().Str("module", "server").Logger(),
 		config:         config,
+		actionService:  actionSvc,
 		indexerService: indexerSvc,
 		ircService:     ircSvc,
 		feedService:    feedSvc,
@@ -57,6 +60,11 @@ func (s *Server) Start() error {
 		return err
 	}
 
+	// resume torrents that were waiting for their resume delay before a restart
+	if err := s.actionService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start action service")
+	}
+
 	// instantiate and start irc networks
 	s.ircService.StartHandlers()
 
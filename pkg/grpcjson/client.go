@@ -0,0 +1,199 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package grpcjson is a minimal unary gRPC client using the json codec.
+// Messages are sent as json with content-subtype json (application/grpc+json) so no generated protobuf code is needed.
+// The server needs a json codec registered, grpc-go servers do with encoding.RegisterCodec.
+package grpcjson
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	DefaultTimeout = 60 * time.Second
+
+	ErrInvalidTarget = errors.New("invalid grpc target")
+	ErrInvalidMethod = errors.New("invalid grpc method")
+)
+
+type Client struct {
+	conn    *grpc.ClientConn
+	timeout time.Duration
+}
+
+type Config struct {
+	// Target is host:port, grpc://host:port for plaintext or grpcs://host:port for TLS
+	Target string
+
+	// TLS skip cert validation
+	TLSSkipVerify bool
+
+	// Timeout in seconds for each call, DefaultTimeout when zero
+	Timeout int
+
+	// Dialer is used to connect to the target instead of the default dialer
+	Dialer func(ctx context.Context, addr string) (net.Conn, error)
+}
+
+// StatusError is returned when the server responds with a non-OK grpc status
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", e.Code, e.Message)
+}
+
+// jsonCodec passes json messages through as is
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	if msg, ok := v.(json.RawMessage); ok {
+		return msg, nil
+	}
+
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	if msg, ok := v.(*json.RawMessage); ok {
+		*msg = append((*msg)[:0], data...)
+		return nil
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// NewClient sets up the connection to the target, it's established lazily on the first call
+func NewClient(cfg Config) (*Client, error) {
+	target, err := ParseTarget(cfg.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		timeout: DefaultTimeout,
+	}
+
+	if cfg.Timeout > 0 {
+		c.timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	creds := insecure.NewCredentials()
+	if target.Scheme == "https" {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: cfg.TLSSkipVerify})
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent("autobrr"),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	}
+
+	if cfg.Dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(cfg.Dialer))
+	}
+
+	c.conn, err = grpc.Dial(target.Host, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create grpc connection")
+	}
+
+	return c, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ParseTarget parses a grpc target into the http2 base url used to reach it
+func ParseTarget(target string) (*url.URL, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, errors.Wrap(ErrInvalidTarget, "empty target")
+	}
+
+	if !strings.Contains(target, "://") {
+		target = "grpc://" + target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidTarget, "could not parse %q: %v", target, err)
+	}
+
+	switch u.Scheme {
+	case "grpc", "http":
+		u.Scheme = "http"
+	case "grpcs", "https":
+		u.Scheme = "https"
+	default:
+		return nil, errors.Wrap(ErrInvalidTarget, "unsupported scheme %q", u.Scheme)
+	}
+
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, errors.Wrap(ErrInvalidTarget, "target must be host:port, got %q", target)
+	}
+
+	if _, err := strconv.Atoi(u.Port()); err != nil {
+		return nil, errors.Wrap(ErrInvalidTarget, "invalid port %q", u.Port())
+	}
+
+	return &url.URL{Scheme: u.Scheme, Host: u.Host}, nil
+}
+
+// ParseMethod validates a full method name like pkg.Service/Method and returns it as a path
+func ParseMethod(method string) (string, error) {
+	method = strings.TrimPrefix(strings.TrimSpace(method), "/")
+
+	service, name, found := strings.Cut(method, "/")
+	if !found || service == "" || name == "" || strings.Contains(name, "/") {
+		return "", errors.Wrap(ErrInvalidMethod, "method must be package.Service/Method, got %q", method)
+	}
+
+	return "/" + method, nil
+}
+
+// Invoke calls the unary method with the json encoded request and returns the json encoded response
+func (c *Client) Invoke(ctx context.Context, method string, request []byte) ([]byte, error) {
+	path, err := ParseMethod(method)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var res json.RawMessage
+	if err := c.conn.Invoke(ctx, path, json.RawMessage(request), &res); err != nil {
+		if st, ok := status.FromError(err); ok {
+			return nil, &StatusError{Code: int(st.Code()), Message: st.Message()}
+		}
+
+		return nil, errors.Wrap(err, "could not call method: %s", path)
+	}
+
+	return res, nil
+}
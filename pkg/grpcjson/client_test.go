@@ -0,0 +1,231 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package grpcjson
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// releasesService is a json codec service with a Push method that echoes the torrent name
+// and a Slow method that waits for the call to be canceled
+var releasesService = grpc.ServiceDesc{
+	ServiceName: "autobrr.test.Releases",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Push",
+			Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req map[string]any
+				if err := dec(&req); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+
+				return map[string]any{"accepted": true, "torrent_name": req["torrent_name"]}, nil
+			},
+		},
+		{
+			MethodName: "Slow",
+			Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+	},
+}
+
+// newTestServer starts an in-process grpc server with the json codec and returns its address
+func newTestServer(t *testing.T, opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := grpc.NewServer(append(opts, grpc.ForceServerCodec(jsonCodec{}))...)
+	srv.RegisterService(&releasesService, struct{}{})
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+// newTestCert returns a self signed certificate for 127.0.0.1
+func newTestCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "grpc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestClient_Invoke(t *testing.T) {
+	target := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		request  string
+		want     string
+		wantCode int
+		wantErr  bool
+	}{
+		{
+			name:    "push_release",
+			method:  "autobrr.test.Releases/Push",
+			request: `{"torrent_name":"That.Show.S01E01.1080p.WEB-DL-GROUP"}`,
+			want:    `{"accepted":true,"torrent_name":"That.Show.S01E01.1080p.WEB-DL-GROUP"}`,
+		},
+		{
+			name:    "leading_slash",
+			method:  "/autobrr.test.Releases/Push",
+			request: `{"torrent_name":"Test"}`,
+			want:    `{"accepted":true,"torrent_name":"Test"}`,
+		},
+		{
+			name:     "unknown_method",
+			method:   "autobrr.test.Releases/Missing",
+			request:  `{}`,
+			wantCode: 12,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_message",
+			method:   "autobrr.test.Releases/Push",
+			request:  `not json`,
+			wantCode: 3,
+			wantErr:  true,
+		},
+		{
+			name:    "invalid_method_name",
+			method:  "Push",
+			request: `{}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(Config{Target: target})
+			assert.NoError(t, err)
+			defer c.Close()
+
+			got, err := c.Invoke(context.Background(), tt.method, []byte(tt.request))
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				if tt.wantCode > 0 {
+					var statusErr *StatusError
+					assert.True(t, errors.As(err, &statusErr))
+					assert.Equal(t, tt.wantCode, statusErr.Code)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestClient_Invoke_tls(t *testing.T) {
+	target := "grpcs://" + newTestServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{newTestCert(t)}})))
+
+	tests := []struct {
+		name    string
+		skip    bool
+		wantErr bool
+	}{
+		{name: "verify_self_signed", wantErr: true},
+		{name: "skip_verify", skip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(Config{Target: target, TLSSkipVerify: tt.skip})
+			assert.NoError(t, err)
+			defer c.Close()
+
+			got, err := c.Invoke(context.Background(), "autobrr.test.Releases/Push", []byte(`{"torrent_name":"Test"}`))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"accepted":true,"torrent_name":"Test"}`, string(got))
+		})
+	}
+}
+
+func TestClient_Invoke_timeout(t *testing.T) {
+	c, err := NewClient(Config{Target: newTestServer(t), Timeout: 1})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	start := time.Now()
+
+	_, err = c.Invoke(context.Background(), "autobrr.test.Releases/Slow", []byte(`{}`))
+
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, int(codes.DeadlineExceeded), statusErr.Code)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{target: "localhost:50051", want: "http://localhost:50051"},
+		{target: "grpc://localhost:50051", want: "http://localhost:50051"},
+		{target: "grpcs://grpc.domain.ltd:443", want: "https://grpc.domain.ltd:443"},
+		{target: "https://grpc.domain.ltd:8443/ignored", want: "https://grpc.domain.ltd:8443"},
+		{target: "", wantErr: true},
+		{target: "localhost", wantErr: true},
+		{target: "ftp://localhost:21", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, err := ParseTarget(tt.target)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTarget)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
@@ -42,6 +42,10 @@ type Config struct {
 	// HTTP Basic auth password
 	BasicPass string
 
+	// Transport is shared between clients for connection reuse.
+	// TLSSkipVerify is not applied to it, it is expected to be configured already.
+	Transport http.RoundTripper
+
 	Timeout int
 	Log     *log.Logger
 }
@@ -66,14 +70,18 @@ func NewClient(cfg Config) *Client {
 		Timeout: c.timeout,
 	}
 
-	customTransport := http.DefaultTransport.(*http.Transport).Clone()
-	if cfg.TLSSkipVerify {
-		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	transport := cfg.Transport
+	if transport == nil {
+		customTransport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.TLSSkipVerify {
+			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		transport = customTransport
 	}
 
 	httpClient := &http.Client{
 		Timeout:   c.timeout,
-		Transport: customTransport,
+		Transport: transport,
 	}
 
 	token := cfg.AuthToken
@@ -26,6 +26,9 @@ type Config struct {
 	Username  string
 	Password  string
 
+	// Transport is shared between clients for connection reuse, uses the default transport if nil
+	Transport http.RoundTripper
+
 	Log *log.Logger
 }
 
@@ -45,7 +48,8 @@ type client struct {
 func New(config Config) Client {
 
 	httpClient := &http.Client{
-		Timeout: time.Second * 120,
+		Timeout:   time.Second * 120,
+		Transport: config.Transport,
 	}
 
 	c := &client{
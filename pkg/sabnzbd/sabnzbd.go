@@ -6,14 +6,53 @@ package sabnzbd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
+const (
+	PriorityDefault = "-100"
+	PriorityPaused  = "-2"
+	PriorityLow     = "-1"
+	PriorityNormal  = "0"
+	PriorityHigh    = "1"
+	PriorityForce   = "2"
+)
+
+var ErrInvalidPriority = errors.New("invalid sabnzbd priority")
+
+var priorityNames = map[string]string{
+	"default": PriorityDefault,
+	"paused":  PriorityPaused,
+	"low":     PriorityLow,
+	"normal":  PriorityNormal,
+	"high":    PriorityHigh,
+	"force":   PriorityForce,
+}
+
+// ParsePriority returns the api value for a priority given by name or value
+func ParsePriority(priority string) (string, error) {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+
+	if v, ok := priorityNames[priority]; ok {
+		return v, nil
+	}
+
+	for _, v := range priorityNames {
+		if v == priority {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidPriority, priority)
+}
+
 type Client struct {
 	addr   string
 	apiKey string
@@ -33,6 +72,9 @@ type Options struct {
 	BasicUser string
 	BasicPass string
 
+	// Transport is shared between clients for connection reuse, uses the default transport if nil
+	Transport http.RoundTripper
+
 	Log *log.Logger
 }
 
@@ -44,7 +86,8 @@ func New(opts Options) *Client {
 		basicPass: opts.BasicPass,
 		log:       log.New(io.Discard, "", log.LstdFlags),
 		Http: &http.Client{
-			Timeout: time.Second * 60,
+			Timeout:   time.Second * 60,
+			Transport: opts.Transport,
 		},
 	}
 
@@ -67,6 +110,18 @@ func (c *Client) AddFromUrl(ctx context.Context, r AddNzbRequest) (*AddFileRespo
 		v.Set("cat", r.Category)
 	}
 
+	if r.Priority != "" {
+		priority, err := ParsePriority(r.Priority)
+		if err != nil {
+			return nil, err
+		}
+		v.Set("priority", priority)
+	}
+
+	if r.Script != "" {
+		v.Set("script", r.Script)
+	}
+
 	addr, err := url.JoinPath(c.addr, "/api")
 	if err != nil {
 		return nil, err
@@ -100,8 +155,6 @@ func (c *Client) AddFromUrl(ctx context.Context, r AddNzbRequest) (*AddFileRespo
 		return nil, err
 	}
 
-	fmt.Print(body)
-
 	var data AddFileResponse
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
@@ -173,4 +226,6 @@ type ApiError struct {
 type AddNzbRequest struct {
 	Url      string
 	Category string
+	Priority string
+	Script   string
 }
@@ -0,0 +1,130 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package sabnzbd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_AddFromUrl(t *testing.T) {
+	var query url.Values
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		query = r.URL.Query()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"nzo_ids":["SABnzbd_nzo_abc123"]}`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		req     AddNzbRequest
+		want    url.Values
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			req:  AddNzbRequest{Url: "https://indexer.example.com/getnzb/1"},
+			want: url.Values{
+				"mode":   {"addurl"},
+				"name":   {"https://indexer.example.com/getnzb/1"},
+				"output": {"json"},
+				"apikey": {"secret"},
+				"cat":    {"*"},
+			},
+		},
+		{
+			name: "category_priority_script",
+			req:  AddNzbRequest{Url: "https://indexer.example.com/getnzb/2", Category: "tv", Priority: "high", Script: "notify.py"},
+			want: url.Values{
+				"mode":     {"addurl"},
+				"name":     {"https://indexer.example.com/getnzb/2"},
+				"output":   {"json"},
+				"apikey":   {"secret"},
+				"cat":      {"tv"},
+				"priority": {"1"},
+				"script":   {"notify.py"},
+			},
+		},
+		{
+			name: "numeric_priority",
+			req:  AddNzbRequest{Url: "https://indexer.example.com/getnzb/3", Priority: "-100"},
+			want: url.Values{
+				"mode":     {"addurl"},
+				"name":     {"https://indexer.example.com/getnzb/3"},
+				"output":   {"json"},
+				"apikey":   {"secret"},
+				"cat":      {"*"},
+				"priority": {"-100"},
+			},
+		},
+		{
+			name:    "invalid_priority",
+			req:     AddNzbRequest{Url: "https://indexer.example.com/getnzb/4", Priority: "5"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query = nil
+
+			c := New(Options{Addr: ts.URL, ApiKey: "secret"})
+
+			res, err := c.AddFromUrl(context.Background(), tt.req)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPriority)
+				assert.Nil(t, query)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"SABnzbd_nzo_abc123"}, res.NzoIDs)
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		priority string
+		want     string
+		wantErr  bool
+	}{
+		{priority: "Default", want: PriorityDefault},
+		{priority: "paused", want: PriorityPaused},
+		{priority: "LOW", want: PriorityLow},
+		{priority: "normal", want: PriorityNormal},
+		{priority: " high ", want: PriorityHigh},
+		{priority: "force", want: PriorityForce},
+		{priority: "2", want: PriorityForce},
+		{priority: "-1", want: PriorityLow},
+		{priority: "-3", wantErr: true},
+		{priority: "urgent", wantErr: true},
+		{priority: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority, func(t *testing.T) {
+			got, err := ParsePriority(tt.priority)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPriority)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
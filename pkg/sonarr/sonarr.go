@@ -26,12 +26,16 @@ type Config struct {
 	Username  string
 	Password  string
 
+	// Transport is shared between clients for connection reuse, uses the default transport if nil
+	Transport http.RoundTripper
+
 	Log *log.Logger
 }
 
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	Command(ctx context.Context, command Command) (*CommandResponse, error)
 }
 
 type client struct {
@@ -45,7 +49,8 @@ type client struct {
 func New(config Config) Client {
 
 	httpClient := &http.Client{
-		Timeout: time.Second * 120,
+		Timeout:   time.Second * 120,
+		Transport: config.Transport,
 	}
 
 	c := &client{
@@ -63,16 +68,17 @@ func New(config Config) Client {
 }
 
 type Release struct {
-	Title            string `json:"title"`
-	InfoUrl          string `json:"infoUrl,omitempty"`
-	DownloadUrl      string `json:"downloadUrl,omitempty"`
-	MagnetUrl        string `json:"magnetUrl,omitempty"`
-	Size             int64  `json:"size"`
-	Indexer          string `json:"indexer"`
-	DownloadProtocol string `json:"downloadProtocol"`
-	Protocol         string `json:"protocol"`
-	PublishDate      string `json:"publishDate"`
-	DownloadClientId int    `json:"downloadClientId,omitempty"`
+	Title            string   `json:"title"`
+	InfoUrl          string   `json:"infoUrl,omitempty"`
+	DownloadUrl      string   `json:"downloadUrl,omitempty"`
+	MagnetUrl        string   `json:"magnetUrl,omitempty"`
+	Size             int64    `json:"size"`
+	Indexer          string   `json:"indexer"`
+	DownloadProtocol string   `json:"downloadProtocol"`
+	Protocol         string   `json:"protocol"`
+	PublishDate      string   `json:"publishDate"`
+	DownloadClientId int      `json:"downloadClientId,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
 }
 
 type PushResponse struct {
@@ -157,3 +163,44 @@ func (c *client) Push(ctx context.Context, release Release) ([]string, error) {
 	// successful push
 	return nil, nil
 }
+
+// CommandDownloadedEpisodesScan imports the completed downloads from the path or download client
+const CommandDownloadedEpisodesScan = "DownloadedEpisodesScan"
+
+// Command is queued through the command endpoint
+type Command struct {
+	Name             string `json:"name"`
+	Path             string `json:"path,omitempty"`
+	DownloadClientId string `json:"downloadClientId,omitempty"`
+	ImportMode       string `json:"importMode,omitempty"`
+}
+
+type CommandResponse struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Command queues the command and returns it with the id of the command queue
+func (c *client) Command(ctx context.Context, command Command) (*CommandResponse, error) {
+	status, res, err := c.postBody(ctx, "command", command)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not queue sonarr command: %s", command.Name)
+	}
+
+	c.Log.Printf("sonarr command status: (%v) response: %v\n", status, string(res))
+
+	switch status {
+	case http.StatusUnauthorized:
+		return nil, errors.New("unauthorized: bad credentials")
+	case http.StatusBadRequest:
+		return nil, errors.New("sonarr: bad request: %s", string(res))
+	}
+
+	response := CommandResponse{}
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return &response, nil
+}
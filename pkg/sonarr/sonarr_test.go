@@ -5,6 +5,7 @@ package sonarr
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -192,3 +193,44 @@ func Test_client_Test(t *testing.T) {
 		})
 	}
 }
+
+func Test_client_Command(t *testing.T) {
+	var got Command
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/command" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("X-Api-Key") != "mock-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&got)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1337,"name":"` + got.Name + `","status":"queued"}`))
+	}))
+	defer ts.Close()
+
+	command := Command{Name: CommandDownloadedEpisodesScan, Path: "/downloads/complete", DownloadClientId: "ABC", ImportMode: "Move"}
+
+	t.Run("queued", func(t *testing.T) {
+		c := New(Config{Hostname: ts.URL, APIKey: "mock-key"})
+
+		res, err := c.Command(context.Background(), command)
+		assert.NoError(t, err)
+		assert.Equal(t, &CommandResponse{ID: 1337, Name: CommandDownloadedEpisodesScan, Status: "queued"}, res)
+		assert.Equal(t, command, got)
+	})
+
+	t.Run("bad_api_key", func(t *testing.T) {
+		c := New(Config{Hostname: ts.URL, APIKey: "bad-key"})
+
+		_, err := c.Command(context.Background(), command)
+		assert.EqualError(t, err, "unauthorized: bad credentials")
+	})
+}
@@ -16,13 +16,26 @@ type Config struct {
 	Password      string
 	TLSSkipVerify bool
 	Timeout       int
+
+	// Transport is shared between clients for connection reuse.
+	// TLSSkipVerify is not applied to it, it is expected to be configured already.
+	Transport http.RoundTripper
 }
 
 func New(endpoint *url.URL, cfg *Config) (*transmissionrpc.Client, error) {
+	transport := cfg.Transport
+	if transport == nil {
+		dt := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.TLSSkipVerify {
+			dt.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		transport = dt
+	}
+
 	ct := &customTransport{
-		Username:      cfg.Username,
-		Password:      cfg.Password,
-		TLSSkipVerify: cfg.TLSSkipVerify,
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+		transport: transport,
 	}
 
 	extra := &transmissionrpc.Config{
@@ -36,23 +49,19 @@ func New(endpoint *url.URL, cfg *Config) (*transmissionrpc.Client, error) {
 	return transmissionrpc.New(endpoint, extra)
 }
 
+// customTransport adds basic auth, the underlying transport is kept so idle connections are reused
 type customTransport struct {
-	Username      string
-	Password      string
-	TLSSkipVerify bool
+	Username  string
+	Password  string
+	transport http.RoundTripper
 }
 
 func (t *customTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	dt := http.DefaultTransport.(*http.Transport).Clone()
-	if t.TLSSkipVerify {
-		dt.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
-
 	r := req.Clone(req.Context())
 
 	if t.Username != "" && t.Password != "" {
 		r.SetBasicAuth(t.Username, t.Password)
 	}
 
-	return dt.RoundTrip(r)
+	return t.transport.RoundTrip(r)
 }
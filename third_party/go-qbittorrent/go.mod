@@ -0,0 +1,16 @@
+module github.com/autobrr/go-qbittorrent
+
+go 1.19
+
+require (
+	github.com/avast/retry-go v3.0.0+incompatible
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.8.0
+	golang.org/x/net v0.0.0-20221002022538-bcab6841153b
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -168,7 +168,29 @@ export const APIClient = {
       body: action
     }),
     delete: (id: number) => appClient.Delete(`api/actions/${id}`),
-    toggleEnable: (id: number) => appClient.Patch(`api/actions/${id}/toggleEnabled`)
+    toggleEnable: (id: number) => appClient.Patch(`api/actions/${id}/toggleEnabled`),
+    clone: (id: number, filterIds: number[], name?: string) => appClient.Post<Action[]>(`api/actions/${id}/clone`, {
+      body: { filter_ids: filterIds, name }
+    }),
+    getTemplates: () => appClient.Get<ActionTemplate[]>("api/actions/templates"),
+    createTemplate: (template: ActionTemplate) => appClient.Post<ActionTemplate>("api/actions/templates", {
+      body: template
+    }),
+    updateTemplate: (template: ActionTemplate) => appClient.Put<ActionTemplate>(`api/actions/templates/${template.id}`, {
+      body: template
+    }),
+    deleteTemplate: (id: number) => appClient.Delete(`api/actions/templates/${id}`),
+    getMacroOverrides: () => appClient.Get<MacroOverride[]>("api/actions/macro-overrides"),
+    createMacroOverride: (override: MacroOverride) => appClient.Post<MacroOverride>("api/actions/macro-overrides", {
+      body: override
+    }),
+    deleteMacroOverride: (id: number) => appClient.Delete(`api/actions/macro-overrides/${id}`),
+    getResults: (releaseId?: number, status?: ActionResultStatus) => appClient.Get<ActionResult[]>("api/actions/results", {
+      queryString: {
+        release_id: releaseId,
+        status
+      }
+    })
   },
   apikeys: {
     getAll: () => appClient.Get<APIKey[]>("api/keys"),
@@ -291,6 +313,9 @@ export const APIClient = {
       { body: notification }
     ),
     delete: (id: number) => appClient.Delete(`api/notification/${id}`),
+    logs: (id: number, limit?: number) => appClient.Get<NotificationLog[]>(`api/notification/${id}/log`, {
+      queryString: { limit }
+    }),
     test: (notification: ServiceNotification) => appClient.Post("api/notification/test", {
       body: notification
     })
@@ -333,7 +358,10 @@ export const APIClient = {
     }),
     replayAction: (releaseId: number, actionId: number) => appClient.Post(
       `api/release/${releaseId}/actions/${actionId}/retry`
-    )
+    ),
+    pending: () => appClient.Get<PendingRelease[]>("api/release/pending"),
+    approvePending: (id: number) => appClient.Post(`api/release/pending/${id}/approve`),
+    rejectPending: (id: number) => appClient.Post(`api/release/pending/${id}/reject`)
   },
   updates: {
     check: () => appClient.Get("api/updates/check"),
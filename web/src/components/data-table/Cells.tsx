@@ -184,6 +184,26 @@ const StatusCellMap: Record<string, StatusCellMapEntry> = {
       </>
     )
   },
+  "PUSH_TIMEOUT": {
+    colors: "bg-orange-100 text-orange-800 hover:bg-orange-200",
+    icon: <ClockIcon className="h-5 w-5" aria-hidden="true" />,
+    textFormatter: (status: ReleaseActionStatus) => (
+      <>
+        <span>
+          Action
+          {" "}
+          <span className="font-bold underline underline-offset-2 decoration-2 decoration-orange-500">
+          timed out
+          </span>
+          {": "}
+          {status.action}
+        </span>
+        <div>
+          {status.action_id > 0 && <RetryActionButton status={status} />}
+        </div>
+      </>
+    )
+  },
   "PENDING": {
     colors: "bg-yellow-100 text-yellow-800 hover:bg-yellow-200",
     icon: <ClockIcon className="h-5 w-5" aria-hidden="true" />,
@@ -177,6 +177,7 @@ interface NumberFieldWideProps {
   defaultValue?: number;
   required?: boolean;
   tooltip?: JSX.Element;
+  step?: string;
 }
 
 export const NumberFieldWide = ({
@@ -186,7 +187,8 @@ export const NumberFieldWide = ({
   help,
   defaultValue,
   tooltip,
-  required
+  required,
+  step
 }: NumberFieldWideProps) => (
   <div className="px-4 space-y-1 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
     <div>
@@ -212,8 +214,9 @@ export const NumberFieldWide = ({
             {...field}
             id={name}
             type="number"
+            step={step}
             value={field.value ? field.value : defaultValue ?? 0}
-            onChange={(e) => { form.setFieldValue(field.name, parseInt(e.target.value)); }}
+            onChange={(e) => { form.setFieldValue(field.name, step ? parseFloat(e.target.value) : parseInt(e.target.value)); }}
             className={classNames(
               meta.touched && meta.error
                 ? "border-red-500 focus:ring-red-500 focus:border-red-500"
@@ -145,13 +145,24 @@ interface DownloadClientSelectProps {
   name: string;
   action: Action;
   clients: DownloadClient[];
+  label?: string;
+  tooltip?: JSX.Element;
+  // list clients of these types instead of the action type and allow choosing none
+  clientTypes?: DownloadClientType[];
 }
 
 export function DownloadClientSelect({
   name,
   action,
-  clients
+  clients,
+  label = "Client",
+  tooltip,
+  clientTypes
 }: DownloadClientSelectProps) {
+  const options: DownloadClient[] = clientTypes
+    ? [{ id: 0, name: "None" } as DownloadClient, ...clients.filter((c) => clientTypes.includes(c.type))]
+    : clients.filter((c) => c.type === action.type);
+
   return (
     <div className="col-span-12 sm:col-span-6">
       <Field name={name} type="select">
@@ -166,8 +177,10 @@ export function DownloadClientSelect({
           >
             {({ open }) => (
               <>
-                <Listbox.Label className="block text-xs font-bold text-gray-800 dark:text-gray-100 uppercase tracking-wide">
-                  Client
+                <Listbox.Label className="flex text-xs font-bold text-gray-800 dark:text-gray-100 uppercase tracking-wide">
+                  {tooltip ? (
+                    <DocsTooltip label={label}>{tooltip}</DocsTooltip>
+                  ) : label}
                 </Listbox.Label>
                 <div className="mt-1 relative">
                   <Listbox.Button className="block w-full shadow-sm sm:text-sm rounded-md border py-2 pl-3 pr-10 text-left focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 border-gray-300 dark:border-gray-700 bg-gray-100 dark:bg-gray-815 dark:text-gray-100">
@@ -194,8 +207,7 @@ export function DownloadClientSelect({
                       static
                       className="absolute z-10 mt-1 w-full border border-gray-400 dark:border-gray-700 bg-white dark:bg-gray-900 shadow-lg max-h-60 rounded-md py-1 text-base overflow-auto focus:outline-none sm:text-sm"
                     >
-                      {clients
-                        .filter((c) => c.type === action.type)
+                      {options
                         .map((client) => (
                           <Listbox.Option
                             key={client.id}
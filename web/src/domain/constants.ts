@@ -81,6 +81,25 @@ export const hdr = [
 
 export const HDR_OPTIONS: MultiSelectOption[] = hdr.map(v => ({ value: v, label: v, key: v }));
 
+export const audio = [
+  "AAC",
+  "DD",
+  "DDP",
+  "DDP Atmos",
+  "TrueHD",
+  "TrueHD Atmos",
+  "Atmos",
+  "DTS",
+  "DTS-HD.MA",
+  "DTS-HD.HRA",
+  "DTS-X",
+  "FLAC",
+  "LPCM",
+  "OPUS"
+];
+
+export const AUDIO_OPTIONS: MultiSelectOption[] = audio.map(v => ({ value: v, label: v, key: v }));
+
 export const quality_other = [
   "REMUX",
   "HYBRID",
@@ -219,6 +238,24 @@ export const languageOptions = [
 
 export const LANGUAGE_OPTIONS = languageOptions.map(v => ({ value: v, label: v, key: v }));
 
+export const subtitleOptions = [
+  "DKSUBS",
+  "HARDSUB",
+  "Hardcoded",
+  "HebSub",
+  "MULTiSUB",
+  "MULTiSUBS",
+  "NLSUBBED",
+  "SUBBED",
+  "SUBFORCED",
+  "SUBPACK",
+  "SUBS",
+  "SWESUB",
+  "VOSTFR"
+];
+
+export const SUBTITLE_OPTIONS = subtitleOptions.map(v => ({ value: v, label: v, key: v }));
+
 export interface RadioFieldsetOption {
   label: string;
   description: string;
@@ -293,7 +330,10 @@ export const DownloadClientTypeOptions: RadioFieldsetOption[] = [
 export const ActionTypeOptions: RadioFieldsetOption[] = [
   { label: "Test", description: "A simple action to test a filter.", value: "TEST" },
   { label: "Watch dir", description: "Add filtered torrents to a watch directory", value: "WATCH_FOLDER" },
+  { label: "Archive torrent", description: "Copy or hardlink the torrent file to an archive directory", value: "ARCHIVE_TORRENT" },
+  { label: "Cleanup", description: "Remove matching torrents from qBittorrent or Transmission", value: "CLEANUP" },
   { label: "Webhook", description: "Run webhook", value: "WEBHOOK" },
+  { label: "gRPC", description: "Call a gRPC method with the json codec", value: "GRPC" },
   { label: "Exec", description: "Run a custom command after a filter match", value: "EXEC" },
   { label: "qBittorrent", description: "Add torrents directly to qBittorrent", value: "QBITTORRENT" },
   { label: "Deluge", description: "Add torrents directly to Deluge", value: "DELUGE_V1" },
@@ -312,7 +352,10 @@ export const ActionTypeOptions: RadioFieldsetOption[] = [
 export const ActionTypeNameMap: Record<ActionType, string> = {
   "TEST": "Test",
   "WATCH_FOLDER": "Watch folder",
+  "ARCHIVE_TORRENT": "Archive torrent",
+  "CLEANUP": "Cleanup",
   "WEBHOOK": "Webhook",
+  "GRPC": "gRPC",
   "EXEC": "Exec",
   "DELUGE_V1": "Deluge v1",
   "DELUGE_V2": "Deluge v2",
@@ -349,6 +392,27 @@ export const ActionContentLayoutOptions: SelectGenericOption<ActionContentLayout
   { label: "Don't create subfolder", description: "Don't create subfolder", value: "SUBFOLDER_NONE" }
 ];
 
+export const ActionStopConditionOptions: SelectGenericOption<ActionStopCondition>[] = [
+  { label: "Metadata received", description: "Stop once the metadata of a magnet link is received", value: "MetadataReceived" },
+  { label: "Files checked", description: "Stop once the files are checked", value: "FilesChecked" }
+];
+
+export const ActionPathOSOptions: SelectGenericOption<ActionPathOS>[] = [
+  { label: "Linux / macOS", description: "Replace / in sanitizePath values", value: "POSIX" },
+  { label: "Windows", description: "Replace \\ / : * ? \" < > | and trailing dots in sanitizePath values", value: "WINDOWS" }
+];
+
+export const ActionArchiveModeOptions: SelectGenericOption<ActionArchiveMode>[] = [
+  { label: "Copy", description: "Write a copy of the torrent file", value: "COPY" },
+  { label: "Hardlink", description: "Hardlink the downloaded torrent file, falls back to copy across filesystems", value: "HARDLINK" }
+];
+
+export const ActionBandwidthPriorityOptions: SelectGenericOption<ActionBandwidthPriority>[] = [
+  { label: "High", description: "High bandwidth priority", value: "HIGH" },
+  { label: "Normal", description: "Normal bandwidth priority", value: "NORMAL" },
+  { label: "Low", description: "Low bandwidth priority", value: "LOW" }
+];
+
 export const ActionRtorrentRenameOptions: SelectGenericOption<ActionContentLayout>[] = [
   { label: "No", description: "No", value: "ORIGINAL" },
   { label: "Yes", description: "Yes", value: "SUBFOLDER_NONE" }
@@ -376,6 +440,10 @@ export const PushStatusOptions: OptionBasic[] = [
   {
     label: "Error",
     value: "PUSH_ERROR"
+  },
+  {
+    label: "Timeout",
+    value: "PUSH_TIMEOUT"
   }
 ];
 
@@ -403,6 +471,18 @@ export const NotificationTypeOptions: OptionBasicTyped<NotificationType>[] = [
   {
     label: "LunaSea",
     value: "LUNASEA"
+  },
+  {
+    label: "Slack",
+    value: "SLACK"
+  },
+  {
+    label: "Email",
+    value: "EMAIL"
+  },
+  {
+    label: "Microsoft Teams",
+    value: "TEAMS"
   }
 ];
 
@@ -448,6 +528,21 @@ export const downloadsPerUnitOptions: OptionBasic[] = [
   }
 ];
 
+export const dedupKeyOptions: OptionBasic[] = [
+  {
+    label: "Name and size",
+    value: "NAME_SIZE"
+  },
+  {
+    label: "Name",
+    value: "NAME"
+  },
+  {
+    label: "Infohash",
+    value: "INFOHASH"
+  }
+];
+
 export const DownloadRuleConditionOptions: OptionBasic[] = [
   {
     label: "Always",
@@ -501,6 +596,31 @@ export const EventOptions: SelectOption[] = [
     value: "IRC_RECONNECTED",
     description: "Reconnected to irc network after error"
   },
+  {
+    label: "IRC Authentication Failed",
+    value: "IRC_AUTH_FAILED",
+    description: "Failed to authenticate with NickServ or SASL on an irc network"
+  },
+  {
+    label: "Release Upgrade",
+    value: "RELEASE_UPGRADE",
+    description: "A grabbed release replaces an earlier grab in lower quality"
+  },
+  {
+    label: "Torrent Stalled",
+    value: "TORRENT_STALLED",
+    description: "A torrent failed to start with an error after it was added to the client"
+  },
+  {
+    label: "Action Disabled",
+    value: "ACTION_DISABLED",
+    description: "An action was disabled after too many failures in a row"
+  },
+  {
+    label: "Release Pending",
+    value: "RELEASE_PENDING",
+    description: "A release matched a filter that requires approval before the actions run"
+  },
   {
     label: "New update",
     value: "APP_UPDATE_AVAILABLE",
@@ -532,12 +652,14 @@ export const tagsMatchLogicOptions: OptionBasic[] = [
 
 export const ExternalFilterTypeOptions: RadioFieldsetOption[] = [
   { label: "Exec", description: "Run a custom command", value: "EXEC" },
-  { label: "Webhook", description: "Run webhook", value: "WEBHOOK" }
+  { label: "Webhook", description: "Run webhook", value: "WEBHOOK" },
+  { label: "Webhook approval", description: "Ask a remote service to approve the release", value: "WEBHOOK_APPROVAL" }
 ];
 
 export const ExternalFilterTypeNameMap = {
   "EXEC": "Exec",
-  "WEBHOOK": "Webhook"
+  "WEBHOOK": "Webhook",
+  "WEBHOOK_APPROVAL": "Webhook approval"
 };
 
 export const ExternalFilterWebhookMethodOptions: OptionBasicTyped<WebhookMethod>[] = [
@@ -29,6 +29,7 @@ import { DocsLink, ExternalLink } from "@components/ExternalLink";
 import { SelectFieldBasic } from "@components/inputs/select_wide";
 
 interface InitialValuesSettings {
+  extra_headers?: Record<string, string>;
   basic?: {
     auth: boolean;
     username: string;
@@ -97,6 +98,81 @@ function FormFieldsDeluge() {
 
       <TextFieldWide name="username" label="Username" />
       <PasswordFieldWide name="password" label="Password" />
+      <TextFieldWide
+        name="settings.save_path"
+        label="Save path"
+        help="Default download path of the client. Available to actions as the ClientSavePath and SavePath macros."
+      />
+    </div>
+  );
+}
+
+function FormFieldsTLSCertificates() {
+  return (
+    <>
+      <TextFieldWide
+        name="settings.tls_ca_file"
+        label="TLS CA file"
+        help="Optional path to a PEM CA bundle to trust, eg. for a reverse proxy with a private CA."
+      />
+      <TextFieldWide
+        name="settings.tls_cert_file"
+        label="TLS client cert file"
+        help="Optional path to a PEM client certificate for mTLS."
+      />
+      <TextFieldWide
+        name="settings.tls_key_file"
+        label="TLS client key file"
+        help="Path to the PEM key of the client certificate."
+      />
+    </>
+  );
+}
+
+// headers are edited as "Name: value" lines and stored as a map
+function FormFieldsExtraHeaders() {
+  const {
+    values: { settings },
+    setFieldValue
+  } = useFormikContext<InitialValues>();
+
+  const [text, setText] = useState(() =>
+    Object.entries(settings.extra_headers ?? {}).map(([name, value]) => `${name}: ${value}`).join("\n")
+  );
+
+  const onChange = (value: string) => {
+    setText(value);
+
+    const headers: Record<string, string> = {};
+    value.split("\n").forEach((line) => {
+      const idx = line.indexOf(":");
+      if (idx > 0) {
+        headers[line.slice(0, idx).trim()] = line.slice(idx + 1).trim();
+      }
+    });
+
+    setFieldValue("settings.extra_headers", headers);
+  };
+
+  return (
+    <div className="space-y-1 p-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4">
+      <div>
+        <label htmlFor="settings.extra_headers" className="flex ml-px text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+          Extra headers
+        </label>
+      </div>
+      <div className="sm:col-span-2">
+        <textarea
+          id="settings.extra_headers"
+          rows={3}
+          value={text}
+          onChange={(e) => onChange(e.target.value)}
+          placeholder="X-Auth-Token: secret"
+          className="block w-full shadow-sm sm:text-sm rounded-md border py-2.5 border-gray-300 dark:border-gray-700 focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 bg-gray-100 dark:bg-gray-850 dark:text-gray-100"
+          data-1p-ignore
+        />
+        <p className="mt-2 text-sm text-gray-500">Optional headers sent with every request to the client, one &quot;Name: value&quot; per line. Eg. for an auth proxy. Saved values are shown as &lt;redacted&gt; and kept unless changed.</p>
+      </div>
     </div>
   );
 }
@@ -137,6 +213,19 @@ function FormFieldsArr() {
       )}
 
       <NumberFieldWide name="settings.external_download_client_id" label="Download Client ID" tooltip={<div><p>Specify what client the arr should use by default. Can be overridden per filter action. You can find the id in the arr by looking at the network responses for download clients.</p></div>} />
+
+      <TextFieldWide
+        name="settings.proxy"
+        label="Proxy"
+        help="Optional proxy for requests to the client. Eg. http://proxy.domain.ltd:3128"
+      />
+      <TextFieldWide
+        name="settings.user_agent"
+        label="User agent"
+        help="Optional User-Agent header for requests to the client. Defaults to autobrr and its version."
+      />
+      <FormFieldsExtraHeaders />
+      <FormFieldsTLSCertificates />
     </div>
   );
 }
@@ -193,6 +282,24 @@ function FormFieldsQbit() {
           <PasswordFieldWide name="settings.basic.password" label="Password" />
         </>
       )}
+      <TextFieldWide
+        name="settings.save_path"
+        label="Save path"
+        help="Default download path of the client. Available to actions as the ClientSavePath and SavePath macros."
+      />
+
+      <TextFieldWide
+        name="settings.proxy"
+        label="Proxy"
+        help="Optional proxy for requests to the client. Eg. http://proxy.domain.ltd:3128"
+      />
+      <TextFieldWide
+        name="settings.user_agent"
+        label="User agent"
+        help="Optional User-Agent header for requests to the client. Defaults to autobrr and its version."
+      />
+      <FormFieldsExtraHeaders />
+      <FormFieldsTLSCertificates />
     </div>
   );
 }
@@ -230,6 +337,29 @@ function FormFieldsPorla() {
           <PasswordFieldWide name="settings.basic.password" label="Password" />
         </>
       )}
+
+      <TextFieldWide
+        name="settings.proxy"
+        label="Proxy"
+        help="Optional proxy for requests to the client. Eg. http://proxy.domain.ltd:3128"
+      />
+      <TextFieldWide
+        name="settings.user_agent"
+        label="User agent"
+        help="Optional User-Agent header for requests to the client. Defaults to autobrr and its version."
+      />
+      <FormFieldsExtraHeaders />
+      <FormFieldsTLSCertificates />
+      <TextFieldWide
+        name="settings.save_path"
+        label="Save path"
+        help="Default download path of the client. Available to actions as the ClientSavePath and SavePath macros."
+      />
+      <SwitchGroupWide
+        name="settings.require_preset"
+        label="Require preset"
+        description="Reject actions without a preset instead of using the default preset."
+      />
     </div>
   );
 }
@@ -275,6 +405,24 @@ function FormFieldsRTorrent() {
           <PasswordFieldWide name="settings.basic.password" label="Password" />
         </>
       )}
+      <TextFieldWide
+        name="settings.save_path"
+        label="Save path"
+        help="Default download path of the client. Available to actions as the ClientSavePath and SavePath macros."
+      />
+
+      <TextFieldWide
+        name="settings.proxy"
+        label="Proxy"
+        help="Optional proxy for requests to the client. Eg. http://proxy.domain.ltd:3128"
+      />
+      <TextFieldWide
+        name="settings.user_agent"
+        label="User agent"
+        help="Optional User-Agent header for requests to the client. Defaults to autobrr and its version."
+      />
+      <FormFieldsExtraHeaders />
+      <FormFieldsTLSCertificates />
     </div>
   );
 }
@@ -316,6 +464,24 @@ function FormFieldsTransmission() {
 
       <TextFieldWide name="username" label="Username" />
       <PasswordFieldWide name="password" label="Password" />
+
+      <TextFieldWide
+        name="settings.proxy"
+        label="Proxy"
+        help="Optional proxy for requests to the client. Eg. http://proxy.domain.ltd:3128"
+      />
+      <TextFieldWide
+        name="settings.user_agent"
+        label="User agent"
+        help="Optional User-Agent header for requests to the client. Defaults to autobrr and its version."
+      />
+      <FormFieldsExtraHeaders />
+      <FormFieldsTLSCertificates />
+      <TextFieldWide
+        name="settings.save_path"
+        label="Save path"
+        help="Default download path of the client. Available to actions as the ClientSavePath and SavePath macros."
+      />
     </div>
   );
 }
@@ -373,6 +539,19 @@ function FormFieldsSabnzbd() {
           <PasswordFieldWide name="settings.basic.password" label="Password" />
         </>
       )}
+
+      <TextFieldWide
+        name="settings.proxy"
+        label="Proxy"
+        help="Optional proxy for requests to the client. Eg. http://proxy.domain.ltd:3128"
+      />
+      <TextFieldWide
+        name="settings.user_agent"
+        label="User agent"
+        help="Optional User-Agent header for requests to the client. Defaults to autobrr and its version."
+      />
+      <FormFieldsExtraHeaders />
+      <FormFieldsTLSCertificates />
     </div>
   );
 }
@@ -547,6 +726,36 @@ export const rulesComponentMap: componentMapType = {
   TRANSMISSION: <FormFieldsRulesTransmission />
 };
 
+function FormFieldsLimits() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-5">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Limits</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Default limits for actions using this client. Can be overridden per filter action.
+        </p>
+      </div>
+
+      <NumberFieldWide
+        name="settings.limit_ratio"
+        label="Ratio limit"
+        step="0.01"
+        help="Stop seeding at ratio. 0 is no limit"
+      />
+      <NumberFieldWide
+        name="settings.limit_seed_time"
+        label="Seed time limit"
+        help="Seed time in minutes. 0 is no limit"
+      />
+    </div>
+  );
+}
+
+export const limitsComponentMap: componentMapType = {
+  QBITTORRENT: <FormFieldsLimits />,
+  TRANSMISSION: <FormFieldsLimits />
+};
+
 interface formButtonsProps {
   isSuccessfulTest: boolean;
   isErrorTest: boolean;
@@ -794,6 +1003,8 @@ export function DownloadClientAddForm({ isOpen, toggle }: formProps) {
 
                       {rulesComponentMap[values.type]}
 
+                      {limitsComponentMap[values.type]}
+
                       <DownloadClientFormButtons
                         type="CREATE"
                         isTesting={isTesting}
@@ -989,6 +1200,8 @@ export function DownloadClientUpdateForm({ client, isOpen, toggle }: updateFormP
 
                         {rulesComponentMap[values.type]}
 
+                        {limitsComponentMap[values.type]}
+
                         <DownloadClientFormButtons
                           type="UPDATE"
                           toggleDeleteModal={toggleDeleteModal}
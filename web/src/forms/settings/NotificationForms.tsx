@@ -24,6 +24,31 @@ import { NumberFieldWide, PasswordFieldWide, SwitchGroupWide, TextFieldWide } fr
 
 import { componentMapType } from "./DownloadClientForms";
 
+interface EventChannelFieldsProps {
+  label: string;
+  placeholder?: string;
+}
+
+const EventChannelFields = ({ label, placeholder }: EventChannelFieldsProps) => (
+  <div className="py-4">
+    <div className="px-4 space-y-1">
+      <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Event channels</Dialog.Title>
+      <p className="text-sm text-gray-500 dark:text-gray-400">
+        Send specific events to another {label.toLowerCase()}. Leave empty to use the default.
+      </p>
+    </div>
+
+    {EventOptions.map((e) => (
+      <PasswordFieldWide
+        key={e.value}
+        name={`event_channels.${e.value}`}
+        label={`${e.label} ${label}`}
+        placeholder={placeholder}
+      />
+    ))}
+  </div>
+);
+
 function FormFieldsDiscord() {
   return (
     <div className="border-t border-gray-200 dark:border-gray-700 py-4">
@@ -47,6 +72,62 @@ function FormFieldsDiscord() {
         help="Discord channel webhook url"
         placeholder="https://discordapp.com/api/webhooks/xx/xx"
       />
+
+      <EventChannelFields label="Webhook URL" placeholder="https://discordapp.com/api/webhooks/xx/xx" />
+    </div>
+  );
+}
+
+function FormFieldsSlack() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          {"Create an "}
+          <ExternalLink
+            href="https://api.slack.com/messaging/webhooks"
+            className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400"
+          >
+            incoming webhook
+          </ExternalLink>
+          {" for your workspace."}
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="Slack incoming webhook url"
+        placeholder="https://hooks.slack.com/services/xx/xx/xx"
+      />
+    </div>
+  );
+}
+
+function FormFieldsTeams() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          {"Create an "}
+          <ExternalLink
+            href="https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/add-incoming-webhook"
+            className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400"
+          >
+            incoming webhook
+          </ExternalLink>
+          {" for your channel."}
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="Teams incoming webhook url"
+        placeholder="https://xx.webhook.office.com/webhookb2/xx"
+      />
     </div>
   );
 }
@@ -132,6 +213,8 @@ function FormFieldsTelegram() {
         label="Message Thread ID"
         help="Message Thread (topic) of a Supergroup"
       />
+
+      <EventChannelFields label="Chat ID" />
     </div>
   );
 }
@@ -169,6 +252,12 @@ function FormFieldsPushover() {
         help="-2, -1, 0 (default), 1, or 2"
         required={true}
       />
+      <TextFieldWide
+        name="image_url"
+        label="Image URL"
+        help="Optional image attached to every message, eg. a logo. Up to 5 MB."
+        placeholder="https://domain.ltd/image.png"
+      />
     </div>
   );
 }
@@ -197,13 +286,59 @@ function FormFieldsGotify() {
   );
 }
 
+function FormFieldsEmail() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Port 465 uses implicit TLS, other ports upgrade with STARTTLS when the server supports it.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="host"
+        label="SMTP server"
+        help="Host and port of the SMTP server"
+        placeholder="smtp.example.com:587"
+        required={true}
+      />
+      <TextFieldWide
+        name="username"
+        label="Username"
+        help="Leave empty if the server does not require authentication"
+      />
+      <PasswordFieldWide
+        name="password"
+        label="Password"
+      />
+      <TextFieldWide
+        name="email_from"
+        label="From"
+        placeholder="autobrr@example.com"
+        required={true}
+      />
+      <TextFieldWide
+        name="targets"
+        label="Recipients"
+        help="Comma separated list of email addresses"
+        placeholder="one@example.com,two@example.com"
+        required={true}
+      />
+    </div>
+  );
+}
+
 const componentMap: componentMapType = {
   DISCORD: <FormFieldsDiscord />,
   NOTIFIARR: <FormFieldsNotifiarr />,
   TELEGRAM: <FormFieldsTelegram />,
   PUSHOVER: <FormFieldsPushover />,
   GOTIFY: <FormFieldsGotify />,
-  LUNASEA: <FormFieldsLunaSea />
+  LUNASEA: <FormFieldsLunaSea />,
+  SLACK: <FormFieldsSlack />,
+  EMAIL: <FormFieldsEmail />,
+  TEAMS: <FormFieldsTeams />
 };
 
 interface NotificationAddFormValues {
@@ -380,6 +515,46 @@ export function NotificationAddForm({ isOpen, toggle }: AddProps) {
                           </div>
 
                           <SwitchGroupWide name="enabled" label="Enabled" />
+                          <NumberFieldWide
+                            name="rate_limit"
+                            label="Rate limit"
+                            help="Max messages per minute, excess messages are dropped. 0 is unlimited."
+                          />
+                          <NumberFieldWide
+                            name="dispatch_order"
+                            label="Dispatch order"
+                            help="Lower numbers are notified first."
+                          />
+                          <NumberFieldWide
+                            name="min_interval"
+                            label="Min interval"
+                            help="Min seconds between messages, skipped messages are sent as one summary. 0 is disabled."
+                          />
+                          <NumberFieldWide
+                            name="max_per_hour"
+                            label="Max per hour"
+                            help="Max messages per hour, skipped messages are sent as one summary. 0 is unlimited."
+                          />
+                          <NumberFieldWide
+                            name="timeout"
+                            label="Timeout"
+                            help="Seconds to wait for the service to respond. 0 uses the default of 30 seconds."
+                          />
+                          <NumberFieldWide
+                            name="settle_period"
+                            label="Settle period"
+                            help="Seconds an irc network has to stay disconnected or reconnected before notifying, flapping connections are not notified. 0 is disabled."
+                          />
+                          <SwitchGroupWide
+                            name="suppress_skipped"
+                            label="Suppress skipped"
+                            description="Don't notify when an action skipped the release, grabs and failures are still sent."
+                          />
+                          <TextFieldWide
+                            name="user_agent"
+                            label="User agent"
+                            help="User-Agent header of the requests. Defaults to autobrr and its version."
+                          />
 
                           <div className="border-t mt-2 border-gray-200 dark:border-gray-700 py-4">
                             <div className="px-4 space-y-1">
@@ -484,6 +659,20 @@ interface InitialValues {
   channel?: string;
   topic?: string;
   host?: string;
+  username?: string;
+  password?: string;
+  targets?: string;
+  email_from?: string;
+  rate_limit?: number;
+  dispatch_order?: number;
+  min_interval?: number;
+  max_per_hour?: number;
+  timeout?: number;
+  settle_period?: number;
+  suppress_skipped?: boolean;
+  user_agent?: string;
+  image_url?: string;
+  event_channels?: Record<string, string>;
   events: NotificationEvent[];
 }
 
@@ -534,6 +723,20 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
     channel: notification.channel,
     topic: notification.topic,
     host: notification.host,
+    username: notification.username,
+    password: notification.password,
+    targets: notification.targets,
+    email_from: notification.email_from,
+    rate_limit: notification.rate_limit,
+    dispatch_order: notification.dispatch_order,
+    min_interval: notification.min_interval,
+    max_per_hour: notification.max_per_hour,
+    timeout: notification.timeout,
+    settle_period: notification.settle_period,
+    suppress_skipped: notification.suppress_skipped,
+    user_agent: notification.user_agent,
+    image_url: notification.image_url,
+    event_channels: notification.event_channels || {},
     events: notification.events || []
   };
 
@@ -604,6 +807,46 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
               </div>
             </div>
             <SwitchGroupWide name="enabled" label="Enabled"/>
+            <NumberFieldWide
+              name="rate_limit"
+              label="Rate limit"
+              help="Max messages per minute, excess messages are dropped. 0 is unlimited."
+            />
+            <NumberFieldWide
+              name="dispatch_order"
+              label="Dispatch order"
+              help="Lower numbers are notified first."
+            />
+            <NumberFieldWide
+              name="min_interval"
+              label="Min interval"
+              help="Min seconds between messages, skipped messages are sent as one summary. 0 is disabled."
+            />
+            <NumberFieldWide
+              name="max_per_hour"
+              label="Max per hour"
+              help="Max messages per hour, skipped messages are sent as one summary. 0 is unlimited."
+            />
+            <NumberFieldWide
+              name="timeout"
+              label="Timeout"
+              help="Seconds to wait for the service to respond. 0 uses the default of 30 seconds."
+            />
+            <NumberFieldWide
+              name="settle_period"
+              label="Settle period"
+              help="Seconds an irc network has to stay disconnected or reconnected before notifying, flapping connections are not notified. 0 is disabled."
+            />
+            <SwitchGroupWide
+              name="suppress_skipped"
+              label="Suppress skipped"
+              description="Don't notify when an action skipped the release, grabs and failures are still sent."
+            />
+            <TextFieldWide
+              name="user_agent"
+              label="User agent"
+              help="User-Agent header of the requests. Defaults to autobrr and its version."
+            />
             <div className="border-t border-gray-200 dark:border-gray-700 py-4">
               <div className="px-4 space-y-1">
                 <Dialog.Title
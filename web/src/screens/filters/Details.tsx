@@ -172,16 +172,23 @@ const FormErrorNotification = () => {
 const actionSchema = z.object({
   enabled: z.boolean(),
   name: z.string(),
-  type: z.enum(["TEST", "EXEC", "WATCH_FOLDER", "WEBHOOK", ...DOWNLOAD_CLIENTS]),
+  type: z.enum(["TEST", "EXEC", "WATCH_FOLDER", "ARCHIVE_TORRENT", "CLEANUP", "WEBHOOK", "GRPC", ...DOWNLOAD_CLIENTS]),
   client_id: z.number().optional(),
   exec_cmd: z.string().optional(),
   exec_args: z.string().optional(),
+  exec_env: z.boolean().optional(),
+  exec_workdir: z.string().optional(),
+  exec_shell: z.boolean().optional(),
+  exec_concurrency: z.number().optional(),
   watch_folder: z.string().optional(),
   category: z.string().optional(),
   tags: z.string().optional(),
   label: z.string().optional(),
+  preset: z.string().optional(),
   save_path: z.string().optional(),
   paused: z.boolean().optional(),
+  resume_delay: z.number().optional(),
+  rescan_client_id: z.number().optional(),
   ignore_rules: z.boolean().optional(),
   limit_upload_speed: z.number().optional(),
   limit_download_speed: z.number().optional(),
@@ -191,11 +198,67 @@ const actionSchema = z.object({
   reannounce_delete: z.boolean().optional(),
   reannounce_interval: z.number().optional(),
   reannounce_max_attempts: z.number().optional(),
+  reannounce_target_peers: z.number().optional(),
+  verify_start: z.boolean().optional(),
+  recheck_resume: z.boolean().optional(),
+  timeout: z.number().optional(),
+  delay: z.number().optional(),
+  disable_after_failures: z.number().optional(),
+  bandwidth_priority: z.string().optional(),
+  peer_limit: z.number().optional(),
+  cleanup_min_age: z.number().optional(),
+  cleanup_min_ratio: z.number().optional(),
+  cleanup_delete_data: z.boolean().optional(),
+  cleanup_dry_run: z.boolean().optional(),
+  move_completed: z.boolean().optional(),
+  move_completed_path: z.string().optional(),
   webhook_host: z.string().optional(),
   webhook_type: z.string().optional(),
   webhook_method: z.string().optional(),
-  webhook_data: z.string().optional()
+  webhook_data: z.string().optional(),
+  webhook_validate_json: z.boolean().optional(),
+  webhook_success_when: z.string().optional(),
+  webhook_file_field: z.string().optional(),
+  comment: z.string().optional(),
+  top_of_queue: z.boolean().optional(),
+  queue_position: z.number().optional(),
+  min_free_space: z.string().optional(),
+  auto_tmm: z.boolean().optional(),
+  stop_condition: z.string().optional(),
+  path_os: z.string().optional(),
+  archive_path: z.string().optional(),
+  archive_filename: z.string().optional(),
+  archive_mode: z.string().optional(),
+  grpc_method: z.string().optional(),
+  grpc_tls_skip_verify: z.boolean().optional(),
+  priority: z.string().optional(),
+  pp_script: z.string().optional()
 }).superRefine((value, ctx) => {
+  if (value.type === "GRPC") {
+    if (!value.webhook_host) {
+      ctx.addIssue({
+        message: "Must specify target",
+        code: z.ZodIssueCode.custom,
+        path: ["webhook_host"]
+      });
+    }
+    if (!value.grpc_method || !/^\/?[\w.]+\/\w+$/.test(value.grpc_method)) {
+      ctx.addIssue({
+        message: "Must be package.Service/Method",
+        code: z.ZodIssueCode.custom,
+        path: ["grpc_method"]
+      });
+    }
+  }
+  if (value.type === "SABNZBD" && value.priority && !value.priority.includes("{{")) {
+    if (!/^(default|paused|low|normal|high|force|-100|-2|-1|0|1|2)$/i.test(value.priority.trim())) {
+      ctx.addIssue({
+        message: "Must be default, paused, low, normal, high or force",
+        code: z.ZodIssueCode.custom,
+        path: ["priority"]
+      });
+    }
+  }
   if (DOWNLOAD_CLIENTS.includes(value.type)) {
     if (!value.client_id) {
       ctx.addIssue({
@@ -211,7 +274,7 @@ const externalFilterSchema = z.object({
   enabled: z.boolean(),
   index: z.number(),
   name: z.string(),
-  type: z.enum(["EXEC", "WEBHOOK"]),
+  type: z.enum(["EXEC", "WEBHOOK", "WEBHOOK_APPROVAL"]),
   exec_cmd: z.string().optional(),
   exec_args: z.string().optional(),
   exec_expect_status: z.number().optional(),
@@ -222,7 +285,9 @@ const externalFilterSchema = z.object({
   webhook_expect_status: z.number().optional(),
   webhook_retry_status: z.string().optional(),
   webhook_retry_attempts: z.number().optional(),
-  webhook_retry_delay_seconds: z.number().optional()
+  webhook_retry_delay_seconds: z.number().optional(),
+  webhook_timeout: z.number().optional(),
+  webhook_fail_open: z.boolean().optional()
 }).superRefine((value, ctx) => {
   if (!value.name) {
     ctx.addIssue({
@@ -256,6 +321,16 @@ const externalFilterSchema = z.object({
     }
   }
 
+  if (value.type == "WEBHOOK_APPROVAL") {
+    if (!value.webhook_host) {
+      ctx.addIssue({
+        message: "Must have webhook host",
+        code: z.ZodIssueCode.custom,
+        path: ["webhook_host"]
+      });
+    }
+  }
+
   if (value.type == "EXEC") {
     if (!value.exec_cmd) {
       ctx.addIssue({
@@ -385,10 +460,22 @@ export const FilterDetails = () => {
               enabled: filter.enabled,
               min_size: filter.min_size,
               max_size: filter.max_size,
+              min_trackers: filter.min_trackers,
+              max_trackers: filter.max_trackers,
+              min_seeders: filter.min_seeders,
+              max_seeders: filter.max_seeders,
+              reject_unknown_seeders: filter.reject_unknown_seeders,
+              require_download: filter.require_download,
+              require_approval: filter.require_approval,
+              min_group_tier: filter.min_group_tier,
+              dedup_window: filter.dedup_window,
+              dedup_key: filter.dedup_key,
               delay: filter.delay,
               priority: filter.priority,
+              stop_on_match: filter.stop_on_match,
               max_downloads: filter.max_downloads,
               max_downloads_unit: filter.max_downloads_unit,
+              max_active_downloads: filter.max_active_downloads,
               use_regex: filter.use_regex || false,
               shows: filter.shows,
               years: filter.years,
@@ -398,6 +485,8 @@ export const FilterDetails = () => {
               containers: filter.containers || [],
               match_hdr: filter.match_hdr || [],
               except_hdr: filter.except_hdr || [],
+              match_audio: filter.match_audio || [],
+              except_audio: filter.except_audio || [],
               match_other: filter.match_other || [],
               except_other: filter.except_other || [],
               seasons: filter.seasons,
@@ -423,7 +512,10 @@ export const FilterDetails = () => {
               except_uploaders: filter.except_uploaders,
               match_language: filter.match_language || [],
               except_language: filter.except_language || [],
+              match_subtitles: filter.match_subtitles || [],
+              except_subtitles: filter.except_subtitles || [],
               freeleech: filter.freeleech,
+              scene: filter.scene,
               freeleech_percent: filter.freeleech_percent,
               formats: filter.formats || [],
               quality: filter.quality || [],
@@ -3,8 +3,19 @@ export const FILTER_FIELDS: Record<string, string> = {
   "enabled": "boolean",
   "delay": "number",
   "priority": "number",
+  "stop_on_match": "boolean",
   "log_score": "number",
   "max_downloads": "number",
+  "max_active_downloads": "number",
+  "min_trackers": "number",
+  "max_trackers": "number",
+  "min_seeders": "number",
+  "max_seeders": "number",
+  "reject_unknown_seeders": "boolean",
+  "require_download": "boolean",
+  "require_approval": "boolean",
+  "min_group_tier": "number",
+  "dedup_window": "number",
   "use_regex": "boolean",
   "scene": "boolean",
   "smart_episode": "boolean",
@@ -34,6 +45,8 @@ export const FILTER_FIELDS: Record<string, string> = {
   "except_sites": "string",
   "origins": "[]string",
   "except_origins": "[]string",
+  "match_subtitles": "[]string",
+  "except_subtitles": "[]string",
   "bonus": "[]string",
   "resolutions": "[]string",
   "codecs": "[]string",
@@ -41,6 +54,8 @@ export const FILTER_FIELDS: Record<string, string> = {
   "containers": "[]string",
   "match_hdr": "[]string",
   "except_hdr": "[]string",
+  "match_audio": "[]string",
+  "except_audio": "[]string",
   "match_other": "[]string",
   "except_other": "[]string",
   "match_release_types": "[]string",
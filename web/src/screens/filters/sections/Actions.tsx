@@ -15,7 +15,7 @@ import { useToggle } from "@hooks/hooks";
 import { APIClient } from "@api/APIClient";
 import { ActionTypeNameMap, ActionTypeOptions, DOWNLOAD_CLIENTS } from "@domain/constants";
 
-import { Select, TextField } from "@components/inputs";
+import { NumberField, Select, TextField } from "@components/inputs";
 import { DeleteModal } from "@components/modals";
 import { EmptyListState } from "@components/emptystates";
 import Toast from "@components/notifications/Toast";
@@ -46,14 +46,26 @@ export function Actions({ filter, values }: FilterActionsProps) {
     watch_folder: "",
     exec_cmd: "",
     exec_args: "",
+    exec_env: false,
+    exec_workdir: "",
+    exec_shell: false,
+    exec_concurrency: 0,
     category: "",
     tags: "",
     label: "",
+    preset: "",
     save_path: "",
     paused: false,
+    resume_delay: 0,
+    rescan_client_id: 0,
     ignore_rules: false,
     skip_hash_check: false,
     content_layout: "" || undefined,
+    stop_condition: "" || undefined,
+    top_of_queue: false,
+    queue_position: 0,
+    min_free_space: "",
+    auto_tmm: false,
     limit_upload_speed: 0,
     limit_download_speed: 0,
     limit_ratio: 0,
@@ -62,12 +74,38 @@ export function Actions({ filter, values }: FilterActionsProps) {
     reannounce_delete: false,
     reannounce_interval: 7,
     reannounce_max_attempts: 25,
+    reannounce_target_peers: 0,
+    verify_start: false,
+    recheck_resume: false,
+    timeout: 0,
+    delay: 0,
+    disable_after_failures: 0,
+    bandwidth_priority: "" || undefined,
+    peer_limit: 0,
+    cleanup_min_age: 0,
+    cleanup_min_ratio: 0,
+    cleanup_delete_data: false,
+    cleanup_dry_run: false,
+    move_completed: false,
+    move_completed_path: "",
     filter_id: filter.id,
     webhook_host: "",
     webhook_type: "",
     webhook_method: "",
     webhook_data: "",
+    grpc_method: "",
+    grpc_tls_skip_verify: false,
+    priority: "",
+    pp_script: "",
     webhook_headers: [],
+    webhook_validate_json: false,
+    webhook_success_when: "",
+    webhook_file_field: "",
+    comment: "",
+    path_os: "" || undefined,
+    archive_path: "",
+    archive_filename: "",
+    archive_mode: "" || undefined,
     external_download_client_id: 0,
     client_id: 0
   };
@@ -166,8 +204,14 @@ const TypeForm = (props: ClientActionProps) => {
     return <FilterActions.Exec {...props} />;
   case "WATCH_FOLDER":
     return <FilterActions.WatchFolder {...props} />;
+  case "ARCHIVE_TORRENT":
+    return <FilterActions.ArchiveTorrent {...props} />;
+  case "CLEANUP":
+    return <FilterActions.Cleanup {...props} />;
   case "WEBHOOK":
     return <FilterActions.WebHook {...props} />;
+  case "GRPC":
+    return <FilterActions.GRPC {...props} />;
   default:
     // TODO(stacksmash76): Indicate error
     return null;
@@ -285,6 +329,33 @@ function FilterActionsItem({ action, clients, idx, initialEdit, remove }: Filter
                 <FilterSection.HalfRow>
                   <TextField name={`actions.${idx}.name`} label="Name" />
                 </FilterSection.HalfRow>
+
+                <FilterSection.HalfRow>
+                  <NumberField
+                    name={`actions.${idx}.timeout`}
+                    label="Timeout"
+                    placeholder="Seconds (0 is disabled)"
+                    tooltip={<div><p>Cancel the action if it takes longer than this many seconds.</p></div>}
+                  />
+                </FilterSection.HalfRow>
+
+                <FilterSection.HalfRow>
+                  <NumberField
+                    name={`actions.${idx}.delay`}
+                    label="Delay"
+                    placeholder="Seconds (0 is disabled)"
+                    tooltip={<div><p>Wait this many seconds before running the action, so earlier actions of the filter can finish. The delay counts towards the timeout.</p></div>}
+                  />
+                </FilterSection.HalfRow>
+
+                <FilterSection.HalfRow>
+                  <NumberField
+                    name={`actions.${idx}.disable_after_failures`}
+                    label="Disable after failures"
+                    placeholder="Takes any number (0 is disabled)"
+                    tooltip={<div><p>Disable the action after this many failed runs in a row and send an Action Disabled notification. A successful run resets the count.</p></div>}
+                  />
+                </FilterSection.HalfRow>
               </FilterSection.Layout>
             </FilterSection.Section>
 
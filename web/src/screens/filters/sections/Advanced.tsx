@@ -94,7 +94,7 @@ const Releases = ({ values }: ValueConsumer) => (
 
 const Groups = ({ values }: ValueConsumer) => (
   <CollapsibleSection
-    defaultOpen={values.match_release_groups || values.except_release_groups}
+    defaultOpen={values.match_release_groups || values.except_release_groups || values.min_group_tier}
     title="Groups"
     subtitle="Match only certain groups and/or ignore other groups."
   >
@@ -122,6 +122,16 @@ const Groups = ({ values }: ValueConsumer) => (
         </div>
       }
     />
+    <Input.NumberField
+      name="min_group_tier"
+      label="Min group tier"
+      placeholder="0 is disabled"
+      tooltip={
+        <div>
+          <p>Minimum trust tier of the release group. Tiers are set with groupTiers in config.toml, groups not listed get the default tier.</p>
+        </div>
+      }
+    />
   </CollapsibleSection>
 );
 
@@ -257,9 +267,9 @@ const Uploaders = ({ values }: ValueConsumer) => (
 
 const Language = ({ values }: ValueConsumer) => (
   <CollapsibleSection
-    defaultOpen={(values.match_language && values.match_language.length > 0) || (values.except_language && values.except_language.length > 0)}
+    defaultOpen={(values.match_language && values.match_language.length > 0) || (values.except_language && values.except_language.length > 0) || (values.match_subtitles && values.match_subtitles.length > 0) || (values.except_subtitles && values.except_subtitles.length > 0)}
     title="Language"
-    subtitle="Match or ignore languages (if announced)"
+    subtitle="Match or ignore languages and subtitles (if announced)"
   >
     <Input.MultiSelect
       name="match_language"
@@ -273,12 +283,24 @@ const Language = ({ values }: ValueConsumer) => (
       label="Except Language"
       columns={6}
     />
+    <Input.MultiSelect
+      name="match_subtitles"
+      options={CONSTS.SUBTITLE_OPTIONS}
+      label="Match Subtitles"
+      columns={6}
+    />
+    <Input.MultiSelect
+      name="except_subtitles"
+      options={CONSTS.SUBTITLE_OPTIONS}
+      label="Except Subtitles"
+      columns={6}
+    />
   </CollapsibleSection>
 );
 
 const Origins = ({ values }: ValueConsumer) => (
   <CollapsibleSection
-    defaultOpen={(values.origins && values.origins.length > 0 || values.except_origins && values.except_origins.length > 0)}
+    defaultOpen={(values.scene || values.origins && values.origins.length > 0 || values.except_origins && values.except_origins.length > 0)}
     title="Origins"
     subtitle="Match Internals, Scene, P2P, etc. (if announced)"
   >
@@ -294,6 +316,14 @@ const Origins = ({ values }: ValueConsumer) => (
       label="Except Origins"
       columns={6}
     />
+    <Components.HalfRow>
+      <Input.SwitchGroup
+        name="scene"
+        label="Scene"
+        className="py-0"
+        description="Only match releases announced as scene."
+      />
+    </Components.HalfRow>
   </CollapsibleSection>
 );
 
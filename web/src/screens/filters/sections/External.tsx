@@ -12,7 +12,7 @@ import { classNames } from "@utils";
 import { useToggle } from "@hooks/hooks";
 import { TextAreaAutoResize } from "@components/inputs/input";
 import { EmptyListState } from "@components/emptystates";
-import { NumberField, Select, TextField } from "@components/inputs";
+import { NumberField, Select, SwitchGroup, TextField } from "@components/inputs";
 import {
   ExternalFilterTypeNameMap,
   ExternalFilterTypeOptions,
@@ -365,6 +365,40 @@ const TypeForm = ({ external, idx }: TypeFormProps) => {
       </>
     );
   }
+  case "WEBHOOK_APPROVAL": {
+    return (
+      <FilterSection.Section
+        title="Approval"
+        subtitle="The release and filter are sent as JSON, the service responds with true/false or {\"approved\": bool, \"reason\": \"...\"}"
+      >
+        <FilterSection.Layout>
+          <TextField
+            name={`external.${idx}.webhook_host`}
+            label="Endpoint"
+            columns={6}
+            placeholder="Host eg. http://localhost/approve"
+            tooltip={<p>URL of the approval service. Pass params and set API tokens etc.</p>}
+          />
+          <TextField
+            name={`external.${idx}.webhook_headers`}
+            label="HTTP Request Headers"
+            columns={6}
+            placeholder="HEADER=custom1;HEADER2=custom2"
+          />
+          <NumberField
+            name={`external.${idx}.webhook_timeout`}
+            label="Timeout in seconds"
+            placeholder="15"
+          />
+          <SwitchGroup
+            name={`external.${idx}.webhook_fail_open`}
+            label="Approve on failure"
+            description="Approve the release if the service can't be reached or responds with an error. Rejects by default."
+          />
+        </FilterSection.Layout>
+      </FilterSection.Section>
+    );
+  }
 
   default: {
     return null;
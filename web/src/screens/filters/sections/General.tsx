@@ -1,7 +1,7 @@
 import { useQuery } from "@tanstack/react-query";
 
 import { APIClient } from "@api/APIClient";
-import { downloadsPerUnitOptions } from "@domain/constants";
+import { dedupKeyOptions, downloadsPerUnitOptions } from "@domain/constants";
 
 import { DocsLink } from "@components/ExternalLink";
 
@@ -107,6 +107,84 @@ export const General = () => {
               </div>
             }
           />
+          <Input.NumberField
+            name="min_trackers"
+            label="Min trackers"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={
+              <div>
+                <p>Minimum number of distinct announce URLs in the torrent file. Requires downloading the torrent file.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Input.NumberField
+            name="max_trackers"
+            label="Max trackers"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={
+              <div>
+                <p>Maximum number of distinct announce URLs in the torrent file. Requires downloading the torrent file.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Input.NumberField
+            name="max_active_downloads"
+            label="Max active downloads"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={
+              <div>
+                <p>Hold the release while the download client of the action has this many active downloads, checking again with a growing delay before skipping it. Only supported by qBittorrent.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Input.NumberField
+            name="min_seeders"
+            label="Min seeders"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={
+              <div>
+                <p>Minimum number of seeders in the announce. Only works with indexers that announce seeders.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Input.NumberField
+            name="max_seeders"
+            label="Max seeders"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={
+              <div>
+                <p>Maximum number of seeders in the announce. Only works with indexers that announce seeders.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Input.NumberField
+            name="dedup_window"
+            label="Dedup window"
+            placeholder="Minutes (0 is disabled)"
+            tooltip={
+              <div>
+                <p>Skip identical releases announced by multiple indexers within this many minutes.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <Input.Select
+            name="dedup_key"
+            label="Dedup by"
+            options={dedupKeyOptions}
+            optionDefaultText="Name and size"
+            tooltip={
+              <div>
+                <p>How releases are compared. Infohash falls back to name and size when it's not known at announce time.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
         </Components.Layout>
 
         <Components.Layout>
@@ -116,6 +194,30 @@ export const General = () => {
             description="Enable or disable this filter."
             className="pb-2 col-span-12 sm:col-span-6"
           />
+          <Input.SwitchGroup
+            name="stop_on_match"
+            label="Stop on match"
+            description="Don't check lower priority filters once this filter matched and its actions ran."
+            className="pb-2 col-span-12 sm:col-span-6"
+          />
+          <Input.SwitchGroup
+            name="reject_unknown_seeders"
+            label="Reject unknown seeders"
+            description="Reject releases without seeders in the announce when min or max seeders is set."
+            className="pb-2 col-span-12 sm:col-span-6"
+          />
+          <Input.SwitchGroup
+            name="require_download"
+            label="Require download"
+            description="Download the torrent file before running actions and skip the release if it fails. The actions reuse the downloaded file."
+            className="pb-2 col-span-12 sm:col-span-6"
+          />
+          <Input.SwitchGroup
+            name="require_approval"
+            label="Require approval"
+            description="Hold matched releases in the pending queue. The actions only run once the release is approved."
+            className="pb-2 col-span-12 sm:col-span-6"
+          />
         </Components.Layout>
       </Components.Section>
     </Components.Page>
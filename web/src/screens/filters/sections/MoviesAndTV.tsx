@@ -40,7 +40,7 @@ const SeasonsAndEpisodes = () => (
         <SwitchGroup
           name="smart_episode"
           label="Smart Episode"
-          description="Do not match episodes older than the last one matched."
+          description="Do not match episodes older than the last one matched, or episodes already grabbed unless it is a quality upgrade."
         />
       </div>
     </Components.Layout>
@@ -133,6 +133,35 @@ const Quality = () => (
       />
     </Components.Layout>
 
+    <Components.Layout gap={Components.WideGridGapClass}>
+      <MultiSelect
+        name="match_audio"
+        options={CONSTS.AUDIO_OPTIONS}
+        label="Match Audio"
+        columns={6}
+        creatable
+        tooltip={
+          <div>
+            <p>Will match releases which contain any of the selected audio formats. Combined values like <code>TrueHD Atmos</code> need every part.</p>
+            <DocsLink href="https://autobrr.com/filters#quality" />
+          </div>
+        }
+      />
+      <MultiSelect
+        name="except_audio"
+        options={CONSTS.AUDIO_OPTIONS}
+        label="Except Audio"
+        columns={6}
+        creatable
+        tooltip={
+          <div>
+            <p>Won't match releases which contain any of the selected audio formats (takes priority over Match Audio).</p>
+            <DocsLink href="https://autobrr.com/filters#quality" />
+          </div>
+        }
+      />
+    </Components.Layout>
+
     <Components.Layout gap={Components.WideGridGapClass}>
       <MultiSelect
         name="match_other"
@@ -1,3 +1,4 @@
+import { ActionPathOSOptions } from "@domain/constants";
 import * as Input from "@components/inputs";
 
 import { CollapsibleSection } from "../_components";
@@ -19,6 +20,16 @@ export const Deluge = ({ idx, action, clients }: ClientActionProps) => (
             clients={clients}
           />
         </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.DownloadClientSelect
+            name={`actions.${idx}.rescan_client_id`}
+            label="Rescan in"
+            action={action}
+            clients={clients}
+            clientTypes={["SONARR", "RADARR"]}
+            tooltip={<p>Queue a downloaded scan in this Sonarr or Radarr instance after the torrent is added. Uses the save path when set.</p>}
+          />
+        </FilterSection.HalfRow>
         <FilterSection.HalfRow>
           <Input.TextField
             name={`actions.${idx}.label`}
@@ -33,6 +44,24 @@ export const Deluge = ({ idx, action, clients }: ClientActionProps) => (
           label="Save path"
           placeholder="eg. /full/path/to/download_folder"
         />
+        <FilterSection.HalfRow>
+          <Input.Select
+            name={`actions.${idx}.path_os`}
+            label="Path OS"
+            optionDefaultText="Select path OS"
+            options={ActionPathOSOptions}
+            tooltip={<p>Characters removed by the <code>sanitizePath</code> macro function. Windows also strips trailing dots.</p>}
+          />
+        </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.TextField
+            name={`actions.${idx}.min_free_space`}
+            label="Min free space"
+            columns={6}
+            placeholder="eg. 50 GB (empty is disabled)"
+            tooltip={<p>Skip the release when the client reports less free space at the save path. Supports units such as MB, GiB, etc.</p>}
+          />
+        </FilterSection.HalfRow>
       </FilterSection.Layout>
 
       <FilterSection.Layout className="pb-6">
@@ -42,7 +71,25 @@ export const Deluge = ({ idx, action, clients }: ClientActionProps) => (
             label="Add paused"
             description="Add torrent as paused"
           />
+          <Input.NumberField
+            name={`actions.${idx}.resume_delay`}
+            label="Resume after (minutes)"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={<p>Resume the torrent added paused after this many minutes, to spread out announces. Pending resumes continue after a restart of autobrr.</p>}
+          />
+        </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.SwitchGroup
+            name={`actions.${idx}.move_completed`}
+            label="Move completed"
+            description="Move the data when the download completes"
+          />
         </FilterSection.HalfRow>
+        <Input.TextAreaAutoResize
+          name={`actions.${idx}.move_completed_path`}
+          label="Move completed path"
+          placeholder="eg. /mnt/bulk/{{ .Indexer }} (supports macros)"
+        />
       </FilterSection.Layout>
 
       <CollapsibleSection
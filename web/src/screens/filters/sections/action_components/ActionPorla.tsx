@@ -1,3 +1,4 @@
+import { ActionPathOSOptions } from "@domain/constants";
 import * as Input from "@components/inputs";
 
 import { CollapsibleSection } from "../_components";
@@ -19,13 +20,23 @@ export const Porla = ({ idx, action, clients }: ClientActionProps) => (
             clients={clients}
           />
         </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.DownloadClientSelect
+            name={`actions.${idx}.rescan_client_id`}
+            label="Rescan in"
+            action={action}
+            clients={clients}
+            clientTypes={["SONARR", "RADARR"]}
+            tooltip={<p>Queue a downloaded scan in this Sonarr or Radarr instance after the torrent is added. Uses the save path when set.</p>}
+          />
+        </FilterSection.HalfRow>
         <FilterSection.HalfRow>
           <Input.TextField
-            name={`actions.${idx}.label`}
+            name={`actions.${idx}.preset`}
             label="Preset"
             placeholder="eg. default"
             tooltip={
-              <div>A case-sensitive preset name as configured in Porla.</div>
+              <div>A case-sensitive preset name as configured in Porla. Supports macros.</div>
             }
           />
         </FilterSection.HalfRow>
@@ -38,6 +49,18 @@ export const Porla = ({ idx, action, clients }: ClientActionProps) => (
         className="pb-6"
       />
 
+      <FilterSection.Layout>
+        <FilterSection.HalfRow>
+          <Input.Select
+            name={`actions.${idx}.path_os`}
+            label="Path OS"
+            optionDefaultText="Select path OS"
+            options={ActionPathOSOptions}
+            tooltip={<p>Characters removed by the <code>sanitizePath</code> macro function. Windows also strips trailing dots.</p>}
+          />
+        </FilterSection.HalfRow>
+      </FilterSection.Layout>
+
       <CollapsibleSection
         noBottomBorder
         title="Limits"
@@ -1,7 +1,7 @@
 import { Link } from "react-router-dom";
 
 import { DocsLink } from "@components/ExternalLink";
-import { ActionContentLayoutOptions } from "@domain/constants";
+import { ActionContentLayoutOptions, ActionPathOSOptions, ActionStopConditionOptions } from "@domain/constants";
 import * as Input from "@components/inputs";
 
 import { CollapsibleSection } from "../_components";
@@ -23,6 +23,16 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
             clients={clients}
           />
         </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.DownloadClientSelect
+            name={`actions.${idx}.rescan_client_id`}
+            label="Rescan in"
+            action={action}
+            clients={clients}
+            clientTypes={["SONARR", "RADARR"]}
+            tooltip={<p>Queue a downloaded scan in this Sonarr or Radarr instance after the torrent is added. Uses the save path when set.</p>}
+          />
+        </FilterSection.HalfRow>
       </FilterSection.Layout>
 
       <FilterSection.Layout>
@@ -53,6 +63,23 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
         />
       </FilterSection.Layout>
 
+      <FilterSection.Layout>
+        <Input.TextField
+          name={`actions.${idx}.comment`}
+          label="Comment"
+          columns={12}
+          placeholder="eg. {{ .FilterName }} {{ .AnnouncedAt }}"
+          tooltip={
+            <div>
+              <p>Written into the torrent file as its comment before adding it. Not supported for magnet links.</p>
+              <br />
+              <p>The field can use macros to transform/add values from metadata:</p>
+              <DocsLink href="https://autobrr.com/filters/macros" />
+            </div>
+          }
+        />
+      </FilterSection.Layout>
+
       <FilterSection.Layout className="pb-6">
         <Input.TextAreaAutoResize
           name={`actions.${idx}.save_path`}
@@ -67,6 +94,15 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
             </div>
           }
         />
+        <FilterSection.HalfRow>
+          <Input.Select
+            name={`actions.${idx}.path_os`}
+            label="Path OS"
+            optionDefaultText="Select path OS"
+            options={ActionPathOSOptions}
+            tooltip={<p>Characters removed by the <code>sanitizePath</code> macro function. Windows also strips trailing dots.</p>}
+          />
+        </FilterSection.HalfRow>
       </FilterSection.Layout>
 
       <CollapsibleSection
@@ -91,6 +127,25 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
             optionDefaultText="Select content layout"
             options={ActionContentLayoutOptions}
           />
+          <Input.Select
+            name={`actions.${idx}.stop_condition`}
+            label="Stop condition"
+            optionDefaultText="Select stop condition"
+            options={ActionStopConditionOptions}
+            tooltip={<p>Stop the torrent once the condition is reached, eg. to inspect magnet links after the metadata is received. Requires qBittorrent 4.5 or newer, older versions add the torrent without it.</p>}
+          />
+          <Input.NumberField
+            name={`actions.${idx}.queue_position`}
+            label="Queue position"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={<p>Move the torrent to this position in the queue after adding. Requires queueing to be enabled in qBittorrent.</p>}
+          />
+          <Input.TextField
+            name={`actions.${idx}.min_free_space`}
+            label="Min free space"
+            placeholder="eg. 50 GB (empty is disabled)"
+            tooltip={<p>Skip the release when qBittorrent reports less free space on the disk of its default save path. Supports units such as MB, GiB, etc.</p>}
+          />
         </FilterSection.HalfRow>
 
         <FilterSection.HalfRow>
@@ -99,11 +154,32 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
             label="Add paused"
             description="Add torrent as paused"
           />
+          <Input.NumberField
+            name={`actions.${idx}.resume_delay`}
+            label="Resume after (minutes)"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={<p>Resume the torrent added paused after this many minutes, to spread out announces. Pending resumes continue after a restart of autobrr.</p>}
+          />
           <Input.SwitchGroup
             name={`actions.${idx}.skip_hash_check`}
             label="Skip hash check"
             description="Add torrent and skip hash check"
           />
+          <Input.SwitchGroup
+            name={`actions.${idx}.top_of_queue`}
+            label="Add to top of queue"
+            description="Add torrent to the top of the queue. Requires queueing to be enabled in qBittorrent."
+          />
+          <Input.SwitchGroup
+            name={`actions.${idx}.auto_tmm`}
+            label="Automatic Torrent Management"
+            description="Let qBittorrent place the torrent in the save path of its category. The save path of the action is ignored."
+          />
+          <Input.SwitchGroup
+            name={`actions.${idx}.recheck_resume`}
+            label="Recheck and resume"
+            description="Add torrent stopped, force a recheck and only resume if all data is present. Useful for cross-seeding."
+          />
         </FilterSection.HalfRow>
       </CollapsibleSection>
 
@@ -170,6 +246,17 @@ export const QBittorrent = ({ idx, action, clients }: ClientActionProps) => (
             name={`actions.${idx}.reannounce_max_attempts`}
             label="Run reannounce Y times"
           />
+          <Input.NumberField
+            name={`actions.${idx}.reannounce_target_peers`}
+            label="Target peers"
+            placeholder="Keep reannouncing until trackers report this many seeds and leechers"
+          />
+          <Input.SwitchGroup
+            name={`actions.${idx}.verify_start`}
+            label="Verify start"
+            description="Check the torrent starts downloading and send a warning if it fails to start with an error or missing files"
+            className="pt-2 pb-4"
+          />
         </FilterSection.HalfRow>
       </CollapsibleSection>
     </FilterSection.Section>
@@ -1,4 +1,4 @@
-import { ActionRtorrentRenameOptions } from "@domain/constants";
+import { ActionPathOSOptions, ActionRtorrentRenameOptions } from "@domain/constants";
 import * as Input from "@components/inputs";
 
 import * as FilterSection from "../_components";
@@ -19,6 +19,16 @@ export const RTorrent = ({ idx, action, clients }: ClientActionProps) => (
             clients={clients}
           />
         </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.DownloadClientSelect
+            name={`actions.${idx}.rescan_client_id`}
+            label="Rescan in"
+            action={action}
+            clients={clients}
+            clientTypes={["SONARR", "RADARR"]}
+            tooltip={<p>Queue a downloaded scan in this Sonarr or Radarr instance after the torrent is added. Uses the save path when set.</p>}
+          />
+        </FilterSection.HalfRow>
 
         <FilterSection.HalfRow>
           <Input.TextField
@@ -36,6 +46,18 @@ export const RTorrent = ({ idx, action, clients }: ClientActionProps) => (
         placeholder="eg. /full/path/to/download_folder"
       />
 
+      <FilterSection.Layout>
+        <FilterSection.HalfRow>
+          <Input.Select
+            name={`actions.${idx}.path_os`}
+            label="Path OS"
+            optionDefaultText="Select path OS"
+            options={ActionPathOSOptions}
+            tooltip={<p>Characters removed by the <code>sanitizePath</code> macro function. Windows also strips trailing dots.</p>}
+          />
+        </FilterSection.HalfRow>
+      </FilterSection.Layout>
+
       <FilterSection.Layout>
         <FilterSection.HalfRow>
           <Input.SwitchGroup
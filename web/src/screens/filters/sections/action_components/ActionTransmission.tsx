@@ -1,4 +1,6 @@
+import { ActionBandwidthPriorityOptions, ActionPathOSOptions } from "@domain/constants";
 import * as Input from "@components/inputs";
+import { DocsLink } from "@components/ExternalLink";
 
 import { CollapsibleSection } from "../_components";
 import * as FilterSection from "../_components";
@@ -19,6 +21,16 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
             clients={clients}
           />
         </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.DownloadClientSelect
+            name={`actions.${idx}.rescan_client_id`}
+            label="Rescan in"
+            action={action}
+            clients={clients}
+            clientTypes={["SONARR", "RADARR"]}
+            tooltip={<p>Queue a downloaded scan in this Sonarr or Radarr instance after the torrent is added. Uses the save path when set.</p>}
+          />
+        </FilterSection.HalfRow>
         <FilterSection.HalfRow>
           <Input.TextField
             name={`actions.${idx}.label`}
@@ -29,6 +41,23 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
         </FilterSection.HalfRow>
       </FilterSection.Layout>
 
+      <FilterSection.Layout>
+        <Input.TextField
+          name={`actions.${idx}.comment`}
+          label="Comment"
+          columns={12}
+          placeholder="eg. {{ .FilterName }} {{ .AnnouncedAt }}"
+          tooltip={
+            <div>
+              <p>Written into the torrent file as its comment before adding it. Not supported for magnet links.</p>
+              <br />
+              <p>The field can use macros to transform/add values from metadata:</p>
+              <DocsLink href="https://autobrr.com/filters/macros" />
+            </div>
+          }
+        />
+      </FilterSection.Layout>
+
       <Input.TextAreaAutoResize
         name={`actions.${idx}.save_path`}
         label="Save path"
@@ -36,6 +65,27 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
         placeholder="eg. /full/path/to/download_folder"
       />
 
+      <FilterSection.Layout>
+        <FilterSection.HalfRow>
+          <Input.Select
+            name={`actions.${idx}.path_os`}
+            label="Path OS"
+            optionDefaultText="Select path OS"
+            options={ActionPathOSOptions}
+            tooltip={<p>Characters removed by the <code>sanitizePath</code> macro function. Windows also strips trailing dots.</p>}
+          />
+        </FilterSection.HalfRow>
+        <FilterSection.HalfRow>
+          <Input.TextField
+            name={`actions.${idx}.min_free_space`}
+            label="Min free space"
+            columns={6}
+            placeholder="eg. 50 GB (empty is disabled)"
+            tooltip={<p>Skip the release when the client reports less free space at the save path. Supports units such as MB, GiB, etc.</p>}
+          />
+        </FilterSection.HalfRow>
+      </FilterSection.Layout>
+
       <FilterSection.Layout className="pb-6">
         <FilterSection.HalfRow>
           <Input.SwitchGroup
@@ -43,6 +93,12 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
             label="Add paused"
             description="Add torrent as paused"
           />
+          <Input.NumberField
+            name={`actions.${idx}.resume_delay`}
+            label="Resume after (minutes)"
+            placeholder="Takes any number (0 is disabled)"
+            tooltip={<p>Resume the torrent added paused after this many minutes, to spread out announces. Pending resumes continue after a restart of autobrr.</p>}
+          />
         </FilterSection.HalfRow>
       </FilterSection.Layout>
 
@@ -77,6 +133,20 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
             placeholder="Takes any number (0 is no limit)"
           />
         </FilterSection.Layout>
+
+        <FilterSection.Layout>
+          <Input.Select
+            name={`actions.${idx}.bandwidth_priority`}
+            label="Bandwidth priority"
+            optionDefaultText="Client default"
+            options={ActionBandwidthPriorityOptions}
+          />
+          <Input.NumberField
+            name={`actions.${idx}.peer_limit`}
+            label="Peer limit"
+            placeholder="Takes any number (0 is client default)"
+          />
+        </FilterSection.Layout>
       </CollapsibleSection>
 
       <CollapsibleSection
@@ -109,6 +179,17 @@ export const Transmission = ({ idx, action, clients }: ClientActionProps) => (
             name={`actions.${idx}.reannounce_max_attempts`}
             label="Run reannounce Y times"
           />
+          <Input.NumberField
+            name={`actions.${idx}.reannounce_target_peers`}
+            label="Target peers"
+            placeholder="Keep reannouncing until trackers report this many seeds and leechers"
+          />
+          <Input.SwitchGroup
+            name={`actions.${idx}.verify_start`}
+            label="Verify start"
+            description="Check the torrent starts downloading and send a warning if it fails to start with an error or missing files"
+            className="pt-2 pb-4"
+          />
         </FilterSection.HalfRow>
       </CollapsibleSection>
     </FilterSection.Section>
@@ -1,4 +1,5 @@
 import { WarningAlert } from "@components/alerts";
+import { ActionArchiveModeOptions, ActionPathOSOptions } from "@domain/constants";
 import * as Input from "@components/inputs";
 
 import * as FilterSection from "../_components";
@@ -27,6 +28,24 @@ export const SABnzbd = ({ idx, action, clients }: ClientActionProps) => (
           tooltip={<p>Category must exist already.</p>}
         />
       </FilterSection.HalfRow>
+      <FilterSection.HalfRow>
+        <Input.TextField
+          name={`actions.${idx}.priority`}
+          label="Priority"
+          columns={6}
+          placeholder="eg. high"
+          tooltip={<p>One of default, paused, low, normal, high or force. Supports macros.</p>}
+        />
+      </FilterSection.HalfRow>
+      <FilterSection.HalfRow>
+        <Input.TextField
+          name={`actions.${idx}.pp_script`}
+          label="Post-processing script"
+          columns={6}
+          placeholder="eg. script.py"
+          tooltip={<p>Script must exist in the SABnzbd scripts folder. Supports macros.</p>}
+        />
+      </FilterSection.HalfRow>
     </FilterSection.Layout>
   </FilterSection.Section>
 );
@@ -57,6 +76,31 @@ export const Exec = ({ idx }: ClientActionProps) => (
         label="Arguments"
         placeholder="Arguments eg. --test"
       />
+
+      <Input.SwitchGroup
+        name={`actions.${idx}.exec_env`}
+        label="Pass release as environment variables"
+        description="Export release data such as AUTOBRR_TORRENT_NAME and AUTOBRR_INDEXER to the program."
+      />
+
+      <Input.TextField
+        name={`actions.${idx}.exec_workdir`}
+        label="Working directory"
+        placeholder="eg. /home/user/scripts"
+      />
+
+      <Input.SwitchGroup
+        name={`actions.${idx}.exec_shell`}
+        label="Run in shell"
+        description="Run the command as a script with sh -c (cmd /C on Windows) to allow pipes and redirects. Put pipes and redirects in the command, the args are passed to it as \"$1\", \"$2\" or \"$@\" and are never run as shell code."
+      />
+
+      <Input.NumberField
+        name={`actions.${idx}.exec_concurrency`}
+        label="Max concurrent runs"
+        placeholder="Takes any number (0 is unlimited)"
+        tooltip={<p>Limit how many commands of this action run at the same time. Further releases wait for a free slot. The global execConcurrency config limit applies as well.</p>}
+      />
     </FilterSection.Layout>
 
   </FilterSection.Section>
@@ -73,10 +117,119 @@ export const WatchFolder = ({ idx }: ClientActionProps) => (
         label="Watch directory"
         placeholder="Watch directory eg. /home/user/rwatch"
       />
+      <FilterSection.HalfRow>
+        <Input.Select
+          name={`actions.${idx}.path_os`}
+          label="Path OS"
+          optionDefaultText="Select path OS"
+          options={ActionPathOSOptions}
+          tooltip={<p>Characters removed by the <code>sanitizePath</code> macro function. Windows also strips trailing dots.</p>}
+        />
+      </FilterSection.HalfRow>
     </FilterSection.Layout>
   </FilterSection.Section>
 );
 
+export const ArchiveTorrent = ({ idx }: ClientActionProps) => (
+  <FilterSection.Section
+    title="Archive Torrent Arguments"
+    subtitle="Keep a copy of the torrent file itself, the downloaded data is not touched. Use an absolute path."
+  >
+    <FilterSection.Layout>
+      <Input.TextAreaAutoResize
+        name={`actions.${idx}.archive_path`}
+        label="Archive directory"
+        placeholder="Archive directory eg. /home/user/torrents/{{ .Indexer }}"
+      />
+      <Input.TextField
+        name={`actions.${idx}.archive_filename`}
+        label="Filename"
+        columns={6}
+        placeholder="Defaults to the release name eg. {{ .Indexer }}-{{ .TorrentName }}"
+        tooltip={<p>Supports macros. The <code>.torrent</code> extension is added if missing.</p>}
+      />
+      <FilterSection.HalfRow>
+        <Input.Select
+          name={`actions.${idx}.archive_mode`}
+          label="Mode"
+          optionDefaultText="Copy"
+          options={ActionArchiveModeOptions}
+        />
+      </FilterSection.HalfRow>
+    </FilterSection.Layout>
+  </FilterSection.Section>
+);
+
+export const Cleanup = ({ idx, action, clients }: ClientActionProps) => (
+  <FilterSection.Section
+    title="Cleanup Arguments"
+    subtitle="Remove torrents from the client that match every criteria set below. Runs when the filter matches a release."
+  >
+    <FilterSection.Layout>
+      <FilterSection.HalfRow>
+        <Input.DownloadClientSelect
+          name={`actions.${idx}.client_id`}
+          action={action}
+          clients={clients}
+          clientTypes={["QBITTORRENT", "TRANSMISSION"]}
+        />
+      </FilterSection.HalfRow>
+      <FilterSection.HalfRow>
+        <Input.TextField
+          name={`actions.${idx}.category`}
+          label="Category"
+          columns={6}
+          placeholder="eg. racing"
+          tooltip={<p>qBittorrent only.</p>}
+        />
+      </FilterSection.HalfRow>
+      <FilterSection.HalfRow>
+        <Input.TextField
+          name={`actions.${idx}.tags`}
+          label="Tags"
+          columns={6}
+          placeholder="eg. tag1,tag2"
+          tooltip={<p>qBittorrent only. The torrent needs every tag.</p>}
+        />
+      </FilterSection.HalfRow>
+      <FilterSection.HalfRow>
+        <Input.TextField
+          name={`actions.${idx}.label`}
+          label="Label"
+          columns={6}
+          placeholder="eg. racing"
+          tooltip={<p>Matches the Transmission label or qBittorrent tag.</p>}
+        />
+      </FilterSection.HalfRow>
+    </FilterSection.Layout>
+    <FilterSection.Layout>
+      <Input.NumberField
+        name={`actions.${idx}.cleanup_min_age`}
+        label="Min age (minutes)"
+        placeholder="Takes any number (0 is any age)"
+        tooltip={<p>Time since the torrent was added to the client.</p>}
+      />
+      <Input.NumberField
+        name={`actions.${idx}.cleanup_min_ratio`}
+        label="Min ratio"
+        placeholder="Takes any number (0 is any ratio)"
+        step={0.25}
+        isDecimal
+      />
+    </FilterSection.Layout>
+    <Input.SwitchGroup
+      name={`actions.${idx}.cleanup_delete_data`}
+      label="Delete data"
+      description="Also remove the downloaded files from disk"
+    />
+    <Input.SwitchGroup
+      name={`actions.${idx}.cleanup_dry_run`}
+      label="Dry run"
+      description="Only list the torrents that would be removed in the action response"
+    />
+  </FilterSection.Section>
+);
+
 export const WebHook = ({ idx }: ClientActionProps) => (
   <FilterSection.Section
     title="Webhook Arguments"
@@ -89,7 +242,7 @@ export const WebHook = ({ idx }: ClientActionProps) => (
         columns={6}
         placeholder="Host eg. http://localhost/webhook"
         tooltip={
-          <p>URL or IP to your API. Pass params and set API tokens etc.</p>
+          <p>URL or IP to your API. Pass params and set API tokens etc. Supports macros like {"{{ .Indexer }}"}, values in the path and query are URL encoded.</p>
         }
       />
     </FilterSection.Layout>
@@ -98,6 +251,66 @@ export const WebHook = ({ idx }: ClientActionProps) => (
       label="Payload (json)"
       placeholder={"Request data: { \"key\": \"value\" }"}
     />
+    <Input.SwitchGroup
+      name={`actions.${idx}.webhook_validate_json`}
+      label="Validate JSON"
+      description="Check the payload is valid JSON after macros are expanded and fail the action before sending if not"
+    />
+    <FilterSection.Layout>
+      <Input.TextField
+        name={`actions.${idx}.webhook_success_when`}
+        label="Success when"
+        columns={6}
+        placeholder="eg. status=accepted"
+        tooltip={
+          <p>Optional check on the JSON response, written as <code>path=value</code> or <code>path!=value</code>. Nested keys and array indexes are separated by dots, eg. <code>result.items.0.ok=true</code>. The action fails when the response doesn't match.</p>
+        }
+      />
+      <Input.TextField
+        name={`actions.${idx}.webhook_file_field`}
+        label="Torrent file field"
+        columns={6}
+        placeholder="eg. torrent"
+        tooltip={
+          <p>Send the .torrent file as a multipart upload in this form field. The payload must then be a JSON object, each key is sent as a form field alongside the file.</p>
+        }
+      />
+    </FilterSection.Layout>
+  </FilterSection.Section>
+);
+
+export const GRPC = ({ idx }: ClientActionProps) => (
+  <FilterSection.Section
+    title="gRPC Arguments"
+    subtitle="Call a unary gRPC method with the json codec upon filter match."
+  >
+    <FilterSection.Layout>
+      <Input.TextField
+        name={`actions.${idx}.webhook_host`}
+        label="Target"
+        columns={6}
+        placeholder="Target eg. localhost:50051"
+        tooltip={
+          <p>host:port for plaintext, or grpcs://host:port for TLS. The server must support the json codec (application/grpc+json).</p>
+        }
+      />
+      <Input.TextField
+        name={`actions.${idx}.grpc_method`}
+        label="Method"
+        columns={6}
+        placeholder="Method eg. package.Service/Method"
+      />
+    </FilterSection.Layout>
+    <Input.TextAreaAutoResize
+      name={`actions.${idx}.webhook_data`}
+      label="Payload (json)"
+      placeholder={"Leave empty to send the release. Request data: { \"key\": \"value\" }"}
+    />
+    <Input.SwitchGroup
+      name={`actions.${idx}.grpc_tls_skip_verify`}
+      label="Skip TLS verification (insecure)"
+      description="Accept any certificate from a grpcs:// target, for self signed certificates."
+    />
   </FilterSection.Section>
 );
 
@@ -129,6 +342,29 @@ export const Arr = ({ idx, action, clients }: ClientActionProps) => (
           }
         />
       </FilterSection.HalfRow>
+
+      {(action.type === "RADARR" || action.type === "SONARR") && (
+        <>
+          <FilterSection.HalfRow>
+            <Input.TextField
+              name={`actions.${idx}.tags`}
+              label="Tags"
+              columns={6}
+              placeholder="eg. autobrr,{{ .Resolution }}"
+              tooltip={<p>Comma separated tags sent with the release push. Supports macros.</p>}
+            />
+          </FilterSection.HalfRow>
+          <FilterSection.HalfRow>
+            <Input.TextField
+              name={`actions.${idx}.label`}
+              label="Label"
+              columns={6}
+              placeholder="eg. {{ .Source }}"
+              tooltip={<p>Sent as an extra tag with the release push. Supports macros.</p>}
+            />
+          </FilterSection.HalfRow>
+        </>
+      )}
     </FilterSection.Layout>
   </FilterSection.Section>
 );
@@ -13,9 +13,9 @@ import { componentMapType } from "@forms/settings/DownloadClientForms";
 import Toast from "@components/notifications/Toast";
 import toast from "react-hot-toast";
 import { Section } from "./_components";
-import { PlusIcon } from "@heroicons/react/24/solid";
+import { ChatBubbleLeftRightIcon, EnvelopeIcon, PlusIcon } from "@heroicons/react/24/solid";
 import { Checkbox } from "@components/Checkbox";
-import { DiscordIcon, GotifyIcon, LunaSeaIcon, NotifiarrIcon, PushoverIcon, TelegramIcon } from "./_components";
+import { DiscordIcon, GotifyIcon, LunaSeaIcon, NotifiarrIcon, PushoverIcon, SlackIcon, TelegramIcon } from "./_components";
 
 export const notificationKeys = {
   all: ["notifications"] as const,
@@ -76,7 +76,10 @@ const iconComponentMap: componentMapType = {
   TELEGRAM: <span className={iconStyle}><TelegramIcon /> Telegram</span>,
   PUSHOVER: <span className={iconStyle}><PushoverIcon /> Pushover</span>,
   GOTIFY: <span className={iconStyle}><GotifyIcon /> Gotify</span>,
-  LUNASEA: <span className={iconStyle}><LunaSeaIcon /> LunaSea</span>
+  LUNASEA: <span className={iconStyle}><LunaSeaIcon /> LunaSea</span>,
+  SLACK: <span className={iconStyle}><SlackIcon /> Slack</span>,
+  EMAIL: <span className={iconStyle}><EnvelopeIcon className="mr-2 h-5" /> Email</span>,
+  TEAMS: <span className={iconStyle}><ChatBubbleLeftRightIcon className="mr-2 h-5" /> Teams</span>
 };
 
 interface ListItemProps {
@@ -49,6 +49,16 @@ interface DownloadClientSettings {
   basic?: DownloadClientBasicAuth;
   rules?: DownloadClientRules;
   external_download_client_id?: number;
+  limit_ratio?: number;
+  limit_seed_time?: number;
+  proxy?: string;
+  save_path?: string;
+  require_preset?: boolean;
+  tls_ca_file?: string;
+  tls_cert_file?: string;
+  tls_key_file?: string;
+  user_agent?: string;
+  extra_headers?: Record<string, string>;
 }
 
 interface DownloadClient {
@@ -11,10 +11,22 @@ interface Filter {
   updated_at: Date;
   min_size: string;
   max_size: string;
+  min_trackers: number;
+  max_trackers: number;
+  min_seeders: number;
+  max_seeders: number;
+  reject_unknown_seeders: boolean;
+  require_download: boolean;
+  require_approval: boolean;
+  min_group_tier: number;
+  dedup_window: number;
+  dedup_key: string;
   delay: number;
   priority: number;
+  stop_on_match: boolean;
   max_downloads: number;
   max_downloads_unit: string;
+  max_active_downloads: number;
   match_releases: string;
   except_releases: string;
   use_regex: boolean;
@@ -41,6 +53,8 @@ interface Filter {
   containers: string[];
   match_hdr: string[];
   except_hdr: string[];
+  match_audio: string[];
+  except_audio: string[];
   match_other: string[];
   except_other: string[];
   years: string;
@@ -61,6 +75,8 @@ interface Filter {
   except_uploaders: string;
   match_language: string[];
   except_language: string[];
+  match_subtitles: string[];
+  except_subtitles: string[];
   tags: string;
   except_tags: string;
   tags_any: string;
@@ -81,15 +97,27 @@ interface Action {
   enabled: boolean;
   exec_cmd?: string;
   exec_args?: string;
+  exec_env?: boolean;
+  exec_workdir?: string;
+  exec_shell?: boolean;
+  exec_concurrency?: number;
   watch_folder?: string;
   category?: string;
   tags?: string;
   label?: string;
+  preset?: string;
   save_path?: string;
   paused?: boolean;
+  resume_delay?: number;
+  rescan_client_id?: number;
   ignore_rules?: boolean;
   skip_hash_check: boolean;
   content_layout?: ActionContentLayout;
+  stop_condition?: ActionStopCondition;
+  top_of_queue?: boolean;
+  queue_position?: number;
+  min_free_space?: string;
+  auto_tmm?: boolean;
   limit_upload_speed?: number;
   limit_download_speed?: number;
   limit_ratio?: number;
@@ -98,21 +126,92 @@ interface Action {
   reannounce_delete: boolean;
   reannounce_interval: number;
   reannounce_max_attempts: number;
+  reannounce_target_peers?: number;
+  verify_start?: boolean;
+  recheck_resume?: boolean;
+  timeout?: number;
+  delay?: number;
+  disable_after_failures?: number;
+  bandwidth_priority?: ActionBandwidthPriority;
+  peer_limit?: number;
+  cleanup_min_age?: number;
+  cleanup_min_ratio?: number;
+  cleanup_delete_data?: boolean;
+  cleanup_dry_run?: boolean;
+  move_completed?: boolean;
+  move_completed_path?: string;
   webhook_host: string,
   webhook_type: string;
   webhook_method: string;
   webhook_data: string,
   webhook_headers: string[];
+  webhook_validate_json?: boolean;
+  webhook_success_when?: string;
+  webhook_file_field?: string;
+  comment?: string;
+  path_os?: ActionPathOS;
+  archive_path?: string;
+  archive_filename?: string;
+  archive_mode?: ActionArchiveMode;
+  grpc_method?: string;
+  grpc_tls_skip_verify?: boolean;
+  priority?: string;
+  pp_script?: string;
   external_download_client_id?: number;
   client_id?: number;
   filter_id?: number;
+  template_id?: number;
+  template_overrides?: string[];
+}
+
+interface ActionTemplate {
+  id: number;
+  name: string;
+  action: Action;
+  created_at?: Date;
+  updated_at?: Date;
+}
+
+interface MacroOverride {
+  id: number;
+  indexer: string;
+  field: string;
+  match: string;
+  value: string;
+  created_at?: Date;
+}
+
+type ActionResultStatus = "SUCCESS" | "SKIPPED" | "FAILED";
+
+interface ActionResult {
+  id: number;
+  action_id: number;
+  action: string;
+  type: ActionType;
+  release_id: number;
+  release_name: string;
+  status: ActionResultStatus;
+  error?: string;
+  rejections: string[];
+  fields?: Record<string, string>;
+  response?: string;
+  started_at: Date;
+  duration: number;
 }
 
 type ActionContentLayout = "ORIGINAL" | "SUBFOLDER_CREATE" | "SUBFOLDER_NONE";
 
-type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "WEBHOOK" | DownloadClientType;
+type ActionStopCondition = "MetadataReceived" | "FilesChecked";
+
+type ActionPathOS = "POSIX" | "WINDOWS";
+
+type ActionArchiveMode = "COPY" | "HARDLINK";
+
+type ActionBandwidthPriority = "HIGH" | "NORMAL" | "LOW";
+
+type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "ARCHIVE_TORRENT" | "CLEANUP" | "WEBHOOK" | "GRPC" | DownloadClientType;
 
-type ExternalType = "EXEC" |  "WEBHOOK";
+type ExternalType = "EXEC" |  "WEBHOOK" | "WEBHOOK_APPROVAL";
 
 type WebhookMethod = "GET" | "POST" | "PUT" | "PATCH" | "DELETE";
 
@@ -134,5 +233,7 @@ interface ExternalFilter {
   webhook_retry_status?: string,
   webhook_retry_attempts?: number;
   webhook_retry_delay_seconds?: number;
+  webhook_timeout?: number;
+  webhook_fail_open?: boolean;
   filter_id?: number;
 }
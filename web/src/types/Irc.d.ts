@@ -17,6 +17,7 @@ interface IrcNetwork {
   use_bouncer: boolean;
   bouncer_addr: string;
   bot_mode: boolean;
+  proxy: string;
   channels: IrcChannel[];
   connected: boolean;
   connected_since: string;
@@ -35,6 +36,7 @@ interface IrcNetworkCreate {
   use_bouncer?: boolean;
   bouncer_addr?: string;
   bot_mode?: boolean;
+  proxy?: string;
   channels: IrcChannel[];
   connected: boolean;
 }
@@ -67,6 +69,7 @@ interface IrcNetworkWithHealth {
   use_bouncer: boolean;
   bouncer_addr: string;
   bot_mode: boolean;
+  proxy: string;
   channels: IrcChannelWithHealth[];
   connected: boolean;
   connected_since: string;
@@ -3,13 +3,18 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "GOTIFY" | "LUNASEA";
+type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "GOTIFY" | "LUNASEA" | "SLACK" | "EMAIL" | "TEAMS";
 type NotificationEvent =
   "PUSH_APPROVED"
   | "PUSH_REJECTED"
   | "PUSH_ERROR"
   | "IRC_DISCONNECTED"
   | "IRC_RECONNECTED"
+  | "IRC_AUTH_FAILED"
+  | "RELEASE_UPGRADE"
+  | "TORRENT_STALLED"
+  | "ACTION_DISABLED"
+  | "RELEASE_PENDING"
   | "APP_UPDATE_AVAILABLE";
 
 interface ServiceNotification {
@@ -25,4 +30,27 @@ interface ServiceNotification {
   priority?: number;
   topic?: string;
   host?: string;
+  username?: string;
+  password?: string;
+  targets?: string;
+  email_from?: string;
+  rate_limit?: number;
+  dispatch_order?: number;
+  min_interval?: number;
+  max_per_hour?: number;
+  timeout?: number;
+  settle_period?: number;
+  suppress_skipped?: boolean;
+  user_agent?: string;
+  image_url?: string;
+  event_channels?: Record<string, string>;
+}
+
+interface NotificationLog {
+  id: number;
+  notification_id: number;
+  event: NotificationEvent;
+  message_id: string;
+  error: string;
+  created_at: string;
 }
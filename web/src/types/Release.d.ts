@@ -52,3 +52,13 @@ interface ReleaseFilter {
   id: string;
   value: string;
 }
+
+interface PendingRelease {
+  id: number;
+  release_id: number;
+  filter_id: number;
+  torrent_name: string;
+  indexer: string;
+  filter_name: string;
+  created_at: string;
+}